@@ -5,9 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"strings"
 	"syscall"
 
+	"github.com/ethpandaops/xcli/pkg/ai"
 	"github.com/ethpandaops/xcli/pkg/autoupgrade"
 	"github.com/ethpandaops/xcli/pkg/commands"
 	"github.com/ethpandaops/xcli/pkg/config"
@@ -53,8 +54,9 @@ func main() {
 		FullTimestamp: true,
 	})
 
-	// Check for updates and auto-upgrade if needed (runs quickly due to caching)
-	if repoPath := findRepoPath(); repoPath != "" {
+	// Check for updates and auto-upgrade if needed (runs quickly due to caching).
+	// The self commands manage upgrades themselves.
+	if repoPath := autoupgrade.FindRepoPath(); repoPath != "" && !isSelfCommand() {
 		upgrader := autoupgrade.NewService(log, repoPath, version.Commit)
 		if err := upgrader.CheckAndUpgrade(ctx); err != nil {
 			log.WithError(err).Debug("Auto-upgrade check failed")
@@ -90,6 +92,13 @@ func main() {
 		log.SetLevel(level)
 		config.SetRuntimeConfigPath(configPath)
 
+		// --profile is declared by the lab and cc commands.
+		if profile := cmd.Flags().Lookup("profile"); profile != nil {
+			config.SetActiveProfile(profile.Value.String())
+		}
+
+		configureAI(log)
+
 		// Enable log writer based on verbose flag
 		logWriter.SetEnabled(verbose)
 
@@ -104,6 +113,9 @@ func main() {
 	rootCmd.AddCommand(commands.NewInitCommand(log, configPath))
 	rootCmd.AddCommand(commands.NewConfigCommand(log, configPath))
 	rootCmd.AddCommand(commands.NewCompletionCommand())
+	rootCmd.AddCommand(commands.NewDiagnoseCommand(log, configPath))
+	rootCmd.AddCommand(commands.NewSelfCommand(log))
+	rootCmd.AddCommand(commands.NewWorkspaceCommand(log))
 
 	// Add stack commands
 	rootCmd.AddCommand(commands.NewLabCommand(log, configPath))
@@ -117,21 +129,33 @@ func main() {
 	}
 }
 
-// findRepoPath attempts to locate the xcli repository for auto-updates.
-func findRepoPath() string {
-	// Load global config to get xcli path
+// configureAI applies AI provider settings from the global config.
+func configureAI(log logrus.FieldLogger) {
 	globalCfg, err := config.LoadGlobalConfig()
 	if err != nil {
-		return ""
+		log.WithError(err).Debug("Failed to load global config for AI providers")
+
+		return
 	}
 
-	// Verify the path has a .git directory
-	if globalCfg.XCLIPath != "" {
-		gitDir := filepath.Join(globalCfg.XCLIPath, ".git")
-		if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
-			return globalCfg.XCLIPath
+	toProvider := func(endpoint config.AIEndpointConfig) ai.HTTPProviderConfig {
+		return ai.HTTPProviderConfig{
+			BaseURL:   endpoint.BaseURL,
+			Model:     endpoint.Model,
+			APIKey:    endpoint.APIKey,
+			APIKeyEnv: endpoint.APIKeyEnv,
+			Timeout:   endpoint.Timeout,
 		}
 	}
 
-	return ""
+	ai.SetConfig(ai.Config{
+		DefaultProvider: ai.ProviderID(strings.ToLower(strings.TrimSpace(globalCfg.AI.Provider))),
+		OpenAI:          toProvider(globalCfg.AI.OpenAI),
+		Local:           toProvider(globalCfg.AI.Local),
+	})
+}
+
+// isSelfCommand reports whether xcli was invoked as 'xcli self ...'.
+func isSelfCommand() bool {
+	return len(os.Args) > 1 && os.Args[1] == "self"
 }
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// envProvider overrides the configured default provider.
+	envProvider = "XCLI_AI_PROVIDER"
+
+	defaultOpenAIBaseURL   = "https://api.openai.com/v1"
+	defaultOpenAIModel     = "gpt-4o-mini"
+	defaultOpenAIAPIKeyEnv = "OPENAI_API_KEY"
+
+	// defaultLocalBaseURL targets Ollama's OpenAI-compatible endpoint. llama.cpp
+	// server and vLLM expose the same API and only need a different base URL.
+	defaultLocalBaseURL = "http://localhost:11434/v1"
+	defaultLocalModel   = "llama3.1"
+)
+
+// Config holds provider settings, typically loaded from the global xcli config.
+type Config struct {
+	DefaultProvider ProviderID
+	OpenAI          HTTPProviderConfig
+	Local           HTTPProviderConfig
+}
+
+// HTTPProviderConfig configures an OpenAI-compatible chat completions endpoint.
+type HTTPProviderConfig struct {
+	BaseURL   string
+	Model     string
+	APIKey    string
+	APIKeyEnv string
+	Timeout   time.Duration
+}
+
+var (
+	configMu     sync.RWMutex
+	activeConfig Config
+)
+
+// SetConfig replaces the provider configuration used by NewEngine.
+func SetConfig(cfg Config) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	activeConfig = cfg
+}
+
+// CurrentConfig returns the provider configuration used by NewEngine.
+func CurrentConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	return activeConfig
+}
+
+// ConfiguredDefaultProvider returns the provider selected by the environment or
+// global config, falling back to DefaultProvider.
+func ConfiguredDefaultProvider() ProviderID {
+	if env := strings.TrimSpace(os.Getenv(envProvider)); env != "" {
+		return ProviderID(strings.ToLower(env))
+	}
+
+	if provider := CurrentConfig().DefaultProvider; provider != "" {
+		return provider
+	}
+
+	return DefaultProvider
+}
+
+// resolve fills unset fields with the given defaults and resolves the API key.
+func (c HTTPProviderConfig) resolve(baseURL, model, apiKeyEnv string) HTTPProviderConfig {
+	if c.BaseURL == "" {
+		c.BaseURL = baseURL
+	}
+
+	if c.Model == "" {
+		c.Model = model
+	}
+
+	if c.APIKeyEnv == "" {
+		c.APIKeyEnv = apiKeyEnv
+	}
+
+	if c.APIKey == "" && c.APIKeyEnv != "" {
+		c.APIKey = os.Getenv(c.APIKeyEnv)
+	}
+
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+
+	return c
+}
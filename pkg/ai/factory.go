@@ -12,14 +12,27 @@ const DefaultProvider = ProviderClaude
 
 // SupportedProviders lists selectable provider IDs.
 func SupportedProviders() []ProviderID {
-	return []ProviderID{ProviderClaude}
+	return []ProviderID{ProviderClaude, ProviderOpenAI, ProviderLocal}
 }
 
-// NewEngine creates an Engine for the given provider.
+// NewEngine creates an Engine for the given provider. An empty provider
+// selects ConfiguredDefaultProvider.
 func NewEngine(provider ProviderID, log logrus.FieldLogger) (Engine, error) {
+	if provider == "" {
+		provider = ConfiguredDefaultProvider()
+	}
+
+	cfg := CurrentConfig()
+
 	switch provider {
-	case "", ProviderClaude:
+	case ProviderClaude:
 		return newClaudeEngine(log), nil
+	case ProviderOpenAI:
+		return newOpenAIEngine(log, ProviderOpenAI,
+			cfg.OpenAI.resolve(defaultOpenAIBaseURL, defaultOpenAIModel, defaultOpenAIAPIKeyEnv), true), nil
+	case ProviderLocal:
+		return newOpenAIEngine(log, ProviderLocal,
+			cfg.Local.resolve(defaultLocalBaseURL, defaultLocalModel, ""), false), nil
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
@@ -60,6 +73,10 @@ func providerLabel(provider ProviderID) string {
 	switch provider {
 	case ProviderClaude:
 		return "Claude"
+	case ProviderOpenAI:
+		return "OpenAI-compatible"
+	case ProviderLocal:
+		return "Local model"
 	default:
 		return string(provider)
 	}
@@ -67,7 +84,7 @@ func providerLabel(provider ProviderID) string {
 
 func providerCapabilities(provider ProviderID) Capabilities {
 	switch provider {
-	case ProviderClaude:
+	case ProviderClaude, ProviderOpenAI, ProviderLocal:
 		return Capabilities{Streaming: true, Interrupt: true, Sessions: true}
 	default:
 		return Capabilities{}
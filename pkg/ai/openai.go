@@ -0,0 +1,351 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sseDataPrefix prefixes payload lines in a streamed chat completions response.
+const sseDataPrefix = "data:"
+
+// chatMessage is a single OpenAI chat completions message.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// chatCompletionChunk is one streamed delta. Reasoning models served by vLLM
+// report thinking as reasoning_content, Ollama uses reasoning.
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+			Reasoning        string `json:"reasoning"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+type chatCompletionError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// openAIEngine talks to any server implementing the OpenAI chat completions API.
+type openAIEngine struct {
+	log        logrus.FieldLogger
+	provider   ProviderID
+	cfg        HTTPProviderConfig
+	requireKey bool
+	client     *http.Client
+}
+
+func newOpenAIEngine(
+	log logrus.FieldLogger,
+	provider ProviderID,
+	cfg HTTPProviderConfig,
+	requireKey bool,
+) *openAIEngine {
+	return &openAIEngine{
+		log:        log.WithField("component", "ai-provider-"+string(provider)),
+		provider:   provider,
+		cfg:        cfg,
+		requireKey: requireKey,
+		// Streams are bounded by the per-turn context rather than a client timeout.
+		client: &http.Client{},
+	}
+}
+
+func (e *openAIEngine) Provider() ProviderID {
+	return e.provider
+}
+
+func (e *openAIEngine) Capabilities() Capabilities {
+	return Capabilities{Streaming: true, Interrupt: true, Sessions: true}
+}
+
+func (e *openAIEngine) IsAvailable() bool {
+	if e.cfg.BaseURL == "" || e.cfg.Model == "" {
+		return false
+	}
+
+	return !e.requireKey || e.cfg.APIKey != ""
+}
+
+// Ask runs a single non-streaming completion and returns the answer text.
+func (e *openAIEngine) Ask(ctx context.Context, prompt string) (string, error) {
+	if !e.IsAvailable() {
+		return "", fmt.Errorf("provider %s is not available", e.provider)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+
+	resp, err := e.post(ctx, chatCompletionRequest{
+		Model:    e.cfg.Model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var decoded chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return "", fmt.Errorf("decode provider response: %w", err)
+	}
+
+	if len(decoded.Choices) == 0 || strings.TrimSpace(decoded.Choices[0].Message.Content) == "" {
+		return "", fmt.Errorf("empty provider response")
+	}
+
+	return decoded.Choices[0].Message.Content, nil
+}
+
+// StartSession returns a session that replays the conversation history on every turn.
+func (e *openAIEngine) StartSession(_ context.Context) (Session, error) {
+	if !e.IsAvailable() {
+		return nil, fmt.Errorf("provider %s is not available", e.provider)
+	}
+
+	return &openAISession{
+		id:     newSessionID(),
+		engine: e,
+	}, nil
+}
+
+// post sends a chat completions request and returns the response on HTTP 200.
+func (e *openAIEngine) post(ctx context.Context, body chatCompletionRequest) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encode provider request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("create provider request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if body.Stream {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	if e.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.cfg.APIKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("provider request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+		message := tailString(string(raw))
+
+		var apiErr chatCompletionError
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Error.Message != "" {
+			message = apiErr.Error.Message
+		}
+
+		return nil, &ProviderDebugError{
+			Cause: fmt.Errorf("provider returned status %d: %s", resp.StatusCode, message),
+			Info: map[string]any{
+				"provider": string(e.provider),
+				"base_url": e.cfg.BaseURL,
+				"model":    e.cfg.Model,
+				"status":   resp.StatusCode,
+			},
+		}
+	}
+
+	return resp, nil
+}
+
+// stream runs a streaming completion, forwarding deltas to onChunk, and returns
+// the concatenated answer text.
+func (e *openAIEngine) stream(
+	ctx context.Context,
+	messages []chatMessage,
+	onChunk func(StreamChunk),
+) (string, error) {
+	resp, err := e.post(ctx, chatCompletionRequest{
+		Model:    e.cfg.Model,
+		Messages: messages,
+		Stream:   true,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var (
+		answer strings.Builder
+		seq    int
+	)
+
+	emit := func(kind StreamChunkKind, text string) {
+		if text == "" {
+			return
+		}
+
+		seq++
+
+		if onChunk != nil {
+			onChunk(StreamChunk{Kind: kind, Text: text, EventType: "delta", Seq: seq})
+		}
+	}
+
+	scanner := newLineScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, sseDataPrefix) {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, sseDataPrefix))
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			e.log.WithError(err).Debug("skipping undecodable provider chunk")
+
+			continue
+		}
+
+		for _, choice := range chunk.Choices {
+			emit(StreamChunkThinking, choice.Delta.ReasoningContent+choice.Delta.Reasoning)
+			emit(StreamChunkAnswer, choice.Delta.Content)
+			answer.WriteString(choice.Delta.Content)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+
+		return "", fmt.Errorf("read provider stream: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	out := strings.TrimSpace(answer.String())
+	if out == "" {
+		return "", fmt.Errorf("empty provider response")
+	}
+
+	return out, nil
+}
+
+// openAISession keeps conversation history client-side, since the chat
+// completions API is stateless.
+type openAISession struct {
+	id     string
+	engine *openAIEngine
+
+	mu      sync.Mutex
+	history []chatMessage
+	cancel  context.CancelFunc
+	closed  bool
+}
+
+func (s *openAISession) ID() string {
+	return s.id
+}
+
+func (s *openAISession) AskStream(ctx context.Context, prompt string, onChunk func(StreamChunk)) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.engine.cfg.Timeout)
+	defer cancel()
+
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+
+		return "", fmt.Errorf("session is closed")
+	}
+
+	messages := make([]chatMessage, 0, len(s.history)+1)
+	messages = append(messages, s.history...)
+	messages = append(messages, chatMessage{Role: "user", Content: prompt})
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	out, err := s.engine.stream(ctx, messages, onChunk)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cancel = nil
+
+	if err != nil {
+		return "", err
+	}
+
+	// Only completed turns join the history so an interrupted or failed turn
+	// can simply be retried.
+	s.history = append(messages, chatMessage{Role: "assistant", Content: out})
+
+	return out, nil
+}
+
+// Interrupt cancels the in-flight request, if any.
+func (s *openAISession) Interrupt(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	return nil
+}
+
+func (s *openAISession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	s.history = nil
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// stubChatServer records requests and answers chat completions with canned text.
+type stubChatServer struct {
+	mu       sync.Mutex
+	requests []chatCompletionRequest
+	answer   string
+	status   int
+}
+
+func (s *stubChatServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req chatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte(`{"error":{"message":"model not found"}}`))
+
+		return
+	}
+
+	if !req.Stream {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": s.answer}}},
+		})
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+
+	fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"reasoning_content\":\"checking logs\"}}]}\n\n")
+
+	for _, word := range strings.SplitAfter(s.answer, " ") {
+		payload, _ := json.Marshal(map[string]any{
+			"choices": []map[string]any{{"delta": map[string]string{"content": word}}},
+		})
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+	}
+
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func newStubEngine(t *testing.T, stub *stubChatServer) *openAIEngine {
+	t.Helper()
+
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	cfg := HTTPProviderConfig{BaseURL: server.URL + "/v1/", Model: "test-model"}.resolve("", "", "")
+
+	return newOpenAIEngine(logrus.New(), ProviderLocal, cfg, false)
+}
+
+func TestOpenAIEngineAsk(t *testing.T) {
+	stub := &stubChatServer{answer: "## Root Cause\nport in use"}
+	engine := newStubEngine(t, stub)
+
+	require.True(t, engine.IsAvailable())
+
+	out, err := engine.Ask(context.Background(), "why did it fail?")
+	require.NoError(t, err)
+	require.Equal(t, stub.answer, out)
+
+	require.Len(t, stub.requests, 1)
+	require.Equal(t, "test-model", stub.requests[0].Model)
+	require.False(t, stub.requests[0].Stream)
+}
+
+func TestOpenAISessionStreamsAndKeepsHistory(t *testing.T) {
+	stub := &stubChatServer{answer: "restart the cbt process"}
+	engine := newStubEngine(t, stub)
+
+	session, err := engine.StartSession(context.Background())
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = session.Close() })
+
+	var chunks []StreamChunk
+
+	out, err := session.AskStream(context.Background(), "first", func(chunk StreamChunk) {
+		chunks = append(chunks, chunk)
+	})
+	require.NoError(t, err)
+	require.Equal(t, stub.answer, out)
+
+	require.Equal(t, StreamChunkThinking, chunks[0].Kind)
+	require.Equal(t, "checking logs", chunks[0].Text)
+
+	var answer strings.Builder
+
+	for i, chunk := range chunks {
+		require.Equal(t, i+1, chunk.Seq)
+
+		if chunk.Kind == StreamChunkAnswer {
+			answer.WriteString(chunk.Text)
+		}
+	}
+
+	require.Equal(t, stub.answer, answer.String())
+
+	_, err = session.AskStream(context.Background(), "second", nil)
+	require.NoError(t, err)
+
+	require.Len(t, stub.requests, 2)
+	require.Equal(t, []chatMessage{
+		{Role: "user", Content: "first"},
+		{Role: "assistant", Content: stub.answer},
+		{Role: "user", Content: "second"},
+	}, stub.requests[1].Messages)
+}
+
+func TestOpenAIEngineSurfacesAPIErrors(t *testing.T) {
+	stub := &stubChatServer{status: http.StatusNotFound}
+	engine := newStubEngine(t, stub)
+
+	_, err := engine.Ask(context.Background(), "hello")
+	require.ErrorContains(t, err, "status 404: model not found")
+
+	var debugErr *ProviderDebugError
+	require.ErrorAs(t, err, &debugErr)
+	require.Equal(t, http.StatusNotFound, debugErr.DebugInfo()["status"])
+}
+
+func TestOpenAIEngineRequiresAPIKey(t *testing.T) {
+	t.Setenv("XCLI_TEST_MISSING_KEY", "")
+
+	cfg := HTTPProviderConfig{}.resolve(defaultOpenAIBaseURL, defaultOpenAIModel, "XCLI_TEST_MISSING_KEY")
+	engine := newOpenAIEngine(logrus.New(), ProviderOpenAI, cfg, true)
+
+	require.False(t, engine.IsAvailable())
+
+	t.Setenv("XCLI_TEST_MISSING_KEY", "sk-test")
+
+	cfg = HTTPProviderConfig{}.resolve(defaultOpenAIBaseURL, defaultOpenAIModel, "XCLI_TEST_MISSING_KEY")
+	engine = newOpenAIEngine(logrus.New(), ProviderOpenAI, cfg, true)
+
+	require.True(t, engine.IsAvailable())
+}
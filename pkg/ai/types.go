@@ -8,6 +8,10 @@ type ProviderID string
 const (
 	// ProviderClaude uses the Claude Code SDK-backed provider.
 	ProviderClaude ProviderID = "claude"
+	// ProviderOpenAI uses a hosted OpenAI-compatible chat completions API.
+	ProviderOpenAI ProviderID = "openai"
+	// ProviderLocal uses a local OpenAI-compatible server (Ollama, llama.cpp, vLLM).
+	ProviderLocal ProviderID = "local"
 )
 
 // Capabilities describe what features a provider supports.
@@ -1,7 +1,11 @@
+// Package autoupgrade keeps xcli up to date from its git checkout. It follows
+// a release channel or a pin, builds the new version, smoke-tests it before
+// swapping it in, and keeps the replaced binary for rollback.
 package autoupgrade
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,14 +14,54 @@ import (
 	"time"
 
 	"github.com/ethpandaops/xcli/pkg/config"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
+	"github.com/ethpandaops/xcli/pkg/release"
 	"github.com/ethpandaops/xcli/pkg/ui"
 	"github.com/sirupsen/logrus"
 )
 
+// ErrDirtyCheckout is returned when the xcli checkout has uncommitted changes.
+var ErrDirtyCheckout = errors.New("xcli checkout has uncommitted changes")
+
 // Service manages automatic upgrades of xcli.
 type Service interface {
-	// CheckAndUpgrade checks for updates and upgrades if needed.
+	// CheckAndUpgrade checks for updates and upgrades if needed. It is rate
+	// limited, honours the pin, disable and skip settings, and never fails
+	// the CLI.
 	CheckAndUpgrade(ctx context.Context) error
+
+	// Check fetches the checkout and resolves the version the configured
+	// channel or pin would upgrade to, with the incoming changelog.
+	Check(ctx context.Context) (*Plan, error)
+
+	// Upgrade builds the plan's target, smoke-tests it and installs it in
+	// place of the running binary, keeping that one for rollback.
+	Upgrade(ctx context.Context, plan *Plan) error
+}
+
+// Plan describes the upgrade from the running build to a target.
+type Plan struct {
+	Channel       string
+	Pinned        bool
+	CurrentCommit string             // Commit the running binary was built from
+	Target        string             // Tag, pin or upstream branch upgraded to
+	TargetCommit  string             // Full commit hash of the target
+	Changelog     *release.Changelog // Commits between current and target
+}
+
+// UpToDate reports whether the running build is the target.
+func (p *Plan) UpToDate() bool {
+	return sameCommit(p.CurrentCommit, p.TargetCommit)
+}
+
+// Version returns the version string the target is built with: the tag for
+// tag targets, "dev" otherwise.
+func (p *Plan) Version() string {
+	if _, err := release.ParseVersion(p.Target); err == nil {
+		return p.Target
+	}
+
+	return "dev"
 }
 
 type service struct {
@@ -37,6 +81,21 @@ func NewService(log logrus.FieldLogger, repoPath, currentCommit string) Service
 	}
 }
 
+// FindRepoPath returns the xcli checkout registered in the global config, or
+// "" if there is none.
+func FindRepoPath() string {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil || globalCfg.XCLIPath == "" {
+		return ""
+	}
+
+	if info, err := os.Stat(filepath.Join(globalCfg.XCLIPath, ".git")); err == nil && info.IsDir() {
+		return globalCfg.XCLIPath
+	}
+
+	return ""
+}
+
 // CheckAndUpgrade performs the upgrade check and execution.
 func (s *service) CheckAndUpgrade(ctx context.Context) error {
 	// Load global config
@@ -47,6 +106,19 @@ func (s *service) CheckAndUpgrade(ctx context.Context) error {
 		return nil // Don't fail the CLI if config load fails
 	}
 
+	settings, err := ResolveSettings(globalCfg.Upgrade)
+	if err != nil {
+		s.log.WithError(err).Warn("Invalid upgrade settings, skipping upgrade check")
+
+		return nil
+	}
+
+	if settings.Disabled {
+		s.log.Debug("Automatic upgrades disabled")
+
+		return nil
+	}
+
 	// Check if we should perform upgrade check based on last check time
 	if !globalCfg.LastUpgradeCheck.IsZero() && time.Since(globalCfg.LastUpgradeCheck) < s.checkInterval {
 		s.log.Debug("Skipping upgrade check (too soon)")
@@ -62,126 +134,320 @@ func (s *service) CheckAndUpgrade(ctx context.Context) error {
 		s.log.WithError(saveErr).Debug("Failed to save global config")
 	}
 
-	// Check if we're in a git repository
-	if !s.isGitRepo() {
-		s.log.Debug("Not a git repository, skipping upgrade check")
-
-		return nil
-	}
-
-	// Fetch latest changes
-	if fetchErr := s.gitFetch(ctx); fetchErr != nil {
-		s.log.WithError(fetchErr).Debug("Failed to fetch updates")
+	plan, err := s.check(ctx, settings)
+	if err != nil {
+		s.log.WithError(err).Debug("Failed to check for updates")
 
 		return nil // Don't fail the CLI if upgrade check fails
 	}
 
-	// Check if we're behind upstream
-	behind, err := s.isBehindUpstream(ctx)
-	if err != nil {
-		s.log.WithError(err).Debug("Failed to check upstream status")
+	if plan.UpToDate() {
+		s.log.Debug("Already up to date")
 
 		return nil
 	}
 
-	if !behind {
-		s.log.Debug("Already up to date")
+	if sameCommit(settings.SkipCommit, plan.TargetCommit) {
+		s.log.WithField("commit", settings.SkipCommit).Debug("Skipping upgrade to a failed or rolled back commit")
 
 		return nil
 	}
 
+	ui.Info(fmt.Sprintf("Upgrading xcli to %s (%s)...", plan.Target, xcligit.ShortCommit(plan.TargetCommit)))
+
 	// Perform upgrade
-	if upgradeErr := s.performUpgrade(ctx); upgradeErr != nil {
+	if upgradeErr := s.Upgrade(ctx, plan); upgradeErr != nil {
 		s.log.WithError(upgradeErr).Warn("Auto-upgrade failed")
+		ui.Warning(fmt.Sprintf("xcli upgrade to %s failed, keeping the current version: %v", plan.Target, upgradeErr))
 
 		return nil // Don't fail the CLI if upgrade fails
 	}
 
-	fmt.Printf("%s %s\n", ui.SuccessSymbol, ui.SuccessStyle.Sprint("xcli upgraded to latest version (will take effect on next run)"))
+	fmt.Printf("%s %s\n", ui.SuccessSymbol, ui.SuccessStyle.Sprintf(
+		"xcli upgraded to %s (takes effect on next run; 'xcli self rollback' restores the previous version)",
+		plan.Target))
 
 	return nil
 }
 
-// isGitRepo checks if the current directory is a git repository.
-func (s *service) isGitRepo() bool {
-	gitDir := filepath.Join(s.repoPath, ".git")
-	info, err := os.Stat(gitDir)
+// Check resolves the upgrade target with the configured settings.
+func (s *service) Check(ctx context.Context) (*Plan, error) {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	return err == nil && info.IsDir()
+	settings, err := ResolveSettings(globalCfg.Upgrade)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.check(ctx, settings)
 }
 
-// gitFetch fetches the latest changes from origin.
-func (s *service) gitFetch(ctx context.Context) error {
-	cmd := exec.CommandContext(ctx, "git", "fetch", "origin", "--quiet")
-	cmd.Dir = s.repoPath
+func (s *service) check(ctx context.Context, settings Settings) (*Plan, error) {
+	if !s.isGitRepo() {
+		return nil, fmt.Errorf("xcli checkout not found at %s", s.repoPath)
+	}
 
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git fetch failed: %w: %s", err, string(output))
+	// Fetch latest changes and tags
+	if _, err := s.git(ctx, "fetch", "origin", "--tags", "--quiet"); err != nil {
+		return nil, err
 	}
 
-	return nil
-}
+	plan := &Plan{
+		Channel:       settings.Channel,
+		Pinned:        settings.Pin != "",
+		CurrentCommit: s.currentCommit,
+	}
 
-// isBehindUpstream checks if the current branch is behind origin.
-func (s *service) isBehindUpstream(ctx context.Context) (bool, error) {
-	// Get current branch
-	branchCmd := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
-	branchCmd.Dir = s.repoPath
+	// Builds without a commit (go build, go run) are compared by checkout.
+	if !isCommitHash(plan.CurrentCommit) {
+		head, err := s.git(ctx, "rev-parse", "HEAD")
+		if err != nil {
+			return nil, err
+		}
 
-	branchOutput, err := branchCmd.Output()
+		plan.CurrentCommit = head
+	}
+
+	target, err := s.resolveTarget(ctx, settings)
 	if err != nil {
-		return false, fmt.Errorf("failed to get current branch: %w", err)
+		return nil, err
 	}
 
-	branch := strings.TrimSpace(string(branchOutput))
+	targetCommit, err := s.git(ctx, "rev-parse", target+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", target, err)
+	}
 
-	// Get local commit
-	localCmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
-	localCmd.Dir = s.repoPath
+	plan.Target = target
+	plan.TargetCommit = targetCommit
+
+	if !plan.UpToDate() {
+		changelog, changelogErr := release.GenerateChangelogBetween(ctx, "xcli", s.repoPath,
+			plan.CurrentCommit, plan.TargetCommit)
+		if changelogErr != nil {
+			s.log.WithError(changelogErr).Debug("Failed to generate changelog")
+		} else {
+			changelog.Since = xcligit.ShortCommit(plan.CurrentCommit)
+			plan.Changelog = changelog
+		}
+	}
+
+	return plan, nil
+}
+
+// resolveTarget returns the ref the settings upgrade to.
+func (s *service) resolveTarget(ctx context.Context, settings Settings) (string, error) {
+	if settings.Pin != "" {
+		return settings.Pin, nil
+	}
 
-	localOutput, err := localCmd.Output()
+	if settings.Channel == ChannelStable {
+		return s.latestStableTag(ctx)
+	}
+
+	branch, err := s.git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
-		return false, fmt.Errorf("failed to get local commit: %w", err)
+		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	localCommit := strings.TrimSpace(string(localOutput))
+	// A detached checkout (left by the stable channel or a pin) follows
+	// origin's default branch.
+	if branch == "HEAD" {
+		return s.git(ctx, "rev-parse", "--abbrev-ref", "origin/HEAD")
+	}
 
-	// Get remote commit
 	// The branch name comes from git rev-parse output, which is safe
-	remoteRef := "origin/" + branch
-	remoteCmd := exec.CommandContext(ctx, "git", "rev-parse", remoteRef)
-	remoteCmd.Dir = s.repoPath
+	return "origin/" + branch, nil
+}
 
-	remoteOutput, err := remoteCmd.Output()
+// latestStableTag returns the highest vX.Y.Z tag, ignoring pre-releases.
+func (s *service) latestStableTag(ctx context.Context) (string, error) {
+	tags, err := s.git(ctx, "tag", "--list", "v*", "--sort=-v:refname")
 	if err != nil {
-		return false, fmt.Errorf("failed to get remote commit: %w", err)
+		return "", fmt.Errorf("failed to list tags: %w", err)
 	}
 
-	remoteCommit := strings.TrimSpace(string(remoteOutput))
+	for tag := range strings.SplitSeq(tags, "\n") {
+		if _, parseErr := release.ParseVersion(tag); parseErr == nil {
+			return tag, nil
+		}
+	}
 
-	return localCommit != remoteCommit, nil
+	return "", fmt.Errorf("no release tags found for the %s channel", ChannelStable)
 }
 
-// performUpgrade executes git pull and make install.
-func (s *service) performUpgrade(ctx context.Context) error {
-	s.log.Info("Upgrading xcli to latest version...")
+// Upgrade moves the checkout to the target, builds it and smoke-tests the
+// build before installing it. On failure the checkout is moved back and the
+// target is skipped by automatic upgrades.
+func (s *service) Upgrade(ctx context.Context, plan *Plan) error {
+	s.log.WithFields(logrus.Fields{
+		"target": plan.Target,
+		"commit": plan.TargetCommit,
+	}).Info("Upgrading xcli")
+
+	status, err := s.git(ctx, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return err
+	}
+
+	if status != "" {
+		return ErrDirtyCheckout
+	}
+
+	branch, err := s.git(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	head, err := s.git(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	if err := s.moveCheckout(ctx, plan, branch); err != nil {
+		return err
+	}
 
-	// Git pull
-	pullCmd := exec.CommandContext(ctx, "git", "pull", "--quiet")
-	pullCmd.Dir = s.repoPath
+	binary, err := s.buildAndVerify(ctx, plan)
+	if err != nil {
+		s.restoreCheckout(ctx, branch, head)
+		s.skipCommit(plan.TargetCommit)
 
-	if output, err := pullCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git pull failed: %w: %s", err, string(output))
+		return err
 	}
 
-	// Make install
-	installCmd := exec.CommandContext(ctx, "make", "install")
-	installCmd.Dir = s.repoPath
-	installCmd.Env = os.Environ()
+	previous, err := installBinary(binary)
+	if err != nil {
+		s.restoreCheckout(ctx, branch, head)
+		s.skipCommit(plan.TargetCommit)
 
-	if output, err := installCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("make install failed: %w: %s", err, string(output))
+		return err
 	}
 
+	s.log.WithField("previous", previous).Debug("Kept previous binary for rollback")
+
+	s.skipCommit("")
+
 	return nil
 }
+
+// moveCheckout fast-forwards a branch checkout following its upstream, and
+// detaches at the target otherwise.
+func (s *service) moveCheckout(ctx context.Context, plan *Plan, branch string) error {
+	if !plan.Pinned && plan.Channel == ChannelMain && branch != "HEAD" {
+		_, err := s.git(ctx, "merge", "--ff-only", "--quiet", plan.TargetCommit)
+
+		return err
+	}
+
+	_, err := s.git(ctx, "checkout", "--quiet", "--detach", plan.TargetCommit)
+
+	return err
+}
+
+// restoreCheckout puts the checkout back on its original branch and commit.
+func (s *service) restoreCheckout(ctx context.Context, branch, commit string) {
+	args := []string{"checkout", "--quiet", "--detach", commit}
+	if branch != "HEAD" {
+		args = []string{"checkout", "--quiet", "-B", branch, commit}
+	}
+
+	if _, err := s.git(ctx, args...); err != nil {
+		s.log.WithError(err).Warn("Failed to restore xcli checkout")
+	}
+}
+
+// buildAndVerify builds the checkout with make and smoke-tests the binary.
+func (s *service) buildAndVerify(ctx context.Context, plan *Plan) (string, error) {
+	buildCmd := exec.CommandContext(ctx, "make", "build", "VERSION="+plan.Version())
+	buildCmd.Dir = s.repoPath
+	buildCmd.Env = os.Environ()
+
+	if output, err := buildCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("make build failed: %w: %s", err, tail(string(output)))
+	}
+
+	binary := filepath.Join(s.repoPath, "bin", "xcli")
+
+	if err := SmokeTest(ctx, binary, plan.TargetCommit); err != nil {
+		return "", fmt.Errorf("new build failed its smoke test: %w", err)
+	}
+
+	return binary, nil
+}
+
+// skipCommit records the commit automatic upgrades must not install.
+func (s *service) skipCommit(commit string) {
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil || globalCfg.Upgrade.SkipCommit == commit {
+		return
+	}
+
+	globalCfg.Upgrade.SkipCommit = commit
+
+	if err := config.SaveGlobalConfig(globalCfg); err != nil {
+		s.log.WithError(err).Debug("Failed to save global config")
+	}
+}
+
+// isGitRepo checks if the current directory is a git repository.
+func (s *service) isGitRepo() bool {
+	gitDir := filepath.Join(s.repoPath, ".git")
+	info, err := os.Stat(gitDir)
+
+	return err == nil && info.IsDir()
+}
+
+// git runs a git command in the checkout and returns its trimmed output.
+func (s *service) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = s.repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// sameCommit compares a possibly abbreviated commit hash with another.
+func sameCommit(a, b string) bool {
+	if !isCommitHash(a) || !isCommitHash(b) {
+		return false
+	}
+
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	return strings.HasPrefix(b, a)
+}
+
+// isCommitHash reports whether s looks like an (abbreviated) commit hash.
+func isCommitHash(s string) bool {
+	if len(s) < 7 || len(s) > 40 {
+		return false
+	}
+
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tail returns the last lines of command output for error messages.
+func tail(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > 10 {
+		lines = lines[len(lines)-10:]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,33 @@
+package autoupgrade
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSameCommit(t *testing.T) {
+	full := "0123456789abcdef0123456789abcdef01234567"
+
+	require.True(t, sameCommit(full, full))
+	require.True(t, sameCommit("0123456", full))
+	require.True(t, sameCommit(full, "0123456789ab"))
+	require.False(t, sameCommit("0123457", full))
+	require.False(t, sameCommit("none", full))
+	require.False(t, sameCommit("", ""))
+}
+
+func TestPlan(t *testing.T) {
+	plan := &Plan{CurrentCommit: "0123456", TargetCommit: "0123456789abcdef", Target: "v1.2.3"}
+	require.True(t, plan.UpToDate())
+	require.Equal(t, "v1.2.3", plan.Version())
+
+	plan = &Plan{CurrentCommit: "none", TargetCommit: "0123456789abcdef", Target: "origin/master"}
+	require.False(t, plan.UpToDate())
+	require.Equal(t, "dev", plan.Version())
+}
+
+func TestVersionCommit(t *testing.T) {
+	require.Equal(t, "0123456", versionCommit("v1.2.3 (commit: 0123456, built: 2026-01-01)"))
+	require.Empty(t, versionCommit("unknown"))
+}
@@ -0,0 +1,222 @@
+package autoupgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
+)
+
+// smokeTestTimeout bounds each command of the smoke test.
+const smokeTestTimeout = 30 * time.Second
+
+// ErrNoPreviousBinary is returned by Rollback when no upgrade has been made.
+var ErrNoPreviousBinary = errors.New("no previous xcli binary to roll back to")
+
+// versionCommitRe extracts the commit from 'xcli --version' output.
+var versionCommitRe = regexp.MustCompile(`commit: ([^,)]+)`)
+
+// RollbackResult describes a rollback.
+type RollbackResult struct {
+	Binary string // Installed binary that was replaced
+	From   string // Version rolled back from
+	To     string // Version now installed
+}
+
+// SmokeTest runs a freshly built binary's --version and 'self smoke'
+// commands, and checks it was built from commit.
+func SmokeTest(ctx context.Context, binary, commit string) error {
+	version, err := runBinary(ctx, binary, "--version")
+	if err != nil {
+		return err
+	}
+
+	if built := versionCommit(version); !sameCommit(built, commit) {
+		return fmt.Errorf("binary reports commit %q, expected %s", built, xcligit.ShortCommit(commit))
+	}
+
+	if _, err := runBinary(ctx, binary, "self", "smoke"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BinaryVersion returns the version line a binary reports.
+func BinaryVersion(ctx context.Context, binary string) string {
+	version, err := runBinary(ctx, binary, "--version")
+	if err != nil {
+		return "unknown"
+	}
+
+	return strings.TrimPrefix(version, "xcli version ")
+}
+
+// PreviousBinaryPath returns where the binary replaced by the last upgrade is
+// kept.
+func PreviousBinaryPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".xcli", "bin", "xcli.previous"), nil
+}
+
+// Rollback swaps the installed binary with the one the last upgrade
+// replaced, so a second rollback undoes the first. The rolled back commit is
+// skipped by automatic upgrades.
+func Rollback(ctx context.Context) (*RollbackResult, error) {
+	exe, err := executablePath()
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := PreviousBinaryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, statErr := os.Stat(previous); os.IsNotExist(statErr) {
+		return nil, ErrNoPreviousBinary
+	}
+
+	result := &RollbackResult{
+		Binary: exe,
+		From:   BinaryVersion(ctx, exe),
+		To:     BinaryVersion(ctx, previous),
+	}
+
+	staged := exe + ".rollback"
+	if err := copyFile(previous, staged); err != nil {
+		return nil, err
+	}
+
+	if err := copyFile(exe, previous); err != nil {
+		_ = os.Remove(staged)
+
+		return nil, err
+	}
+
+	if err := os.Rename(staged, exe); err != nil {
+		_ = os.Remove(staged)
+
+		return nil, fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+
+	if globalCfg, cfgErr := config.LoadGlobalConfig(); cfgErr == nil {
+		globalCfg.Upgrade.SkipCommit = versionCommit(result.From)
+
+		_ = config.SaveGlobalConfig(globalCfg)
+	}
+
+	return result, nil
+}
+
+// installBinary replaces the running executable with binary, keeping the
+// running one at PreviousBinaryPath. It returns the kept binary's path.
+func installBinary(binary string) (string, error) {
+	exe, err := executablePath()
+	if err != nil {
+		return "", err
+	}
+
+	previous, err := PreviousBinaryPath()
+	if err != nil {
+		return "", err
+	}
+
+	if err := copyFile(exe, previous); err != nil {
+		return "", err
+	}
+
+	// Stage next to the executable so the final rename is atomic.
+	staged := exe + ".new"
+	if err := copyFile(binary, staged); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(staged, exe); err != nil {
+		_ = os.Remove(staged)
+
+		return "", fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+
+	return previous, nil
+}
+
+func executablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate xcli binary: %w", err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", exe, err)
+	}
+
+	return resolved, nil
+}
+
+// runBinary runs an xcli binary with automatic upgrades disabled.
+func runBinary(ctx context.Context, binary string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, smokeTestTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Env = append(os.Environ(), EnvAutoUpgrade+"=false")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %w: %s",
+			filepath.Base(binary), strings.Join(args, " "), err, tail(string(output)))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func versionCommit(version string) string {
+	match := versionCommitRe.FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+
+	return match[1]
+}
+
+// copyFile copies src to an executable dst, creating dst's directory.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(dst), err)
+	}
+
+	//nolint:gosec // Binaries must be executable
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,76 @@
+package autoupgrade
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+)
+
+// Release channels.
+const (
+	// ChannelMain follows the checkout's upstream branch.
+	ChannelMain = "main"
+	// ChannelStable only upgrades to release tags (vX.Y.Z).
+	ChannelStable = "stable"
+)
+
+// Environment variables overriding the global upgrade config.
+const (
+	// EnvAutoUpgrade enables or disables automatic upgrades (true/false, 1/0).
+	EnvAutoUpgrade = "XCLI_AUTO_UPGRADE"
+	// EnvChannel selects the release channel.
+	EnvChannel = "XCLI_UPGRADE_CHANNEL"
+	// EnvPin pins xcli to a tag or commit.
+	EnvPin = "XCLI_UPGRADE_PIN"
+)
+
+// Settings is the effective upgrade configuration.
+type Settings struct {
+	Channel    string
+	Pin        string
+	Disabled   bool
+	SkipCommit string
+}
+
+// ResolveSettings applies environment overrides to the global upgrade
+// config and validates the channel.
+func ResolveSettings(cfg config.GlobalUpgradeConfig) (Settings, error) {
+	settings := Settings{
+		Channel:    cfg.Channel,
+		Pin:        cfg.Pin,
+		Disabled:   cfg.Disabled,
+		SkipCommit: cfg.SkipCommit,
+	}
+
+	if env := strings.TrimSpace(os.Getenv(EnvChannel)); env != "" {
+		settings.Channel = env
+	}
+
+	if env := strings.TrimSpace(os.Getenv(EnvPin)); env != "" {
+		settings.Pin = env
+	}
+
+	if env := strings.TrimSpace(os.Getenv(EnvAutoUpgrade)); env != "" {
+		enabled, err := strconv.ParseBool(env)
+		if err != nil {
+			return settings, fmt.Errorf("invalid %s=%q: use true or false", EnvAutoUpgrade, env)
+		}
+
+		settings.Disabled = !enabled
+	}
+
+	settings.Channel = strings.ToLower(strings.TrimSpace(settings.Channel))
+
+	switch settings.Channel {
+	case "":
+		settings.Channel = ChannelMain
+	case ChannelMain, ChannelStable:
+	default:
+		return settings, fmt.Errorf("unknown upgrade channel %q (valid: %s, %s)", settings.Channel, ChannelMain, ChannelStable)
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,51 @@
+package autoupgrade
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSettings(t *testing.T) {
+	t.Setenv(EnvChannel, "")
+	t.Setenv(EnvPin, "")
+	t.Setenv(EnvAutoUpgrade, "")
+
+	settings, err := ResolveSettings(config.GlobalUpgradeConfig{})
+	require.NoError(t, err)
+	require.Equal(t, ChannelMain, settings.Channel)
+	require.False(t, settings.Disabled)
+
+	settings, err = ResolveSettings(config.GlobalUpgradeConfig{Channel: "Stable", Pin: "v1.0.0", Disabled: true})
+	require.NoError(t, err)
+	require.Equal(t, ChannelStable, settings.Channel)
+	require.Equal(t, "v1.0.0", settings.Pin)
+	require.True(t, settings.Disabled)
+
+	_, err = ResolveSettings(config.GlobalUpgradeConfig{Channel: "nightly"})
+	require.ErrorContains(t, err, "unknown upgrade channel")
+}
+
+func TestResolveSettingsEnvOverrides(t *testing.T) {
+	t.Setenv(EnvChannel, "stable")
+	t.Setenv(EnvPin, "abc1234")
+	t.Setenv(EnvAutoUpgrade, "true")
+
+	settings, err := ResolveSettings(config.GlobalUpgradeConfig{Channel: ChannelMain, Pin: "v1.0.0", Disabled: true})
+	require.NoError(t, err)
+	require.Equal(t, ChannelStable, settings.Channel)
+	require.Equal(t, "abc1234", settings.Pin)
+	require.False(t, settings.Disabled)
+
+	t.Setenv(EnvAutoUpgrade, "0")
+
+	settings, err = ResolveSettings(config.GlobalUpgradeConfig{})
+	require.NoError(t, err)
+	require.True(t, settings.Disabled)
+
+	t.Setenv(EnvAutoUpgrade, "sometimes")
+
+	_, err = ResolveSettings(config.GlobalUpgradeConfig{})
+	require.ErrorContains(t, err, EnvAutoUpgrade)
+}
@@ -0,0 +1,277 @@
+// Package bootstrap sets up an ethpandaops workspace from scratch: the
+// side-by-side checkouts of the lab repositories and xcli, their
+// prerequisites, and the lab configuration. Every step skips work that is
+// already done, so bootstrapping again repairs a partial workspace.
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/discovery"
+	"github.com/ethpandaops/xcli/pkg/git"
+	"github.com/ethpandaops/xcli/pkg/prerequisites"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// Clone protocols.
+const (
+	ProtocolHTTPS = "https"
+	ProtocolSSH   = "ssh"
+)
+
+// ForkRemote is the remote added for the user's fork of each repository.
+const ForkRemote = "fork"
+
+// defaultJobs is how many repositories are cloned at once.
+const defaultJobs = 4
+
+// RepoState is what bootstrapping did to a repository.
+type RepoState string
+
+const (
+	// RepoCloned means the repository was cloned.
+	RepoCloned RepoState = "cloned"
+	// RepoPresent means an existing checkout was kept.
+	RepoPresent RepoState = "present"
+	// RepoFailed means the repository could not be set up.
+	RepoFailed RepoState = "failed"
+)
+
+// Options configures a bootstrap.
+type Options struct {
+	// Dir is the ethpandaops directory the repositories are checked out in.
+	Dir string
+	// Protocol is ProtocolHTTPS (default) or ProtocolSSH.
+	Protocol string
+	// Fork is the GitHub user whose forks are added as the "fork" remote.
+	Fork string
+	// Jobs is how many repositories are cloned at once.
+	Jobs int
+}
+
+// RepoResult is the outcome of setting up one repository.
+type RepoResult struct {
+	Name  string
+	Path  string
+	State RepoState
+	// Remote is the fork remote URL when it was added or changed.
+	Remote string
+	Err    error
+}
+
+// Bootstrapper sets up an ethpandaops workspace.
+type Bootstrapper struct {
+	log     logrus.FieldLogger
+	opts    Options
+	disc    *discovery.Discovery
+	prereqs prerequisites.Checker
+}
+
+// New validates the options and creates a Bootstrapper.
+func New(log logrus.FieldLogger, opts Options) (*Bootstrapper, error) {
+	switch opts.Protocol {
+	case "":
+		opts.Protocol = ProtocolHTTPS
+	case ProtocolHTTPS, ProtocolSSH:
+	default:
+		return nil, fmt.Errorf("unknown clone protocol %q (valid: %s, %s)", opts.Protocol, ProtocolHTTPS, ProtocolSSH)
+	}
+
+	dir, err := filepath.Abs(opts.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid directory %s: %w", opts.Dir, err)
+	}
+
+	opts.Dir = dir
+
+	if opts.Jobs <= 0 {
+		opts.Jobs = defaultJobs
+	}
+
+	log = log.WithField("component", "bootstrap")
+
+	return &Bootstrapper{
+		log:     log,
+		opts:    opts,
+		disc:    discovery.NewDiscovery(log, dir),
+		prereqs: prerequisites.NewChecker(log),
+	}, nil
+}
+
+// Dir returns the ethpandaops directory.
+func (b *Bootstrapper) Dir() string {
+	return b.opts.Dir
+}
+
+// Repos returns the repository names in the workspace: the lab repositories
+// followed by xcli.
+func Repos() []string {
+	repos := make([]string, 0, 6)
+	for _, repo := range discovery.Layout("").Ordered() {
+		repos = append(repos, repo.Name)
+	}
+
+	return append(repos, constants.RepoXCLI)
+}
+
+// LabRepos returns the lab repository paths in the workspace.
+func (b *Bootstrapper) LabRepos() config.LabReposConfig {
+	return *discovery.Layout(b.opts.Dir)
+}
+
+// CloneURL returns the clone URL for owner's copy of a repository.
+func (b *Bootstrapper) CloneURL(owner, repo string) string {
+	if b.opts.Protocol == ProtocolSSH {
+		return constants.GetGitHubSSHURL(owner, repo)
+	}
+
+	return fmt.Sprintf(constants.GitHubURLTemplate, owner, repo)
+}
+
+// CloneAll clones every missing repository in parallel and validates the
+// existing ones. Results are in Repos order.
+func (b *Bootstrapper) CloneAll(ctx context.Context) []RepoResult {
+	names := Repos()
+	results := make([]RepoResult, len(names))
+
+	if err := os.MkdirAll(b.opts.Dir, 0755); err != nil {
+		for i, name := range names {
+			results[i] = RepoResult{Name: name, State: RepoFailed, Err: err}
+		}
+
+		return results
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(b.opts.Jobs)
+
+	for i, name := range names {
+		group.Go(func() error {
+			results[i] = b.ensureRepo(groupCtx, name)
+
+			// One failed repository must not cancel the others.
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+
+	return results
+}
+
+// ensureRepo clones a repository unless a checkout already exists, then
+// sets up the fork remote.
+func (b *Bootstrapper) ensureRepo(ctx context.Context, name string) RepoResult {
+	result := RepoResult{
+		Name:  name,
+		Path:  filepath.Join(b.opts.Dir, name),
+		State: RepoPresent,
+	}
+
+	fail := func(err error) RepoResult {
+		result.State = RepoFailed
+		result.Err = err
+
+		return result
+	}
+
+	if _, err := os.Stat(result.Path); os.IsNotExist(err) {
+		b.log.WithField("repo", name).Info("cloning repository")
+
+		url := b.CloneURL(constants.GitHubOrg, name)
+		if err := git.Clone(ctx, url, result.Path, discovery.CloneBranch(name)); err != nil {
+			return fail(err)
+		}
+
+		result.State = RepoCloned
+	} else if err != nil {
+		return fail(err)
+	}
+
+	if info, err := os.Stat(filepath.Join(result.Path, ".git")); err != nil || !info.IsDir() {
+		return fail(fmt.Errorf("%s exists but is not a git checkout; move it aside and bootstrap again", result.Path))
+	}
+
+	if err := b.disc.ValidateRepo(name, result.Path); err != nil {
+		return fail(err)
+	}
+
+	if b.opts.Fork != "" {
+		url := b.CloneURL(b.opts.Fork, name)
+
+		changed, err := git.SetRemote(ctx, result.Path, ForkRemote, url)
+		if err != nil {
+			return fail(fmt.Errorf("failed to set %s remote: %w", ForkRemote, err))
+		}
+
+		if changed {
+			result.Remote = url
+		}
+	}
+
+	return result
+}
+
+// RunPrerequisites runs the setup steps of every lab repository that was
+// set up, skipping steps that are already done. It returns the error of each
+// repository that failed.
+func (b *Bootstrapper) RunPrerequisites(ctx context.Context, results []RepoResult) map[string]error {
+	failed := make(map[string]error)
+
+	for _, result := range results {
+		if result.State == RepoFailed || result.Name == constants.RepoXCLI {
+			continue
+		}
+
+		if err := b.prereqs.Run(ctx, result.Path, result.Name); err != nil {
+			failed[result.Name] = err
+		}
+	}
+
+	return failed
+}
+
+// WriteConfig points the lab configuration in the xcli checkout at the
+// workspace's repositories, creating .xcli.yaml with the default lab
+// configuration when it is missing. Other settings in an existing file are
+// kept. The xcli checkout is registered for auto-upgrades.
+func (b *Bootstrapper) WriteConfig() (string, error) {
+	xcliDir := filepath.Join(b.opts.Dir, constants.RepoXCLI)
+	configPath := filepath.Join(xcliDir, config.DefaultConfigFileName)
+
+	rootCfg := &config.Config{}
+
+	if _, err := os.Stat(configPath); err == nil {
+		result, loadErr := config.Load(configPath)
+		if loadErr != nil {
+			return configPath, fmt.Errorf("failed to load existing config: %w", loadErr)
+		}
+
+		rootCfg = result.Config
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return configPath, err
+	}
+
+	if rootCfg.Lab == nil {
+		rootCfg.Lab = config.DefaultLab()
+	}
+
+	rootCfg.Lab.Repos = b.LabRepos()
+
+	if err := rootCfg.Save(configPath); err != nil {
+		return configPath, fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	if err := config.SetXCLIPath(xcliDir); err != nil {
+		b.log.WithError(err).Warn("failed to register xcli path globally (non-fatal)")
+	}
+
+	return configPath, nil
+}
@@ -0,0 +1,95 @@
+package bootstrap
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootstrapRepairsExistingWorkspace(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	for _, name := range Repos() {
+		newCheckout(t, filepath.Join(dir, name), name)
+	}
+
+	b, err := New(logrus.New(), Options{Dir: dir, Protocol: ProtocolSSH, Fork: "alice"})
+	require.NoError(t, err)
+
+	results := b.CloneAll(ctx)
+	require.Len(t, results, len(Repos()))
+
+	for _, result := range results {
+		require.NoError(t, result.Err, result.Name)
+		require.Equal(t, RepoPresent, result.State)
+		require.Equal(t, "git@github.com:alice/"+result.Name+".git", result.Remote)
+	}
+
+	// A second run keeps everything as it is.
+	for _, result := range b.CloneAll(ctx) {
+		require.NoError(t, result.Err, result.Name)
+		require.Empty(t, result.Remote)
+	}
+
+	configPath, err := b.WriteConfig()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, constants.RepoXCLI, config.DefaultConfigFileName), configPath)
+
+	loaded, err := config.Load(configPath)
+	require.NoError(t, err)
+	require.NotNil(t, loaded.Config.Lab)
+	require.Equal(t, filepath.Join(dir, constants.RepoLab), loaded.Config.Lab.Repos.Lab)
+
+	globalCfg, err := config.LoadGlobalConfig()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, constants.RepoXCLI), globalCfg.XCLIPath)
+}
+
+func TestBootstrapRejectsNonCheckout(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, constants.RepoCBT), 0755))
+
+	b, err := New(logrus.New(), Options{Dir: dir})
+	require.NoError(t, err)
+
+	result := b.ensureRepo(context.Background(), constants.RepoCBT)
+	require.Equal(t, RepoFailed, result.State)
+	require.ErrorContains(t, result.Err, "not a git checkout")
+}
+
+func TestNewRejectsUnknownProtocol(t *testing.T) {
+	_, err := New(logrus.New(), Options{Dir: t.TempDir(), Protocol: "ftp"})
+	require.ErrorContains(t, err, "unknown clone protocol")
+}
+
+// newCheckout creates a git checkout with the files discovery expects.
+func newCheckout(t *testing.T, path, name string) {
+	t.Helper()
+
+	files := []string{"go.mod"}
+
+	switch name {
+	case constants.RepoXatuCBT:
+		files = append(files, "models/.keep")
+	case constants.RepoLab:
+		files = []string{"package.json", "src/.keep"}
+	}
+
+	for _, file := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(path, file)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(path, file), nil, 0644))
+	}
+
+	out, err := exec.Command("git", "init", "--quiet", path).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
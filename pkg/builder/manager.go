@@ -100,6 +100,30 @@ func (m *Manager) BuildAll(ctx context.Context, force bool) error {
 	return g.Wait()
 }
 
+// BuildRepo runs a repository's full build, including the code it generates
+// first (xatu-cbt and cbt-api protos, cbt-api handlers), even when its
+// binary already exists.
+func (m *Manager) BuildRepo(ctx context.Context, repo string) error {
+	switch repo {
+	case constants.RepoXatuCBT:
+		if err := m.GenerateXatuCBTProtos(ctx); err != nil {
+			return err
+		}
+
+		return m.BuildXatuCBT(ctx, true)
+	case constants.RepoCBT:
+		return m.BuildCBT(ctx, true)
+	case constants.RepoCBTAPI:
+		return m.BuildCBTAPI(ctx, true)
+	case constants.RepoLabBackend:
+		return m.BuildLabBackend(ctx, true)
+	case constants.RepoLab:
+		return m.installLabDeps(ctx, true)
+	default:
+		return fmt.Errorf("unknown repository: %s", repo)
+	}
+}
+
 // BuildXatuCBT builds only the xatu-cbt binary (needed for infrastructure startup).
 func (m *Manager) BuildXatuCBT(ctx context.Context, force bool) error {
 	result := m.BuildXatuCBTWithResult(ctx, force)
@@ -76,6 +76,7 @@ type apiHandler struct {
 	sseHub            *SSEHub
 	stack             stackState
 	gitCache          gitCache
+	envCache          environmentCache
 	mu                sync.RWMutex
 }
 
@@ -96,11 +97,25 @@ type serviceResponse struct {
 	Ports   []int  `json:"ports"`
 	Health  string `json:"health"`
 	LogFile string `json:"logFile"`
+	// Restarts is how often docker restarted the service's container.
+	Restarts int `json:"restarts,omitempty"`
+	// Usage is the latest resource usage sample, when one is available.
+	Usage *usageInfo `json:"usage,omitempty"`
+}
+
+// usageInfo is a resource usage sample of a service or container.
+type usageInfo struct {
+	CPUPercent       float64 `json:"cpuPercent"`
+	MemoryBytes      uint64  `json:"memoryBytes"`
+	MemoryLimitBytes uint64  `json:"memoryLimitBytes,omitempty"`
+	OpenFiles        int     `json:"openFiles,omitempty"`
+	LimitMode        string  `json:"limitMode,omitempty"`
 }
 
 // configResponse is a sanitized view of the lab configuration.
 type configResponse struct {
 	Mode     string        `json:"mode"`
+	Profile  string        `json:"profile,omitempty"`
 	Networks []networkInfo `json:"networks"`
 	Ports    portsInfo     `json:"ports"`
 	CfgPath  string        `json:"cfgPath"`
@@ -110,6 +125,7 @@ type networkInfo struct {
 	Name       string `json:"name"`
 	Enabled    bool   `json:"enabled"`
 	PortOffset int    `json:"portOffset"`
+	Stopped    bool   `json:"stopped,omitempty"`
 }
 
 type portsInfo struct {
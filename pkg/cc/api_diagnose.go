@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/ethpandaops/xcli/pkg/ai"
+	"github.com/ethpandaops/xcli/pkg/constants"
 	"github.com/ethpandaops/xcli/pkg/diagnostic"
 )
 
@@ -28,6 +29,33 @@ type diagnoseSession struct {
 	runMu       sync.Mutex
 	stateMu     sync.Mutex
 	interrupted bool
+
+	// transcript records prompts, responses and tool calls for this session.
+	transcript *diagnostic.Transcript
+	// pendingTools holds decision channels for tool calls awaiting approval.
+	pendingTools map[string]pendingToolCall
+	// autoApprove lists tools the user approved for the rest of the session.
+	autoApprove map[string]bool
+	// cancelTools aborts pending approvals and tool runs of the current turn.
+	cancelTools context.CancelFunc
+}
+
+// pendingToolCall is a tool call waiting for the user's decision.
+type pendingToolCall struct {
+	tool     string
+	decision chan bool
+}
+
+func newDiagnoseSession(session ai.Session, service string, provider ai.ProviderID) *diagnoseSession {
+	return &diagnoseSession{
+		id:           session.ID(),
+		service:      service,
+		provider:     provider,
+		session:      session,
+		transcript:   diagnostic.NewTranscript(session.ID(), service, string(provider)),
+		pendingTools: make(map[string]pendingToolCall, 4),
+		autoApprove:  make(map[string]bool, 4),
+	}
 }
 
 func (s *diagnoseSession) setInterrupted(v bool) {
@@ -44,6 +72,68 @@ func (s *diagnoseSession) wasInterrupted() bool {
 	return s.interrupted
 }
 
+// addPendingToolCall registers a tool call awaiting approval. It returns
+// autoApproved=true, without registering, when the user already approved
+// the tool for this session.
+func (s *diagnoseSession) addPendingToolCall(call *diagnostic.ToolCall) (decision <-chan bool, autoApproved bool) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	if s.autoApprove[call.Tool] {
+		return nil, true
+	}
+
+	ch := make(chan bool, 1)
+	s.pendingTools[call.ID] = pendingToolCall{tool: call.Tool, decision: ch}
+
+	return ch, false
+}
+
+func (s *diagnoseSession) removePendingToolCall(id string) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	delete(s.pendingTools, id)
+}
+
+// resolveToolCall delivers the user's decision for a pending tool call.
+func (s *diagnoseSession) resolveToolCall(id string, approve, always bool) bool {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	pending, ok := s.pendingTools[id]
+	if !ok {
+		return false
+	}
+
+	delete(s.pendingTools, id)
+
+	pending.decision <- approve
+
+	if approve && always {
+		s.autoApprove[pending.tool] = true
+	}
+
+	return true
+}
+
+func (s *diagnoseSession) setCancelTools(cancel context.CancelFunc) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	s.cancelTools = cancel
+}
+
+func (s *diagnoseSession) abortTools() {
+	s.stateMu.Lock()
+	cancel := s.cancelTools
+	s.stateMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
 type diagnoseStartRequest struct {
 	Provider  string `json:"provider"`
 	RequestID string `json:"requestId"`
@@ -121,7 +211,7 @@ func (a *apiHandler) handlePostDiagnose(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	prompt := buildDiagnosePrompt(name, status, health, logLines)
+	prompt := buildDiagnosePrompt(name, status, health, logLines, nil)
 
 	response, err := engine.Ask(r.Context(), prompt)
 	if err != nil {
@@ -190,12 +280,7 @@ func (a *apiHandler) handlePostDiagnoseStart(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	s := &diagnoseSession{
-		id:       session.ID(),
-		service:  name,
-		provider: provider,
-		session:  session,
-	}
+	s := newDiagnoseSession(session, name, provider)
 	a.storeDiagnoseSession(s)
 
 	requestID := normalizeRequestID(req.RequestID)
@@ -205,7 +290,7 @@ func (a *apiHandler) handlePostDiagnoseStart(w http.ResponseWriter, r *http.Requ
 		keyProvider:  string(provider),
 	})
 
-	prompt := buildDiagnosePrompt(name, status, health, logLines)
+	prompt := buildDiagnosePrompt(name, status, health, logLines, diagnoseToolSpecs(a.diagnoseTools()))
 	//nolint:gosec // G118: the diagnose turn is deliberately fire-and-forget and must outlive the HTTP request; it runs under its own timeout (see runDiagnoseTurn).
 	go a.runDiagnoseTurn(s, requestID, prompt)
 }
@@ -315,6 +400,7 @@ func (a *apiHandler) handlePostDiagnoseInterrupt(w http.ResponseWriter, r *http.
 	}
 
 	s.setInterrupted(true)
+	s.abortTools()
 
 	if err := s.session.Interrupt(r.Context()); err != nil {
 		writeJSON(w, http.StatusInternalServerError, map[string]string{keyError: fmt.Sprintf("interrupt failed: %v", err)})
@@ -393,7 +479,17 @@ func (a *apiHandler) runDiagnoseTurn(s *diagnoseSession, requestID, prompt strin
 	ctx, cancel := context.WithTimeout(context.Background(), diagnoseTurnTimeout)
 	defer cancel()
 
-	response, err := s.session.AskStream(ctx, prompt, func(chunk ai.StreamChunk) {
+	// Tool approvals and runs use a child context so an interrupt can abort
+	// them without tearing down the provider session.
+	toolCtx, cancelTools := context.WithCancel(ctx)
+	defer cancelTools()
+
+	s.setCancelTools(cancelTools)
+	defer s.setCancelTools(nil)
+
+	defer a.saveDiagnoseTranscript(s)
+
+	onChunk := func(chunk ai.StreamChunk) {
 		a.broadcastDiagnoseEvent("diagnose_stream", map[string]any{
 			keySessionID: s.id,
 			keyRequestID: requestID,
@@ -404,35 +500,42 @@ func (a *apiHandler) runDiagnoseTurn(s *diagnoseSession, requestID, prompt strin
 			"eventType":  chunk.EventType,
 			"seq":        chunk.Seq,
 		})
-	})
-	if err != nil {
-		if s.wasInterrupted() || errors.Is(err, context.Canceled) {
+	}
+
+	var response string
+
+	for round := 0; ; round++ {
+		s.transcript.Add(diagnostic.TranscriptEntry{Kind: diagnostic.TranscriptPrompt, Text: prompt})
+
+		var err error
+
+		response, err = s.session.AskStream(ctx, prompt, onChunk)
+		if err != nil {
+			a.handleDiagnoseTurnError(s, requestID, err)
+
 			return
 		}
 
-		debugInfo := map[string]any{}
+		s.transcript.Add(diagnostic.TranscriptEntry{Kind: diagnostic.TranscriptResponse, Text: response})
 
-		var providerDebugErr *ai.ProviderDebugError
-		if errors.As(err, &providerDebugErr) {
-			debugInfo = providerDebugErr.DebugInfo()
+		calls := diagnostic.ParseToolCalls(response)
+		if len(calls) == 0 || round >= diagnoseMaxToolRounds {
+			break
 		}
 
-		a.log.WithError(err).WithFields(map[string]any{
-			keyService:   s.service,
-			"session_id": s.id,
-			keyProvider:  s.provider,
-			"debug":      debugInfo,
-		}).Warn("diagnose stream failed")
+		for i := range calls {
+			calls[i].ID = fmt.Sprintf("%s-%d-%s", requestID, round+1, calls[i].ID)
+		}
 
-		a.broadcastDiagnoseEvent("diagnose_error", map[string]any{
-			keySessionID: s.id,
-			keyRequestID: requestID,
-			keyService:   s.service,
-			keyProvider:  s.provider,
-			keyError:     err.Error(),
-		})
+		results := a.runDiagnoseToolCalls(toolCtx, s, requestID, calls)
+		if s.wasInterrupted() || ctx.Err() != nil {
+			return
+		}
 
-		return
+		prompt = diagnostic.BuildToolResultsPrompt(results)
+		if round+1 == diagnoseMaxToolRounds {
+			prompt += "\nThe tool budget for this turn is used up. Give your final answer now without calling tools.\n"
+		}
 	}
 
 	diagnosis := diagnostic.ParseDiagnosisResponse(response)
@@ -446,6 +549,52 @@ func (a *apiHandler) runDiagnoseTurn(s *diagnoseSession, requestID, prompt strin
 	})
 }
 
+// handleDiagnoseTurnError logs and broadcasts a failed provider call. Errors
+// caused by an interrupt are expected and silently dropped.
+func (a *apiHandler) handleDiagnoseTurnError(s *diagnoseSession, requestID string, err error) {
+	if s.wasInterrupted() || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	s.transcript.Add(diagnostic.TranscriptEntry{Kind: diagnostic.TranscriptError, Text: err.Error()})
+
+	debugInfo := map[string]any{}
+
+	var providerDebugErr *ai.ProviderDebugError
+	if errors.As(err, &providerDebugErr) {
+		debugInfo = providerDebugErr.DebugInfo()
+	}
+
+	a.log.WithError(err).WithFields(map[string]any{
+		keyService:   s.service,
+		"session_id": s.id,
+		keyProvider:  s.provider,
+		"debug":      debugInfo,
+	}).Warn("diagnose stream failed")
+
+	a.broadcastDiagnoseEvent("diagnose_error", map[string]any{
+		keySessionID: s.id,
+		keyRequestID: requestID,
+		keyService:   s.service,
+		keyProvider:  s.provider,
+		keyError:     err.Error(),
+	})
+}
+
+// saveDiagnoseTranscript persists the session transcript next to the rebuild
+// failure reports so tool use can be audited later.
+func (a *apiHandler) saveDiagnoseTranscript(s *diagnoseSession) {
+	stateDir := a.backend.StateDir()
+	if stateDir == "" {
+		return
+	}
+
+	store := diagnostic.NewStore(a.log, filepath.Join(stateDir, constants.DirErrors))
+	if err := store.SaveTranscript(s.transcript); err != nil {
+		a.log.WithError(err).WithField("session_id", s.id).Warn("failed to save diagnose transcript")
+	}
+}
+
 func (a *apiHandler) providerFromString(provider string) ai.ProviderID {
 	p := strings.TrimSpace(provider)
 	if p == "" {
@@ -569,8 +718,9 @@ func (a *apiHandler) collectServiceLogs(name string) []string {
 	return nil
 }
 
-// buildDiagnosePrompt creates the log-focused prompt for AI analysis.
-func buildDiagnosePrompt(name, status, health string, logLines []string) string {
+// buildDiagnosePrompt creates the log-focused prompt for AI analysis. When
+// tools is non-empty the prompt also describes the read-only tool protocol.
+func buildDiagnosePrompt(name, status, health string, logLines []string, tools []diagnostic.ToolSpec) string {
 	var sb strings.Builder
 
 	sb.WriteString("Analyze these service logs and provide a diagnosis.\n\n")
@@ -602,6 +752,11 @@ func buildDiagnosePrompt(name, status, health string, logLines []string) string
 
 	sb.WriteString("```\n")
 
+	if len(tools) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(diagnostic.BuildToolInstructions(tools))
+	}
+
 	return sb.String()
 }
 
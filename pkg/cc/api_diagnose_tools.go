@@ -0,0 +1,592 @@
+package cc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
+)
+
+const (
+	// diagnoseToolTimeout bounds a single tool execution.
+	diagnoseToolTimeout = 30 * time.Second
+	// diagnoseToolApprovalTimeout is how long a tool call waits for the user
+	// before it is treated as denied.
+	diagnoseToolApprovalTimeout = 5 * time.Minute
+	// diagnoseMaxToolRounds caps how many tool round-trips one turn may take.
+	diagnoseMaxToolRounds = 8
+	// diagnoseToolOutputLimit truncates tool output sent back to the AI.
+	diagnoseToolOutputLimit = 64 * 1024
+
+	toolReadLogs        = "read_logs"
+	toolGrepLogs        = "grep_logs"
+	toolListConfigs     = "list_configs"
+	toolReadConfig      = "read_config"
+	toolQueryClickHouse = "query_clickhouse"
+	toolReadRedisKey    = "read_redis_key"
+	toolListProcesses   = "list_processes"
+
+	toolArgService = "service"
+)
+
+// clickHouseQuerier is implemented by backends that expose a ClickHouse HTTP
+// endpoint the diagnose tools may query.
+type clickHouseQuerier interface {
+	ClickHouseHTTPURL() string
+}
+
+// diagnoseToolFunc executes a tool call for a diagnose session.
+type diagnoseToolFunc func(ctx context.Context, s *diagnoseSession, call *diagnostic.ToolCall) (string, error)
+
+// diagnoseTool pairs a tool spec with its read-only implementation.
+type diagnoseTool struct {
+	spec diagnostic.ToolSpec
+	run  diagnoseToolFunc
+}
+
+type diagnoseToolDecisionRequest struct {
+	SessionID  string `json:"sessionId"`
+	ToolCallID string `json:"toolCallId"`
+	Approve    bool   `json:"approve"`
+	// Always auto-approves further calls of the same tool in this session.
+	Always bool `json:"always"`
+}
+
+// diagnoseTools returns the tool set available for this stack. Tools that need
+// Redis or ClickHouse are only offered when the stack provides them.
+func (a *apiHandler) diagnoseTools() []diagnoseTool {
+	tools := []diagnoseTool{
+		{
+			spec: diagnostic.ToolSpec{
+				Name:        toolReadLogs,
+				Description: "Read the last lines of a service log.",
+				Args: map[string]string{
+					toolArgService: "service name (defaults to the diagnosed service)",
+					"lines":        "number of lines, default 200, max 2000",
+				},
+				ArgOrder: []string{toolArgService, "lines"},
+			},
+			run: a.toolReadLogs,
+		},
+		{
+			spec: diagnostic.ToolSpec{
+				Name:        toolGrepLogs,
+				Description: "Search a service log with a regular expression (RE2 syntax).",
+				Args: map[string]string{
+					toolArgService: "service name (defaults to the diagnosed service)",
+					"pattern":      "regular expression, required",
+					"max_matches":  "maximum matching lines returned, default 100, max 500",
+				},
+				ArgOrder: []string{toolArgService, "pattern", "max_matches"},
+			},
+			run: a.toolGrepLogs,
+		},
+		{
+			spec: diagnostic.ToolSpec{
+				Name:        toolListConfigs,
+				Description: "List the generated service config files.",
+			},
+			run: a.toolListConfigs,
+		},
+		{
+			spec: diagnostic.ToolSpec{
+				Name:        toolReadConfig,
+				Description: "Read a generated service config file and its override, if any.",
+				Args: map[string]string{
+					"name": "config file name from list_configs, required",
+				},
+				ArgOrder: []string{"name"},
+			},
+			run: a.toolReadConfig,
+		},
+		{
+			spec: diagnostic.ToolSpec{
+				Name:        toolListProcesses,
+				Description: "List stack services with status, PID, ports and health.",
+			},
+			run: a.toolListProcesses,
+		},
+	}
+
+	if _, ok := a.backend.(clickHouseQuerier); ok {
+		tools = append(tools, diagnoseTool{
+			spec: diagnostic.ToolSpec{
+				Name:        toolQueryClickHouse,
+				Description: "Run a read-only query (SELECT, SHOW, DESCRIBE, EXPLAIN, WITH) against the local CBT ClickHouse.",
+				Args: map[string]string{
+					"query": "SQL query, required",
+					"limit": "maximum rows returned, default 100, max 1000",
+				},
+				ArgOrder: []string{"query", "limit"},
+			},
+			run: a.toolQueryClickHouse,
+		})
+	}
+
+	if a.redis != nil {
+		tools = append(tools, diagnoseTool{
+			spec: diagnostic.ToolSpec{
+				Name:        toolReadRedisKey,
+				Description: "Read a Redis key (any type) from the local Redis.",
+				Args: map[string]string{
+					"key": "key name, required",
+					"db":  "database number, default 0",
+				},
+				ArgOrder: []string{"key", "db"},
+			},
+			run: a.toolReadRedisKey,
+		})
+	}
+
+	return tools
+}
+
+func diagnoseToolSpecs(tools []diagnoseTool) []diagnostic.ToolSpec {
+	specs := make([]diagnostic.ToolSpec, 0, len(tools))
+	for _, tool := range tools {
+		specs = append(specs, tool.spec)
+	}
+
+	return specs
+}
+
+// handlePostDiagnoseTool records the user's decision for a pending tool call.
+func (a *apiHandler) handlePostDiagnoseTool(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{keyError: errMsgServiceNameRequired})
+
+		return
+	}
+
+	var req diagnoseToolDecisionRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{keyError: err.Error()})
+
+		return
+	}
+
+	if strings.TrimSpace(req.SessionID) == "" || strings.TrimSpace(req.ToolCallID) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{keyError: "sessionId and toolCallId are required"})
+
+		return
+	}
+
+	s, ok := a.getDiagnoseSession(req.SessionID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{keyError: errMsgDiagnoseSessionNotFound})
+
+		return
+	}
+
+	if s.service != name {
+		writeJSON(w, http.StatusBadRequest, map[string]string{keyError: errMsgSessionServiceMismatch})
+
+		return
+	}
+
+	if !s.resolveToolCall(req.ToolCallID, req.Approve, req.Always) {
+		writeJSON(w, http.StatusNotFound, map[string]string{keyError: "tool call is not awaiting approval"})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		keySessionID: s.id,
+		"toolCallId": req.ToolCallID,
+		"approved":   req.Approve,
+	})
+}
+
+// runDiagnoseToolCalls asks for approval and executes each tool call in order.
+func (a *apiHandler) runDiagnoseToolCalls(
+	ctx context.Context,
+	s *diagnoseSession,
+	requestID string,
+	calls []diagnostic.ToolCall,
+) []diagnostic.ToolResult {
+	tools := make(map[string]diagnoseTool, 8)
+	for _, tool := range a.diagnoseTools() {
+		tools[tool.spec.Name] = tool
+	}
+
+	results := make([]diagnostic.ToolResult, 0, len(calls))
+
+	for i := range calls {
+		call := calls[i]
+		result := diagnostic.ToolResult{Call: call}
+
+		tool, known := tools[call.Tool]
+
+		switch {
+		case call.ParseError != "":
+			result.Approved = true
+			result.Error = call.ParseError
+		case !known:
+			result.Approved = true
+			result.Error = fmt.Sprintf("unknown tool %q", call.Tool)
+		default:
+			s.transcript.Add(diagnostic.TranscriptEntry{Kind: diagnostic.TranscriptToolCall, Tool: &call})
+
+			result.Approved = a.awaitToolApproval(ctx, s, requestID, &call)
+			if result.Approved {
+				toolCtx, cancel := context.WithTimeout(ctx, diagnoseToolTimeout)
+				output, err := tool.run(toolCtx, s, &call)
+
+				cancel()
+
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Output = truncateToolOutput(output)
+				}
+			}
+		}
+
+		approved := result.Approved
+		s.transcript.Add(diagnostic.TranscriptEntry{
+			Kind:     diagnostic.TranscriptToolResult,
+			Text:     firstNonEmpty(result.Error, result.Output),
+			Tool:     &call,
+			Approved: &approved,
+		})
+
+		a.broadcastDiagnoseEvent("diagnose_tool_result", map[string]any{
+			keySessionID: s.id,
+			keyRequestID: requestID,
+			keyService:   s.service,
+			"toolCallId": call.ID,
+			"tool":       call.Tool,
+			"approved":   result.Approved,
+			"output":     result.Output,
+			keyError:     result.Error,
+		})
+
+		results = append(results, result)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results
+}
+
+// awaitToolApproval broadcasts a tool request and blocks until the user
+// decides, the approval times out or the turn is interrupted.
+func (a *apiHandler) awaitToolApproval(
+	ctx context.Context,
+	s *diagnoseSession,
+	requestID string,
+	call *diagnostic.ToolCall,
+) bool {
+	decision, autoApproved := s.addPendingToolCall(call)
+
+	a.broadcastDiagnoseEvent("diagnose_tool_request", map[string]any{
+		keySessionID:   s.id,
+		keyRequestID:   requestID,
+		keyService:     s.service,
+		keyProvider:    s.provider,
+		"toolCallId":   call.ID,
+		"tool":         call.Tool,
+		"args":         call.Args,
+		"autoApproved": autoApproved,
+	})
+
+	if autoApproved {
+		return true
+	}
+
+	defer s.removePendingToolCall(call.ID)
+
+	timer := time.NewTimer(diagnoseToolApprovalTimeout)
+	defer timer.Stop()
+
+	select {
+	case approved := <-decision:
+		return approved
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (a *apiHandler) toolReadLogs(_ context.Context, s *diagnoseSession, call *diagnostic.ToolCall) (string, error) {
+	service, err := a.toolService(s, call)
+	if err != nil {
+		return "", err
+	}
+
+	lines := readLastLines(a.toolLogPath(service), call.IntArg("lines", 200, 2000))
+	if len(lines) == 0 && a.logHistoryFn != nil {
+		lines = a.logHistoryFn(service)
+		if n := call.IntArg("lines", 200, 2000); len(lines) > n {
+			lines = lines[len(lines)-n:]
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no logs available for service %s", service)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func (a *apiHandler) toolGrepLogs(ctx context.Context, s *diagnoseSession, call *diagnostic.ToolCall) (string, error) {
+	service, err := a.toolService(s, call)
+	if err != nil {
+		return "", err
+	}
+
+	pattern := call.StringArg("pattern", "")
+	if pattern == "" {
+		return "", errors.New("pattern is required")
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	f, err := os.Open(a.toolLogPath(service))
+	if err != nil {
+		return "", fmt.Errorf("failed to open log for %s: %w", service, err)
+	}
+	defer f.Close()
+
+	maxMatches := call.IntArg("max_matches", 100, 500)
+	matches := make([]string, 0, maxMatches)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+
+		if lineNo%10000 == 0 && ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		if !re.MatchString(scanner.Text()) {
+			continue
+		}
+
+		// Keep the most recent matches, which are usually the relevant ones.
+		if len(matches) == maxMatches {
+			matches = matches[1:]
+		}
+
+		matches = append(matches, fmt.Sprintf("%d: %s", lineNo, scanner.Text()))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read log for %s: %w", service, err)
+	}
+
+	if len(matches) == 0 {
+		return "no matches", nil
+	}
+
+	return strings.Join(matches, "\n"), nil
+}
+
+func (a *apiHandler) toolListConfigs(_ context.Context, _ *diagnoseSession, _ *diagnostic.ToolCall) (string, error) {
+	files, err := a.backend.GetConfigFiles()
+	if err != nil {
+		return "", err
+	}
+
+	if len(files) == 0 {
+		return "no config files", nil
+	}
+
+	var sb strings.Builder
+
+	for _, f := range files {
+		fmt.Fprintf(&sb, "%s (%d bytes", f.Name, f.Size)
+
+		if f.HasOverride {
+			sb.WriteString(", has override")
+		}
+
+		sb.WriteString(")\n")
+	}
+
+	return sb.String(), nil
+}
+
+func (a *apiHandler) toolReadConfig(_ context.Context, _ *diagnoseSession, call *diagnostic.ToolCall) (string, error) {
+	name := call.StringArg("name", "")
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+
+	file, err := a.backend.GetConfigFile(filepath.Base(name))
+	if err != nil {
+		return "", err
+	}
+
+	if !file.HasOverride {
+		return file.Content, nil
+	}
+
+	return file.Content + "\n# --- override ---\n" + file.OverrideContent, nil
+}
+
+func (a *apiHandler) toolListProcesses(ctx context.Context, _ *diagnoseSession, _ *diagnostic.ToolCall) (string, error) {
+	var sb strings.Builder
+
+	for _, svc := range a.backend.GetServices(ctx) {
+		ports := make([]string, 0, len(svc.Ports))
+		for _, p := range svc.Ports {
+			ports = append(ports, strconv.Itoa(p))
+		}
+
+		fmt.Fprintf(&sb, "%s\tstatus=%s\tpid=%d\tports=%s\thealth=%s\n",
+			svc.Name, svc.Status, svc.PID, strings.Join(ports, ","), svc.Health)
+	}
+
+	if sb.Len() == 0 {
+		return "no services", nil
+	}
+
+	return sb.String(), nil
+}
+
+func (a *apiHandler) toolQueryClickHouse(ctx context.Context, _ *diagnoseSession, call *diagnostic.ToolCall) (string, error) {
+	querier, ok := a.backend.(clickHouseQuerier)
+	if !ok {
+		return "", errors.New("ClickHouse is not available for this stack")
+	}
+
+	query, err := readOnlyQuery(call.StringArg("query", ""))
+	if err != nil {
+		return "", err
+	}
+
+	limit := call.IntArg("limit", 100, 1000)
+
+	params := url.Values{}
+	params.Set("readonly", "1")
+	params.Set("max_result_rows", strconv.Itoa(limit))
+	params.Set("result_overflow_mode", "break")
+	params.Set("max_execution_time", strconv.Itoa(int(diagnoseToolTimeout/time.Second)))
+	params.Set("default_format", "TabSeparatedWithNames")
+
+	endpoint := strings.TrimRight(querier.ClickHouseHTTPURL(), "/") + "/?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(query))
+	if err != nil {
+		return "", fmt.Errorf("failed to build query request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("query failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, diagnoseToolOutputLimit+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read query result: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("clickhouse returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return string(body), nil
+}
+
+func (a *apiHandler) toolReadRedisKey(ctx context.Context, _ *diagnoseSession, call *diagnostic.ToolCall) (string, error) {
+	key := call.StringArg("key", "")
+	if key == "" {
+		return "", errors.New("key is required")
+	}
+
+	detail, err := a.redis.getKey(ctx, call.IntArg("db", 0, 0), key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(detail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode key: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// toolService resolves the service argument, defaulting to the diagnosed
+// service, and rejects services the stack does not know about.
+func (a *apiHandler) toolService(s *diagnoseSession, call *diagnostic.ToolCall) (string, error) {
+	service := call.StringArg(toolArgService, s.service)
+
+	if _, _, ok := a.getServiceStatus(service); !ok {
+		return "", fmt.Errorf("unknown service %q", service)
+	}
+
+	return service, nil
+}
+
+func (a *apiHandler) toolLogPath(service string) string {
+	// filepath.Base strips any traversal, as in collectServiceLogs.
+	return filepath.Clean(a.backend.LogFilePath(filepath.Base(service)))
+}
+
+// readOnlyStatementPattern matches the statement kinds query_clickhouse allows.
+var readOnlyStatementPattern = regexp.MustCompile(`(?i)^(select|show|describe|desc|explain|with)\b`)
+
+// readOnlyQuery validates a query for query_clickhouse. The server-side
+// readonly=1 setting is the real guard; this rejects obvious misuse early
+// with a clearer error for the model.
+func readOnlyQuery(query string) (string, error) {
+	q := strings.TrimSpace(query)
+	q = strings.TrimSpace(strings.TrimSuffix(q, ";"))
+
+	if q == "" {
+		return "", errors.New("query is required")
+	}
+
+	if strings.Contains(q, ";") {
+		return "", errors.New("only a single statement is allowed")
+	}
+
+	if !readOnlyStatementPattern.MatchString(q) {
+		return "", errors.New("only SELECT, SHOW, DESCRIBE, EXPLAIN and WITH queries are allowed")
+	}
+
+	return q, nil
+}
+
+func truncateToolOutput(output string) string {
+	if len(output) <= diagnoseToolOutputLimit {
+		return output
+	}
+
+	return output[:diagnoseToolOutputLimit] + "\n... (output truncated)"
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,94 @@
+package cc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiagnoseToolCalls(t *testing.T) {
+	t.Parallel()
+
+	response := "Let me check the logs.\n\n" +
+		"```xcli-tool\n{\"tool\": \"grep_logs\", \"args\": {\"pattern\": \"panic\", \"max_matches\": 20}}\n```\n\n" +
+		"```xcli-tool\nnot json\n```\n"
+
+	calls := diagnostic.ParseToolCalls(response)
+	require.Len(t, calls, 2)
+
+	require.Equal(t, "tool-1", calls[0].ID)
+	require.Equal(t, toolGrepLogs, calls[0].Tool)
+	require.Empty(t, calls[0].ParseError)
+	require.Equal(t, "panic", calls[0].StringArg("pattern", ""))
+	require.Equal(t, 20, calls[0].IntArg("max_matches", 100, 500))
+	require.Equal(t, "mainnet", calls[0].StringArg("network", "mainnet"))
+
+	require.NotEmpty(t, calls[1].ParseError)
+
+	require.Empty(t, diagnostic.ParseToolCalls("## Root Cause\nport already in use"))
+}
+
+func TestReadOnlyQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{query: "SELECT 1;", want: "SELECT 1"},
+		{query: "  with x as (select 1) select * from x", want: "with x as (select 1) select * from x"},
+		{query: "SHOW TABLES", want: "SHOW TABLES"},
+		{query: "DROP TABLE fct_block", wantErr: true},
+		{query: "SELECT 1; DROP TABLE fct_block", wantErr: true},
+		{query: "INSERT INTO t SELECT 1", wantErr: true},
+		{query: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := readOnlyQuery(tt.query)
+		if tt.wantErr {
+			require.Error(t, err, tt.query)
+
+			continue
+		}
+
+		require.NoError(t, err, tt.query)
+		require.Equal(t, tt.want, got)
+	}
+}
+
+func TestDiagnoseSessionToolApproval(t *testing.T) {
+	t.Parallel()
+
+	a := &apiHandler{}
+	s := &diagnoseSession{
+		pendingTools: make(map[string]pendingToolCall, 1),
+		autoApprove:  make(map[string]bool, 1),
+	}
+	call := &diagnostic.ToolCall{ID: "req-1-1-tool-1", Tool: toolListProcesses}
+
+	done := make(chan bool, 1)
+
+	go func() { done <- a.awaitToolApproval(context.Background(), s, "req-1", call) }()
+
+	require.Eventually(t, func() bool {
+		return s.resolveToolCall(call.ID, true, true)
+	}, time.Second, 10*time.Millisecond)
+	require.True(t, <-done)
+
+	// "always" approves later calls of the same tool without asking.
+	require.True(t, a.awaitToolApproval(context.Background(), s, "req-1", call))
+	require.False(t, s.resolveToolCall(call.ID, true, false))
+
+	// Interrupting the turn denies calls still awaiting a decision.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	denied := &diagnostic.ToolCall{ID: "req-1-1-tool-2", Tool: toolReadLogs}
+	require.False(t, a.awaitToolApproval(ctx, s, "req-1", denied))
+	require.Empty(t, s.pendingTools)
+}
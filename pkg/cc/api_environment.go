@@ -0,0 +1,71 @@
+package cc
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/toolchain"
+)
+
+// environmentCacheTTL is how long toolchain checks are reused. Installing a
+// tool is rare, and each check runs every tool's version command.
+const environmentCacheTTL = 5 * time.Minute
+
+// environmentCache holds the last toolchain check.
+type environmentCache struct {
+	resp      *environmentResponse
+	fetchedAt time.Time
+	mu        sync.RWMutex
+}
+
+// environmentResponse compares the installed toolchain with what the stack's
+// repositories declare.
+type environmentResponse struct {
+	Tools     []toolchain.Result `json:"tools"`
+	Error     string             `json:"error,omitempty"`
+	CheckedAt time.Time          `json:"checkedAt"`
+}
+
+// handleGetEnvironment returns the toolchain check for the stack's
+// repositories. ?refresh=true bypasses the cache.
+func (a *apiHandler) handleGetEnvironment(w http.ResponseWriter, r *http.Request) {
+	a.envCache.mu.RLock()
+
+	if a.envCache.resp != nil && r.URL.Query().Get("refresh") != "true" &&
+		time.Since(a.envCache.fetchedAt) < environmentCacheTTL {
+		cached := *a.envCache.resp
+		a.envCache.mu.RUnlock()
+
+		writeJSON(w, http.StatusOK, cached)
+
+		return
+	}
+
+	a.envCache.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	reqs := []toolchain.Requirement{{Tool: toolchain.ToolDocker, Source: a.backend.Name()}}
+
+	repoReqs, err := toolchain.ForRepos(a.backend.GitRepos())
+	reqs = append(reqs, repoReqs...)
+
+	resp := environmentResponse{
+		Tools:     toolchain.Check(ctx, reqs),
+		CheckedAt: time.Now(),
+	}
+
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	a.envCache.mu.Lock()
+	a.envCache.resp = &resp
+	a.envCache.fetchedAt = resp.CheckedAt
+	a.envCache.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, resp)
+}
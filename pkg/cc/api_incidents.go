@@ -0,0 +1,82 @@
+package cc
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
+	"github.com/ethpandaops/xcli/pkg/tui"
+)
+
+const (
+	// healthUnhealthy is the health monitor status of a failing service.
+	healthUnhealthy = "unhealthy"
+	// unhealthyIncidentThreshold is the number of consecutive failed health
+	// checks after which a running service is recorded as an incident.
+	unhealthyIncidentThreshold = 3
+	// defaultIncidentLimit is the number of incidents listed by default.
+	defaultIncidentLimit = 50
+)
+
+// incidentBackend is implemented by backends that record runtime incidents
+// for crashed or unhealthy services.
+type incidentBackend interface {
+	// RecordUnhealthy records an incident for a service failing health checks.
+	RecordUnhealthy(name, lastError string)
+	// ListIncidents returns recent incidents, newest first.
+	ListIncidents(limit int) ([]*diagnostic.Incident, error)
+	// SetIncidentHandler registers fn to be called for every new incident.
+	SetIncidentHandler(fn func(*diagnostic.Incident))
+}
+
+// recordUnhealthyIncidents records an incident for every service whose
+// consecutive health check failures just reached the threshold, so each
+// unhealthy streak is recorded once.
+func recordUnhealthyIncidents(backend StackBackend, health map[string]tui.HealthStatus) {
+	recorder, ok := backend.(incidentBackend)
+	if !ok {
+		return
+	}
+
+	for name, status := range health {
+		if status.Status == healthUnhealthy && status.ConsecutiveFailures == unhealthyIncidentThreshold {
+			recorder.RecordUnhealthy(name, status.LastError)
+		}
+	}
+}
+
+// handleGetIncidents handles GET /api/stacks/{stack}/incidents.
+func (a *apiHandler) handleGetIncidents(w http.ResponseWriter, r *http.Request) {
+	recorder, ok := a.backend.(incidentBackend)
+	if !ok {
+		writeJSON(w, http.StatusOK, []*diagnostic.Incident{})
+
+		return
+	}
+
+	limit := defaultIncidentLimit
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				keyError: "limit must be a positive integer",
+			})
+
+			return
+		}
+
+		limit = parsed
+	}
+
+	incidents, err := recorder.ListIncidents(limit)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			keyError: err.Error(),
+		})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, incidents)
+}
@@ -0,0 +1,73 @@
+package cc
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// networkBackend is implemented by backends that can start and stop a single
+// network's services while the rest of the stack keeps running.
+type networkBackend interface {
+	StartNetwork(ctx context.Context, network string) error
+	StopNetwork(ctx context.Context, network string, resetBounds bool) error
+}
+
+// handlePostNetworkAction handles POST /api/stacks/{stack}/networks/{name}/{start,stop}.
+// Stop accepts ?resetBounds=true to flush the network's Redis database.
+func (a *apiHandler) handlePostNetworkAction(w http.ResponseWriter, r *http.Request, action string) {
+	backend, ok := a.backend.(networkBackend)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{
+			keyError: "stack does not support network actions",
+		})
+
+		return
+	}
+
+	name := r.PathValue("name")
+	if name == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			keyError: "network name required",
+		})
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	var err error
+
+	switch action {
+	case "start":
+		err = backend.StartNetwork(ctx, name)
+	case "stop":
+		err = backend.StopNetwork(ctx, name, r.URL.Query().Get("resetBounds") == "true")
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			keyError: "unknown action: " + action,
+		})
+
+		return
+	}
+
+	if err != nil {
+		a.log.WithError(err).WithFields(logrus.Fields{
+			"network": name,
+			"action":  action,
+		}).Error("Network action failed")
+
+		writeJSON(w, http.StatusInternalServerError, map[string]string{
+			keyError: err.Error(),
+		})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		keyStatus: keyOK,
+	})
+}
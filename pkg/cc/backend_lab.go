@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/ethpandaops/xcli/pkg/config"
 	"github.com/ethpandaops/xcli/pkg/configtui"
 	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
+	"github.com/ethpandaops/xcli/pkg/dockerhost"
 	"github.com/ethpandaops/xcli/pkg/git"
 	"github.com/ethpandaops/xcli/pkg/instance"
 	"github.com/ethpandaops/xcli/pkg/orchestrator"
@@ -30,6 +33,9 @@ type labBackend struct {
 	cfgPath string
 	runtime *instance.Runtime
 	gitChk  *git.Checker
+
+	incidentMu sync.RWMutex
+	incidentFn func(*diagnostic.Incident)
 }
 
 // Compile-time interface check.
@@ -44,7 +50,7 @@ func newLabBackend(
 	runtime *instance.Runtime,
 	gitChk *git.Checker,
 ) *labBackend {
-	return &labBackend{
+	b := &labBackend{
 		log:     log,
 		wrapper: tui.NewOrchestratorWrapper(orch),
 		orch:    orch,
@@ -53,6 +59,10 @@ func newLabBackend(
 		runtime: runtime,
 		gitChk:  gitChk,
 	}
+
+	b.watchIncidents(orch)
+
+	return b
 }
 
 // Name returns "lab".
@@ -71,6 +81,9 @@ func (b *labBackend) Capabilities() StackCapabilities {
 		HasGitRepos:       true,
 		HasRegenerate:     true,
 		HasRebuild:        true,
+		HasIncidents:      true,
+		HasNetworks:       true,
+		HasEnvironment:    true,
 	}
 }
 
@@ -90,6 +103,7 @@ func (b *labBackend) GetServices(_ context.Context) []serviceResponse {
 			Ports:   svc.Ports,
 			Health:  svc.Health,
 			LogFile: svc.LogFile,
+			Usage:   newUsageInfo(svc.Usage),
 		}
 
 		if svc.Uptime > 0 {
@@ -103,25 +117,48 @@ func (b *labBackend) GetServices(_ context.Context) []serviceResponse {
 		result = append(result, serviceResponse{
 			Name:   i.Name,
 			Status: i.Status,
+			Usage:  newUsageInfo(i.Usage),
 		})
 	}
 
 	return result
 }
 
+// newUsageInfo converts a TUI usage sample for the API.
+func newUsageInfo(usage *tui.ResourceUsage) *usageInfo {
+	if usage == nil {
+		return nil
+	}
+
+	return &usageInfo{
+		CPUPercent:       usage.CPUPercent,
+		MemoryBytes:      usage.MemoryBytes,
+		MemoryLimitBytes: usage.MemoryLimitBytes,
+		OpenFiles:        usage.OpenFiles,
+		LimitMode:        usage.LimitMode,
+	}
+}
+
 // GetConfigSummary returns the sanitized config for the sidebar.
 func (b *labBackend) GetConfigSummary() any {
+	var manifest *instance.Manifest
+	if b.runtime != nil {
+		manifest = b.runtime.Manifest
+	}
+
 	networks := make([]networkInfo, 0, len(b.labCfg.Networks))
 	for _, n := range b.labCfg.Networks {
 		networks = append(networks, networkInfo{
 			Name:       n.Name,
 			Enabled:    n.Enabled,
 			PortOffset: n.PortOffset,
+			Stopped:    manifest.NetworkStopped(n.Name),
 		})
 	}
 
 	return configResponse{
 		Mode:     b.labCfg.Mode,
+		Profile:  b.labCfg.Profile,
 		Networks: networks,
 		Ports: portsInfo{
 			LabBackend:      b.portPlan().LabBackend,
@@ -181,6 +218,16 @@ func (b *labBackend) RebuildService(ctx context.Context, name string) error {
 	return b.wrapper.RebuildService(ctx, name)
 }
 
+// StartNetwork starts one network's CBT services on the running stack.
+func (b *labBackend) StartNetwork(ctx context.Context, network string) error {
+	return b.orch.StartNetwork(ctx, network)
+}
+
+// StopNetwork stops one network's CBT services on the running stack.
+func (b *labBackend) StopNetwork(ctx context.Context, network string, resetBounds bool) error {
+	return b.orch.StopNetwork(ctx, network, resetBounds)
+}
+
 // LogSource returns how to stream logs for a given service.
 func (b *labBackend) LogSource(name string) LogSourceInfo {
 	if container, ok := b.orch.InfrastructureManager().DockerContainerName(name); ok {
@@ -546,10 +593,50 @@ func (b *labBackend) RecreateOrchestrator() error {
 
 	b.orch = newOrch
 	b.wrapper.SetOrchestrator(newOrch)
+	b.watchIncidents(newOrch)
 
 	return nil
 }
 
+// RecordUnhealthy records an incident for a service failing health checks.
+func (b *labBackend) RecordUnhealthy(name, lastError string) {
+	b.orch.RecordUnhealthy(name, lastError)
+}
+
+// ListIncidents returns recent runtime incidents, newest first.
+func (b *labBackend) ListIncidents(limit int) ([]*diagnostic.Incident, error) {
+	store := diagnostic.NewStore(b.log, filepath.Join(b.orch.StateDir(), constants.DirErrors))
+
+	return store.ListIncidents(limit)
+}
+
+// SetIncidentHandler registers fn to be called for every new incident.
+func (b *labBackend) SetIncidentHandler(fn func(*diagnostic.Incident)) {
+	b.incidentMu.Lock()
+	defer b.incidentMu.Unlock()
+
+	b.incidentFn = fn
+}
+
+// watchIncidents forwards the incidents recorded by orch to the registered
+// handler. Called again whenever the orchestrator is recreated.
+func (b *labBackend) watchIncidents(orch *orchestrator.Orchestrator) {
+	recorder := orch.Incidents()
+	if recorder == nil {
+		return
+	}
+
+	recorder.OnIncident(func(incident *diagnostic.Incident) {
+		b.incidentMu.RLock()
+		fn := b.incidentFn
+		b.incidentMu.RUnlock()
+
+		if fn != nil {
+			fn(incident)
+		}
+	})
+}
+
 // StateDir returns the lab stack's state directory.
 func (b *labBackend) StateDir() string {
 	return b.orch.StateDir()
@@ -596,11 +683,27 @@ func (b *labBackend) workspaceStateDir() string {
 
 // RedisAddr returns the Redis address for the Lab stack.
 func (b *labBackend) RedisAddr() string {
-	if port := b.portPlan().Redis; port > 0 {
-		return fmt.Sprintf("localhost:%d", port)
+	return fmt.Sprintf("%s:%d", b.infraHost(), b.portPlan().Redis)
+}
+
+// ClickHouseHTTPURL returns the CBT ClickHouse HTTP endpoint.
+func (b *labBackend) ClickHouseHTTPURL() string {
+	return fmt.Sprintf("http://%s:%d", b.infraHost(), b.portPlan().ClickHouseCBT01HTTP)
+}
+
+// infraHost returns the host infrastructure is reached on, which differs
+// from localhost only for an untunnelled remote Docker host.
+func (b *labBackend) infraHost() string {
+	if b.labCfg == nil {
+		return dockerhost.LocalServiceHost
+	}
+
+	target, err := dockerhost.Resolve(b.labCfg.Infrastructure.Docker)
+	if err != nil {
+		return dockerhost.LocalServiceHost
 	}
 
-	return "localhost:0"
+	return target.ServiceHost()
 }
 
 func (b *labBackend) portPlan() instance.PortPlan {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/ethpandaops/xcli/pkg/compose"
 	"github.com/ethpandaops/xcli/pkg/config"
@@ -25,6 +26,9 @@ type xatuConfigRequest struct {
 	EnvOverrides map[string]string `json:"envOverrides"`
 }
 
+// xatuWaitTimeout bounds how long Up waits for the stack's healthchecks.
+const xatuWaitTimeout = 5 * time.Minute
+
 // xatuBackend implements StackBackend for the Xatu docker-compose stack.
 type xatuBackend struct {
 	log     logrus.FieldLogger
@@ -77,6 +81,9 @@ func (b *xatuBackend) Capabilities() StackCapabilities {
 		HasGitRepos:       true,
 		HasRegenerate:     false,
 		HasRebuild:        true,
+		HasIncidents:      false,
+		HasNetworks:       false,
+		HasEnvironment:    true,
 	}
 }
 
@@ -103,37 +110,72 @@ func (b *xatuBackend) GetServices(ctx context.Context) []serviceResponse {
 		b.log.WithError(err).Debug("Failed to get docker compose ps (stack may be down)")
 	}
 
+	containers := make([]string, 0, len(statuses))
+
 	for _, svc := range statuses {
 		statusMap[svc.Service] = svc
+		containers = append(containers, svc.Name)
+	}
+
+	restarts, err := b.runner.RestartCounts(ctx, containers)
+	if err != nil {
+		b.log.WithError(err).Debug("Failed to read container restart counts")
 	}
 
 	// Merge: use config as the base, overlay runtime status.
 	result := make([]serviceResponse, 0, len(serviceNames))
 
 	for _, name := range serviceNames {
-		status := stackStatusStopped
-		health := "unknown"
+		resp := serviceResponse{
+			Name:   name,
+			Status: stackStatusStopped,
+			Health: "unknown",
+		}
 
 		if svc, ok := statusMap[name]; ok {
 			switch svc.State {
 			case stackStatusRunning:
-				status = stackStatusRunning
-				health = "healthy"
-			case "exited":
-				status = "exited"
+				resp.Status = stackStatusRunning
+			case compose.StateExited:
+				resp.Status = compose.StateExited
+			}
+
+			resp.Health = xatuServiceHealth(svc)
+			resp.Restarts = restarts[svc.Name]
+
+			for _, p := range svc.Publishers {
+				if p.PublishedPort > 0 && !slices.Contains(resp.Ports, p.PublishedPort) {
+					resp.Ports = append(resp.Ports, p.PublishedPort)
+				}
+			}
+
+			if endpoints := svc.Endpoints(); len(endpoints) > 0 {
+				resp.URL = "http://" + endpoints[0]
 			}
 		}
 
-		result = append(result, serviceResponse{
-			Name:   name,
-			Status: status,
-			Health: health,
-		})
+		result = append(result, resp)
 	}
 
 	return result
 }
 
+// xatuServiceHealth maps a container's state and healthcheck to the
+// dashboard's health values.
+func xatuServiceHealth(svc compose.ServiceStatus) string {
+	switch {
+	case svc.Failed() || svc.Health == compose.HealthUnhealthy:
+		return "unhealthy"
+	case svc.Ready() && svc.State == compose.StateRunning:
+		return "healthy"
+	case svc.State == compose.StateRunning:
+		// Running but its healthcheck has not passed yet.
+		return "degraded"
+	default:
+		return "unknown"
+	}
+}
+
 // GetConfigSummary returns a summary for the sidebar.
 func (b *xatuBackend) GetConfigSummary() any {
 	return xatuConfigResponse{
@@ -168,7 +210,32 @@ func (b *xatuBackend) Up(ctx context.Context, progress ProgressFunc) error {
 		progress("compose_up", "Starting containers...")
 	}
 
-	return b.runner.Up(ctx, false)
+	if err := b.runner.Up(ctx, false); err != nil {
+		return err
+	}
+
+	// Phase 4: Wait for healthchecks.
+	if progress != nil {
+		progress("wait_healthy", "Waiting for services to become healthy...")
+	}
+
+	_, err := b.runner.WaitHealthy(ctx, xatuWaitTimeout, func(statuses []compose.ServiceStatus) {
+		if progress == nil {
+			return
+		}
+
+		ready := 0
+
+		for _, st := range statuses {
+			if st.Ready() {
+				ready++
+			}
+		}
+
+		progress("wait_healthy", fmt.Sprintf("Waiting for services to become healthy (%d/%d)...", ready, len(statuses)))
+	})
+
+	return err
 }
 
 // Down stops the docker-compose stack.
@@ -2,6 +2,7 @@ package cc
 
 import (
 	"embed"
+	"fmt"
 	"io/fs"
 	"net/http"
 	"strings"
@@ -37,6 +38,16 @@ func (h *spaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.fs.ServeHTTP(w, r)
 }
 
+// CheckFrontend verifies the Command Center frontend was embedded at build
+// time.
+func CheckFrontend() error {
+	if _, err := fs.Stat(frontendFS, "frontend/dist/index.html"); err != nil {
+		return fmt.Errorf("command center frontend not embedded: %w", err)
+	}
+
+	return nil
+}
+
 func newSPAHandler() *spaHandler {
 	sub, err := fs.Sub(frontendFS, "frontend/dist")
 	if err != nil {
@@ -15,6 +15,9 @@ const defaultCapabilities: StackCapabilities = {
   hasGitRepos: true,
   hasRegenerate: true,
   hasRebuild: true,
+  hasIncidents: true,
+  hasNetworks: true,
+  hasEnvironment: true,
 };
 
 const STACK_STORAGE_KEY = 'xcli:active-stack';
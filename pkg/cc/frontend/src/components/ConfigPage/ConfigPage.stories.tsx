@@ -34,6 +34,9 @@ const meta = {
       hasGitRepos: true,
       hasRegenerate: true,
       hasRebuild: true,
+      hasIncidents: true,
+      hasNetworks: true,
+      hasEnvironment: true,
     },
   },
 } satisfies Meta<typeof ConfigPage>;
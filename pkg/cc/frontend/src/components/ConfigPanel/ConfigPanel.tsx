@@ -4,14 +4,17 @@ import Spinner from '@/components/Spinner';
 interface ConfigPanelProps {
   config: ConfigInfo | null;
   services: ServiceInfo[];
+  /** Starts or stops a single network; network chips are read-only without it. */
+  onNetworkAction?: (network: string, action: 'start' | 'stop') => void;
 }
 
-export default function ConfigPanel({ config, services }: ConfigPanelProps) {
+export default function ConfigPanel({ config, services, onNetworkAction }: ConfigPanelProps) {
   if (!config || !config.networks) {
     return <Spinner />;
   }
 
   const enabledNetworks = config.networks.filter(n => n.enabled);
+  const runningNetworks = enabledNetworks.filter(n => !n.stopped);
 
   // Build a lookup from service name to its data for port/URL resolution.
   const svcMap = new Map(services.map(s => [s.name, s]));
@@ -39,11 +42,34 @@ export default function ConfigPanel({ config, services }: ConfigPanelProps) {
       {/* Mode + Networks inline */}
       <div className="flex items-center gap-2">
         <span className="rounded-xs bg-accent/20 px-2 py-0.5 font-medium text-accent-light">{config.mode}</span>
-        {enabledNetworks.map(n => (
-          <span key={n.name} className="rounded-xs bg-success/20 px-2 py-0.5 text-success">
-            {n.name}
-          </span>
-        ))}
+        {config.profile && (
+          <span className="rounded-xs bg-accent/10 px-2 py-0.5 text-accent-light">profile: {config.profile}</span>
+        )}
+        {enabledNetworks.map(n => {
+          const chipClass = n.stopped
+            ? 'rounded-xs bg-border/40 px-2 py-0.5 text-text-disabled line-through'
+            : 'rounded-xs bg-success/20 px-2 py-0.5 text-success';
+
+          if (!onNetworkAction) {
+            return (
+              <span key={n.name} className={chipClass}>
+                {n.name}
+              </span>
+            );
+          }
+
+          return (
+            <button
+              key={n.name}
+              type="button"
+              title={n.stopped ? `Start ${n.name}` : `Stop ${n.name}`}
+              onClick={() => onNetworkAction(n.name, n.stopped ? 'start' : 'stop')}
+              className={`${chipClass} cursor-pointer transition-opacity hover:opacity-75`}
+            >
+              {n.name}
+            </button>
+          );
+        })}
       </div>
 
       {/* Services — per-network ports */}
@@ -55,27 +81,27 @@ export default function ConfigPanel({ config, services }: ConfigPanelProps) {
             port={getPort('lab-frontend', config.ports.labFrontend)}
             href={getUrl('lab-frontend', `http://localhost:${config.ports.labFrontend}`)}
           />
-          {enabledNetworks.map(n => {
+          {runningNetworks.map(n => {
             const name = `cbt-api-${n.name}`;
             const fallbackPort = config.ports.cbtApiBase + n.portOffset;
             const port = getPort(name, fallbackPort);
             return (
               <PortRow
                 key={name}
-                label={`CBT API${enabledNetworks.length > 1 ? ` (${n.name})` : ''}`}
+                label={`CBT API${runningNetworks.length > 1 ? ` (${n.name})` : ''}`}
                 port={port}
                 href={`http://localhost:${port}/docs`}
               />
             );
           })}
-          {enabledNetworks.map(n => {
+          {runningNetworks.map(n => {
             const name = `cbt-${n.name}`;
             const fallbackPort = config.ports.cbtFrontendBase + n.portOffset;
             const port = getPort(name, fallbackPort);
             return (
               <PortRow
                 key={`cbt-fe-${n.name}`}
-                label={`CBT Frontend${enabledNetworks.length > 1 ? ` (${n.name})` : ''}`}
+                label={`CBT Frontend${runningNetworks.length > 1 ? ` (${n.name})` : ''}`}
                 port={port}
                 href={`http://localhost:${port}`}
               />
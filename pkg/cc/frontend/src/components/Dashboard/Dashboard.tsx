@@ -6,15 +6,19 @@ import type {
   RepoInfo,
   StatusResponse,
   GitResponse,
+  EnvironmentResponse,
   HealthStatus,
   StackStatus,
   StackProgressEvent,
   DiagnosisReport,
   DiagnosisTurn,
+  DiagnoseToolCall,
+  DiagnoseToolCallStatus,
   AIProviderInfo,
   CBTOverridesState,
   StackCapabilities,
   XatuConfigResponse,
+  Incident,
 } from '@/types';
 import { useSSE } from '@/hooks/useSSE';
 import { useAPI } from '@/hooks/useAPI';
@@ -23,6 +27,8 @@ import ServiceCard from '@/components/ServiceCard';
 import ConfigPanel from '@/components/ConfigPanel';
 import XatuConfigPanel from '@/components/XatuConfigPanel';
 import GitStatus from '@/components/GitStatus';
+import Environment from '@/components/Environment';
+import Incidents from '@/components/Incidents';
 import CBTOverridesGlance from '@/components/CBTOverridesGlance';
 import SidebarSection from '@/components/SidebarSection';
 import LogViewer from '@/components/LogViewer';
@@ -41,6 +47,7 @@ import { Group, Panel, Separator } from 'react-resizable-panels';
 import type { PanelImperativeHandle, PanelSize } from 'react-resizable-panels';
 
 const MAX_LOGS = 100_000;
+const MAX_INCIDENTS = 50;
 
 function bootPhasesFor(isLab: boolean) {
   return isLab ? BOOT_PHASES : XATU_BOOT_PHASES;
@@ -143,14 +150,23 @@ export default function Dashboard({
   otherRunningStack,
   onStackStatusChange,
 }: DashboardProps) {
-  const { fetchJSON, postJSON, postDiagnoseStart, postDiagnoseMessage, postDiagnoseInterrupt, deleteDiagnoseSession } =
-    useAPI(stack);
+  const {
+    fetchJSON,
+    postJSON,
+    postDiagnoseStart,
+    postDiagnoseMessage,
+    postDiagnoseInterrupt,
+    postDiagnoseTool,
+    deleteDiagnoseSession,
+  } = useAPI(stack);
   const { notify, enabled: notificationsEnabled, toggle: toggleNotifications } = useNotifications();
   const [services, setServices] = useState<ServiceInfo[]>([]);
   const [config, setConfig] = useState<ConfigInfo | null>(null);
   const [xatuConfig, setXatuConfig] = useState<XatuConfigResponse | null>(null);
   const [repos, setRepos] = useState<RepoInfo[]>([]);
+  const [environment, setEnvironment] = useState<EnvironmentResponse | null>(null);
   const [overrides, setOverrides] = useState<CBTOverridesState | null>(null);
+  const [incidents, setIncidents] = useState<Incident[]>([]);
   const [openTabs, setOpenTabs] = useState<string[]>([]);
   const [activeTab, setActiveTab] = useState<string | null>(null);
   const logsRef = useRef<LogLine[]>([]);
@@ -177,6 +193,7 @@ export default function Dashboard({
   const [thinkingText, setThinkingText] = useState('');
   const [answerText, setAnswerText] = useState('');
   const [activityText, setActivityText] = useState('');
+  const [toolCalls, setToolCalls] = useState<DiagnoseToolCall[]>([]);
   const [completedTurns, setCompletedTurns] = useState<DiagnosisTurn[]>([]);
   const [currentTurnPrompt, setCurrentTurnPrompt] = useState<string | undefined>(undefined);
   const diagnoseAvailable = providers.some(provider => provider.available);
@@ -188,6 +205,9 @@ export default function Dashboard({
   const isLabStack = capabilities.hasServiceConfigs;
   const showCbtOverrides = capabilities.hasCbtOverrides;
   const showGitRepos = capabilities.hasGitRepos;
+  const showEnvironment = capabilities.hasEnvironment;
+  const showIncidents = capabilities.hasIncidents;
+  const canControlNetworks = capabilities.hasNetworks && stackStatus === 'running';
 
   useEffect(() => {
     diagnoseSessionRef.current = diagnoseSessionId;
@@ -258,6 +278,10 @@ export default function Dashboard({
         .catch(console.error);
     }
 
+    if (showEnvironment) {
+      fetchJSON<EnvironmentResponse>('/environment').then(setEnvironment).catch(console.error);
+    }
+
     fetchJSON<LogLine[]>('/logs')
       .then(data => {
         if (data.length > 0) {
@@ -288,7 +312,18 @@ export default function Dashboard({
     if (!isLabStack) {
       fetchJSON<XatuConfigResponse>('/config').then(setXatuConfig).catch(console.error);
     }
-  }, [fetchJSON, showGitRepos, showCbtOverrides, isLabStack]);
+
+    if (showIncidents) {
+      fetchJSON<Incident[]>('/incidents').then(setIncidents).catch(console.error);
+    }
+  }, [fetchJSON, showGitRepos, showEnvironment, showCbtOverrides, isLabStack, showIncidents]);
+
+  const recheckEnvironment = useCallback(() => {
+    setEnvironment(null);
+    fetchJSON<EnvironmentResponse>('/environment?refresh=true').then(setEnvironment).catch(console.error);
+  }, [fetchJSON]);
+
+  const environmentIssues = environment?.tools.filter(tool => tool.status !== 'ok').length ?? 0;
 
   // Initial data load + periodic git refresh
   useEffect(() => {
@@ -384,6 +419,14 @@ export default function Dashboard({
 
           break;
         }
+        case 'incident': {
+          const incident = data as Incident;
+          setIncidents(prev => [incident, ...prev.filter(i => i.id !== incident.id)].slice(0, MAX_INCIDENTS));
+          notify(`xcli: ${incident.service} failed`, {
+            body: incident.diagnosis?.hint ?? (incident.trigger === 'exit' ? 'Process exited' : 'Service is unhealthy'),
+          });
+          break;
+        }
         case 'diagnose_stream': {
           const evt = data as {
             sessionId?: string;
@@ -452,6 +495,56 @@ export default function Dashboard({
           setDiagnosing(false);
           break;
         }
+        case 'diagnose_tool_request': {
+          const evt = data as {
+            sessionId?: string;
+            requestId?: string;
+            toolCallId?: string;
+            tool?: string;
+            args?: Record<string, unknown>;
+            autoApproved?: boolean;
+          };
+          if (!evt.sessionId || !evt.toolCallId) break;
+          if (diagnoseSessionRef.current && evt.sessionId !== diagnoseSessionRef.current) break;
+          if (!evt.requestId || evt.requestId !== diagnoseRequestRef.current) break;
+
+          const call: DiagnoseToolCall = {
+            id: evt.toolCallId,
+            tool: evt.tool ?? 'unknown',
+            args: evt.args,
+            status: evt.autoApproved ? 'running' : 'pending',
+          };
+          setToolCalls(prev => [...prev.filter(c => c.id !== call.id), call]);
+          break;
+        }
+        case 'diagnose_tool_result': {
+          const evt = data as {
+            sessionId?: string;
+            requestId?: string;
+            toolCallId?: string;
+            tool?: string;
+            approved?: boolean;
+            output?: string;
+            error?: string;
+          };
+          if (!evt.sessionId || !evt.toolCallId) break;
+          if (diagnoseSessionRef.current && evt.sessionId !== diagnoseSessionRef.current) break;
+          if (!evt.requestId || evt.requestId !== diagnoseRequestRef.current) break;
+
+          const result: DiagnoseToolCall = {
+            id: evt.toolCallId,
+            tool: evt.tool ?? 'unknown',
+            status: evt.approved ? 'done' : 'denied',
+            output: evt.output,
+            error: evt.error,
+          };
+          setToolCalls(prev => {
+            const existing = prev.find(c => c.id === result.id);
+            if (!existing) return [...prev, result];
+            return prev.map(c => (c.id === result.id ? { ...c, ...result } : c));
+          });
+          break;
+        }
         case 'diagnose_interrupted': {
           const evt = data as {
             sessionId?: string;
@@ -535,6 +628,19 @@ export default function Dashboard({
     postJSON<{ status: string }>('/stack/cancel').catch(console.error);
   }, [postJSON]);
 
+  const handleNetworkAction = useCallback(
+    (network: string, action: 'start' | 'stop') => {
+      postJSON<{ status: string }>(`/networks/${encodeURIComponent(network)}/${action}`)
+        .then(() => fetchJSON<StatusResponse>('/status'))
+        .then(data => {
+          setServices(data.services ?? []);
+          setConfig(data.config);
+        })
+        .catch(console.error);
+    },
+    [postJSON, fetchJSON]
+  );
+
   const handleStackAction = useCallback(() => {
     if (!stackStatus || stackStatus === 'starting' || stackStatus === 'stopping') return;
 
@@ -571,6 +677,7 @@ export default function Dashboard({
       setThinkingText('');
       setAnswerText('');
       setActivityText('');
+      setToolCalls([]);
       setCompletedTurns([]);
       setCurrentTurnPrompt(undefined);
 
@@ -597,7 +704,7 @@ export default function Dashboard({
       setDiagnoseRequestId(requestId);
       setCompletedTurns(prev => [
         ...prev,
-        { prompt: currentTurnPrompt, thinking: thinkingText, activity: activityText, answer: answerText },
+        { prompt: currentTurnPrompt, thinking: thinkingText, activity: activityText, answer: answerText, toolCalls },
       ]);
       setCurrentTurnPrompt(prompt);
       setDiagnosing(true);
@@ -606,6 +713,7 @@ export default function Dashboard({
       setThinkingText('');
       setAnswerText('');
       setActivityText('');
+      setToolCalls([]);
 
       postDiagnoseMessage<{ sessionId: string }>(diagnoseService, {
         sessionId: diagnoseSessionId,
@@ -626,9 +734,27 @@ export default function Dashboard({
       postDiagnoseMessage,
       selectedProvider,
       thinkingText,
+      toolCalls,
     ]
   );
 
+  const handleDiagnoseToolDecision = useCallback(
+    (toolCallId: string, approve: boolean, always: boolean) => {
+      if (!diagnoseService || !diagnoseSessionId) return;
+
+      const status: DiagnoseToolCallStatus = approve ? 'running' : 'denied';
+      setToolCalls(prev => prev.map(c => (c.id === toolCallId ? { ...c, status } : c)));
+
+      postDiagnoseTool<{ approved: boolean }>(diagnoseService, {
+        sessionId: diagnoseSessionId,
+        toolCallId,
+        approve,
+        always,
+      }).catch(err => setDiagnosisError(err instanceof Error ? err.message : String(err)));
+    },
+    [diagnoseService, diagnoseSessionId, postDiagnoseTool]
+  );
+
   const handleDiagnoseInterrupt = useCallback(() => {
     if (!diagnoseService || !diagnoseSessionId) return;
 
@@ -652,6 +778,7 @@ export default function Dashboard({
     setThinkingText('');
     setAnswerText('');
     setActivityText('');
+    setToolCalls([]);
     setCompletedTurns([]);
     setCurrentTurnPrompt(undefined);
   }, [deleteDiagnoseSession, diagnoseService, diagnoseSessionId]);
@@ -732,7 +859,15 @@ export default function Dashboard({
         storageKey="xcli:sidebar:config"
         action={onNavigateConfig ? { label: 'Manage', onClick: onNavigateConfig } : undefined}
       >
-        {isLabStack ? <ConfigPanel config={config} services={services} /> : <XatuConfigPanel config={xatuConfig} />}
+        {isLabStack ? (
+          <ConfigPanel
+            config={config}
+            services={services}
+            onNetworkAction={canControlNetworks ? handleNetworkAction : undefined}
+          />
+        ) : (
+          <XatuConfigPanel config={xatuConfig} />
+        )}
       </SidebarSection>
       {showCbtOverrides && (
         <SidebarSection
@@ -743,11 +878,30 @@ export default function Dashboard({
           <CBTOverridesGlance overrides={overrides} />
         </SidebarSection>
       )}
+      {showIncidents && (
+        <SidebarSection
+          title={incidents.length > 0 ? `Incidents (${incidents.length})` : 'Incidents'}
+          storageKey="xcli:sidebar:incidents"
+          defaultOpen={false}
+        >
+          <Incidents incidents={incidents} />
+        </SidebarSection>
+      )}
       {showGitRepos && (
         <SidebarSection title="Git Status" storageKey="xcli:sidebar:git" defaultOpen={false}>
           <GitStatus repos={repos} />
         </SidebarSection>
       )}
+      {showEnvironment && (
+        <SidebarSection
+          title={environmentIssues > 0 ? `Environment (${environmentIssues})` : 'Environment'}
+          storageKey="xcli:sidebar:environment"
+          defaultOpen={false}
+          action={{ label: 'Recheck', onClick: recheckEnvironment }}
+        >
+          <Environment environment={environment} />
+        </SidebarSection>
+      )}
     </div>
   );
 
@@ -966,6 +1120,7 @@ export default function Dashboard({
           thinkingText={thinkingText}
           activityText={activityText}
           answerText={answerText}
+          toolCalls={toolCalls}
           completedTurns={completedTurns}
           currentTurnPrompt={currentTurnPrompt}
           diagnosis={diagnosis}
@@ -974,6 +1129,7 @@ export default function Dashboard({
           canInterrupt={!!selectedProviderInfo?.capabilities.interrupt}
           canInteract={!!selectedProviderInfo?.capabilities.sessions}
           onInterrupt={handleDiagnoseInterrupt}
+          onToolDecision={handleDiagnoseToolDecision}
           onSendFollowUp={handleDiagnoseFollowUp}
           onClose={handleCloseDiagnosis}
           onRetry={() => {
@@ -1,5 +1,5 @@
 import { useCallback, useEffect, useMemo, useRef, useState } from 'react';
-import type { AIProviderInfo, DiagnosisReport, DiagnosisTurn } from '@/types';
+import type { AIProviderInfo, DiagnoseToolCall, DiagnosisReport, DiagnosisTurn } from '@/types';
 import Spinner from '@/components/Spinner';
 import Markdown from '@/components/Markdown';
 
@@ -14,12 +14,14 @@ interface DiagnosisPanelProps {
   thinkingText: string;
   activityText: string;
   answerText: string;
+  toolCalls?: DiagnoseToolCall[];
   diagnosis: DiagnosisReport | null;
   error: string | null;
   loading: boolean;
   canInterrupt: boolean;
   canInteract: boolean;
   onInterrupt: () => void;
+  onToolDecision?: (toolCallId: string, approve: boolean, always: boolean) => void;
   onSendFollowUp: (prompt: string) => void;
   onClose: () => void;
   onRetry: () => void;
@@ -36,12 +38,14 @@ export default function DiagnosisPanel({
   thinkingText,
   activityText,
   answerText,
+  toolCalls = [],
   diagnosis,
   error,
   loading,
   canInterrupt,
   canInteract,
   onInterrupt,
+  onToolDecision,
   onSendFollowUp,
   onClose,
   onRetry,
@@ -78,9 +82,9 @@ export default function DiagnosisPanel({
     const el = streamRef.current;
     if (!el) return;
     el.scrollTop = el.scrollHeight;
-  }, [thinkingText, activityText, answerText]);
+  }, [thinkingText, activityText, answerText, toolCalls]);
 
-  const hasCurrentContent = !!(thinkingText || activityText.trim() || answerText.trim());
+  const hasCurrentContent = !!(thinkingText || activityText.trim() || answerText.trim() || toolCalls.length > 0);
   const isEmpty = completedTurns.length === 0 && !hasCurrentContent;
 
   return (
@@ -186,6 +190,8 @@ export default function DiagnosisPanel({
                   </Markdown>
                 </div>
               )}
+
+              {toolCalls.length > 0 && <ToolCalls calls={toolCalls} onDecision={onToolDecision} />}
             </div>
 
             {error && (
@@ -334,6 +340,66 @@ function TurnOutput({ turn }: { turn: DiagnosisTurn }) {
           <Markdown className="rounded-xs border border-border/50 bg-surface-light px-3 py-2">{turn.answer}</Markdown>
         </div>
       )}
+
+      {turn.toolCalls && turn.toolCalls.length > 0 && <ToolCalls calls={turn.toolCalls} />}
+    </div>
+  );
+}
+
+function ToolCalls({
+  calls,
+  onDecision,
+}: {
+  calls: DiagnoseToolCall[];
+  onDecision?: (toolCallId: string, approve: boolean, always: boolean) => void;
+}) {
+  return (
+    <div className="mt-3 flex flex-col">
+      <h4 className="mb-1 text-xs/4 font-semibold tracking-wider text-text-muted uppercase">Tool Calls</h4>
+      <div className="flex flex-col gap-2">
+        {calls.map(call => (
+          <div key={call.id} className="rounded-xs border border-border/50 bg-surface-light px-3 py-2">
+            <div className="flex flex-wrap items-center gap-2">
+              <span className="font-mono text-xs/5 text-accent-light">{call.tool}</span>
+              {call.args && Object.keys(call.args).length > 0 && (
+                <span className="font-mono text-xs/5 break-all text-text-muted">{JSON.stringify(call.args)}</span>
+              )}
+              <span className="ml-auto text-[11px]/4 text-text-muted uppercase">{call.status}</span>
+            </div>
+
+            {call.status === 'pending' && onDecision && (
+              <div className="mt-2 flex items-center gap-2">
+                <button
+                  onClick={() => onDecision(call.id, true, false)}
+                  className="rounded-xs bg-accent/20 px-3 py-1 text-xs/4 font-medium text-accent-light transition-colors hover:bg-accent/30"
+                >
+                  Approve
+                </button>
+                <button
+                  onClick={() => onDecision(call.id, true, true)}
+                  className="rounded-xs bg-accent/10 px-3 py-1 text-xs/4 font-medium text-accent-light transition-colors hover:bg-accent/20"
+                >
+                  Always allow {call.tool}
+                </button>
+                <button
+                  onClick={() => onDecision(call.id, false, false)}
+                  className="rounded-xs border border-error/30 bg-error/10 px-3 py-1 text-xs/4 font-medium text-error transition-colors hover:bg-error/20"
+                >
+                  Deny
+                </button>
+              </div>
+            )}
+
+            {call.error && <p className="mt-2 font-mono text-xs/5 text-error/80">{call.error}</p>}
+
+            {call.output && (
+              <pre className="mt-2 max-h-48 overflow-auto font-mono text-xs/5 whitespace-pre-wrap text-text-muted">
+                {call.output}
+              </pre>
+            )}
+          </div>
+        ))}
+      </div>
     </div>
   );
 }
@@ -0,0 +1,46 @@
+import type { Meta, StoryObj } from '@storybook/react-vite';
+import Environment from './Environment';
+import { mockEnvironmentResponse } from '@/stories/fixtures';
+
+const meta = {
+  title: 'Components/Environment',
+  component: Environment,
+  decorators: [
+    Story => (
+      <div className="bg-bg p-8">
+        <Story />
+      </div>
+    ),
+  ],
+} satisfies Meta<typeof Environment>;
+
+export default meta;
+type Story = StoryObj<typeof meta>;
+
+export const WithIssues: Story = {
+  args: {
+    environment: mockEnvironmentResponse,
+  },
+};
+
+export const Ready: Story = {
+  args: {
+    environment: {
+      ...mockEnvironmentResponse,
+      tools: mockEnvironmentResponse.tools.filter(tool => tool.status === 'ok'),
+    },
+  },
+};
+
+export const WithReadError: Story = {
+  args: {
+    environment: {
+      ...mockEnvironmentResponse,
+      error: 'lab: invalid package.json: unexpected end of JSON input',
+    },
+  },
+};
+
+export const Loading: Story = {
+  args: { environment: null },
+};
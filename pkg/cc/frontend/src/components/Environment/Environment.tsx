@@ -0,0 +1,38 @@
+import type { EnvironmentResponse, ToolStatus } from '@/types';
+import Spinner from '@/components/Spinner';
+
+interface EnvironmentProps {
+  environment: EnvironmentResponse | null;
+}
+
+const statusClass: Record<ToolStatus, string> = {
+  ok: 'text-success',
+  missing: 'text-error',
+  outdated: 'text-warning',
+  unknown: 'text-warning',
+};
+
+export default function Environment({ environment }: EnvironmentProps) {
+  if (!environment) return <Spinner />;
+
+  return (
+    <div className="flex flex-col gap-2">
+      {environment.tools.map(tool => (
+        <div key={tool.tool} className="rounded-xs bg-surface px-3 py-2 text-xs/4">
+          <div className="flex items-center justify-between">
+            <span className="font-medium text-text-secondary">{tool.tool}</span>
+            <span className="font-mono text-text-muted">{tool.installed || 'not installed'}</span>
+          </div>
+          <div className="mt-1 flex gap-2">
+            <span className={statusClass[tool.status]}>{tool.status}</span>
+            {tool.required && <span className="text-text-disabled">requires {tool.required}+</span>}
+          </div>
+          {tool.remediation && <div className="mt-1 break-words text-text-muted">{tool.remediation}</div>}
+        </div>
+      ))}
+      {environment.error && (
+        <div className="rounded-xs bg-surface px-3 py-2 text-xs/4 text-error">{environment.error}</div>
+      )}
+    </div>
+  );
+}
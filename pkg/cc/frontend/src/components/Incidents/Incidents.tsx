@@ -0,0 +1,74 @@
+import { useState } from 'react';
+import type { Incident } from '@/types';
+
+interface IncidentsProps {
+  incidents: Incident[];
+}
+
+function incidentSummary(incident: Incident): string {
+  if (incident.trigger === 'exit') {
+    return incident.exitCode !== undefined ? `exited with code ${incident.exitCode}` : 'exited unexpectedly';
+  }
+
+  return incident.error ? `unhealthy: ${incident.error}` : 'unhealthy';
+}
+
+export default function Incidents({ incidents }: IncidentsProps) {
+  const [expanded, setExpanded] = useState<string | null>(null);
+
+  if (incidents.length === 0) {
+    return <div className="text-xs/4 text-text-muted">No runtime incidents recorded</div>;
+  }
+
+  return (
+    <div className="flex flex-col gap-2">
+      {incidents.map(incident => {
+        const open = expanded === incident.id;
+        const boundPorts = (incident.ports ?? []).filter(p => p.bound);
+        const downInfra = Object.entries(incident.infra ?? {})
+          .filter(([, running]) => !running)
+          .map(([name]) => name);
+
+        return (
+          <div key={incident.id} className="rounded-xs bg-surface px-3 py-2 text-xs/4">
+            <button
+              onClick={() => setExpanded(open ? null : incident.id)}
+              className="flex w-full items-center justify-between text-left"
+            >
+              <span className="font-medium text-text-secondary">{incident.service}</span>
+              <span className="font-mono text-text-muted">{new Date(incident.time).toLocaleString()}</span>
+            </button>
+            <div className="mt-1 text-error">{incidentSummary(incident)}</div>
+            {incident.diagnosis && (
+              <div className="mt-1 text-warning">
+                {incident.diagnosis.hint}
+                <span className="ml-1 text-text-disabled">({incident.diagnosis.patternName})</span>
+              </div>
+            )}
+            {open && (
+              <div className="mt-2 flex flex-col gap-2 border-t border-border/50 pt-2">
+                {incident.diagnosis?.suggestion && (
+                  <pre className="whitespace-pre-wrap text-text-tertiary">{incident.diagnosis.suggestion}</pre>
+                )}
+                {boundPorts.length > 0 && (
+                  <div className="text-text-tertiary">
+                    Ports bound:{' '}
+                    {boundPorts.map(p => `${p.port}${p.process ? ` (${p.process} ${p.pid})` : ''}`).join(', ')}
+                  </div>
+                )}
+                {downInfra.length > 0 && (
+                  <div className="text-warning">Infrastructure down: {downInfra.join(', ')}</div>
+                )}
+                {incident.logLines && incident.logLines.length > 0 && (
+                  <pre className="max-h-64 overflow-auto rounded-xs bg-surface-light p-2 font-mono text-text-muted">
+                    {incident.logLines.join('\n')}
+                  </pre>
+                )}
+              </div>
+            )}
+          </div>
+        );
+      })}
+    </div>
+  );
+}
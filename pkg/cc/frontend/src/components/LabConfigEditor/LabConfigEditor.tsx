@@ -111,6 +111,11 @@ export default function LabConfigEditor({ onToast, onNavigateDashboard, stack }:
   }
 
   const isHybrid = config.mode === 'hybrid';
+  const modeDescriptions: Record<string, string> = {
+    local: 'Fully local development environment',
+    hybrid: 'External Xatu ClickHouse with local CBT processing',
+    'xatu-local': 'Xatu ClickHouse from the running xatu stack with local CBT processing',
+  };
 
   return (
     <div className="mx-auto max-w-4xl">
@@ -120,12 +125,18 @@ export default function LabConfigEditor({ onToast, onNavigateDashboard, stack }:
           {/* Mode */}
           <Section title="Mode" icon={modeIcon}>
             <div className="flex gap-2">
-              {['local', 'hybrid'].map(m => (
+              {['local', 'hybrid', 'xatu-local'].map(m => (
                 <button
                   key={m}
                   onClick={() => {
                     const updated = { ...config, mode: m };
-                    const xatuMode = m === 'local' ? 'local' : 'external';
+                    // xatu-local discovers its Xatu ClickHouse at startup and keeps the hybrid settings.
+                    const xatuMode =
+                      m === 'xatu-local'
+                        ? config.infrastructure?.ClickHouse?.Xatu?.Mode
+                        : m === 'local'
+                          ? 'local'
+                          : 'external';
 
                     updated.infrastructure = {
                       ...config.infrastructure,
@@ -147,7 +158,7 @@ export default function LabConfigEditor({ onToast, onNavigateDashboard, stack }:
               ))}
             </div>
             <p className="mt-3 text-xs/4 text-text-disabled">
-              {isHybrid ? 'External Xatu ClickHouse with local CBT processing' : 'Fully local development environment'}
+              {modeDescriptions[config.mode] ?? modeDescriptions.local}
             </p>
           </Section>
 
@@ -218,7 +229,13 @@ export default function LabConfigEditor({ onToast, onNavigateDashboard, stack }:
           <div className="grid grid-cols-2 gap-4">
             <ClusterCard
               label="Xatu"
-              description={isHybrid ? 'External production data' : 'Local instance'}
+              description={
+                config.mode === 'xatu-local'
+                  ? 'Discovered from the xatu stack'
+                  : isHybrid
+                    ? 'External production data'
+                    : 'Local instance'
+              }
               cluster={config.infrastructure?.ClickHouse?.Xatu}
               onChange={xatu =>
                 setConfig({
@@ -53,3 +53,18 @@ export const Selected: Story = {
 export const DockerService: Story = {
   args: { service: mockServices[4] },
 };
+
+export const WithUsage: Story = {
+  args: {
+    service: {
+      ...mockServices[0],
+      usage: {
+        cpuPercent: 42.5,
+        memoryBytes: 734003200,
+        memoryLimitBytes: 2147483648,
+        openFiles: 87,
+        limitMode: 'cgroup',
+      },
+    },
+  },
+};
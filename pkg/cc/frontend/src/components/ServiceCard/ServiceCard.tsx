@@ -144,10 +144,17 @@ export default function ServiceCard({
           </svg>
         </div>
 
-        {/* Meta row: uptime, PID */}
+        {/* Meta row: uptime, PID, resource usage */}
         <div className="mt-1 flex items-center gap-2 pl-4 text-xs/4 text-text-disabled">
           {service.uptime && <span>{service.uptime}</span>}
           {service.pid > 0 && <span>PID {service.pid}</span>}
+          {!!service.restarts && <span className="text-warning">{service.restarts} restarts</span>}
+          {service.usage && (
+            <span title={service.usage.limitMode ? `Limits enforced via ${service.usage.limitMode}` : undefined}>
+              {service.usage.cpuPercent.toFixed(1)}% · {formatBytes(service.usage.memoryBytes)}
+              {service.usage.memoryLimitBytes ? `/${formatBytes(service.usage.memoryLimitBytes)}` : ''}
+            </span>
+          )}
         </div>
 
         {/* Actions — visible on hover */}
@@ -172,6 +179,17 @@ export default function ServiceCard({
   );
 }
 
+function formatBytes(bytes: number): string {
+  const units = ['B', 'KiB', 'MiB', 'GiB', 'TiB'];
+  let value = bytes;
+  let unit = 0;
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024;
+    unit++;
+  }
+  return `${value.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`;
+}
+
 function ActionBtn({ label, onClick }: { label: string; onClick: () => void }) {
   return (
     <button
@@ -20,6 +20,7 @@ export const XATU_BOOT_PHASES = [
   { id: 'validate_config', label: 'Validate Configuration' },
   { id: 'pull_images', label: 'Pull Docker Images' },
   { id: 'compose_up', label: 'Start Containers' },
+  { id: 'wait_healthy', label: 'Wait For Healthchecks' },
 ];
 
 export const XATU_STOP_PHASES = [{ id: 'compose_down', label: 'Stop Containers' }];
@@ -147,6 +147,17 @@ export function useAPI(stack: string) {
     [requestJSON]
   );
 
+  const postDiagnoseTool = useCallback(
+    async <T>(service: string, body: unknown): Promise<T> => {
+      return requestJSON<T>(`/services/${encodeURIComponent(service)}/diagnose/tool`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(body),
+      });
+    },
+    [requestJSON]
+  );
+
   const deleteDiagnoseSession = useCallback(
     async <T>(service: string, sessionId: string): Promise<T> => {
       return requestJSON<T>(
@@ -171,6 +182,7 @@ export function useAPI(stack: string) {
     postDiagnoseStart,
     postDiagnoseMessage,
     postDiagnoseInterrupt,
+    postDiagnoseTool,
     deleteDiagnoseSession,
   };
 }
@@ -37,8 +37,11 @@ export function useSSE(handler: SSEHandler, onConnect: (() => void) | undefined,
     es.addEventListener('diagnose_stream', onEvent('diagnose_stream'));
     es.addEventListener('diagnose_result', onEvent('diagnose_result'));
     es.addEventListener('diagnose_error', onEvent('diagnose_error'));
+    es.addEventListener('diagnose_tool_request', onEvent('diagnose_tool_request'));
+    es.addEventListener('diagnose_tool_result', onEvent('diagnose_tool_result'));
     es.addEventListener('diagnose_interrupted', onEvent('diagnose_interrupted'));
     es.addEventListener('diagnose_session_closed', onEvent('diagnose_session_closed'));
+    es.addEventListener('incident', onEvent('incident'));
 
     es.onerror = () => {
       es.close();
@@ -9,7 +9,9 @@ import type {
   CBTOverridesState,
   StackStatus,
   GitResponse,
+  EnvironmentResponse,
   StatusResponse,
+  Incident,
 } from '@/types';
 
 // --- Services ---
@@ -330,8 +332,70 @@ export const mockGitResponse: GitResponse = {
   repos: mockRepos,
 };
 
+export const mockEnvironmentResponse: EnvironmentResponse = {
+  tools: [
+    {
+      tool: 'go',
+      installed: '1.23.4',
+      required: '1.24.0',
+      sources: ['cbt go.mod', 'cbt-api go.mod', 'lab-backend go.mod'],
+      requiredBy: ['cbt-api go.mod'],
+      status: 'outdated',
+      remediation:
+        'Upgrade go from 1.23.4 to 1.24.0 or newer (required by cbt-api go.mod): https://go.dev/dl/ (or: brew install go)',
+    },
+    {
+      tool: 'node',
+      installed: '22.11.0',
+      required: '22',
+      sources: ['lab .nvmrc', 'lab package.json'],
+      requiredBy: ['lab .nvmrc'],
+      status: 'ok',
+    },
+    { tool: 'pnpm', installed: '9.15.4', required: '9.15.4', sources: ['lab packageManager'], status: 'ok' },
+    { tool: 'docker', installed: '27.3.1', sources: ['lab'], status: 'ok' },
+    {
+      tool: 'protoc',
+      sources: ['cbt-api Makefile'],
+      status: 'missing',
+      remediation:
+        'Install protoc (required by cbt-api Makefile): brew install protobuf (Linux: apt install protobuf-compiler)',
+    },
+  ],
+  checkedAt: new Date().toISOString(),
+};
+
 export const mockStatusResponse: StatusResponse = {
   services: mockServices,
   config: mockConfig,
   timestamp: new Date().toISOString(),
 };
+
+// --- Incidents ---
+
+export const mockIncidents: Incident[] = [
+  {
+    id: 'a1b2c3d4',
+    service: 'cbt-sepolia',
+    trigger: 'exit',
+    time: new Date().toISOString(),
+    pid: 48213,
+    exitCode: 1,
+    error: 'exit status 1',
+    logLines: [
+      'level=info msg="starting cbt" network=sepolia',
+      'level=fatal msg="failed to start" error="listen tcp :9101: bind: address already in use"',
+    ],
+    ports: [
+      { port: 9101, bound: true, pid: 1234, process: 'cbt' },
+      { port: 8081, bound: false },
+    ],
+    infra: { ClickHouse: true, Redis: true },
+    diagnosis: {
+      patternName: 'port-already-in-use',
+      matched: true,
+      hint: 'A required port is already in use by another process.',
+      confidence: 'high',
+    },
+  },
+];
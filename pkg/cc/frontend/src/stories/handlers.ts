@@ -3,12 +3,14 @@ import {
   mockStatusResponse,
   mockStackStatus,
   mockGitResponse,
+  mockEnvironmentResponse,
   mockLogs,
   mockLabConfig,
   mockConfigFiles,
   mockConfigFileContent,
   mockCBTOverrides,
   mockConfig,
+  mockIncidents,
 } from './fixtures';
 
 // --- Status ---
@@ -33,6 +35,15 @@ export const gitHandlers = [
   }),
 ];
 
+// --- Environment ---
+
+export const environmentHandlers = [
+  http.get('/api/stacks/:stack/environment', async () => {
+    await delay(100);
+    return HttpResponse.json(mockEnvironmentResponse);
+  }),
+];
+
 // --- Logs ---
 
 export const logHandlers = [
@@ -180,17 +191,31 @@ export const aiHandlers = [
     await delay(50);
     return HttpResponse.json({ status: 'interrupted', sessionId: 'sess-storybook', requestId: 'req-storybook' });
   }),
+  http.post('/api/stacks/:stack/services/:name/diagnose/tool', async () => {
+    await delay(50);
+    return HttpResponse.json({ sessionId: 'sess-storybook', toolCallId: 'req-storybook-1-tool-1', approved: true });
+  }),
   http.delete('/api/stacks/:stack/services/:name/diagnose/session/:session', async () => {
     await delay(50);
     return HttpResponse.json({ status: 'closed', sessionId: 'sess-storybook' });
   }),
 ];
 
+// --- Incidents ---
+
+export const incidentHandlers = [
+  http.get('/api/stacks/:stack/incidents', async () => {
+    await delay(50);
+    return HttpResponse.json(mockIncidents);
+  }),
+];
+
 // --- All Handlers ---
 
 export const allHandlers = [
   ...statusHandlers,
   ...gitHandlers,
+  ...environmentHandlers,
   ...logHandlers,
   ...stackActionHandlers,
   ...serviceActionHandlers,
@@ -200,4 +225,5 @@ export const allHandlers = [
   ...configRegenerateHandlers,
   ...stacksHandlers,
   ...aiHandlers,
+  ...incidentHandlers,
 ];
@@ -7,6 +7,16 @@ export interface ServiceInfo {
   ports: number[];
   health: string;
   logFile: string;
+  restarts?: number;
+  usage?: ResourceUsage;
+}
+
+export interface ResourceUsage {
+  cpuPercent: number;
+  memoryBytes: number;
+  memoryLimitBytes?: number;
+  openFiles?: number;
+  limitMode?: string;
 }
 
 export interface HealthStatus {
@@ -30,6 +40,7 @@ export interface NetworkInfo {
   name: string;
   enabled: boolean;
   portOffset: number;
+  stopped?: boolean;
 }
 
 export interface ConfigInfo {
@@ -48,6 +59,7 @@ export interface ConfigInfo {
     grafana: number;
   };
   cfgPath: string;
+  profile?: string;
 }
 
 export interface StatusResponse {
@@ -74,6 +86,24 @@ export interface GitResponse {
   repos: RepoInfo[];
 }
 
+export type ToolStatus = 'ok' | 'missing' | 'outdated' | 'unknown';
+
+export interface ToolchainResult {
+  tool: string;
+  installed?: string;
+  required?: string;
+  sources: string[];
+  requiredBy?: string[];
+  status: ToolStatus;
+  remediation?: string;
+}
+
+export interface EnvironmentResponse {
+  tools: ToolchainResult[];
+  error?: string;
+  checkedAt: string;
+}
+
 export interface StackStatus {
   status: string;
   runningServices: number;
@@ -298,6 +328,9 @@ export interface StackCapabilities {
   hasGitRepos: boolean;
   hasRegenerate: boolean;
   hasRebuild: boolean;
+  hasIncidents: boolean;
+  hasNetworks: boolean;
+  hasEnvironment: boolean;
 }
 
 export interface StackInfo {
@@ -328,6 +361,60 @@ export interface DiagnosisTurn {
   thinking: string;
   activity: string;
   answer: string;
+  toolCalls?: DiagnoseToolCall[];
+}
+
+export type DiagnoseToolCallStatus = 'pending' | 'running' | 'denied' | 'done';
+
+export interface DiagnoseToolCall {
+  id: string;
+  tool: string;
+  args?: Record<string, unknown>;
+  status: DiagnoseToolCallStatus;
+  output?: string;
+  error?: string;
+}
+
+export type IncidentTrigger = 'exit' | 'unhealthy';
+
+export interface IncidentPort {
+  port: number;
+  bound: boolean;
+  pid?: number;
+  process?: string;
+}
+
+export interface IncidentConfig {
+  path: string;
+  content?: string;
+  truncated?: boolean;
+  error?: string;
+}
+
+export interface PatternDiagnosis {
+  patternName: string;
+  matched: boolean;
+  hint: string;
+  suggestion?: string;
+  confidence: string;
+  fixCommands?: string[];
+  source?: string;
+}
+
+export interface Incident {
+  id: string;
+  service: string;
+  trigger: IncidentTrigger;
+  time: string;
+  pid?: number;
+  exitCode?: number;
+  error?: string;
+  logFile?: string;
+  logLines?: string[];
+  configs?: IncidentConfig[];
+  ports?: IncidentPort[];
+  infra?: Record<string, boolean>;
+  diagnosis?: PatternDiagnosis;
 }
 
 export interface CBTOverridesState {
@@ -0,0 +1,94 @@
+package cc
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// metric describes one exported gauge.
+type metric struct {
+	name  string
+	help  string
+	value func(*usageInfo) float64
+}
+
+// usageMetrics are the gauges exported per service and container.
+var usageMetrics = []metric{
+	{
+		name:  "xcli_service_cpu_percent",
+		help:  "CPU use of the service; 100 means one full core.",
+		value: func(u *usageInfo) float64 { return u.CPUPercent },
+	},
+	{
+		name:  "xcli_service_memory_bytes",
+		help:  "Resident memory of the service.",
+		value: func(u *usageInfo) float64 { return float64(u.MemoryBytes) },
+	},
+	{
+		name:  "xcli_service_memory_limit_bytes",
+		help:  "Enforced memory cap of the service, 0 when unlimited.",
+		value: func(u *usageInfo) float64 { return float64(u.MemoryLimitBytes) },
+	},
+	{
+		name:  "xcli_service_open_files",
+		help:  "Open file descriptors of the service process group.",
+		value: func(u *usageInfo) float64 { return float64(u.OpenFiles) },
+	},
+}
+
+// handleMetrics exports the resource usage of every stack's services in the
+// Prometheus text format.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.stacksMu.RLock()
+	stacks := make([]*stackContext, 0, len(s.stacks))
+
+	for _, sc := range s.stacks {
+		stacks = append(stacks, sc)
+	}
+
+	s.stacksMu.RUnlock()
+
+	slices.SortFunc(stacks, func(a, b *stackContext) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	services := make(map[string][]serviceResponse, len(stacks))
+	for _, sc := range stacks {
+		services[sc.name] = sc.backend.GetServices(r.Context())
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	for _, m := range usageMetrics {
+		writeMetric(w, m, stacks, services)
+	}
+}
+
+// writeMetric writes one gauge with a sample per sampled service.
+func writeMetric(
+	w io.Writer,
+	m metric,
+	stacks []*stackContext,
+	services map[string][]serviceResponse,
+) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", m.name, m.help, m.name)
+
+	for _, sc := range stacks {
+		for _, svc := range services[sc.name] {
+			if svc.Usage == nil {
+				continue
+			}
+
+			fmt.Fprintf(w, "%s{stack=%s,service=%s} %s\n",
+				m.name,
+				strconv.Quote(sc.name),
+				strconv.Quote(svc.Name),
+				strconv.FormatFloat(m.value(svc.Usage), 'g', -1, 64),
+			)
+		}
+	}
+}
@@ -216,6 +216,7 @@ func (s *Server) handleGetStacks(
 func (s *Server) registerRoutes(mux *http.ServeMux) {
 	s.registerStackRoutes(mux, "/api/stacks/{stack}")
 	mux.HandleFunc("GET /api/stacks", s.handleGetStacks)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
 
 	// SPA - must be last (catch-all)
 	mux.Handle("/", newSPAHandler())
@@ -268,6 +269,10 @@ func (s *Server) registerStackRoutes(
 		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
 			sc.api.handleGetGit(w, r)
 		}))
+	mux.HandleFunc("GET "+prefix+"/environment",
+		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
+			sc.api.handleGetEnvironment(w, r)
+		}))
 
 	// Service actions
 	mux.HandleFunc("POST "+prefix+"/services/{name}/start",
@@ -287,6 +292,16 @@ func (s *Server) registerStackRoutes(
 			sc.api.handlePostServiceAction(w, r, "rebuild")
 		}))
 
+	// Network actions
+	mux.HandleFunc("POST "+prefix+"/networks/{name}/start",
+		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
+			sc.api.handlePostNetworkAction(w, r, "start")
+		}))
+	mux.HandleFunc("POST "+prefix+"/networks/{name}/stop",
+		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
+			sc.api.handlePostNetworkAction(w, r, "stop")
+		}))
+
 	// Config management
 	mux.HandleFunc("GET "+prefix+"/config",
 		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
@@ -408,6 +423,10 @@ func (s *Server) registerStackRoutes(
 		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
 			sc.api.handlePostDiagnoseInterrupt(w, r)
 		}))
+	mux.HandleFunc("POST "+prefix+"/services/{name}/diagnose/tool",
+		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
+			sc.api.handlePostDiagnoseTool(w, r)
+		}))
 	mux.HandleFunc("DELETE "+prefix+"/services/{name}/diagnose/session/{session}",
 		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
 			sc.api.handleDeleteDiagnoseSession(w, r)
@@ -421,6 +440,12 @@ func (s *Server) registerStackRoutes(
 			sc.handleGetLogs(w, r)
 		}))
 
+	// Runtime incidents
+	mux.HandleFunc("GET "+prefix+"/incidents",
+		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
+			sc.api.handleGetIncidents(w, r)
+		}))
+
 	// SSE events
 	mux.HandleFunc("GET "+prefix+"/events",
 		sh(func(sc *stackContext, w http.ResponseWriter, r *http.Request) {
@@ -14,6 +14,9 @@ type StackCapabilities struct {
 	HasGitRepos       bool `json:"hasGitRepos"`
 	HasRegenerate     bool `json:"hasRegenerate"`
 	HasRebuild        bool `json:"hasRebuild"`
+	HasIncidents      bool `json:"hasIncidents"`
+	HasNetworks       bool `json:"hasNetworks"`
+	HasEnvironment    bool `json:"hasEnvironment"`
 }
 
 // ProgressFunc reports stack lifecycle progress.
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ethpandaops/xcli/pkg/ai"
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
 	"github.com/ethpandaops/xcli/pkg/tui"
 	"github.com/sirupsen/logrus"
 )
@@ -54,7 +55,7 @@ func newStackContext(
 		log:               l,
 		backend:           backend,
 		redis:             redis,
-		aiDefaultProvider: ai.DefaultProvider,
+		aiDefaultProvider: ai.ConfiguredDefaultProvider(),
 		diagnoseSessions:  make(map[string]*diagnoseSession, 8),
 		logHistoryFn: func(service string) []string {
 			sc.logHistoryMu.RLock()
@@ -72,6 +73,12 @@ func newStackContext(
 		sseHub: sseHub,
 	}
 
+	if recorder, ok := backend.(incidentBackend); ok {
+		recorder.SetIncidentHandler(func(incident *diagnostic.Incident) {
+			sseHub.Broadcast("incident", incident)
+		})
+	}
+
 	// Detect if the stack was already running (e.g. docker containers from a previous session)
 	// and initialise the stack status accordingly. We require a majority of services to be
 	// running to avoid false positives from port conflicts (e.g. Lab detecting Xatu's
@@ -141,6 +148,7 @@ func (sc *stackContext) broadcastLoop(ctx context.Context, wg *sync.WaitGroup) {
 			}
 
 			sc.sseHub.Broadcast("health", health)
+			recordUnhealthyIncidents(sc.backend, health)
 		case logLine, ok := <-logCh:
 			if !ok {
 				return
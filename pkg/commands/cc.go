@@ -80,6 +80,7 @@ Use --no-open to prevent this behavior.`,
 
 	cmd.Flags().IntVarP(&port, "port", "p", 0, "Port for the web dashboard (default: selected instance port)")
 	cmd.Flags().StringVar(&instanceOverride, "instance", "", "Lab instance id override")
+	cmd.Flags().String("profile", "", "Lab config profile to apply (\"none\" for the base config)")
 	cmd.Flags().BoolVar(&noOpen, "no-open", false, "Don't open browser automatically")
 
 	return cmd
@@ -12,7 +12,7 @@ func completeModes() func(*cobra.Command, []string, string) ([]string, cobra.She
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
 
-		return []string{constants.ModeLocal, constants.ModeHybrid}, cobra.ShellCompDirectiveNoFileComp
+		return constants.Modes, cobra.ShellCompDirectiveNoFileComp
 	}
 }
 
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/ethpandaops/xcli/pkg/config"
 	"github.com/ethpandaops/xcli/pkg/ui"
@@ -57,23 +59,139 @@ Examples:
 Examples:
   xcli config validate              # Validate all stacks (default)
   xcli config validate --stack=lab  # Validate only lab stack
-  xcli config validate --stack=all  # Validate all stacks (explicit)`,
+  xcli config validate --stack=all  # Validate all stacks (explicit)
+
+Unknown keys and values of the wrong type are reported with their line and
+column. Files written for an older schema version are checked as migrated
+and left unchanged; 'xcli config migrate' upgrades them.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			result, err := config.Load(configPath)
+			result, err := loadConfigForValidate(configPath)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+				return err
 			}
 
 			return validateConfigForStack(result.Config, stackFilter)
 		},
 	}
 
+	// config migrate subcommand
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade .xcli.yaml to the current schema version",
+		Long: `Rewrite .xcli.yaml in the current schema version. The original is kept as
+.xcli.yaml.v<N>.bak. xcli reads older files without migrating them, so this
+is only needed to update the file itself.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := config.LoadWithProfile(configPath, config.NoProfile)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if _, err := os.Stat(result.ConfigPath); err != nil {
+				return fmt.Errorf("no config file to migrate: %w", err)
+			}
+
+			if result.Migration == nil {
+				ui.Success(fmt.Sprintf("%s is already at schema v%d", result.ConfigPath, config.SchemaVersion))
+
+				return nil
+			}
+
+			migration, err := config.MigrateFile(result.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			ui.Success(fmt.Sprintf("Migrated %s from schema v%d to v%d (backup: %s)",
+				result.ConfigPath, migration.From, migration.To, migration.Backup))
+			printMigrationSteps(migration)
+
+			return nil
+		},
+	}
+
+	// config schema subcommand
+	var schemaOutput string
+
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Export the .xcli.yaml JSON Schema",
+		Long: `Print the JSON Schema for .xcli.yaml, for editor validation and autocompletion.
+
+With the YAML language server (VS Code, Neovim, ...), save it next to the
+config and reference it from the first line of .xcli.yaml:
+
+  xcli config schema -o .xcli.schema.json
+  # yaml-language-server: $schema=.xcli.schema.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema, err := config.JSONSchema()
+			if err != nil {
+				return err
+			}
+
+			if schemaOutput == "" {
+				fmt.Print(string(schema))
+
+				return nil
+			}
+
+			//nolint:gosec // Schema file permissions are intentionally 0644 for editors
+			if err := os.WriteFile(schemaOutput, schema, 0644); err != nil {
+				return fmt.Errorf("failed to write schema: %w", err)
+			}
+
+			ui.Success(fmt.Sprintf("Wrote config schema v%d to %s", config.SchemaVersion, schemaOutput))
+
+			return nil
+		},
+	}
+
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "Write the schema to a file instead of stdout")
+
 	cmd.AddCommand(showCmd)
 	cmd.AddCommand(validateCmd)
+	cmd.AddCommand(migrateCmd)
+	cmd.AddCommand(schemaCmd)
 
 	return cmd
 }
 
+// loadConfigForValidate loads the config, listing schema issues one per line
+// and reporting any schema migration the file still needs.
+func loadConfigForValidate(configPath string) (*config.LoadResult, error) {
+	result, err := config.Load(configPath)
+
+	var schemaErr *config.SchemaError
+	if errors.As(err, &schemaErr) {
+		ui.Error(fmt.Sprintf("%s does not match the config schema:", schemaErr.File))
+
+		for _, issue := range schemaErr.Issues {
+			fmt.Printf("  %s:%s\n", schemaErr.File, issue)
+		}
+
+		return nil, fmt.Errorf("configuration has %d schema issue(s)", len(schemaErr.Issues))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if m := result.Migration; m != nil {
+		ui.Warning(fmt.Sprintf("%s uses schema v%d; run 'xcli config migrate' to upgrade it to v%d",
+			result.ConfigPath, m.From, m.To))
+		printMigrationSteps(m)
+	}
+
+	return result, nil
+}
+
+// printMigrationSteps lists the migrations that change a config file.
+func printMigrationSteps(m *config.MigrationResult) {
+	for _, applied := range m.Applied {
+		fmt.Printf("  - %s\n", applied)
+	}
+}
+
 // displayConfigForStack marshals and displays config for specified stack(s).
 // stack can be "all", "lab", or future stack names.
 func displayConfigForStack(cfg *config.Config, stack string) error {
@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewDiagnoseCommand creates the diagnose command.
+func NewDiagnoseCommand(log logrus.FieldLogger, configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Manage build failure diagnostics",
+		Long: `Manage the error patterns used to diagnose build failures.
+
+To diagnose a lab instance, use 'xcli lab diagnose'.`,
+	}
+
+	cmd.AddCommand(newDiagnosePatternsCommand(log, configPath))
+
+	return cmd
+}
+
+func newDiagnosePatternsCommand(log logrus.FieldLogger, configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "patterns",
+		Short: "Manage user-defined error pattern packs",
+		Long: `Manage user-defined error pattern packs.
+
+Pattern packs are YAML files loaded from ~/.xcli/patterns/ and the repo's
+.xcli/patterns/ directory, on top of the built-in patterns. A pack pattern
+with the same name as a built-in replaces it.`,
+	}
+
+	cmd.AddCommand(newDiagnosePatternsLintCommand(log, configPath))
+
+	return cmd
+}
+
+func newDiagnosePatternsLintCommand(log logrus.FieldLogger, configPath string) *cobra.Command {
+	var replay []string
+
+	cmd := &cobra.Command{
+		Use:   "lint [file-or-dir...]",
+		Short: "Validate pattern packs and replay saved reports against them",
+		Long: `Validate pattern packs: YAML schema, regex syntax, phase and confidence
+values, duplicate names, and that every example matches its pattern.
+
+Without arguments, lints the packs in ~/.xcli/patterns/ and the repo's
+.xcli/patterns/.
+
+With --replay, saved rebuild reports (the JSON files written to an
+instance's errors directory) are matched against the built-in and linted
+patterns, showing which pattern diagnoses each failure.
+
+Examples:
+  xcli diagnose patterns lint
+  xcli diagnose patterns lint .xcli/patterns/team.yaml
+  xcli diagnose patterns lint --replay ~/.xcli/instances/<id>/errors`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := patternLintFiles(configPath, args)
+			if err != nil {
+				return err
+			}
+
+			matcher := diagnostic.NewPatternMatcher()
+			problems := lintPatternPacks(matcher, files)
+
+			if len(replay) > 0 {
+				if err := replayReports(log, matcher, replay); err != nil {
+					return err
+				}
+			}
+
+			if problems > 0 {
+				return fmt.Errorf("%d pattern pack problem(s) found", problems)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&replay, "replay", nil,
+		"Saved rebuild report JSON files or directories to replay against the patterns")
+
+	return cmd
+}
+
+// patternLintFiles resolves lint arguments to pack files, defaulting to the
+// standard pattern directories.
+func patternLintFiles(configPath string, args []string) ([]string, error) {
+	if len(args) == 0 {
+		return diagnostic.PatternPackFiles(diagnostic.PatternSearchDirs(repoRootFromConfig(configPath))...)
+	}
+
+	files := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", arg, err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, arg)
+
+			continue
+		}
+
+		dirFiles, err := diagnostic.PatternPackFiles(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, dirFiles...)
+	}
+
+	return files, nil
+}
+
+// lintPatternPacks validates each pack, adds valid packs to matcher and
+// returns the number of problems found.
+func lintPatternPacks(matcher *diagnostic.PatternMatcher, files []string) int {
+	ui.Header("Pattern Packs")
+
+	if len(files) == 0 {
+		ui.Info("No pattern packs found")
+
+		return 0
+	}
+
+	problems := 0
+	definedIn := make(map[string]string, 16)
+
+	for _, file := range files {
+		pack, err := diagnostic.LoadPatternPackFile(file)
+		if err != nil {
+			ui.Error(err.Error())
+
+			problems++
+
+			continue
+		}
+
+		if errs := pack.Validate(); len(errs) > 0 {
+			ui.Error(fmt.Sprintf("%s (%s)", pack.Name, file))
+
+			for _, validationErr := range errs {
+				fmt.Printf("    %s\n", validationErr)
+			}
+
+			problems += len(errs)
+
+			continue
+		}
+
+		for _, def := range pack.Patterns {
+			if previous, ok := definedIn[def.Name]; ok {
+				ui.Warning(fmt.Sprintf("%s: pattern %q overrides the one in %s", file, def.Name, previous))
+			} else if matcher.HasPattern(def.Name) {
+				ui.Info(fmt.Sprintf("%s: pattern %q overrides a built-in pattern", file, def.Name))
+			}
+
+			definedIn[def.Name] = file
+		}
+
+		if err := matcher.AddPack(pack); err != nil {
+			ui.Error(err.Error())
+
+			problems++
+
+			continue
+		}
+
+		ui.Success(fmt.Sprintf("%s (%s): %d pattern(s)", pack.Name, file, len(pack.Patterns)))
+	}
+
+	return problems
+}
+
+// replayReports matches the failures in saved reports against matcher.
+func replayReports(log logrus.FieldLogger, matcher *diagnostic.PatternMatcher, paths []string) error {
+	files := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return fmt.Errorf("failed to list reports in %s: %w", path, err)
+		}
+
+		files = append(files, matches...)
+	}
+
+	ui.Blank()
+	ui.Header("Replay")
+
+	rows := make([][]string, 0, len(files))
+	unmatched := 0
+
+	for _, file := range files {
+		report, err := diagnostic.LoadReportFile(file)
+		if err != nil {
+			log.WithError(err).WithField("file", file).Warn("Skipping unreadable report")
+
+			continue
+		}
+
+		for _, result := range matcher.ReplayReport(report) {
+			pattern := result.PatternName()
+			source := "built-in"
+
+			switch {
+			case pattern == "":
+				pattern = "-"
+				source = "-"
+				unmatched++
+			case result.Diagnosis.Source != "":
+				source = result.Diagnosis.Source
+			}
+
+			rows = append(rows, []string{result.ReportID, result.Service, string(result.Phase), pattern, source})
+		}
+	}
+
+	if len(rows) == 0 {
+		ui.Info("No failed build results found in the given reports")
+
+		return nil
+	}
+
+	ui.Table([]string{"Report", "Service", "Phase", "Pattern", "Source"}, rows)
+
+	if unmatched > 0 {
+		ui.Warning(fmt.Sprintf("%d failure(s) matched no pattern", unmatched))
+	} else {
+		ui.Success("Every failure matched a pattern")
+	}
+
+	return nil
+}
+
+// repoRootFromConfig returns the directory holding the xcli config file.
+func repoRootFromConfig(configPath string) string {
+	absPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return filepath.Dir(configPath)
+	}
+
+	return filepath.Dir(absPath)
+}
@@ -43,6 +43,7 @@ Use 'xcli lab [command] --help' for more information about a command.`,
 	}
 
 	cmd.PersistentFlags().StringVar(&instanceOverride, "instance", "", "Lab instance id override")
+	cmd.PersistentFlags().String("profile", "", "Lab config profile to apply (\"none\" for the base config)")
 
 	s := stack.NewLabStack(log, configPath, &instanceOverride)
 
@@ -59,8 +60,14 @@ Use 'xcli lab [command] --help' for more information about a command.`,
 	cmd.AddCommand(stack.NewStartCommand(s))
 	cmd.AddCommand(stack.NewStopCommand(s))
 	cmd.AddCommand(stack.NewRestartCommand(s))
-	cmd.AddCommand(NewLabListCommand())
-	cmd.AddCommand(NewLabShowCommand())
+	cmd.AddCommand(NewLabListCommand(configPath))
+	cmd.AddCommand(NewLabShowCommand(configPath))
+	cmd.AddCommand(NewLabGCCommand(configPath))
+	cmd.AddCommand(NewLabCloneCommand(configPath))
+	cmd.AddCommand(NewLabPortsCommand())
+	cmd.AddCommand(NewLabProfileCommand(configPath))
+	cmd.AddCommand(NewLabNetworkCommand(configPath, s))
+	cmd.AddCommand(NewLabLockCommand(s))
 
 	var destroyYes bool
 
@@ -95,6 +102,7 @@ the instance id to confirm.`,
 	cmd.AddCommand(NewLabTUICommand(log, configPath, &instanceOverride))
 	cmd.AddCommand(NewLabDiagnoseCommand(log, configPath, &instanceOverride))
 	cmd.AddCommand(NewLabReleaseCommand(log, configPath))
+	cmd.AddCommand(NewLabReposCommand(log, configPath))
 	cmd.AddCommand(NewLabXatuCBTCommand(log, configPath))
 
 	return cmd
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ethpandaops/xcli/pkg/instance"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewLabCloneCommand creates the lab clone command.
+func NewLabCloneCommand(configPath string) *cobra.Command {
+	var (
+		from   string
+		to     string
+		dryRun bool
+		yes    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clone --from <instance-id> --to <dir-or-id>",
+		Short: "Clone a lab instance, including its data, into a new instance",
+		Long: `Create a new lab instance from an existing one so it starts warm instead of
+with empty ClickHouse and Redis.
+
+--to is either a directory (for example a new git worktree) or a new instance
+id in the source's workspace. The clone gets its own port slot and copies of:
+  - the source's Docker volumes (ClickHouse, Redis, Prometheus, Grafana)
+  - .xcli.yaml (when the target has none), .cbt-overrides.yaml, and
+    .xcli/custom-configs and .xcli/custom-dashboards
+
+The source may keep running. Its volumes are copied file by file, so the
+containers using them are paused (docker pause) for the copy and unpaused
+afterwards. Every volume is copied as of the moment of the pause, never
+mid-merge, and ClickHouse and Redis open the copy as after an unclean shutdown.
+Queries and CBT runs against the source stall until the copy finishes.
+
+Examples:
+  xcli lab clone --from main --to ../lab-feature-x
+  xcli lab clone --from main --to scratch --dry-run`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			sourceID, err := instance.SanitizeID(from)
+			if err != nil {
+				return fmt.Errorf("--from: %w", err)
+			}
+
+			registry, err := instance.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+
+			source, err := registry.Load(sourceID)
+			if err != nil {
+				return err
+			}
+
+			dockerHost, err := labDockerHost(configPath)
+			if err != nil {
+				return err
+			}
+
+			docker, dockerErr := instance.NewDockerResourceProvider(dockerHost)
+			if dockerErr != nil {
+				ui.Warning(fmt.Sprintf("Docker unavailable, volumes will not be copied: %v", dockerErr))
+			}
+
+			cloner := instance.NewCloner(registry, docker)
+
+			plan, err := cloner.Plan(cmd.Context(), source, to)
+			if err != nil {
+				return err
+			}
+
+			printClonePlan(plan)
+
+			if dryRun {
+				return nil
+			}
+
+			if !yes {
+				confirmed, confirmErr := ui.Confirm(fmt.Sprintf("Clone %s into %s?", source.InstanceID, plan.Manifest.InstanceID))
+				if confirmErr != nil {
+					return confirmErr
+				}
+
+				if !confirmed {
+					ui.Info("Cancelled.")
+
+					return nil
+				}
+			}
+
+			if err := cloner.Apply(cmd.Context(), plan); err != nil {
+				return err
+			}
+
+			ui.Success(fmt.Sprintf("Cloned %s into %s", source.InstanceID, plan.Manifest.InstanceID))
+			ui.Info(fmt.Sprintf("Start it with: xcli lab up --instance %s", plan.Manifest.InstanceID))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Source instance id")
+	cmd.Flags().StringVar(&to, "to", "", "Target directory or new instance id")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the plan without changing anything")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompt")
+
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func printClonePlan(plan *instance.ClonePlan) {
+	manifest := plan.Manifest
+
+	ui.Header(fmt.Sprintf("Clone %s -> %s", plan.Source.InstanceID, manifest.InstanceID))
+	ui.Info(fmt.Sprintf("Root: %s", manifest.RootDir))
+	ui.Info(fmt.Sprintf("Config: %s", manifest.ConfigPath))
+
+	sourcePorts := plan.Source.Ports.NamedPorts()
+	ports := manifest.Ports.NamedPorts()
+
+	rows := make([][]string, 0, len(ports))
+	for _, name := range sortedKeysInt(ports) {
+		if ports[name] == 0 {
+			continue
+		}
+
+		rows = append(rows, []string{name, strconv.Itoa(sourcePorts[name]), strconv.Itoa(ports[name])})
+	}
+
+	if len(rows) > 0 {
+		ui.Blank()
+		ui.Table([]string{"Port", "Source", "Clone"}, rows)
+	}
+
+	if len(plan.Volumes) > 0 {
+		rows = make([][]string, 0, len(plan.Volumes))
+		for _, vol := range plan.Volumes {
+			rows = append(rows, []string{vol.Source, vol.Target})
+		}
+
+		ui.Blank()
+		ui.Table([]string{"Volume", "Copy"}, rows)
+		ui.Info("Running containers using these volumes are paused while they are copied.")
+	}
+
+	if len(plan.Files) > 0 {
+		rows = make([][]string, 0, len(plan.Files))
+		for _, file := range plan.Files {
+			rows = append(rows, []string{file.Source, file.Target})
+		}
+
+		ui.Blank()
+		ui.Table([]string{"File", "Copy"}, rows)
+	}
+}
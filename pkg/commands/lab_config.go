@@ -43,9 +43,9 @@ Note: These commands are also available via:
 		Use:   "validate",
 		Short: "Validate lab configuration",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			result, err := config.Load(configPath)
+			result, err := loadConfigForValidate(configPath)
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+				return err
 			}
 
 			return validateConfigForStack(result.Config, "lab")
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ethpandaops/xcli/pkg/ai"
 	"github.com/ethpandaops/xcli/pkg/config"
@@ -20,6 +21,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// maxListedIncidents is the number of incidents listed by --incidents.
+const maxListedIncidents = 20
+
 // NewLabDiagnoseCommand creates the lab diagnose command.
 func NewLabDiagnoseCommand(
 	log logrus.FieldLogger,
@@ -27,9 +31,10 @@ func NewLabDiagnoseCommand(
 	instanceOverride *string,
 ) *cobra.Command {
 	var (
-		useAI    bool
-		provider string
-		reportID string
+		useAI     bool
+		provider  string
+		reportID  string
+		incidents bool
 	)
 
 	cmd := &cobra.Command{
@@ -43,11 +48,17 @@ instance id, ports, reconciled status, and common multi-instance traps.
 By default, uses pattern matching for instant results.
 Use --ai flag to get AI-powered analysis from Claude Code.
 
+With --incidents, lists runtime incidents instead: services that exited
+unexpectedly or became unhealthy while running, with their exit code, log
+tail, port and infrastructure state, and matched error pattern.
+
 Examples:
   xcli lab diagnose                    # Show instance state and latest failure
   xcli lab --instance alpha diagnose   # Diagnose another instance
   xcli lab diagnose --ai               # Use Claude Code for AI analysis
-  xcli lab diagnose --id xxx           # Diagnose specific report by ID`,
+  xcli lab diagnose --id xxx           # Diagnose specific report by ID
+  xcli lab diagnose --incidents        # List runtime incidents
+  xcli lab diagnose --incidents --id x # Show a specific incident`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			diag, err := buildLabDiagnosticContext(
 				cmd.Context(),
@@ -60,7 +71,11 @@ Examples:
 
 			printLabDiagnostics(diag)
 
-			store := diagnostic.NewStore(log, filepath.Join(diag.Runtime.Manifest.StateDir, "errors"))
+			store := diagnostic.NewStore(log, filepath.Join(diag.Runtime.Manifest.StateDir, constants.DirErrors))
+
+			if incidents {
+				return showIncidents(store, reportID)
+			}
 
 			// Load report (latest or by ID)
 			var report *diagnostic.RebuildReport
@@ -91,6 +106,10 @@ Examples:
 
 			// Try AI diagnosis if requested
 			if useAI {
+				if provider == "" {
+					provider = string(ai.ConfiguredDefaultProvider())
+				}
+
 				engine, engineErr := ai.NewEngine(ai.ProviderID(provider), log)
 				if engineErr != nil {
 					ui.Warning("AI provider is not available: " + engineErr.Error())
@@ -124,8 +143,13 @@ Examples:
 				}
 			}
 
-			// Pattern matching fallback
-			matcher := diagnostic.NewPatternMatcher()
+			// Pattern matching fallback, including user pattern packs
+			matcher, packErrs := diagnostic.NewPatternMatcherWithPacks(
+				diagnostic.PatternSearchDirs(diag.Runtime.Manifest.RootDir)...,
+			)
+			for _, packErr := range packErrs {
+				ui.Warning(fmt.Sprintf("Skipping pattern pack: %v", packErr))
+			}
 
 			for _, result := range report.Failed() {
 				diag := matcher.Match(&result)
@@ -141,12 +165,67 @@ Examples:
 	}
 
 	cmd.Flags().BoolVar(&useAI, "ai", false, "Use AI for diagnosis")
-	cmd.Flags().StringVar(&provider, "provider", string(ai.DefaultProvider), "AI provider to use")
+	cmd.Flags().StringVar(&provider, "provider", "",
+		"AI provider to use (claude, openai, local; defaults to the global config)")
 	cmd.Flags().StringVar(&reportID, "id", "", "Diagnose specific report by ID")
+	cmd.Flags().BoolVar(&incidents, "incidents", false, "List runtime incidents of crashed or unhealthy services")
 
 	return cmd
 }
 
+// showIncidents lists recent runtime incidents and shows the details of the
+// latest one, or of the incident with the given ID.
+func showIncidents(store *diagnostic.Store, id string) error {
+	ui.Blank()
+	ui.Header("Runtime Incidents")
+
+	if id != "" {
+		incident, err := store.LoadIncident(id)
+		if err != nil {
+			return err
+		}
+
+		ui.DisplayIncident(incident)
+
+		return nil
+	}
+
+	incidents, err := store.ListIncidents(maxListedIncidents)
+	if err != nil {
+		return err
+	}
+
+	if len(incidents) == 0 {
+		ui.Success("No runtime incidents recorded")
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(incidents))
+
+	for _, incident := range incidents {
+		pattern := "-"
+		if incident.Diagnosis != nil {
+			pattern = incident.Diagnosis.PatternName
+		}
+
+		rows = append(rows, []string{
+			incident.ID,
+			incident.Time.Format(time.DateTime),
+			incident.Service,
+			incident.Summary(),
+			pattern,
+		})
+	}
+
+	ui.Table([]string{"ID", "Time", "Service", "What", "Pattern"}, rows)
+
+	ui.DisplayIncident(incidents[0])
+	ui.Info("Show another incident with: xcli lab diagnose --incidents --id <id>")
+
+	return nil
+}
+
 type labDiagnosticContext struct {
 	Runtime        *instance.Runtime
 	Reconciled     *instance.ReconciledInstance
@@ -243,7 +322,7 @@ func buildLabDiagnosticContext(
 
 	var reconciled *instance.ReconciledInstance
 
-	result, reconcileErr := instance.NewReconciler(registry).ReconcileAll(ctx)
+	result, reconcileErr := instance.NewReconciler(registry, labCfg.Infrastructure.Docker).ReconcileAll(ctx)
 	if reconcileErr != nil {
 		traps = append(traps, fmt.Sprintf("Could not reconcile instances: %v", reconcileErr))
 	} else {
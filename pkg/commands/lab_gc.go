@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/ethpandaops/xcli/pkg/instance"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewLabGCCommand creates the lab gc command.
+func NewLabGCCommand(configPath string) *cobra.Command {
+	var (
+		dryRun  bool
+		yes     bool
+		minSize string
+		opts    instance.GCOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Clean up stale lab instances and orphan Docker resources",
+		Long: `Garbage-collect lab instances that no longer have a working copy, and
+Docker containers and volumes labelled by xcli that no registry manifest owns.
+
+The plan covers:
+  - Registry manifests whose root dir no longer exists (plus their Docker resources)
+  - Orphan containers and volumes (stopped, then removed)
+  - Reservations stuck in "reserved" with no processes, ports or containers
+
+Use --adopt <id> to register an orphan from its Docker labels instead of
+removing it. The plan is always printed first; --dry-run stops there.
+
+Examples:
+  xcli lab gc --dry-run
+  xcli lab gc --older-than 168h --min-size 500MB
+  xcli lab gc --adopt feature-x --yes`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if minSize != "" {
+				size, err := units.FromHumanSize(minSize)
+				if err != nil {
+					return fmt.Errorf("invalid --min-size %q: %w", minSize, err)
+				}
+
+				opts.MinSize = size
+			}
+
+			for i, id := range opts.Adopt {
+				sanitized, err := instance.SanitizeID(id)
+				if err != nil {
+					return err
+				}
+
+				opts.Adopt[i] = sanitized
+			}
+
+			registry, err := instance.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+
+			dockerHost, err := labDockerHost(configPath)
+			if err != nil {
+				return err
+			}
+
+			// A Docker client error is reported by the reconciler as DockerError.
+			docker, _ := instance.NewDockerResourceProvider(dockerHost)
+
+			reconciler := instance.NewReconciler(registry, dockerHost)
+			reconciler.Docker = docker
+
+			result, err := reconciler.ReconcileAll(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if result.DockerError != nil {
+				ui.Warning(fmt.Sprintf("Docker reconciliation skipped: %v", result.DockerError))
+			}
+
+			gc := instance.NewGarbageCollector(registry, docker)
+
+			plan, err := gc.Plan(cmd.Context(), result, opts)
+			if err != nil {
+				return err
+			}
+
+			printGCPlan(plan)
+
+			if len(plan.Actions) == 0 || dryRun {
+				return nil
+			}
+
+			if !yes {
+				confirmed, confirmErr := ui.Confirm(fmt.Sprintf("Apply %d gc action(s)?", len(plan.Actions)))
+				if confirmErr != nil {
+					return confirmErr
+				}
+
+				if !confirmed {
+					ui.Info("Cancelled.")
+
+					return nil
+				}
+			}
+
+			if err := gc.Apply(cmd.Context(), plan); err != nil {
+				return err
+			}
+
+			ui.Success(fmt.Sprintf("Applied %d gc action(s)", len(plan.Actions)))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the plan without changing anything")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompt")
+	cmd.Flags().DurationVar(&opts.OlderThan, "older-than", 0,
+		"Only collect manifests and Docker resources at least this old (e.g. 72h)")
+	cmd.Flags().StringVar(&minSize, "min-size", "",
+		"Only remove orphan Docker resources using at least this much disk (e.g. 500MB)")
+	cmd.Flags().StringSliceVar(&opts.Adopt, "adopt", nil,
+		"Register an orphan instance from its Docker labels instead of removing it")
+
+	return cmd
+}
+
+func printGCPlan(plan *instance.GCPlan) {
+	if len(plan.Actions) == 0 {
+		ui.Success("Nothing to clean up")
+	} else {
+		rows := make([][]string, 0, len(plan.Actions))
+		for _, action := range plan.Actions {
+			size := "-"
+			if action.Resource != nil && action.Resource.Size > 0 {
+				size = units.HumanSize(float64(action.Resource.Size))
+			}
+
+			rows = append(rows, []string{action.Kind, action.InstanceID, action.Target(), size, action.Reason})
+		}
+
+		ui.Header("GC plan")
+		ui.Table([]string{"Action", "Instance", "Target", "Size", "Reason"}, rows)
+	}
+
+	if len(plan.Skipped) > 0 {
+		ui.Blank()
+		ui.Info(fmt.Sprintf("Skipped by filters:\n  %s", strings.Join(plan.Skipped, "\n  ")))
+	}
+}
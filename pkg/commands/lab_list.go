@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
-	"strings"
 
+	"github.com/ethpandaops/xcli/pkg/config"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
 	"github.com/ethpandaops/xcli/pkg/instance"
 	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/ethpandaops/xcli/pkg/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +17,7 @@ import (
 const tableColumnValue = "Value"
 
 // NewLabListCommand creates the lab list command.
-func NewLabListCommand() *cobra.Command {
+func NewLabListCommand(configPath string) *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "List known lab instances",
@@ -25,7 +27,12 @@ func NewLabListCommand() *cobra.Command {
 				return err
 			}
 
-			result, err := instance.NewReconciler(registry).ReconcileAll(cmd.Context())
+			dockerHost, err := labDockerHost(configPath)
+			if err != nil {
+				return err
+			}
+
+			result, err := instance.NewReconciler(registry, dockerHost).ReconcileAll(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -52,7 +59,7 @@ func NewLabListCommand() *cobra.Command {
 }
 
 // NewLabShowCommand creates the lab show command.
-func NewLabShowCommand() *cobra.Command {
+func NewLabShowCommand(configPath string) *cobra.Command {
 	return &cobra.Command{
 		Use:   "show <instance-id>",
 		Short: "Show one lab instance",
@@ -68,7 +75,12 @@ func NewLabShowCommand() *cobra.Command {
 				return err
 			}
 
-			result, err := instance.NewReconciler(registry).ReconcileAll(cmd.Context())
+			dockerHost, err := labDockerHost(configPath)
+			if err != nil {
+				return err
+			}
+
+			result, err := instance.NewReconciler(registry, dockerHost).ReconcileAll(cmd.Context())
 			if err != nil {
 				return err
 			}
@@ -90,6 +102,17 @@ func NewLabShowCommand() *cobra.Command {
 	}
 }
 
+// labDockerHost returns the Docker host lab infrastructure runs on, as
+// configured by the lab config at configPath.
+func labDockerHost(configPath string) (config.DockerHostConfig, error) {
+	labCfg, _, err := workspace.LoadLabConfig(configPath, false)
+	if err != nil {
+		return config.DockerHostConfig{}, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return labCfg.Infrastructure.Docker, nil
+}
+
 func printInstanceList(instances []*instance.ReconciledInstance) {
 	for i, item := range instances {
 		if i > 0 {
@@ -114,7 +137,7 @@ func printInstanceList(instances []*instance.ReconciledInstance) {
 			rows = append(rows, []string{
 				name,
 				repo.Branch,
-				shortCommit(repo.Commit),
+				xcligit.ShortCommit(repo.Commit),
 				dirtyLabel(repo.Dirty),
 				repo.Path,
 			})
@@ -137,6 +160,10 @@ func printInstanceDetails(item *instance.ReconciledInstance) {
 	ui.Info(fmt.Sprintf("State dir: %s", manifest.StateDir))
 	ui.Info(fmt.Sprintf("Mode: %s", manifest.Mode))
 
+	if manifest.Profile != "" {
+		ui.Info(fmt.Sprintf("Profile: %s", manifest.Profile))
+	}
+
 	if manifest.LastError != "" {
 		ui.Warning(fmt.Sprintf("Last error: %s", manifest.LastError))
 	}
@@ -206,7 +233,7 @@ func printRepoTable(manifest *instance.Manifest) {
 		rows = append(rows, []string{
 			name,
 			repo.Branch,
-			shortCommit(repo.Commit),
+			xcligit.ShortCommit(repo.Commit),
 			dirtyLabel(repo.Dirty),
 			repo.Path,
 		})
@@ -218,15 +245,6 @@ func printRepoTable(manifest *instance.Manifest) {
 	}
 }
 
-func shortCommit(commit string) string {
-	commit = strings.TrimSpace(commit)
-	if len(commit) <= 12 {
-		return commit
-	}
-
-	return commit[:12]
-}
-
 func dirtyLabel(dirty bool) string {
 	if dirty {
 		return "dirty"
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+// labLocker writes and verifies the workspace's xcli.lock.
+type labLocker interface {
+	WriteLock(ctx context.Context, fromInstance bool) error
+	CheckLock(ctx context.Context, restore bool) error
+}
+
+// NewLabLockCommand creates the lab lock command.
+func NewLabLockCommand(locker labLocker) *cobra.Command {
+	var (
+		fromInstance bool
+		check        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Write xcli.lock to make the stack reproducible",
+		Long: `Write xcli.lock in the workspace root. It records the commit and dirty state
+of every lab repository and of the xatu repo, the mode, profile and enabled
+networks, a hash of .cbt-overrides.yaml, and the xcli version.
+
+Commit xcli.lock, then run 'xcli lab up --locked' to check out the locked
+commits before building. Up fails with a diff when the workspace still
+diverges, for example when a repository has uncommitted changes or the
+overrides were edited.
+
+Examples:
+  xcli lab lock                    # Lock the current checkouts
+  xcli lab lock --from-instance    # Lock what the instance last started with
+  xcli lab lock --check            # Compare the workspace with xcli.lock`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if check {
+				return locker.CheckLock(cmd.Context(), false)
+			}
+
+			return locker.WriteLock(cmd.Context(), fromInstance)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fromInstance, "from-instance", false,
+		"Use the commits and mode recorded when the selected instance was last started")
+	cmd.Flags().BoolVar(&check, "check", false, "Compare the workspace with xcli.lock without writing it")
+	cmd.MarkFlagsMutuallyExclusive("from-instance", "check")
+
+	return cmd
+}
@@ -0,0 +1,325 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/instance"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+// labNetworkController starts and stops single networks on a running lab
+// instance.
+type labNetworkController interface {
+	StartNetwork(ctx context.Context, network string) error
+	StopNetwork(ctx context.Context, network string, resetBounds bool) error
+	ResetNetworkRedis(ctx context.Context, network string) error
+}
+
+// NewLabNetworkCommand creates the lab network command.
+func NewLabNetworkCommand(configPath string, controller labNetworkController) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Add, remove, start or stop lab networks",
+		Long: `Add or remove networks in .xcli.yaml, or start and stop one network's
+services on a running stack.
+
+Built-in networks (mainnet, sepolia, hoodi, holesky) need only a name. Custom
+devnets need a genesis timestamp and may set their slot duration, fork
+schedule, external Xatu database and backfill window. These settings flow into
+the generated CBT and lab-backend configs and into seed data generation.`,
+	}
+
+	cmd.AddCommand(newLabNetworkAddCommand(configPath))
+	cmd.AddCommand(newLabNetworkRemoveCommand(configPath, controller))
+	cmd.AddCommand(newLabNetworkStartCommand(configPath, controller))
+	cmd.AddCommand(newLabNetworkStopCommand(configPath, controller))
+
+	return cmd
+}
+
+func newLabNetworkAddCommand(configPath string) *cobra.Command {
+	var (
+		net        config.NetworkConfig
+		forks      []string
+		portOffset int
+		disabled   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a network",
+		Long: `Add a network to the lab config. Without --port-offset the first offset whose
+CBT, cbt-api and frontend ports are free is used.
+
+Examples:
+  xcli lab network add holesky
+  xcli lab network add fusaka_devnet_3 \
+    --genesis-timestamp 1753300800 \
+    --seconds-per-slot 6 \
+    --fork electra=0 --fork fulu=256 \
+    --database fusaka_devnet_3 \
+    --backfill-window 6h`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCfg, ws, err := workspace.LoadConfig(configPath, true, false)
+			if err != nil {
+				return err
+			}
+
+			if rootCfg.Lab == nil {
+				return fmt.Errorf("lab configuration not found - run 'xcli lab init' first")
+			}
+
+			labCfg := rootCfg.Lab
+
+			net.Name = args[0]
+			net.Enabled = !disabled
+
+			if _, exists := labCfg.Network(net.Name); exists {
+				return fmt.Errorf("network %q already exists", net.Name)
+			}
+
+			net.Forks, err = parseForks(forks)
+			if err != nil {
+				return err
+			}
+
+			net.PortOffset = portOffset
+			if portOffset < 0 {
+				net.PortOffset, err = instance.FreeNetworkPortOffset(labCfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Pin the existing networks' Redis databases first: configs written
+			// before redisDB existed derive them from the enabled networks.
+			if err := labCfg.PinRedisDBs(); err != nil {
+				return err
+			}
+
+			redisDB, err := labCfg.FreeRedisDB()
+			if err != nil {
+				return err
+			}
+
+			net.RedisDB = new(redisDB)
+
+			networks := append(slices.Clone(labCfg.Networks), net)
+			if err := config.ValidateNetworks(networks); err != nil {
+				return err
+			}
+
+			labCfg.Networks = networks
+
+			if _, err := instance.BuildPortPlan(labCfg, 0); err != nil {
+				return err
+			}
+
+			if err := rootCfg.Save(ws.ConfigPath); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+
+			ui.Success(fmt.Sprintf("Added network %s (port offset %d, Redis database %d)", net.Name, net.PortOffset, redisDB))
+
+			if net.Enabled {
+				ui.Info("Apply with: xcli lab config regenerate && xcli lab down && xcli lab up")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint64Var(&net.GenesisTimestamp, "genesis-timestamp", 0, "Genesis time in Unix seconds (required for custom networks)")
+	cmd.Flags().IntVar(&net.SecondsPerSlot, "seconds-per-slot", 0, "Slot duration in seconds (default 12)")
+	cmd.Flags().StringArrayVar(&forks, "fork", nil, "Consensus fork activation as name=epoch (repeatable)")
+	cmd.Flags().StringVar(&net.Database, "database", "", "External Xatu database holding this network's data")
+	cmd.Flags().StringVar(&net.Backfill.Window, "backfill-window", "", "How far back external models start, e.g. 6h (default 1h)")
+	cmd.Flags().Uint64Var(&net.Backfill.MinBlock, "min-block", 0, "First execution block external models read")
+	cmd.Flags().IntVar(&portOffset, "port-offset", -1, "Port offset for CBT, cbt-api and frontend ports (default: first free)")
+	cmd.Flags().BoolVar(&disabled, "disabled", false, "Add the network without enabling it")
+
+	return cmd
+}
+
+func newLabNetworkRemoveCommand(configPath string, controller labNetworkController) *cobra.Command {
+	var keepRedis bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a network",
+		Long: `Remove a network from the lab config. The other networks keep their Redis
+databases.
+
+The removed network's Redis database is flushed first, so a network added
+later with the same database starts without its bounds. This needs the lab's
+Redis to be running; otherwise xcli warns and removes the network anyway.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNetworks(configPath, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCfg, ws, err := workspace.LoadConfig(configPath, true, false)
+			if err != nil {
+				return err
+			}
+
+			if rootCfg.Lab == nil {
+				return fmt.Errorf("lab configuration not found - run 'xcli lab init' first")
+			}
+
+			labCfg := rootCfg.Lab
+			name := args[0]
+
+			// A disabled network without a redisDB never had a database.
+			_, hadRedisDB := labCfg.RedisDB(name)
+
+			// Pin the Redis databases before the network goes: configs written
+			// before redisDB existed derive them from the enabled networks, so
+			// removing one would shift the networks after it.
+			if err := labCfg.PinRedisDBs(); err != nil {
+				return err
+			}
+
+			networks := slices.DeleteFunc(slices.Clone(labCfg.Networks), func(net config.NetworkConfig) bool {
+				return net.Name == name
+			})
+			if len(networks) == len(labCfg.Networks) {
+				return fmt.Errorf("network %q not found", name)
+			}
+
+			if !slices.ContainsFunc(networks, func(net config.NetworkConfig) bool { return net.Enabled }) {
+				return fmt.Errorf("cannot remove %s: at least one network must stay enabled", name)
+			}
+
+			redisDB, _ := labCfg.RedisDB(name)
+
+			if hadRedisDB && !keepRedis {
+				if err := controller.ResetNetworkRedis(cmd.Context(), name); err != nil {
+					ui.Warning(fmt.Sprintf(
+						"Could not flush Redis database %d of %s: %v\nA network added later may reuse it; flush it with 'redis-cli -n %d FLUSHDB' on the lab's Redis",
+						redisDB, name, err, redisDB,
+					))
+				}
+			}
+
+			labCfg.Networks = networks
+
+			if err := rootCfg.Save(ws.ConfigPath); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+
+			ui.Success(fmt.Sprintf("Removed network %s (Redis database %d is free)", name, redisDB))
+			ui.Info("Apply with: xcli lab config regenerate && xcli lab down && xcli lab up")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&keepRedis, "keep-redis", false, "Do not flush the network's Redis database")
+
+	return cmd
+}
+
+func newLabNetworkStartCommand(configPath string, controller labNetworkController) *cobra.Command {
+	return &cobra.Command{
+		Use:   "start <name>",
+		Short: "Start one network's CBT services on the running stack",
+		Long: `Start a network's cbt and cbt-api services on the running lab stack. The
+network's migrations run and its Redis bounds are seeded when missing, then
+lab-backend is restarted with the network back in its list. Other networks
+keep running.
+
+Use this after 'xcli lab up --network <name>' or 'xcli lab network stop'.
+
+Example:
+  xcli lab network start mainnet`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNetworks(configPath, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return controller.StartNetwork(cmd.Context(), args[0])
+		},
+	}
+}
+
+func newLabNetworkStopCommand(configPath string, controller labNetworkController) *cobra.Command {
+	var resetBounds bool
+
+	cmd := &cobra.Command{
+		Use:   "stop <name>",
+		Short: "Stop one network's CBT services on the running stack",
+		Long: `Stop a network's cbt and cbt-api services on the running lab stack and
+restart lab-backend without it. Other networks keep running. The network stays
+enabled in .xcli.yaml; 'xcli lab network start' brings it back.
+
+Its Redis bounds are kept unless --reset-bounds is given.
+
+Example:
+  xcli lab network stop sepolia`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNetworks(configPath, true),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return controller.StopNetwork(cmd.Context(), args[0], resetBounds)
+		},
+	}
+
+	cmd.Flags().BoolVar(&resetBounds, "reset-bounds", false, "Flush the network's Redis database")
+
+	return cmd
+}
+
+// completeNetworks completes configured network names, or only the enabled
+// ones when enabledOnly is set.
+func completeNetworks(configPath string, enabledOnly bool) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		labCfg, _, err := workspace.LoadLabConfig(configPath, false)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		names := make([]string, 0, len(labCfg.Networks))
+		for _, net := range labCfg.Networks {
+			if enabledOnly && !net.Enabled {
+				continue
+			}
+
+			names = append(names, net.Name)
+		}
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// parseForks parses name=epoch fork flags.
+func parseForks(values []string) (map[string]uint64, error) {
+	if len(values) == 0 {
+		return nil, nil //nolint:nilnil // no fork schedule was given
+	}
+
+	forks := make(map[string]uint64, len(values))
+
+	for _, value := range values {
+		name, epochStr, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --fork %q (use name=epoch)", value)
+		}
+
+		epoch, err := strconv.ParseUint(epochStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --fork %q: epoch must be a non-negative integer", value)
+		}
+
+		forks[strings.ToLower(name)] = epoch
+	}
+
+	return forks, nil
+}
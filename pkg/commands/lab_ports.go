@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethpandaops/xcli/pkg/instance"
+	"github.com/ethpandaops/xcli/pkg/portutil"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// NewLabPortsCommand creates the lab ports command.
+func NewLabPortsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ports",
+		Short: "Show the port plan of every lab instance",
+		Long: `Show every named port of every registered lab instance side by side,
+with whether the port is currently bound and by which process.
+
+Ports can be pinned per instance in .xcli.yaml, using the names shown here:
+
+  lab:
+    instance:
+      slot: 2              # always use port slot 2
+      ports:
+        lab-frontend: 5173 # pin one port, overriding the slot
+        grafana: 3100
+
+Allocation fails with the owning instance or process when a pinned port or
+slot is taken, instead of silently moving to another slot.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			registry, err := instance.DefaultRegistry()
+			if err != nil {
+				return err
+			}
+
+			manifests, err := registry.LoadAll()
+			if err != nil {
+				return err
+			}
+
+			if len(manifests) == 0 {
+				ui.Info("No lab instances registered")
+
+				return nil
+			}
+
+			listening, listenErr := portutil.ListeningPorts()
+			if listenErr != nil {
+				ui.Warning(fmt.Sprintf("Live port state unavailable: %v", listenErr))
+			}
+
+			printPortMatrix(manifests, listening)
+
+			return nil
+		},
+	}
+}
+
+// printPortMatrix prints one row per port name and one column per instance.
+// Bound ports show the owning PID, and ports planned by more than one
+// running instance are listed below the table.
+func printPortMatrix(manifests []*instance.Manifest, listening map[int]portutil.PortConflict) {
+	var names []string
+
+	seen := make(map[string]bool)
+
+	for _, manifest := range manifests {
+		for _, name := range manifest.Ports.OrderedPortNames() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	headers := []string{"Port"}
+	owners := make(map[int][]string)
+
+	for _, manifest := range manifests {
+		headers = append(headers, fmt.Sprintf("%s (%s, slot %d)", manifest.InstanceID, manifest.Status, manifest.Ports.Slot))
+
+		// Stopped instances may share a slot; only running ones can clash.
+		if manifest.Status != instance.StatusRunning {
+			continue
+		}
+
+		for _, port := range manifest.Ports.AllPorts() {
+			owners[port] = append(owners[port], manifest.InstanceID)
+		}
+	}
+
+	rows := make([][]string, 0, len(names))
+
+	for _, name := range names {
+		row := []string{name}
+
+		for _, manifest := range manifests {
+			port := manifest.Ports.NamedPorts()[name]
+			row = append(row, portCell(port, listening))
+		}
+
+		rows = append(rows, row)
+	}
+
+	ui.Table(headers, rows)
+
+	if listening != nil {
+		ui.Info("Bound ports show the listening PID; other ports are free.")
+	}
+
+	shared := make([]int, 0)
+
+	for port, ids := range owners {
+		if len(ids) > 1 {
+			shared = append(shared, port)
+		}
+	}
+
+	sort.Ints(shared)
+
+	for _, port := range shared {
+		ui.Warning(fmt.Sprintf("Port %d is planned by running instances %s", port, strings.Join(owners[port], ", ")))
+	}
+}
+
+func portCell(port int, listening map[int]portutil.PortConflict) string {
+	if port == 0 {
+		return "-"
+	}
+
+	cell := strconv.Itoa(port)
+
+	owner, bound := listening[port]
+	if !bound {
+		return cell
+	}
+
+	cell += fmt.Sprintf(" pid %d", owner.PID)
+	if owner.Process != "" {
+		cell += " " + owner.Process
+	}
+
+	return cell
+}
@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// NewLabProfileCommand creates the lab profile command.
+func NewLabProfileCommand(configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "List and inspect lab config profiles",
+		Long: `Profiles are named lab settings deep-merged over the base lab config in
+.xcli.yaml. Maps merge key by key; lists such as networks replace the base
+list. Define them inline or as files next to .xcli.yaml:
+
+  profiles:
+    mainnet-hybrid:
+      mode: hybrid
+      infrastructure:
+        observability:
+          enabled: true
+
+  .xcli.profiles/sepolia-local.yaml:
+    mode: local
+    networks:
+      - name: sepolia
+        enabled: true
+
+Select one with --profile on any lab command or on 'xcli cc'. The profile is
+recorded in the instance manifest and reused until another --profile is
+given; --profile none returns to the base config.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List available profiles",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ws, err := workspace.Resolve(configPath, true, false)
+			if err != nil {
+				return err
+			}
+
+			profiles, err := config.ListProfiles(ws.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			if len(profiles) == 0 {
+				ui.Info(fmt.Sprintf("No profiles defined in %s or %s/", ws.ConfigPath, config.ProfilesDirName))
+
+				return nil
+			}
+
+			rows := make([][]string, 0, len(profiles))
+			for _, profile := range profiles {
+				name := profile.Name
+				if name == config.ActiveProfile() {
+					name += " (active)"
+				}
+
+				rows = append(rows, []string{name, profile.Source, strings.Join(fragmentKeys(profile.Fragment), ", ")})
+			}
+
+			ui.Table([]string{"Profile", "Source", "Overrides"}, rows)
+
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show [name]",
+		Short: "Show the effective lab config with a profile applied",
+		Long: `Show the effective lab config with a profile applied. External ClickHouse
+passwords are redacted.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile := config.ActiveProfile()
+			if len(args) == 1 {
+				profile = args[0]
+			}
+
+			labCfg, _, err := workspace.LoadLabConfigWithProfile(configPath, false, profile)
+			if err != nil {
+				return err
+			}
+
+			data, err := yaml.Marshal(labCfg.Redacted())
+			if err != nil {
+				return fmt.Errorf("failed to marshal config: %w", err)
+			}
+
+			if labCfg.Profile != "" {
+				ui.Header(fmt.Sprintf("Lab config with profile %s", labCfg.Profile))
+			} else {
+				ui.Header("Base lab config")
+			}
+
+			fmt.Print(string(data))
+
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "diff <name> [other]",
+		Short: "Show the settings a profile changes",
+		Long: `Show the settings that differ between the base lab config and a profile,
+or between two profiles.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fromName, toName := "", args[0]
+			if len(args) == 2 {
+				fromName, toName = args[0], args[1]
+			}
+
+			from, _, err := workspace.LoadLabConfigWithProfile(configPath, false, fromName)
+			if err != nil {
+				return err
+			}
+
+			to, _, err := workspace.LoadLabConfigWithProfile(configPath, false, toName)
+			if err != nil {
+				return err
+			}
+
+			changes, err := config.DiffLabConfigs(from, to)
+			if err != nil {
+				return err
+			}
+
+			fromLabel := fromName
+			if fromLabel == "" {
+				fromLabel = "base"
+			}
+
+			if len(changes) == 0 {
+				ui.Info(fmt.Sprintf("%s and %s are identical", fromLabel, toName))
+
+				return nil
+			}
+
+			rows := make([][]string, 0, len(changes))
+			for _, change := range changes {
+				rows = append(rows, []string{change.Path, orDash(change.From), orDash(change.To)})
+			}
+
+			ui.Table([]string{"Setting", fromLabel, toName}, rows)
+
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func fragmentKeys(fragment map[string]any) []string {
+	keys := make([]string, 0, len(fragment))
+	for key := range fragment {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -29,12 +29,15 @@ const (
 // NewLabReleaseCommand creates the lab release command.
 func NewLabReleaseCommand(log logrus.FieldLogger, _ string) *cobra.Command {
 	var (
-		bumpFlag    string
-		yesFlag     bool
-		noWatchFlag bool
-		timeoutFlag time.Duration
-		stackFlag   bool
-		noDepsFlag  bool
+		bumpFlag     string
+		yesFlag      bool
+		noWatchFlag  bool
+		timeoutFlag  time.Duration
+		stackFlag    bool
+		noDepsFlag   bool
+		dryRunFlag   bool
+		bumpDepsFlag bool
+		mergeTimeout time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -47,13 +50,24 @@ This command helps you create releases for lab stack projects by:
   - Respecting project dependencies (cbt must build before xatu-cbt)
   - Guiding you through version selection (for semver projects)
   - Watching builds until completion (default behavior)
+  - Writing each release's notes from the commits since its last tag,
+    grouped by conventional-commit type (feat, fix, perf, ...)
 
 Dependencies:
-  xatu-cbt depends on cbt - when both are released, cbt builds first
+  xatu-cbt and cbt-api depend on cbt - when released together, cbt builds first
+
+  With --bump-dependents, once cbt's build succeeds each dependent's go.mod is
+  bumped to the new tag (go get, go mod tidy) on an xcli/bump-* branch in a
+  temporary worktree of its checkout, built there the way 'xcli lab rebuild'
+  does (proto and code generation included), pushed and opened as a pull
+  request; the checkout itself is not touched. Once every pull request is
+  open they are waited on together: each dependent is released once its pull
+  request is merged, and skipped if it is not merged within --merge-timeout.
+  Needs a GitHub token (see below).
 
 Supported projects:
   cbt          - ClickHouse transformation tool (semver, tag-triggered)
-  cbt-api      - REST API for CBT (semver, tag-triggered)
+  cbt-api      - REST API for CBT (semver, tag-triggered, depends on cbt)
   lab-backend  - Lab API gateway (semver, tag-triggered)
   xatu-cbt     - CBT models and migrations (workflow dispatch, depends on cbt)
 
@@ -72,13 +86,17 @@ Options:
   xcli lab release cbt xatu-cbt --no-watch  # Trigger without watching
   xcli lab release --stack --timeout 1h     # Watch with custom timeout
   xcli lab release xatu-cbt --no-deps       # Skip dependency prompts
+  xcli lab release --stack --dry-run        # Print the plan and changelogs only
+  xcli lab release --stack --bump-dependents  # Open go.mod bump PRs between phases
 
-Note: Requires GitHub CLI (gh) to be installed and authenticated.`,
+Note: Talks to the GitHub API with a token from GITHUB_TOKEN, GH_TOKEN or
+gh's config (gh auth login). Without one it falls back to the GitHub CLI (gh),
+which must be installed and authenticated.`,
 		Args:              cobra.ArbitraryArgs,
 		ValidArgsFunction: completeReleasableProjects(),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRelease(cmd.Context(), log, args, stackFlag, bumpFlag,
-				yesFlag, !noWatchFlag, noDepsFlag, timeoutFlag)
+				yesFlag, !noWatchFlag, noDepsFlag, dryRunFlag, bumpDepsFlag, timeoutFlag, mergeTimeout)
 		},
 	}
 
@@ -88,6 +106,12 @@ Note: Requires GitHub CLI (gh) to be installed and authenticated.`,
 	cmd.Flags().DurationVar(&timeoutFlag, "timeout", 30*time.Minute, "Timeout for watching build")
 	cmd.Flags().BoolVar(&stackFlag, "stack", false, "Release entire stack with dependency ordering")
 	cmd.Flags().BoolVar(&noDepsFlag, "no-deps", false, "Skip dependency checks and prompts")
+	cmd.Flags().BoolVar(&dryRunFlag, "dry-run", false,
+		"Print the ordered release plan and changelogs without releasing (bump defaults to patch)")
+	cmd.Flags().BoolVar(&bumpDepsFlag, "bump-dependents", false,
+		"Open pull requests bumping dependents' go.mod to released versions, and release them once merged")
+	cmd.Flags().DurationVar(&mergeTimeout, "merge-timeout", 2*time.Hour,
+		"How long to wait for the --bump-dependents pull requests to be merged")
 
 	return cmd
 }
@@ -99,8 +123,8 @@ func runRelease(
 	args []string,
 	releaseStack bool,
 	bumpFlag string,
-	skipConfirm, watch, skipDeps bool,
-	timeout time.Duration,
+	skipConfirm, watch, skipDeps, dryRun, bumpDependents bool,
+	timeout, mergeTimeout time.Duration,
 ) error {
 	svc := release.NewService(log)
 
@@ -113,7 +137,7 @@ func runRelease(
 		return err
 	}
 
-	ui.Success("GitHub CLI authenticated")
+	ui.Success("GitHub authenticated")
 	ui.Blank()
 
 	// Step 2: Get project info
@@ -165,6 +189,10 @@ func runRelease(
 		selectedProjects = selected
 	}
 
+	if dryRun {
+		return runDryRun(ctx, log, projects, selectedProjects, bumpFlag, skipDeps)
+	}
+
 	// Step 4: Pre-flight checks (local repo status)
 	if !skipConfirm {
 		if err := runPreflightChecks(ctx, log, selectedProjects); err != nil {
@@ -172,15 +200,26 @@ func runRelease(
 		}
 	}
 
+	var bumper *dependentBumper
+
+	if bumpDependents {
+		b, bumpErr := newDependentBumper(log, svc, mergeTimeout)
+		if bumpErr != nil {
+			return bumpErr
+		}
+
+		bumper = b
+	}
+
 	// Step 5: Single project: use existing flow with dependency check
 	if len(selectedProjects) == 1 {
 		return runSingleRelease(ctx, log, svc, projects, selectedProjects[0],
-			bumpFlag, skipConfirm, watch, skipDeps, timeout)
+			bumpFlag, skipConfirm, watch, skipDeps, timeout, bumper)
 	}
 
 	// Step 5: Multiple projects: dependency-aware flow
 	return runMultiRelease(ctx, log, svc, projects, selectedProjects,
-		bumpFlag, skipConfirm, watch, skipDeps, timeout)
+		bumpFlag, skipConfirm, watch, skipDeps, timeout, bumper)
 }
 
 // runSingleRelease handles releasing a single project with dependency check.
@@ -193,6 +232,7 @@ func runSingleRelease(
 	bumpFlag string,
 	skipConfirm, watch, skipDeps bool,
 	timeout time.Duration,
+	bumper *dependentBumper,
 ) error {
 	// Check for missing dependencies (unless --no-deps)
 	if !skipDeps {
@@ -209,7 +249,7 @@ func runSingleRelease(
 				allProjects := append(added, project)
 
 				return runMultiRelease(ctx, log, svc, projectInfos, allProjects,
-					bumpFlag, skipConfirm, watch, skipDeps, timeout)
+					bumpFlag, skipConfirm, watch, skipDeps, timeout, bumper)
 			}
 			// User chose to skip dependencies - continue with single release
 		}
@@ -239,7 +279,8 @@ func runSingleRelease(
 	var err error
 
 	if projectInfo.IsSemver {
-		result, err = handleSemverRelease(ctx, svc, projectInfo, bumpFlag, skipConfirm)
+		changelog := generateChangelogs(ctx, log, []string{project})[project]
+		result, err = handleSemverRelease(ctx, svc, projectInfo, changelog, bumpFlag, skipConfirm)
 	} else {
 		result, err = handleWorkflowRelease(ctx, svc, projectInfo, skipConfirm)
 	}
@@ -360,6 +401,7 @@ func runMultiRelease(
 	bumpFlag string,
 	skipConfirm, watch, skipDeps bool,
 	timeout time.Duration,
+	bumper *dependentBumper,
 ) error {
 	// Build project info map
 	infoMap := make(map[string]*release.ProjectInfo, len(projectInfos))
@@ -440,6 +482,8 @@ func runMultiRelease(
 		return fmt.Errorf("no projects to release")
 	}
 
+	attachChangelogs(ctx, log, configs)
+
 	// Display confirmation summary
 	displayReleasePlan(configs, deps)
 
@@ -468,9 +512,16 @@ func runMultiRelease(
 		executePhase1(ctx, svc, phase1, configs, deps, report, failedDeps, watch, timeout)
 	}
 
-	// Phase 2: Release projects that depend on Phase 1 (skip if deps failed)
+	// Phase 2: Release projects that depend on Phase 1 (skip if deps failed),
+	// once their go.mod bumps are merged
 	if len(phase2) > 0 {
-		watchItems = executePhase2(ctx, svc, phase2, configs, deps, report, failedDeps, watchItems)
+		var blocked map[string]string
+
+		if bumper != nil {
+			blocked = bumper.bumpPhase(ctx, phase2, configs, deps, failedDeps)
+		}
+
+		watchItems = executePhase2(ctx, svc, phase2, configs, deps, report, failedDeps, blocked, watchItems)
 	}
 
 	// Phase 3: Release independent projects
@@ -478,6 +529,10 @@ func runMultiRelease(
 		watchItems = executePhase3(ctx, svc, phase3, configs, report, watchItems)
 	}
 
+	for _, cfg := range configs {
+		report.SetChangelog(cfg.Project, cfg.Changelog)
+	}
+
 	// Watch remaining builds concurrently
 	if watch && len(watchItems) > 0 {
 		ui.Blank()
@@ -544,7 +599,7 @@ func executeRelease(
 	var err error
 
 	if config.Info.IsSemver {
-		result, err = svc.ReleaseSemver(ctx, config.Project, config.BumpType)
+		result, err = svc.ReleaseSemver(ctx, config.Project, config.BumpType, config.Changelog.Markdown())
 	} else {
 		result, err = svc.ReleaseWorkflow(ctx, config.Project)
 	}
@@ -592,6 +647,10 @@ func displayReleasePlan(configs []release.ProjectReleaseConfig, deps map[string]
 					strings.Join(info.DependsOn, ", "))))
 			}
 		}
+
+		if cfg.Changelog != nil {
+			fmt.Printf("     %s\n", pterm.Gray("↳ Changelog: "+describeChangelog(cfg.Changelog)))
+		}
 	}
 
 	ui.Blank()
@@ -693,6 +752,10 @@ func displayReleaseSummary(report *release.ReleaseReport) {
 			if entry.URL != "" {
 				fmt.Printf("    %s\n", pterm.Gray(entry.URL))
 			}
+
+			if entry.Changelog != nil {
+				fmt.Printf("    %s\n", pterm.Gray("Changelog: "+describeChangelog(entry.Changelog)))
+			}
 		}
 	}
 
@@ -755,6 +818,7 @@ func handleSemverRelease(
 	ctx context.Context,
 	svc release.Service,
 	project *release.ProjectInfo,
+	changelog *release.Changelog,
 	bumpFlag string,
 	skipConfirm bool,
 ) (*release.ReleaseResult, error) {
@@ -808,6 +872,7 @@ func handleSemverRelease(
 		ui.Blank()
 		ui.Info(fmt.Sprintf("Current version: %s", project.CurrentVersion))
 		ui.Info(fmt.Sprintf("New version: %s", newVersion.String()))
+		ui.Info(fmt.Sprintf("Changelog: %s", describeChangelog(changelog)))
 		ui.Blank()
 
 		confirmed, confirmErr := ui.Confirm(fmt.Sprintf("Create release %s for %s?", newVersion.String(), project.Name))
@@ -816,7 +881,7 @@ func handleSemverRelease(
 		}
 	}
 
-	return svc.ReleaseSemver(ctx, project.Name, bumpType)
+	return svc.ReleaseSemver(ctx, project.Name, bumpType, changelog.Markdown())
 }
 
 // handleWorkflowRelease handles releases for workflow dispatch projects.
@@ -900,7 +965,8 @@ func executePhase1(
 	}
 }
 
-// executePhase2 releases projects that depend on Phase 1 (skipping if deps failed).
+// executePhase2 releases projects that depend on Phase 1 (skipping if deps
+// failed or the project is blocked).
 func executePhase2(
 	ctx context.Context,
 	svc release.Service,
@@ -909,6 +975,7 @@ func executePhase2(
 	deps map[string]*release.DependencyInfo,
 	report *release.ReleaseReport,
 	failedDeps map[string]bool,
+	blocked map[string]string,
 	watchItems []release.WatchItem,
 ) []release.WatchItem {
 	ui.Blank()
@@ -918,10 +985,10 @@ func executePhase2(
 	for _, project := range phase2 {
 		// Check if any dependency failed
 		depInfo := deps[project]
-		skipReason := ""
+		skipReason := blocked[project]
 
 		for _, dep := range depInfo.DependsOn {
-			if failedDeps[dep] {
+			if skipReason == "" && failedDeps[dep] {
 				skipReason = fmt.Sprintf("dependency %s failed", dep)
 
 				break
@@ -1124,23 +1191,7 @@ func runPreflightChecks(
 	log logrus.FieldLogger,
 	selectedProjects []string,
 ) error {
-	// Try to find and load config to get repo paths
-	configPath := config.FindConfig("")
-	if configPath == "" {
-		log.Debug("no config found for pre-flight checks (skipping local repo checks)")
-
-		return nil
-	}
-
-	labCfg, _, err := workspace.LoadLabConfig(configPath, false)
-	if err != nil {
-		log.WithError(err).Debug("could not load lab config for pre-flight checks (skipping local repo checks)")
-
-		return nil
-	}
-
-	// Build map of project -> repo path
-	repoPaths := getRepoPathsFromLabConfig(labCfg, selectedProjects)
+	repoPaths := loadReleaseRepoPaths(log, selectedProjects)
 	if len(repoPaths) == 0 {
 		return nil
 	}
@@ -1239,6 +1290,26 @@ func runPreflightChecks(
 	return nil
 }
 
+// loadReleaseRepoPaths maps the selected projects to their local checkouts,
+// from the lab config if one can be found.
+func loadReleaseRepoPaths(log logrus.FieldLogger, projects []string) map[string]string {
+	configPath := config.FindConfig("")
+	if configPath == "" {
+		log.Debug("no config found (skipping local repo checks)")
+
+		return nil
+	}
+
+	labCfg, _, err := workspace.LoadLabConfig(configPath, false)
+	if err != nil {
+		log.WithError(err).Debug("could not load lab config (skipping local repo checks)")
+
+		return nil
+	}
+
+	return getRepoPathsFromLabConfig(labCfg, projects)
+}
+
 // getRepoPathsFromLabConfig extracts repo paths for selected projects from lab config.
 func getRepoPathsFromLabConfig(labCfg *config.LabConfig, projects []string) map[string]string {
 	paths := make(map[string]string, len(projects))
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/builder"
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/release"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+	"github.com/sirupsen/logrus"
+)
+
+// dependentBumper bumps dependent repos' go.mod to freshly released
+// dependency versions between release phases: it builds each bump in a
+// temporary worktree, opens it as a pull request and waits for the merges.
+type dependentBumper struct {
+	log          logrus.FieldLogger
+	prs          release.PullRequestService
+	labCfg       *config.LabConfig
+	stateDir     string
+	mergeTimeout time.Duration
+}
+
+// openBump is a bump pull request waiting to be merged.
+type openBump struct {
+	project string
+	dep     string
+	repo    string
+	pr      *release.PullRequest
+}
+
+// newDependentBumper checks that pull requests can be opened and the
+// dependents' checkouts can be found.
+func newDependentBumper(
+	log logrus.FieldLogger,
+	svc release.Service,
+	mergeTimeout time.Duration,
+) (*dependentBumper, error) {
+	prs, ok := svc.(release.PullRequestService)
+	if !ok {
+		return nil, fmt.Errorf("--bump-dependents opens pull requests through the GitHub API: %w", release.ErrNoGitHubToken)
+	}
+
+	configPath := config.FindConfig("")
+	if configPath == "" {
+		return nil, fmt.Errorf("--bump-dependents needs a lab config to find the dependent repositories")
+	}
+
+	labCfg, ws, err := workspace.LoadLabConfig(configPath, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lab config: %w", err)
+	}
+
+	return &dependentBumper{
+		log:          log,
+		prs:          prs,
+		labCfg:       labCfg,
+		stateDir:     ws.StateDir,
+		mergeTimeout: mergeTimeout,
+	}, nil
+}
+
+// bumpPhase opens a bump pull request for every Phase 2 project whose
+// dependencies were released, then waits for all of them to merge. It
+// returns the projects that must not be released, with the reason.
+func (b *dependentBumper) bumpPhase(
+	ctx context.Context,
+	phase2 []string,
+	configs []release.ProjectReleaseConfig,
+	deps map[string]*release.DependencyInfo,
+	failedDeps map[string]bool,
+) map[string]string {
+	blocked := make(map[string]string, len(phase2))
+	opened := make([]openBump, 0, len(phase2))
+
+	ui.Blank()
+	ui.Header("Bumping dependent repositories")
+	ui.Blank()
+
+	for _, project := range phase2 {
+		for _, dep := range deps[project].DependsOn {
+			config := findConfig(configs, dep)
+			if failedDeps[dep] || config == nil || config.NewVersion == "" {
+				continue
+			}
+
+			bump, err := b.open(ctx, project, dep, config.NewVersion)
+			if err != nil {
+				ui.Error(fmt.Sprintf("%s: %v", project, err))
+
+				blocked[project] = fmt.Sprintf("%s bump not merged", dep)
+
+				break
+			}
+
+			if bump != nil {
+				opened = append(opened, *bump)
+			}
+		}
+	}
+
+	for _, bump := range b.waitForMerges(ctx, opened) {
+		if _, ok := blocked[bump.project]; !ok {
+			blocked[bump.project] = fmt.Sprintf("%s bump not merged", bump.dep)
+		}
+	}
+
+	return blocked
+}
+
+// open updates project's go.mod to dep at version in a temporary worktree,
+// builds it and opens the bump as a pull request. It returns nil when no
+// bump is needed.
+func (b *dependentBumper) open(ctx context.Context, project, dep, version string) (*openBump, error) {
+	repoPath := getRepoPathsFromLabConfig(b.labCfg, []string{project})[project]
+	if repoPath == "" {
+		return nil, fmt.Errorf("no local checkout configured")
+	}
+
+	bump := release.DependencyBump{
+		Project:    project,
+		RepoPath:   repoPath,
+		Dependency: dep,
+		Version:    version,
+	}
+
+	requires, err := release.RequiresModule(repoPath, bump.Module())
+	if err != nil {
+		return nil, err
+	}
+
+	if !requires {
+		ui.Info(fmt.Sprintf("%s does not require %s, no bump needed", project, bump.Module()))
+
+		return nil, nil //nolint:nilnil // no pull request needed
+	}
+
+	base, err := b.prs.DefaultBranch(ctx, bump.Repo())
+	if err != nil {
+		return nil, err
+	}
+
+	spinner := ui.NewSpinner(fmt.Sprintf("Bumping %s to %s in %s...", dep, version, project))
+
+	err = release.PrepareDependencyBump(ctx, &bump, base)
+
+	defer func() {
+		if cleanupErr := release.CleanupDependencyBump(context.WithoutCancel(ctx), bump); cleanupErr != nil {
+			b.log.WithError(cleanupErr).WithField("project", project).Warn("failed to remove bump worktree")
+		}
+	}()
+
+	if errors.Is(err, release.ErrNoBumpNeeded) {
+		spinner.Success(fmt.Sprintf("%s already requires %s %s", project, dep, version))
+
+		return nil, nil //nolint:nilnil // no pull request needed
+	}
+
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("Failed to bump %s in %s", dep, project))
+
+		return nil, err
+	}
+
+	if err := b.build(ctx, bump); err != nil {
+		spinner.Fail(fmt.Sprintf("%s does not build with %s %s", project, dep, version))
+
+		return nil, err
+	}
+
+	if err := release.PushDependencyBump(ctx, bump); err != nil {
+		spinner.Fail(fmt.Sprintf("Failed to push %s", bump.Branch()))
+
+		return nil, err
+	}
+
+	pr, err := b.prs.OpenPullRequest(ctx, bump.Repo(), bump.Branch(), base, bump.Title(), bump.Body())
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("Failed to open pull request for %s", project))
+
+		return nil, err
+	}
+
+	spinner.Success(fmt.Sprintf("Opened %s", pr.URL))
+
+	return &openBump{project: project, dep: dep, repo: bump.Repo(), pr: pr}, nil
+}
+
+// build runs the bumped dependent's lab build, code generation included, with
+// its repository pointed at the bump worktree, so the generated code and
+// binaries land there rather than in the running lab's checkout.
+func (b *dependentBumper) build(ctx context.Context, bump release.DependencyBump) error {
+	cfg := *b.labCfg
+	cfg.Repos = cfg.Repos.With(bump.Project, bump.WorkDir)
+
+	return builder.NewManager(b.log, &cfg, b.stateDir).BuildRepo(ctx, bump.Project)
+}
+
+// waitForMerges waits for every opened bump pull request at once. It returns
+// the bumps that were not merged.
+func (b *dependentBumper) waitForMerges(ctx context.Context, opened []openBump) []openBump {
+	if len(opened) == 0 {
+		return nil
+	}
+
+	spinner := ui.NewSpinner(fmt.Sprintf("Waiting for %d bump pull request(s) to be merged...", len(opened)))
+
+	errs := make([]error, len(opened))
+
+	var wg sync.WaitGroup
+
+	for i, bump := range opened {
+		wg.Go(func() {
+			_, errs[i] = b.prs.WaitForMerge(ctx, bump.repo, bump.pr.Number, release.WatchOptions{
+				Timeout:      b.mergeTimeout,
+				PollInterval: 30 * time.Second,
+			})
+		})
+	}
+
+	wg.Wait()
+
+	var failed []openBump
+
+	for i, bump := range opened {
+		if errs[i] != nil {
+			failed = append(failed, bump)
+		}
+	}
+
+	if len(failed) == 0 {
+		spinner.Success(fmt.Sprintf("All %d bump pull request(s) merged", len(opened)))
+
+		return nil
+	}
+
+	spinner.Fail(fmt.Sprintf("%d of %d bump pull request(s) not merged", len(failed), len(opened)))
+
+	for i, bump := range opened {
+		if errs[i] != nil {
+			ui.Error(fmt.Sprintf("%s #%d: %v", bump.project, bump.pr.Number, errs[i]))
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/release"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeXatuCBTMakefile generates the package main imports in "make proto", as
+// xatu-cbt does, so "make build" fails without the generate step.
+const fakeXatuCBTMakefile = `proto:
+	mkdir -p pkg/proto/clickhouse
+	printf 'package clickhouse\n\nconst Tables = 1\n' > pkg/proto/clickhouse/tables.go
+
+build:
+	go build -o bin/xatu-cbt .
+`
+
+func TestDependentBumperBuildsWorktreeWithGenerateStep(t *testing.T) {
+	for _, tool := range []string{"make", "go"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+
+	checkout := t.TempDir()
+	workDir := t.TempDir()
+
+	writeTestFile(t, workDir, "go.mod", "module example.com/xatu-cbt\n\ngo 1.21\n")
+	writeTestFile(t, workDir, "main.go", "package main\n\nimport \"example.com/xatu-cbt/pkg/proto/clickhouse\"\n\n"+
+		"func main() { _ = clickhouse.Tables }\n")
+	writeTestFile(t, workDir, "Makefile", fakeXatuCBTMakefile)
+
+	labCfg := config.DefaultLab()
+	labCfg.Repos.XatuCBT = checkout
+
+	bumper := &dependentBumper{log: logrus.New(), labCfg: labCfg, stateDir: t.TempDir()}
+
+	require.NoError(t, bumper.build(context.Background(), release.DependencyBump{
+		Project:  constants.ProjectXatuCBT,
+		RepoPath: checkout,
+		WorkDir:  workDir,
+	}))
+
+	require.FileExists(t, filepath.Join(workDir, "bin", constants.RepoXatuCBT))
+	require.NoDirExists(t, filepath.Join(checkout, "bin"), "the lab's checkout is not built")
+	require.Equal(t, checkout, labCfg.Repos.XatuCBT, "the lab config is not modified")
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
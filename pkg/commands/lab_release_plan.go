@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethpandaops/xcli/pkg/release"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/sirupsen/logrus"
+)
+
+// runDryRun prints the release plan for the selected projects, in the order
+// a release would execute it, without prompting or releasing anything.
+func runDryRun(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	projectInfos []release.ProjectInfo,
+	projects []string,
+	bumpFlag string,
+	skipDeps bool,
+) error {
+	bump := release.BumpPatch
+
+	switch bumpFlag {
+	case "":
+	case bumpPatch, bumpMinor, bumpMajor:
+		bump = release.BumpType(bumpFlag)
+	default:
+		return fmt.Errorf("invalid bump type: %s (valid: patch, minor, major)", bumpFlag)
+	}
+
+	infoMap := make(map[string]*release.ProjectInfo, len(projectInfos))
+	for i := range projectInfos {
+		infoMap[projectInfos[i].Name] = &projectInfos[i]
+	}
+
+	if !skipDeps {
+		for project, missing := range release.CheckMissingDependencies(projects) {
+			for _, dep := range missing {
+				version := "unknown"
+				if info, ok := infoMap[dep]; ok {
+					version = info.CurrentVersion
+				}
+
+				ui.Info(fmt.Sprintf("%s depends on %s, which is not selected: it will use %s %s",
+					project, dep, dep, version))
+			}
+		}
+	}
+
+	ordered, deps, _ := release.AnalyzeDependencies(projects)
+	configs := make([]release.ProjectReleaseConfig, 0, len(ordered))
+
+	for _, project := range ordered {
+		info, ok := infoMap[project]
+		if !ok {
+			ui.Warning(fmt.Sprintf("Skipping %s: project info not available", project))
+
+			continue
+		}
+
+		cfg := release.ProjectReleaseConfig{Project: project, Info: info}
+
+		if info.IsSemver {
+			current, err := release.ParseVersion(info.CurrentVersion)
+			if err != nil {
+				ui.Warning(fmt.Sprintf("Skipping %s: could not parse version %s: %v", project, info.CurrentVersion, err))
+
+				continue
+			}
+
+			cfg.BumpType = bump
+			cfg.NewVersion = current.Bump(bump).String()
+		}
+
+		configs = append(configs, cfg)
+	}
+
+	attachChangelogs(ctx, log, configs)
+
+	steps := release.BuildPlan(configs, ordered, deps)
+
+	ui.Section("Release Plan (dry run)")
+	ui.Blank()
+
+	rows := make([][]string, 0, len(steps))
+
+	for i, step := range steps {
+		version := step.CurrentVersion
+		action := "dispatch " + step.Workflow
+
+		if step.NextVersion != "" {
+			version = fmt.Sprintf("%s → %s (%s)", step.CurrentVersion, step.NextVersion, step.BumpType)
+			action = "create release " + step.NextVersion + " (tag-triggered build)"
+		}
+
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1), step.Project, version, phaseLabel(step), action, describeChangelog(step.Changelog),
+		})
+	}
+
+	ui.Table([]string{"#", "Project", "Version", "Phase", "Release", "Changes"}, rows)
+
+	for _, step := range steps {
+		if notes := step.Changelog.Markdown(); notes != "" {
+			ui.Blank()
+			ui.Header(fmt.Sprintf("%s release notes", step.Project))
+			fmt.Print(notes)
+		}
+	}
+
+	ui.Blank()
+	ui.Info("Dry run: nothing was released")
+
+	return nil
+}
+
+// phaseLabel describes when a plan step runs.
+func phaseLabel(step release.PlanStep) string {
+	switch step.Phase {
+	case release.PhaseDependencies:
+		return "1: release, wait for build"
+	case release.PhaseDependents:
+		return "2: after " + strings.Join(step.DependsOn, ", ")
+	default:
+		return "3: independent"
+	}
+}
+
+// attachChangelogs generates each config's changelog from its local checkout.
+func attachChangelogs(ctx context.Context, log logrus.FieldLogger, configs []release.ProjectReleaseConfig) {
+	projects := make([]string, 0, len(configs))
+	for _, cfg := range configs {
+		projects = append(projects, cfg.Project)
+	}
+
+	changelogs := generateChangelogs(ctx, log, projects)
+
+	for i := range configs {
+		configs[i].Changelog = changelogs[configs[i].Project]
+	}
+}
+
+// generateChangelogs builds a changelog per project from the commits on
+// origin's default branch since its latest tag, fetching each checkout
+// first. Projects without a checkout or tag are left out.
+func generateChangelogs(ctx context.Context, log logrus.FieldLogger, projects []string) map[string]*release.Changelog {
+	changelogs := make(map[string]*release.Changelog, len(projects))
+
+	repoPaths := loadReleaseRepoPaths(log, projects)
+	if len(repoPaths) == 0 {
+		return changelogs
+	}
+
+	spinner := ui.NewSpinner("Generating changelogs...")
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for project, path := range repoPaths {
+		wg.Go(func() {
+			changelog, err := release.GenerateRemoteChangelog(ctx, project, path)
+			if err != nil {
+				log.WithError(err).WithField("project", project).Warn("failed to generate changelog")
+
+				return
+			}
+
+			mu.Lock()
+			changelogs[project] = changelog
+			mu.Unlock()
+		})
+	}
+
+	wg.Wait()
+
+	spinner.Success(fmt.Sprintf("Generated %d changelog(s)", len(changelogs)))
+
+	return changelogs
+}
+
+// describeChangelog summarizes a changelog for plan output.
+func describeChangelog(changelog *release.Changelog) string {
+	if changelog == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d commits since %s", len(changelog.Entries), changelog.Since)
+}
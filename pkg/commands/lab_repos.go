@@ -0,0 +1,393 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
+	"github.com/ethpandaops/xcli/pkg/instance"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// defaultPinFile is where 'lab repos pin' records commits, relative to the
+// workspace root.
+const defaultPinFile = "xcli.pins.json"
+
+// NewLabReposCommand creates the lab repos command.
+func NewLabReposCommand(log logrus.FieldLogger, configPath string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repos",
+		Short: "Run git workflows across all lab repositories",
+		Long: `Inspect and update the lab repositories (cbt, xatu-cbt, cbt-api, lab-backend,
+lab) together.
+
+Pull and checkout leave repositories with uncommitted changes untouched.`,
+	}
+
+	cmd.AddCommand(newLabReposStatusCommand(log, configPath))
+	cmd.AddCommand(newLabReposPullCommand(configPath))
+	cmd.AddCommand(newLabReposCheckoutCommand(log, configPath))
+	cmd.AddCommand(newLabReposPinCommand(configPath))
+
+	return cmd
+}
+
+func newLabReposStatusCommand(log logrus.FieldLogger, configPath string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show branch, sync state, dirty files and last commit of each repo",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			labCfg, _, err := workspace.LoadLabConfig(configPath, false)
+			if err != nil {
+				return err
+			}
+
+			spinner := ui.NewSpinner("Fetching lab repositories")
+
+			statuses := xcligit.NewChecker(log).CheckRepositories(cmd.Context(), labCfg.Repos.Map())
+
+			byName := make(map[string]xcligit.RepoStatus, len(statuses))
+			for _, status := range statuses {
+				byName[status.Name] = status
+			}
+
+			spinner.Success("Fetched lab repositories")
+
+			rows := make([][]string, 0, len(statuses))
+
+			for _, repo := range labCfg.Repos.Ordered() {
+				status := byName[repo.Name]
+				if status.Error != nil {
+					rows = append(rows, []string{repo.Name, "-", status.Error.Error(), "-", "-"})
+
+					continue
+				}
+
+				dirty := "-"
+				if status.HasUncommitted {
+					dirty = fmt.Sprintf("%d files", status.UncommittedCount)
+				}
+
+				lastCommit := "-"
+				if commit, commitErr := xcligit.LastCommit(cmd.Context(), repo.Path); commitErr == nil {
+					lastCommit = fmt.Sprintf("%s %s (%s)", commit.ShortHash, truncateSubject(commit.Subject), commit.Date)
+				}
+
+				rows = append(rows, []string{repo.Name, status.CurrentBranch, syncState(status), dirty, lastCommit})
+			}
+
+			ui.Table([]string{"Repository", "Branch", "Sync", "Dirty", "Last Commit"}, rows)
+
+			return nil
+		},
+	}
+}
+
+func newLabReposPullCommand(configPath string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull",
+		Short: "Fast-forward every clean repo to its upstream",
+		Long: `Pull every lab repository in parallel with --ff-only. Repositories with
+uncommitted changes are skipped; diverged branches fail without being merged.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			labCfg, _, err := workspace.LoadLabConfig(configPath, false)
+			if err != nil {
+				return err
+			}
+
+			spinner := ui.NewSpinner("Pulling lab repositories")
+
+			results := forEachLabRepo(labCfg, func(repo config.LabRepoPath) string {
+				pulled, pullErr := xcligit.PullFastForward(cmd.Context(), repo.Path)
+
+				switch {
+				case errors.Is(pullErr, xcligit.ErrDirty):
+					return "skipped (uncommitted changes)"
+				case pullErr != nil:
+					return "failed: " + pullErr.Error()
+				case pulled:
+					return "updated"
+				default:
+					return "already up to date"
+				}
+			})
+
+			failed := countFailedRows(results)
+
+			if failed > 0 {
+				spinner.Warning(fmt.Sprintf("%d of %d repositories failed to pull", failed, len(results)))
+			} else {
+				spinner.Success("Pulled lab repositories")
+			}
+
+			ui.Table([]string{"Repository", "Result"}, results)
+
+			if failed > 0 {
+				return fmt.Errorf("%d repositories failed to pull", failed)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newLabReposCheckoutCommand(log logrus.FieldLogger, configPath string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "checkout <branch>",
+		Short: "Check out a branch in every repo that has it",
+		Long: `Check out a branch in every lab repository that has it locally or on origin,
+after fetching. Repositories without the branch, or with uncommitted changes,
+stay where they are.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			labCfg, _, err := workspace.LoadLabConfig(configPath, false)
+			if err != nil {
+				return err
+			}
+
+			branch := args[0]
+			spinner := ui.NewSpinner(fmt.Sprintf("Checking out %s", branch))
+
+			results := forEachLabRepo(labCfg, func(repo config.LabRepoPath) string {
+				ctx := cmd.Context()
+
+				if fetchErr := xcligit.Fetch(ctx, repo.Path); fetchErr != nil {
+					log.WithError(fetchErr).WithField("repo", repo.Name).Debug("fetch failed, using local refs")
+				}
+
+				if !xcligit.HasBranch(ctx, repo.Path, branch) {
+					return "no such branch"
+				}
+
+				checkoutErr := xcligit.Checkout(ctx, repo.Path, branch)
+
+				switch {
+				case errors.Is(checkoutErr, xcligit.ErrDirty):
+					return "skipped (uncommitted changes)"
+				case checkoutErr != nil:
+					return "failed: " + checkoutErr.Error()
+				default:
+					return "checked out"
+				}
+			})
+
+			var checkedOut int
+
+			for _, row := range results {
+				if row[1] == "checked out" {
+					checkedOut++
+				}
+			}
+
+			failed := countFailedRows(results)
+
+			switch {
+			case failed > 0:
+				spinner.Warning(fmt.Sprintf("%d of %d repositories failed to check out %s", failed, len(results), branch))
+			case checkedOut == 0:
+				spinner.Warning(fmt.Sprintf("No repository was switched to %s", branch))
+			default:
+				spinner.Success(fmt.Sprintf("Checked out %s in %d repositories", branch, checkedOut))
+			}
+
+			ui.Table([]string{"Repository", "Result"}, results)
+
+			if failed > 0 {
+				return fmt.Errorf("%d repositories failed to check out %s", failed, branch)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newLabReposPinCommand(configPath string) *cobra.Command {
+	var restore bool
+
+	cmd := &cobra.Command{
+		Use:   "pin [file]",
+		Short: "Record or restore the exact commit of every repo",
+		Long: `Record the current commit of every lab repository in a pin file, or check
+those commits out again with --restore. Commit the pin file so a teammate can
+reproduce the exact stack.
+
+The file defaults to ` + defaultPinFile + ` in the workspace root. --restore
+also accepts an instance manifest.json, restoring the commits that instance
+was started from. Repositories are matched by name, so the pin file works
+across checkouts in different paths.
+
+Examples:
+  xcli lab repos pin
+  xcli lab repos pin --restore
+  xcli lab repos pin --restore .xcli/instances/<id>/manifest.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			labCfg, ws, err := workspace.LoadLabConfig(configPath, false)
+			if err != nil {
+				return err
+			}
+
+			path := filepath.Join(ws.RootDir, defaultPinFile)
+			if len(args) == 1 {
+				path = args[0]
+			}
+
+			if restore {
+				return restorePins(cmd.Context(), labCfg, path)
+			}
+
+			return writePins(cmd.Context(), labCfg, path)
+		},
+	}
+
+	cmd.Flags().BoolVar(&restore, "restore", false, "Check out the pinned commits instead of recording them")
+
+	return cmd
+}
+
+func writePins(ctx context.Context, labCfg *config.LabConfig, path string) error {
+	repos, err := instance.SnapshotLabRepos(ctx, labCfg)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot lab repos: %w", err)
+	}
+
+	if err := xcligit.WritePinFile(path, &xcligit.PinFile{CreatedAt: time.Now().UTC(), Repos: repos}); err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(repos))
+
+	for _, repo := range labCfg.Repos.Ordered() {
+		version := repos[repo.Name]
+
+		rows = append(rows, []string{repo.Name, version.Branch, xcligit.ShortCommit(version.Commit)})
+
+		if version.Dirty {
+			ui.Warning(fmt.Sprintf("%s has uncommitted changes that are not part of the pin", repo.Name))
+		}
+	}
+
+	ui.Table([]string{"Repository", "Branch", "Commit"}, rows)
+	ui.Success(fmt.Sprintf("Pinned %d repositories to %s", len(repos), path))
+
+	return nil
+}
+
+func restorePins(ctx context.Context, labCfg *config.LabConfig, path string) error {
+	pins, err := xcligit.ReadPinFile(path)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+
+	rows := make([][]string, 0, len(pins.Repos))
+
+	for _, repo := range labCfg.Repos.Ordered() {
+		version, ok := pins.Repos[repo.Name]
+		if !ok {
+			rows = append(rows, []string{repo.Name, "-", "not pinned"})
+
+			continue
+		}
+
+		changed, restoreErr := xcligit.Restore(ctx, repo.Path, version)
+
+		result := "restored"
+
+		switch {
+		case errors.Is(restoreErr, xcligit.ErrDirty):
+			failed++
+			result = "skipped (uncommitted changes)"
+		case restoreErr != nil:
+			failed++
+			result = "failed: " + restoreErr.Error()
+		case !changed:
+			result = "already at commit"
+		}
+
+		rows = append(rows, []string{repo.Name, xcligit.ShortCommit(version.Commit), result})
+	}
+
+	ui.Table([]string{"Repository", "Commit", "Result"}, rows)
+
+	if failed > 0 {
+		return fmt.Errorf("%d repositories were not restored to %s", failed, path)
+	}
+
+	ui.Success(fmt.Sprintf("Restored lab repositories from %s", path))
+
+	return nil
+}
+
+// forEachLabRepo runs fn for every lab repository in parallel and returns
+// one [name, result] row per repository in the standard order.
+func forEachLabRepo(labCfg *config.LabConfig, fn func(repo config.LabRepoPath) string) [][]string {
+	repos := labCfg.Repos.Ordered()
+	results := make([]string, len(repos))
+
+	var wg sync.WaitGroup
+
+	for i, repo := range repos {
+		wg.Go(func() {
+			results[i] = fn(repo)
+		})
+	}
+
+	wg.Wait()
+
+	rows := make([][]string, 0, len(repos))
+	for i, repo := range repos {
+		rows = append(rows, []string{repo.Name, results[i]})
+	}
+
+	return rows
+}
+
+// countFailedRows counts the forEachLabRepo rows whose result is a failure.
+func countFailedRows(rows [][]string) int {
+	var failed int
+
+	for _, row := range rows {
+		if strings.HasPrefix(row[1], "failed: ") {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// syncState describes how a repo's branch relates to its upstream.
+func syncState(status xcligit.RepoStatus) string {
+	switch {
+	case status.BehindBy > 0 && status.AheadBy > 0:
+		return fmt.Sprintf("↓%d ↑%d", status.BehindBy, status.AheadBy)
+	case status.BehindBy > 0:
+		return fmt.Sprintf("↓%d behind", status.BehindBy)
+	case status.AheadBy > 0:
+		return fmt.Sprintf("↑%d ahead", status.AheadBy)
+	default:
+		return "up to date"
+	}
+}
+
+// truncateSubject shortens a commit subject to fit the status table.
+func truncateSubject(subject string) string {
+	const maxLen = 50
+
+	runes := []rune(subject)
+	if len(runes) <= maxLen {
+		return subject
+	}
+
+	return string(runes[:maxLen-3]) + "..."
+}
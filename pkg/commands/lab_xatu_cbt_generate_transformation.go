@@ -298,12 +298,12 @@ func runGenerateTransformationTest(
 			return fmt.Errorf("failed to detect range columns: %w", err)
 		}
 
-		discoveryResult, err = seeddata.FallbackRangeDiscovery(ctx, gen, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
+		discoveryResult, err = seeddata.NewHeuristicDiscovery(log, gen).Discover(ctx, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
 		if err != nil {
-			return fmt.Errorf("fallback range discovery failed: %w", err)
+			return fmt.Errorf("heuristic range discovery failed: %w", err)
 		}
 	} else {
-		engine, engineErr := ai.NewEngine(ai.DefaultProvider, log)
+		engine, engineErr := ai.NewEngine(ai.ConfiguredDefaultProvider(), log)
 
 		discoveryClient := seeddata.NewClaudeDiscoveryClient(log, gen, engine)
 		if engineErr != nil || !discoveryClient.IsAvailable() {
@@ -318,9 +318,9 @@ func runGenerateTransformationTest(
 				return fmt.Errorf("failed to detect range columns: %w", err)
 			}
 
-			discoveryResult, err = seeddata.FallbackRangeDiscovery(ctx, gen, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
+			discoveryResult, err = seeddata.NewHeuristicDiscovery(log, gen).Discover(ctx, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
 			if err != nil {
-				return fmt.Errorf("fallback range discovery failed: %w", err)
+				return fmt.Errorf("heuristic range discovery failed: %w", err)
 			}
 		} else {
 			// Gather schema information
@@ -402,9 +402,9 @@ func runGenerateTransformationTest(
 					return fmt.Errorf("failed to detect range columns: %w", rangeErr)
 				}
 
-				discoveryResult, err = seeddata.FallbackRangeDiscovery(ctx, gen, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
+				discoveryResult, err = seeddata.NewHeuristicDiscovery(log, gen).Discover(ctx, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
 				if err != nil {
-					return fmt.Errorf("fallback range discovery failed: %w", err)
+					return fmt.Errorf("heuristic range discovery failed: %w", err)
 				}
 			} else {
 				analysisSpinner.Success(fmt.Sprintf("Strategy generated (confidence: %.0f%%)", discoveryResult.OverallConfidence*100))
@@ -459,6 +459,11 @@ func runGenerateTransformationTest(
 	ui.Blank()
 	ui.Header("Proposed Strategy")
 	ui.Info(fmt.Sprintf("Summary: %s", discoveryResult.Summary))
+
+	for _, reason := range discoveryResult.Reasoning {
+		ui.Info(fmt.Sprintf("  → %s", reason))
+	}
+
 	ui.Blank()
 	ui.Info(fmt.Sprintf("Primary Range: %s (%s)", discoveryResult.PrimaryRangeColumn, discoveryResult.PrimaryRangeType))
 	ui.Info(fmt.Sprintf("  From: %s", discoveryResult.FromValue))
@@ -513,6 +518,16 @@ func runGenerateTransformationTest(
 		if strategy.Optional {
 			ui.Info("      (optional - LEFT JOIN)")
 		}
+
+		// Display reasoning if present
+		if strategy.Reasoning != "" {
+			reasoning := strategy.Reasoning
+			if runes := []rune(reasoning); len(runes) > 80 {
+				reasoning = string(runes[:77]) + "..."
+			}
+
+			ui.Info(fmt.Sprintf("      Reasoning: %s", reasoning))
+		}
 	}
 
 	// Display warnings
@@ -988,7 +1003,7 @@ func promptForTransformationModel(xatuCBTPath string) (string, error) {
 func generateAIAssertions(ctx context.Context, log logrus.FieldLogger, model string, externalModels []string, xatuCBTPath string) ([]seeddata.Assertion, error) {
 	aiSpinner := ui.NewSpinner("Analyzing transformation SQL with Claude")
 
-	engine, engineErr := ai.NewEngine(ai.DefaultProvider, log)
+	engine, engineErr := ai.NewEngine(ai.ConfiguredDefaultProvider(), log)
 	if engineErr != nil {
 		aiSpinner.Fail("AI engine not available")
 
@@ -1176,12 +1191,12 @@ func runSingleModelGeneration(
 			return fmt.Errorf("failed to detect range columns: %w", rangeErr)
 		}
 
-		discoveryResult, err = seeddata.FallbackRangeDiscovery(ctx, gen, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
+		discoveryResult, err = seeddata.NewHeuristicDiscovery(log, gen).Discover(ctx, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
 		if err != nil {
-			return fmt.Errorf("fallback range discovery failed: %w", err)
+			return fmt.Errorf("heuristic range discovery failed: %w", err)
 		}
 	} else {
-		engine, engineErr := ai.NewEngine(ai.DefaultProvider, log)
+		engine, engineErr := ai.NewEngine(ai.ConfiguredDefaultProvider(), log)
 
 		discoveryClient := seeddata.NewClaudeDiscoveryClient(log, gen, engine)
 		if engineErr != nil || !discoveryClient.IsAvailable() {
@@ -1193,9 +1208,9 @@ func runSingleModelGeneration(
 				return fmt.Errorf("failed to detect range columns: %w", rangeErr)
 			}
 
-			discoveryResult, err = seeddata.FallbackRangeDiscovery(ctx, gen, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
+			discoveryResult, err = seeddata.NewHeuristicDiscovery(log, gen).Discover(ctx, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
 			if err != nil {
-				return fmt.Errorf("fallback range discovery failed: %w", err)
+				return fmt.Errorf("heuristic range discovery failed: %w", err)
 			}
 		} else {
 			// Use AI for analysis
@@ -1238,9 +1253,9 @@ func runSingleModelGeneration(
 					return fmt.Errorf("failed to detect range columns: %w", rangeErr)
 				}
 
-				discoveryResult, err = seeddata.FallbackRangeDiscovery(ctx, gen, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
+				discoveryResult, err = seeddata.NewHeuristicDiscovery(log, gen).Discover(ctx, externalModels, network, rangeInfos, duration, labCfg.Repos.XatuCBT)
 				if err != nil {
-					return fmt.Errorf("fallback range discovery failed: %w", err)
+					return fmt.Errorf("heuristic range discovery failed: %w", err)
 				}
 			} else {
 				logInfo(fmt.Sprintf("Claude strategy generated (confidence: %.0f%%)", discoveryResult.OverallConfidence*100))
@@ -1257,6 +1272,10 @@ func runSingleModelGeneration(
 	if discoveryResult != nil {
 		logInfo(fmt.Sprintf("Range: %s [%s → %s]", discoveryResult.PrimaryRangeColumn, discoveryResult.FromValue, discoveryResult.ToValue))
 
+		for _, reason := range discoveryResult.Reasoning {
+			logInfo(fmt.Sprintf("  → %s", reason))
+		}
+
 		for _, strategy := range discoveryResult.Strategies {
 			if strategy.RangeColumn == "" || strategy.ColumnType == seeddata.RangeColumnTypeNone {
 				logInfo(fmt.Sprintf("  • %s: (dimension table - all data)", strategy.Model))
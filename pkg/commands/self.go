@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethpandaops/xcli/pkg/autoupgrade"
+	"github.com/ethpandaops/xcli/pkg/cc"
+	"github.com/ethpandaops/xcli/pkg/config"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/ethpandaops/xcli/pkg/version"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewSelfCommand creates the self command for managing the xcli install.
+func NewSelfCommand(log logrus.FieldLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self",
+		Short: "Manage the xcli installation",
+		Long: `Update, roll back and verify the xcli installation.
+
+xcli upgrades itself from its checkout (registered by 'xcli lab init') at most
+once an hour. Configure it in the global ~/.xcli/config.yaml:
+
+  upgrade:
+    channel: stable   # "main" (default) follows the upstream branch,
+                      # "stable" only upgrades to release tags
+    pin: v0.4.0       # Stay on a tag or commit
+    disabled: true    # Only upgrade with 'xcli self update'
+
+XCLI_UPGRADE_CHANNEL, XCLI_UPGRADE_PIN and XCLI_AUTO_UPGRADE=false override it.
+
+Every upgrade builds the new version, smoke-tests it and keeps the replaced
+binary for 'xcli self rollback'. A build that fails its smoke test, or that is
+rolled back, is not installed again automatically.`,
+	}
+
+	cmd.AddCommand(newSelfUpdateCommand(log))
+	cmd.AddCommand(newSelfRollbackCommand())
+	cmd.AddCommand(newSelfSmokeCommand())
+
+	return cmd
+}
+
+func newSelfUpdateCommand(log logrus.FieldLogger) *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Upgrade xcli now, or show what an upgrade would bring in",
+		Long: `Upgrade xcli to the latest version of its channel, or to its pin, now. Runs
+even when automatic upgrades are disabled.
+
+Examples:
+  xcli self update           # Upgrade now
+  xcli self update --check   # Show the incoming changelog without upgrading`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			repoPath := autoupgrade.FindRepoPath()
+			if repoPath == "" {
+				return fmt.Errorf("xcli checkout not registered: run 'xcli lab init' from your workspace first")
+			}
+
+			upgrader := autoupgrade.NewService(log, repoPath, version.Commit)
+
+			spinner := ui.NewSpinner("Checking for updates...")
+
+			plan, err := upgrader.Check(cmd.Context())
+			if err != nil {
+				spinner.Fail("Failed to check for updates")
+
+				return err
+			}
+
+			channel := plan.Channel
+			if plan.Pinned {
+				channel = "pinned"
+			}
+
+			if plan.UpToDate() {
+				spinner.Success(fmt.Sprintf("xcli is up to date (%s: %s)", channel, plan.Target))
+
+				return nil
+			}
+
+			spinner.Success(fmt.Sprintf("Update available (%s): %s → %s",
+				channel, xcligit.ShortCommit(plan.CurrentCommit), plan.Target))
+
+			if notes := plan.Changelog.Markdown(); notes != "" {
+				ui.Blank()
+				fmt.Print(notes)
+				ui.Blank()
+			} else if plan.Pinned {
+				ui.Info(fmt.Sprintf("Pinned to %s: this moves xcli back from %s",
+					plan.Target, xcligit.ShortCommit(plan.CurrentCommit)))
+			}
+
+			if check {
+				ui.Info("Run 'xcli self update' to upgrade")
+
+				return nil
+			}
+
+			spinner = ui.NewSpinner(fmt.Sprintf("Building and verifying %s...", plan.Target))
+
+			if err := upgrader.Upgrade(cmd.Context(), plan); err != nil {
+				spinner.Fail("Upgrade failed, keeping the current version")
+
+				return err
+			}
+
+			spinner.Success(fmt.Sprintf("xcli upgraded to %s ('xcli self rollback' restores the previous version)",
+				plan.Target))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Show the incoming version and changelog without upgrading")
+
+	return cmd
+}
+
+func newSelfRollbackCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore the xcli binary replaced by the last upgrade",
+		Long: `Restore the xcli binary replaced by the last upgrade. The rolled back version
+is kept, so running rollback again undoes it. Automatic upgrades will not
+reinstall the rolled back version; 'xcli self update' still can.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			result, err := autoupgrade.Rollback(cmd.Context())
+			if errors.Is(err, autoupgrade.ErrNoPreviousBinary) {
+				ui.Info("Nothing to roll back: xcli has not been upgraded yet")
+
+				return nil
+			}
+
+			if err != nil {
+				return err
+			}
+
+			ui.Success(fmt.Sprintf("Rolled back %s", result.Binary))
+			ui.Info(fmt.Sprintf("From: %s", result.From))
+			ui.Info(fmt.Sprintf("To:   %s", result.To))
+
+			return nil
+		},
+	}
+}
+
+// newSelfSmokeCommand checks that a build works before an upgrade installs
+// it.
+func newSelfSmokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    "smoke",
+		Short:  "Smoke-test this xcli build",
+		Hidden: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if _, err := config.LoadGlobalConfig(); err != nil {
+				return fmt.Errorf("global config: %w", err)
+			}
+
+			if err := cc.CheckFrontend(); err != nil {
+				return err
+			}
+
+			fmt.Println("ok")
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,247 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethpandaops/xcli/pkg/bootstrap"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/toolchain"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// NewWorkspaceCommand creates the workspace command.
+func NewWorkspaceCommand(log logrus.FieldLogger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Manage the ethpandaops workspace",
+		Long:  `Set up and repair the ethpandaops workspace: xcli and the lab repositories checked out side by side.`,
+	}
+
+	cmd.AddCommand(newWorkspaceBootstrapCommand(log))
+
+	return cmd
+}
+
+func newWorkspaceBootstrapCommand(log logrus.FieldLogger) *cobra.Command {
+	var (
+		dir                string
+		ssh                bool
+		fork               string
+		jobs               int
+		skipPrerequisites  bool
+		skipToolchainCheck bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Clone and prepare every repository the lab stack needs",
+		Long: `Set up the ethpandaops workspace in one step:
+  1. Clone cbt, xatu-cbt, cbt-api, lab-backend, lab and xcli side by side
+     (in parallel), adding a "fork" remote for your forks with --fork
+  2. Check the Go, Node, pnpm, Docker, protoc and buf installs
+  3. Run each repository's prerequisites (.env files, pnpm install, ...)
+  4. Write the lab configuration to xcli/.xcli.yaml
+
+Existing checkouts, prerequisites and settings are kept, so running it again
+repairs a partially set up workspace.
+
+--dir defaults to the parent directory when run from an xcli checkout, and to
+the current directory otherwise.
+
+Examples:
+  xcli workspace bootstrap --dir ~/src/ethpandaops
+  xcli workspace bootstrap --ssh --fork <github-user>`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if dir == "" {
+				dir = defaultWorkspaceDir()
+			}
+
+			protocol := bootstrap.ProtocolHTTPS
+			if ssh {
+				protocol = bootstrap.ProtocolSSH
+			}
+
+			b, err := bootstrap.New(log, bootstrap.Options{
+				Dir:      dir,
+				Protocol: protocol,
+				Fork:     fork,
+				Jobs:     jobs,
+			})
+			if err != nil {
+				return err
+			}
+
+			return runWorkspaceBootstrap(cmd, b, skipPrerequisites, skipToolchainCheck)
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "ethpandaops directory to check the repositories out in")
+	cmd.Flags().BoolVar(&ssh, "ssh", false, "Clone over SSH instead of HTTPS")
+	cmd.Flags().StringVar(&fork, "fork", "", "GitHub user whose forks are added as the \"fork\" remote")
+	cmd.Flags().IntVar(&jobs, "jobs", 4, "Repositories to clone in parallel")
+	cmd.Flags().BoolVar(&skipPrerequisites, "skip-prerequisites", false, "Do not run repository prerequisites")
+	cmd.Flags().BoolVar(&skipToolchainCheck, "skip-toolchain-check", false, "Do not check installed tool versions")
+
+	return cmd
+}
+
+func runWorkspaceBootstrap(cmd *cobra.Command, b *bootstrap.Bootstrapper, skipPrerequisites, skipToolchainCheck bool) error {
+	ctx := cmd.Context()
+	problems := 0
+
+	ui.Header(fmt.Sprintf("Bootstrapping workspace: %s", b.Dir()))
+
+	spinner := ui.NewSpinner(fmt.Sprintf("Cloning repositories into %s", b.Dir()))
+
+	results := b.CloneAll(ctx)
+
+	rows := make([][]string, 0, len(results))
+	failed := make(map[string]bool, len(results))
+
+	for _, result := range results {
+		state := string(result.State)
+
+		switch {
+		case result.Err != nil:
+			state = fmt.Sprintf("failed: %v", result.Err)
+			failed[result.Name] = true
+			problems++
+		case result.Remote != "":
+			state = fmt.Sprintf("%s, %s remote: %s", state, bootstrap.ForkRemote, result.Remote)
+		}
+
+		rows = append(rows, []string{result.Name, result.Path, state})
+	}
+
+	if len(failed) > 0 {
+		spinner.Warning(fmt.Sprintf("%d of %d repositories could not be set up", len(failed), len(results)))
+	} else {
+		spinner.Success("All repositories checked out")
+	}
+
+	ui.Blank()
+	ui.Table([]string{"Repository", "Path", "State"}, rows)
+
+	if !skipToolchainCheck {
+		ui.Header("Checking toolchain")
+
+		reqs, err := toolchain.ForRepos(b.LabRepos().Map())
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Could not read toolchain requirements: %v", err))
+		}
+
+		// Tool problems only break builds, so they do not fail the bootstrap.
+		if printToolchainResults(toolchain.Check(ctx, append(toolchain.Baseline(), reqs...))) {
+			ui.Success("All tools installed")
+		}
+	}
+
+	if !skipPrerequisites {
+		ui.Header("Running prerequisites")
+
+		spinner = ui.NewSpinner("Running repository prerequisites")
+
+		if errs := b.RunPrerequisites(ctx, results); len(errs) > 0 {
+			spinner.Fail(fmt.Sprintf("Prerequisites failed for %d repositories", len(errs)))
+
+			for _, result := range results {
+				if err, ok := errs[result.Name]; ok {
+					ui.Error(fmt.Sprintf("%s: %v", result.Name, err))
+				}
+			}
+
+			problems += len(errs)
+		} else {
+			spinner.Success("All prerequisites complete")
+		}
+	}
+
+	ui.Header("Writing configuration")
+
+	if len(failed) > 0 {
+		ui.Warning("Skipped: not every repository is checked out")
+	} else {
+		configPath, err := b.WriteConfig()
+		if err != nil {
+			ui.Error(err.Error())
+
+			problems++
+		} else {
+			ui.Success(fmt.Sprintf("Lab configuration written to %s", configPath))
+		}
+	}
+
+	ui.Blank()
+
+	if problems > 0 {
+		ui.Error("Workspace bootstrap incomplete. Fix the issues above and run 'xcli workspace bootstrap' again.")
+
+		return fmt.Errorf("workspace bootstrap incomplete")
+	}
+
+	ui.Success("Workspace ready!")
+	ui.Header("Next steps:")
+	fmt.Printf("  1. cd %s\n", filepath.Join(b.Dir(), constants.RepoXCLI))
+	fmt.Println("  2. Run 'xcli lab init' to set external ClickHouse credentials, if you need them")
+	fmt.Println("  3. Run 'xcli lab up' to start the lab stack")
+
+	return nil
+}
+
+// printToolchainResults prints a table of toolchain check results followed
+// by the remediation of each failing tool. It reports whether every tool is
+// usable.
+func printToolchainResults(results []toolchain.Result) bool {
+	rows := make([][]string, 0, len(results))
+	allOK := true
+
+	for _, result := range results {
+		installed := result.Installed
+		if installed == "" {
+			installed = "-"
+		}
+
+		required, requiredBy := result.Required, result.RequiredBy
+		if required == "" {
+			required, requiredBy = "any", result.Sources
+		}
+
+		rows = append(rows, []string{
+			result.Tool, installed, required, string(result.Status), strings.Join(requiredBy, ", "),
+		})
+
+		if !result.OK() {
+			allOK = false
+		}
+	}
+
+	ui.Table([]string{"Tool", "Installed", "Required", "Status", "Required by"}, rows)
+
+	for _, result := range results {
+		if !result.OK() {
+			ui.Warning(result.Remediation)
+		}
+	}
+
+	return allOK
+}
+
+// defaultWorkspaceDir returns the parent directory when run from an xcli
+// checkout, and the current directory otherwise.
+func defaultWorkspaceDir() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+
+	if _, err := os.Stat(filepath.Join(cwd, "cmd", "xcli")); err == nil {
+		return filepath.Dir(cwd)
+	}
+
+	return cwd
+}
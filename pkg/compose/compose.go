@@ -22,6 +22,31 @@ type ServiceStatus struct {
 	State   string `json:"state"`
 	Status  string `json:"status"`
 	Ports   string `json:"ports"`
+	// Health is the healthcheck status ("healthy", "unhealthy", "starting"),
+	// empty when the service defines no healthcheck.
+	Health   string `json:"health"`
+	ExitCode int    `json:"exitCode"`
+	// Publishers lists the service's port mappings.
+	Publishers []PortPublisher `json:"publishers"`
+}
+
+// PortPublisher is one port mapping of a docker-compose service.
+type PortPublisher struct {
+	URL           string `json:"URL"`
+	TargetPort    int    `json:"TargetPort"`
+	PublishedPort int    `json:"PublishedPort"`
+	Protocol      string `json:"Protocol"`
+}
+
+// PublishedPort returns the host port a container TCP port is published on.
+func (s ServiceStatus) PublishedPort(targetPort int) (int, bool) {
+	for _, p := range s.Publishers {
+		if p.TargetPort == targetPort && p.PublishedPort > 0 && (p.Protocol == "" || p.Protocol == "tcp") {
+			return p.PublishedPort, true
+		}
+	}
+
+	return 0, false
 }
 
 // Runner executes docker-compose commands against a project directory.
@@ -131,9 +156,10 @@ func (r *Runner) Logs(ctx context.Context, service string, follow bool) error {
 	return r.runAttached(ctx, args...)
 }
 
-// PS returns the status of all services in the stack.
+// PS returns the status of all service containers in the stack, including
+// stopped ones.
 func (r *Runner) PS(ctx context.Context) ([]ServiceStatus, error) {
-	cmd := r.buildCommand(ctx, "ps", "--format", "json")
+	cmd := r.buildCommand(ctx, "ps", "--all", "--format", "json")
 
 	var stdout, stderr bytes.Buffer
 
@@ -0,0 +1,183 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Container states and health statuses reported by docker compose ps.
+const (
+	StateRunning = "running"
+	StateExited  = "exited"
+
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+	HealthStarting  = "starting"
+)
+
+// waitPollInterval is how often WaitHealthy polls docker compose ps.
+const waitPollInterval = 2 * time.Second
+
+// Ready reports whether a service container is up: running and healthy when
+// it defines a healthcheck. One-shot containers (migrations, init jobs) are
+// ready once they exit with code 0.
+func (s ServiceStatus) Ready() bool {
+	switch s.State {
+	case StateRunning:
+		return s.Health == "" || s.Health == HealthHealthy
+	case StateExited:
+		return s.ExitCode == 0
+	default:
+		return false
+	}
+}
+
+// Failed reports whether a service container exited with an error.
+func (s ServiceStatus) Failed() bool {
+	return s.State == StateExited && s.ExitCode != 0
+}
+
+// Endpoints returns the host:port addresses the service publishes, one per
+// published port.
+func (s ServiceStatus) Endpoints() []string {
+	endpoints := make([]string, 0, len(s.Publishers))
+	seen := make(map[int]bool, len(s.Publishers))
+
+	for _, p := range s.Publishers {
+		if p.PublishedPort == 0 || seen[p.PublishedPort] {
+			continue
+		}
+
+		seen[p.PublishedPort] = true
+
+		host := p.URL
+		if host == "" || host == "0.0.0.0" || host == "::" {
+			host = "localhost"
+		}
+
+		endpoints = append(endpoints, fmt.Sprintf("%s:%d", host, p.PublishedPort))
+	}
+
+	return endpoints
+}
+
+// WaitHealthy polls the stack until every service container is Ready. It
+// fails as soon as a container exits with an error, or when timeout passes;
+// the returned statuses are from the last poll. onPoll, if set, is called
+// after each poll.
+func (r *Runner) WaitHealthy(
+	ctx context.Context,
+	timeout time.Duration,
+	onPoll func([]ServiceStatus),
+) ([]ServiceStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	var (
+		statuses []ServiceStatus
+		pending  = []string{"containers to be created"}
+	)
+
+	for {
+		current, err := r.PS(ctx)
+
+		switch {
+		case err == nil:
+			statuses = current
+
+			if onPoll != nil {
+				onPoll(statuses)
+			}
+
+			var failed []string
+
+			pending = pending[:0]
+
+			for _, st := range statuses {
+				switch {
+				case st.Failed():
+					failed = append(failed, fmt.Sprintf("%s (exit code %d)", st.Service, st.ExitCode))
+				case !st.Ready():
+					pending = append(pending, st.Service)
+				}
+			}
+
+			if len(failed) > 0 {
+				return statuses, fmt.Errorf("services failed: %s", strings.Join(failed, ", "))
+			}
+
+			if len(statuses) > 0 && len(pending) == 0 {
+				return statuses, nil
+			}
+		case ctx.Err() == nil:
+			return statuses, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return statuses, fmt.Errorf("timed out after %v waiting for %s", timeout, strings.Join(pending, ", "))
+		case <-ticker.C:
+		}
+	}
+}
+
+// RestartCounts returns how often docker restarted each container, keyed by
+// container name.
+func (r *Runner) RestartCounts(ctx context.Context, containers []string) (map[string]int, error) {
+	counts := make(map[string]int, len(containers))
+	if len(containers) == 0 {
+		return counts, nil
+	}
+
+	args := append([]string{"inspect", "--format", "{{.Name}} {{.RestartCount}}"}, containers...)
+
+	//nolint:gosec // G204: container names come from docker compose ps
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Env = r.buildCommand(ctx).Env
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("docker inspect failed: %w (stderr: %s)", err, stderr.String())
+	}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(stdout.String()), "\n") {
+		name, count, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+
+		if n, err := strconv.Atoi(count); err == nil {
+			counts[strings.TrimPrefix(name, "/")] = n
+		}
+	}
+
+	return counts, nil
+}
+
+// LogTail returns the last lines of a service's logs.
+func (r *Runner) LogTail(ctx context.Context, service string, lines int) (string, error) {
+	cmd := r.buildCommand(ctx, "logs", "--no-color", "--tail", strconv.Itoa(lines), service)
+
+	var output bytes.Buffer
+
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker compose logs failed: %w", err)
+	}
+
+	return strings.TrimRight(output.String(), "\n"), nil
+}
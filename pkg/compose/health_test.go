@@ -0,0 +1,42 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceStatusReadiness(t *testing.T) {
+	tests := []struct {
+		name   string
+		status ServiceStatus
+		ready  bool
+		failed bool
+	}{
+		{name: "running without healthcheck", status: ServiceStatus{State: StateRunning}, ready: true},
+		{name: "healthy", status: ServiceStatus{State: StateRunning, Health: HealthHealthy}, ready: true},
+		{name: "starting", status: ServiceStatus{State: StateRunning, Health: HealthStarting}},
+		{name: "unhealthy", status: ServiceStatus{State: StateRunning, Health: HealthUnhealthy}},
+		{name: "restarting", status: ServiceStatus{State: "restarting"}},
+		{name: "one-shot done", status: ServiceStatus{State: StateExited}, ready: true},
+		{name: "crashed", status: ServiceStatus{State: StateExited, ExitCode: 1}, failed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.ready, tt.status.Ready())
+			require.Equal(t, tt.failed, tt.status.Failed())
+		})
+	}
+}
+
+func TestServiceStatusEndpoints(t *testing.T) {
+	status := ServiceStatus{Publishers: []PortPublisher{
+		{URL: "0.0.0.0", TargetPort: 8123, PublishedPort: 8123, Protocol: "tcp"},
+		{URL: "::", TargetPort: 8123, PublishedPort: 8123, Protocol: "tcp"},
+		{URL: "127.0.0.1", TargetPort: 9000, PublishedPort: 19000, Protocol: "tcp"},
+		{TargetPort: 9009},
+	}}
+
+	require.Equal(t, []string{"localhost:8123", "127.0.0.1:19000"}, status.Endpoints())
+}
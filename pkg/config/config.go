@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -46,12 +47,18 @@ func EffectiveConfigPath(path string) string {
 // Config represents the xcli root configuration
 // It contains stack-specific configurations and optional workspace-level settings.
 type Config struct {
+	// Version is the config schema version, see SchemaVersion.
+	Version int `yaml:"version,omitempty"`
+
 	// Lab stack configuration
 	Lab *LabConfig `yaml:"lab,omitempty"`
 
 	// Xatu stack configuration (docker-compose based)
 	Xatu *XatuConfig `yaml:"xatu,omitempty"`
 
+	// Profiles are named lab config fragments selected with --profile.
+	Profiles map[string]any `yaml:"profiles,omitempty"`
+
 	// Workspace-level settings (optional, for future use)
 	// Workspace *WorkspaceConfig `yaml:"workspace,omitempty"`
 }
@@ -66,11 +73,25 @@ type LabConfig struct {
 	Ports          LabPortsConfig       `yaml:"ports"`
 	Dev            LabDevConfig         `yaml:"dev"`
 	TUI            TUIConfig            `yaml:"tui"`
+	// Resources caps CPU and memory per service, keyed by service name
+	// (cbt-mainnet) or kind (cbt, cbt-api, lab-backend, lab-frontend).
+	Resources map[string]ResourceLimitsConfig `yaml:"resources,omitempty"`
+
+	// Profile is the profile merged into this config by Load; never saved.
+	Profile string `yaml:"-"`
+	// XatuStack is the xatu stack section of the same config file, used as
+	// the data source in xatu-local mode; never saved.
+	XatuStack *XatuConfig `yaml:"-"`
 }
 
-// LabInstanceConfig contains per-instance identity settings.
+// LabInstanceConfig contains per-instance identity and port settings.
 type LabInstanceConfig struct {
 	ID string `yaml:"id,omitempty"`
+	// Slot pins the instance to one port slot instead of the first free one.
+	Slot *int `yaml:"slot,omitempty"`
+	// Ports pins named ports (as shown by 'xcli lab ports') to fixed values,
+	// overriding the slot-derived port.
+	Ports map[string]int `yaml:"ports,omitempty"`
 }
 
 // LabReposConfig contains paths to lab repositories.
@@ -99,6 +120,25 @@ func (r LabReposConfig) Map() map[string]string {
 	}
 }
 
+// With returns a copy with the named repository at path. Unknown names leave
+// the copy unchanged.
+func (r LabReposConfig) With(name, path string) LabReposConfig {
+	switch name {
+	case constants.RepoCBT:
+		r.CBT = path
+	case constants.RepoXatuCBT:
+		r.XatuCBT = path
+	case constants.RepoCBTAPI:
+		r.CBTAPI = path
+	case constants.RepoLabBackend:
+		r.LabBackend = path
+	case constants.RepoLab:
+		r.Lab = path
+	}
+
+	return r
+}
+
 // Ordered returns lab repositories in the standard display/init order.
 func (r LabReposConfig) Ordered() []LabRepoPath {
 	return []LabRepoPath{
@@ -110,12 +150,34 @@ func (r LabReposConfig) Ordered() []LabRepoPath {
 	}
 }
 
-// NetworkConfig represents a network configuration.
+// NetworkConfig represents a network configuration. Built-in networks
+// (mainnet, sepolia, hoodi, holesky) only need a name; custom devnets set
+// their genesis and, where they differ from the defaults, the rest.
 type NetworkConfig struct {
 	Name             string `yaml:"name"`
 	Enabled          bool   `yaml:"enabled"`
 	PortOffset       int    `yaml:"portOffset"`
 	GenesisTimestamp uint64 `yaml:"genesisTimestamp,omitempty"` // Optional: Unix timestamp for custom networks
+	// RedisDB is the Redis database index holding the network's CBT state.
+	// 'xcli lab network add' allocates it so it never moves.
+	RedisDB *int `yaml:"redisDB,omitempty"`
+	// SecondsPerSlot is the consensus slot duration. Default: 12.
+	SecondsPerSlot int `yaml:"secondsPerSlot,omitempty"`
+	// Forks maps consensus fork names to their activation epoch.
+	Forks map[string]uint64 `yaml:"forks,omitempty"`
+	// Database is the external Xatu database holding this network's data.
+	// Defaults to infrastructure.clickhouse.xatu.externalDatabase.
+	Database string `yaml:"database,omitempty"`
+	// Backfill sets how far back external models start.
+	Backfill NetworkBackfillConfig `yaml:"backfill,omitempty"`
+}
+
+// NetworkBackfillConfig bounds the data external models backfill.
+type NetworkBackfillConfig struct {
+	// Window is how far before now external models start, e.g. 6h. Default: 1h.
+	Window string `yaml:"window,omitempty"`
+	// MinBlock is the first execution block external models read.
+	MinBlock uint64 `yaml:"minBlock,omitempty"`
 }
 
 // InfrastructureConfig contains infrastructure settings.
@@ -124,6 +186,7 @@ type InfrastructureConfig struct {
 	Redis              RedisConfig         `yaml:"redis"`
 	Volumes            VolumesConfig       `yaml:"volumes"`
 	Observability      ObservabilityConfig `yaml:"observability"`
+	Docker             DockerHostConfig    `yaml:"docker,omitempty"`
 	ClickHouseXatuPort int                 `yaml:"clickhouseXatuPort"`
 	ClickHouseCBTPort  int                 `yaml:"clickhouseCbtPort"`
 	RedisPort          int                 `yaml:"redisPort"`
@@ -198,6 +261,37 @@ func (c ClickHouseClusterConfig) ExternalURLWithCredentials() (string, error) {
 	return parsed.String(), nil
 }
 
+// maskedSecret replaces secrets in displayed configuration.
+const maskedSecret = "********"
+
+// Redacted returns a copy safe to display: the password is masked and the
+// URL's embedded password replaced.
+func (c ClickHouseClusterConfig) Redacted() ClickHouseClusterConfig {
+	if c.ExternalPassword != "" {
+		c.ExternalPassword = maskedSecret
+	}
+
+	if c.ExternalURL != "" {
+		if parsed, err := url.Parse(c.ExternalURL); err == nil {
+			c.ExternalURL = parsed.Redacted()
+		} else {
+			c.ExternalURL = maskedSecret
+		}
+	}
+
+	return c
+}
+
+// Redacted returns a copy of the lab config safe to display, with the
+// external ClickHouse credentials redacted.
+func (c *LabConfig) Redacted() *LabConfig {
+	redacted := *c
+	redacted.Infrastructure.ClickHouse.Xatu = c.Infrastructure.ClickHouse.Xatu.Redacted()
+	redacted.Infrastructure.ClickHouse.CBT = c.Infrastructure.ClickHouse.CBT.Redacted()
+
+	return &redacted
+}
+
 // RedisConfig contains Redis configuration.
 type RedisConfig struct {
 	Port int `yaml:"port"`
@@ -215,6 +309,22 @@ type ObservabilityConfig struct {
 	GrafanaPort    int  `yaml:"grafanaPort,omitempty"`
 }
 
+// DockerHostConfig selects the Docker daemon that runs lab infrastructure.
+// When empty, DOCKER_HOST, DOCKER_CONTEXT and the current docker context are
+// used, in that order.
+type DockerHostConfig struct {
+	// Host is a Docker endpoint such as ssh://user@devbox or tcp://devbox:2376.
+	Host string `yaml:"host,omitempty"`
+	// Context names a docker context to read the endpoint from.
+	Context string `yaml:"context,omitempty"`
+	// Address is the host local services dial to reach remote infrastructure
+	// when no tunnel is used. Defaults to the endpoint's hostname.
+	Address string `yaml:"address,omitempty"`
+	// Tunnel forwards the infrastructure ports over SSH to localhost.
+	// Defaults to true for ssh:// endpoints.
+	Tunnel *bool `yaml:"tunnel,omitempty"`
+}
+
 // LabPortsConfig contains lab stack port assignments.
 type LabPortsConfig struct {
 	LabBackend      int `yaml:"labBackend"`
@@ -255,10 +365,20 @@ type XatuReposConfig struct {
 type LoadResult struct {
 	Config     *Config
 	ConfigPath string // Absolute path to the config file that was loaded
+	// Migration is set when the file uses an older schema. Load migrates it
+	// in memory only; MigrateFile ('xcli config migrate') rewrites the file.
+	Migration *MigrationResult
 }
 
 // Save writes the configuration to a file.
 func (c *Config) Save(path string) error {
+	// A profile-merged config would bake the profile into the base file.
+	if c.Lab != nil && c.Lab.Profile != "" {
+		return fmt.Errorf("cannot save config while profile %q is applied; rerun with --profile %s", c.Lab.Profile, NoProfile)
+	}
+
+	c.Version = SchemaVersion
+
 	// Marshal to YAML
 	data, err := yaml.Marshal(c)
 	if err != nil {
@@ -318,8 +438,8 @@ func (c *LabConfig) ValidateRepos() error {
 // Validate checks if the lab configuration is valid.
 func (c *LabConfig) Validate() error {
 	// Check mode
-	if c.Mode != constants.ModeLocal && c.Mode != constants.ModeHybrid {
-		return fmt.Errorf("invalid mode: %s (must be '%s' or '%s')", c.Mode, constants.ModeLocal, constants.ModeHybrid)
+	if !slices.Contains(constants.Modes, c.Mode) {
+		return fmt.Errorf("invalid mode: %s (must be one of %s)", c.Mode, strings.Join(constants.Modes, ", "))
 	}
 
 	// Check repo paths exist
@@ -342,6 +462,10 @@ func (c *LabConfig) Validate() error {
 		return fmt.Errorf("at least one network must be enabled")
 	}
 
+	if err := ValidateNetworks(c.Networks); err != nil {
+		return err
+	}
+
 	// Validate hybrid mode configuration
 	if c.Mode == constants.ModeHybrid && c.Infrastructure.ClickHouse.Xatu.Mode == constants.InfraModeExternal {
 		if c.Infrastructure.ClickHouse.Xatu.ExternalURL == "" {
@@ -362,9 +486,44 @@ func (c *LabConfig) Validate() error {
 		}
 	}
 
+	if c.Mode == constants.ModeXatuLocal && (c.XatuStack == nil || c.XatuStack.Repos.Xatu == "") {
+		return fmt.Errorf("xatu-local mode reads from the xatu stack, but it is not configured - run 'xcli xatu init' first")
+	}
+
+	if err := c.Infrastructure.Docker.Validate(); err != nil {
+		return fmt.Errorf("invalid infrastructure.docker: %w", err)
+	}
+
+	if err := ValidateResources(c.Resources); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Validate checks the Docker host settings.
+func (c DockerHostConfig) Validate() error {
+	if c.Host != "" && c.Context != "" {
+		return fmt.Errorf("host and context are mutually exclusive")
+	}
+
+	if c.Host == "" {
+		return nil
+	}
+
+	scheme, _, found := strings.Cut(c.Host, "://")
+	if !found {
+		return fmt.Errorf("host %q must include a scheme (unix://, tcp:// or ssh://)", c.Host)
+	}
+
+	switch scheme {
+	case "unix", "tcp", "ssh":
+		return nil
+	default:
+		return fmt.Errorf("unsupported host scheme %q (must be unix, tcp or ssh)", scheme)
+	}
+}
+
 // EnabledNetworks returns a slice of enabled networks.
 func (c *LabConfig) EnabledNetworks() []NetworkConfig {
 	enabled := make([]NetworkConfig, 0, len(c.Networks))
@@ -459,7 +618,8 @@ func LoadXatuConfig(configPath string) (*XatuConfig, string, error) {
 // Default returns a root configuration with sensible defaults.
 func Default() *Config {
 	return &Config{
-		Lab: DefaultLab(),
+		Version: SchemaVersion,
+		Lab:     DefaultLab(),
 	}
 }
 
@@ -475,9 +635,9 @@ func DefaultLab() *LabConfig {
 		},
 		Mode: constants.ModeHybrid,
 		Networks: []NetworkConfig{
-			{Name: "mainnet", Enabled: true, PortOffset: 0},
-			{Name: "sepolia", Enabled: false, PortOffset: 1},
-			{Name: "hoodi", Enabled: false, PortOffset: 2},
+			{Name: "mainnet", Enabled: true, PortOffset: 0, RedisDB: new(0)},
+			{Name: "sepolia", Enabled: false, PortOffset: 1, RedisDB: new(1)},
+			{Name: "hoodi", Enabled: false, PortOffset: 2, RedisDB: new(2)},
 		},
 		Infrastructure: InfrastructureConfig{
 			ClickHouse: ClickHouseConfig{
@@ -516,10 +676,20 @@ func DefaultLab() *LabConfig {
 	}
 }
 
-// Load reads and parses a config file.
+// Load reads and parses a config file with the active profile applied.
 // If the path is ".xcli.yaml" (the default), it will search upward through parent directories.
 // Returns the config and the resolved absolute path to the config file.
 func Load(path string) (*LoadResult, error) {
+	return LoadWithProfile(path, activeProfile)
+}
+
+// LoadWithProfile is Load with an explicit profile. An empty profile or
+// NoProfile loads the base config.
+func LoadWithProfile(path, profile string) (*LoadResult, error) {
+	if profile == NoProfile {
+		profile = ""
+	}
+
 	path = EffectiveConfigPath(path)
 
 	// If using default path, search for it in parent directories
@@ -535,6 +705,10 @@ func Load(path string) (*LoadResult, error) {
 
 	// Check if file exists
 	if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+		if profile != "" {
+			return nil, fmt.Errorf("profile %q requires a config file, none found at %s", profile, absPath)
+		}
+
 		// If original path was searched for and not found, return default
 		return &LoadResult{
 			Config:     Default(),
@@ -548,6 +722,22 @@ func Load(path string) (*LoadResult, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	data, migration, err := migrateData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate %s: %w", absPath, err)
+	}
+
+	if err := CheckSchema(absPath, data); err != nil {
+		return nil, err
+	}
+
+	if profile != "" {
+		data, err = mergeProfile(absPath, data, profile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Try to parse as new format first
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
@@ -557,9 +747,14 @@ func Load(path string) (*LoadResult, error) {
 	// Apply defaults to loaded config
 	cfg.setDefaults()
 
+	if cfg.Lab != nil {
+		cfg.Lab.Profile = profile
+	}
+
 	return &LoadResult{
 		Config:     &cfg,
 		ConfigPath: absPath,
+		Migration:  migration,
 	}, nil
 }
 
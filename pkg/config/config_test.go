@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -104,3 +105,98 @@ func TestExternalURLWithCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateNetworksRequiresGenesisForCustomNetworks(t *testing.T) {
+	builtin := []NetworkConfig{{Name: "mainnet"}, {Name: "holesky", PortOffset: 1}}
+	require.NoError(t, ValidateNetworks(builtin))
+
+	devnet := NetworkConfig{Name: "devnet_1", PortOffset: 3}
+	require.ErrorContains(t, ValidateNetworks(append(builtin, devnet)), "needs genesisTimestamp")
+
+	devnet.GenesisTimestamp = 1753300800
+	require.NoError(t, ValidateNetworks(append(builtin, devnet)))
+	assert.Equal(t, 12, devnet.SlotSeconds())
+
+	devnet.Backfill.Window = "a week"
+	require.ErrorContains(t, ValidateNetworks(append(builtin, devnet)), "invalid backfill.window")
+
+	require.ErrorContains(t, ValidateNetworks([]NetworkConfig{{Name: "mainnet"}, {Name: "mainnet"}}), "more than once")
+	require.ErrorContains(t, ValidateNetworks([]NetworkConfig{{Name: "Devnet"}}), "invalid network name")
+	require.ErrorContains(t, ValidateNetworks([]NetworkConfig{{Name: "fusaka-devnet-3"}}), "invalid network name")
+}
+
+func TestRedisDBStaysWithNetwork(t *testing.T) {
+	// Without redisDB, enabled networks take their position among the
+	// enabled networks, as configs written before the field expect.
+	cfg := &LabConfig{Networks: []NetworkConfig{
+		{Name: "mainnet", Enabled: true},
+		{Name: "sepolia", Enabled: true, PortOffset: 1},
+		{Name: "holesky", Enabled: false, PortOffset: 2},
+		{Name: "hoodi", Enabled: true, PortOffset: 3},
+	}}
+
+	db, ok := cfg.RedisDB("hoodi")
+	require.True(t, ok)
+	assert.Equal(t, 2, db)
+
+	_, ok = cfg.RedisDB("holesky")
+	assert.False(t, ok, "a disabled network without redisDB has no database")
+
+	_, ok = cfg.RedisDB("gnosis")
+	assert.False(t, ok, "an unknown network must not fall back to mainnet's database")
+
+	require.NoError(t, cfg.PinRedisDBs())
+
+	dbs := make(map[string]int, len(cfg.Networks))
+	for _, net := range cfg.Networks {
+		require.NotNil(t, net.RedisDB, net.Name)
+		dbs[net.Name] = *net.RedisDB
+	}
+
+	assert.Equal(t, map[string]int{"mainnet": 0, "sepolia": 1, "holesky": 3, "hoodi": 2}, dbs)
+	require.NoError(t, ValidateNetworks(cfg.Networks))
+
+	// Removing a network in the middle moves none of the others.
+	cfg.Networks = slices.Delete(cfg.Networks, 1, 2)
+
+	db, ok = cfg.RedisDB("hoodi")
+	require.True(t, ok)
+	assert.Equal(t, 2, db)
+
+	free, err := cfg.FreeRedisDB()
+	require.NoError(t, err)
+	assert.Equal(t, 1, free)
+}
+
+func TestValidateNetworksRejectsSharedRedisDB(t *testing.T) {
+	networks := []NetworkConfig{
+		{Name: "mainnet", RedisDB: new(0)},
+		{Name: "sepolia", PortOffset: 1, RedisDB: new(0)},
+	}
+	require.ErrorContains(t, ValidateNetworks(networks), `networks "mainnet" and "sepolia" both use redisDB 0`)
+
+	networks[1].RedisDB = new(RedisDatabases)
+	require.ErrorContains(t, ValidateNetworks(networks), "redisDB must be between 0 and 15")
+}
+
+func TestResourceLimitsFallsBackToServiceKind(t *testing.T) {
+	cfg := &LabConfig{Resources: map[string]ResourceLimitsConfig{
+		"cbt":             {CPUs: 2, Memory: "2g"},
+		"cbt-api-mainnet": {Nice: 5},
+	}}
+
+	assert.Equal(t, int64(2<<30), cfg.ResourceLimits("cbt-hoodi").MemoryBytes())
+	assert.Equal(t, 5, cfg.ResourceLimits("cbt-api-mainnet").Nice)
+	assert.True(t, cfg.ResourceLimits("cbt-api-hoodi").IsZero())
+	assert.True(t, cfg.ResourceLimits("lab-backend").IsZero())
+
+	for value, want := range map[string]int64{"": 0, "1024": 1024, "512m": 512 << 20, "1.5GiB": 3 << 29, "64kb": 64 << 10} {
+		got, err := ParseMemory(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	require.ErrorContains(t, ValidateResources(map[string]ResourceLimitsConfig{"cbt": {Memory: "lots"}}), "resources.cbt")
+	require.ErrorContains(t, ValidateResources(map[string]ResourceLimitsConfig{"cbt": {Nice: 20}}), "between 0 and 19")
+	require.ErrorContains(t, ValidateResources(map[string]ResourceLimitsConfig{"cbt": {CPUs: 0.001}}), "at least 0.01")
+}
@@ -11,9 +11,45 @@ import (
 
 // GlobalConfig represents the global xcli configuration stored in ~/.xcli/config.yaml.
 type GlobalConfig struct {
-	XCLIPath          string    `yaml:"xcliPath,omitempty"`
-	LastUpgradeCheck  time.Time `yaml:"lastUpgradeCheck,omitempty"`
-	LastUpgradeCommit string    `yaml:"lastUpgradeCommit,omitempty"`
+	XCLIPath          string              `yaml:"xcliPath,omitempty"`
+	LastUpgradeCheck  time.Time           `yaml:"lastUpgradeCheck,omitempty"`
+	LastUpgradeCommit string              `yaml:"lastUpgradeCommit,omitempty"`
+	Upgrade           GlobalUpgradeConfig `yaml:"upgrade,omitempty"`
+	AI                GlobalAIConfig      `yaml:"ai,omitempty"`
+}
+
+// GlobalUpgradeConfig controls how xcli upgrades itself from its checkout.
+type GlobalUpgradeConfig struct {
+	// Channel is "main" (default), following the checkout's upstream branch,
+	// or "stable", which only upgrades to release tags.
+	Channel string `yaml:"channel,omitempty"`
+	// Pin is a tag or commit to stay on. Upgrades install it and go no further.
+	Pin string `yaml:"pin,omitempty"`
+	// Disabled turns off automatic upgrades; 'xcli self update' still works.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// SkipCommit is a commit automatic upgrades will not install, set when
+	// its build fails the smoke test or is rolled back.
+	SkipCommit string `yaml:"skipCommit,omitempty"`
+}
+
+// GlobalAIConfig selects and configures the AI provider used by diagnose and
+// seed-data features.
+type GlobalAIConfig struct {
+	// Provider is the default provider: claude, openai or local.
+	Provider string `yaml:"provider,omitempty"`
+	// OpenAI configures a hosted OpenAI-compatible API.
+	OpenAI AIEndpointConfig `yaml:"openai,omitempty"`
+	// Local configures a local OpenAI-compatible server (Ollama, llama.cpp, vLLM).
+	Local AIEndpointConfig `yaml:"local,omitempty"`
+}
+
+// AIEndpointConfig configures an OpenAI-compatible chat completions endpoint.
+type AIEndpointConfig struct {
+	BaseURL   string        `yaml:"baseUrl,omitempty"`
+	Model     string        `yaml:"model,omitempty"`
+	APIKey    string        `yaml:"apiKey,omitempty"`
+	APIKeyEnv string        `yaml:"apiKeyEnv,omitempty"`
+	Timeout   time.Duration `yaml:"timeout,omitempty"`
 }
 
 // LoadGlobalConfig loads the global config from ~/.xcli/config.yaml.
@@ -84,9 +120,13 @@ func SetXCLIPath(path string) error {
 		return fmt.Errorf("no .xcli.yaml found in %s", absPath)
 	}
 
-	cfg := &GlobalConfig{
-		XCLIPath: absPath,
+	// Preserve the rest of the global config (e.g. AI provider settings)
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		cfg = &GlobalConfig{}
 	}
 
+	cfg.XCLIPath = absPath
+
 	return SaveGlobalConfig(cfg)
 }
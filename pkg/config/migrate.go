@@ -0,0 +1,263 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the .xcli.yaml schema version this xcli reads and writes.
+// Files without a version key are version 1.
+const SchemaVersion = 2
+
+// migration upgrades a config document from one schema version to the next.
+// apply reports whether it changed the document.
+type migration struct {
+	from        int
+	description string
+	apply       func(root *yaml.Node) (bool, error)
+}
+
+// migrations run in order; each moves a document from .from to .from+1.
+var migrations = []migration{
+	{
+		from:        1,
+		description: "move top-level lab settings under lab:",
+		apply:       wrapLegacyLabConfig,
+	},
+}
+
+// MigrationResult describes a config file upgraded to SchemaVersion.
+type MigrationResult struct {
+	From int
+	To   int
+	// Backup is the copy of the original file, empty until MigrateFile
+	// writes the migration.
+	Backup string
+	// Applied lists the migrations that changed the document.
+	Applied []string
+}
+
+// MigrateFile upgrades a config file to SchemaVersion in place, keeping the
+// original as <path>.v<N>.bak. It returns nil when no migration changes the
+// file.
+func MigrateFile(path string) (*MigrationResult, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	migrated, result, err := migrateData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+
+	if result == nil {
+		return nil, nil //nolint:nilnil // the file is already at SchemaVersion
+	}
+
+	result.Backup = fmt.Sprintf("%s.v%d.bak", path, result.From)
+
+	if err := os.WriteFile(result.Backup, data, info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("failed to back up config before migration: %w", err)
+	}
+
+	// CC and the CLI may load the file while it is rewritten.
+	if err := writeFileAtomic(path, migrated, info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("failed to write migrated config: %w", err)
+	}
+
+	return result, nil
+}
+
+// writeFileAtomic replaces a file through a temporary file in the same
+// directory, so readers see either the old or the new content.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("failed to chmod temp file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// migrateData applies every pending migration to raw config data. The result
+// is nil when no migration changed the document; a file that only lacks the
+// version key is left as is.
+func migrateData(data []byte) ([]byte, *MigrationResult, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	// An empty file has nothing to migrate; Load treats it as defaults.
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return data, nil, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("line %d: config must be a map", root.Line)
+	}
+
+	version, err := documentVersion(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if version > SchemaVersion {
+		return nil, nil, fmt.Errorf("config schema version %d is newer than this xcli supports (%d); upgrade xcli with 'xcli self update'", version, SchemaVersion)
+	}
+
+	if version == SchemaVersion {
+		return data, nil, nil
+	}
+
+	result := &MigrationResult{From: version, To: SchemaVersion}
+
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+
+		changed, err := m.apply(root)
+		if err != nil {
+			return nil, nil, fmt.Errorf("migration from version %d (%s): %w", m.from, m.description, err)
+		}
+
+		if changed {
+			result.Applied = append(result.Applied, m.description)
+		}
+	}
+
+	if len(result.Applied) == 0 {
+		return data, nil, nil
+	}
+
+	setDocumentVersion(root, SchemaVersion)
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+
+	return buf.Bytes(), result, nil
+}
+
+// documentVersion reads the version key of a config document.
+func documentVersion(root *yaml.Node) (int, error) {
+	_, value := mappingEntry(root, "version")
+	if value == nil {
+		return 1, nil
+	}
+
+	version, err := strconv.Atoi(value.Value)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("line %d: version must be a positive integer, got %q", value.Line, value.Value)
+	}
+
+	return version, nil
+}
+
+// setDocumentVersion sets the version key, adding it first when missing.
+func setDocumentVersion(root *yaml.Node, version int) {
+	if _, value := mappingEntry(root, "version"); value != nil {
+		value.Value = strconv.Itoa(version)
+		value.Tag = "!!int"
+
+		return
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// mappingEntry returns the key and value nodes of a mapping entry, or nils.
+func mappingEntry(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+// wrapLegacyLabConfig moves lab settings written at the top level, as early
+// versions of 'xcli lab init' did, under a lab: key. Files that already have
+// a lab: key are left alone.
+func wrapLegacyLabConfig(root *yaml.Node) (bool, error) {
+	if key, _ := mappingEntry(root, "lab"); key != nil {
+		return false, nil
+	}
+
+	labKeys := make(map[string]bool)
+	for _, name := range fieldNames(labConfigType) {
+		labKeys[name] = true
+	}
+
+	lab := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	kept := make([]*yaml.Node, 0, len(root.Content))
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if labKeys[key.Value] {
+			lab.Content = append(lab.Content, key, value)
+
+			continue
+		}
+
+		kept = append(kept, key, value)
+	}
+
+	if len(lab.Content) == 0 {
+		return false, nil
+	}
+
+	labKey := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "lab"}
+	root.Content = append(kept, labKey, lab)
+
+	return true, nil
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+)
+
+// networkNamePattern keeps network names usable as unquoted ClickHouse
+// database names (the transformation database is named after the network),
+// Redis keys and service name suffixes.
+var networkNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_]*$`)
+
+// IsBuiltin reports whether xcli knows the network without extra settings.
+func (n NetworkConfig) IsBuiltin() bool {
+	_, ok := constants.NetworkGenesisTimestamps[n.Name]
+
+	return ok
+}
+
+// Genesis returns the network's genesis time in Unix seconds.
+func (n NetworkConfig) Genesis() uint64 {
+	if n.GenesisTimestamp != 0 {
+		return n.GenesisTimestamp
+	}
+
+	return constants.NetworkGenesisTimestamps[n.Name]
+}
+
+// SlotSeconds returns the network's slot duration in seconds.
+func (n NetworkConfig) SlotSeconds() int {
+	if n.SecondsPerSlot > 0 {
+		return n.SecondsPerSlot
+	}
+
+	return constants.DefaultSecondsPerSlot
+}
+
+// BackfillWindow returns how far before now external models start.
+func (n NetworkConfig) BackfillWindow() time.Duration {
+	window := n.Backfill.Window
+	if window == "" {
+		window = constants.DefaultBackfillWindow
+	}
+
+	d, err := time.ParseDuration(window)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(constants.DefaultBackfillWindow)
+	}
+
+	return d
+}
+
+// MinBlock returns the first execution block external models read.
+func (n NetworkConfig) MinBlock() uint64 {
+	if n.Backfill.MinBlock != 0 {
+		return n.Backfill.MinBlock
+	}
+
+	return constants.NetworkMinBlocks[n.Name]
+}
+
+// SortedForks returns the fork names ordered by activation epoch.
+func (n NetworkConfig) SortedForks() []string {
+	names := make([]string, 0, len(n.Forks))
+	for name := range n.Forks {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if n.Forks[names[i]] != n.Forks[names[j]] {
+			return n.Forks[names[i]] < n.Forks[names[j]]
+		}
+
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// Network returns the configured network with the given name.
+func (c *LabConfig) Network(name string) (NetworkConfig, bool) {
+	for _, net := range c.Networks {
+		if net.Name == name {
+			return net, true
+		}
+	}
+
+	return NetworkConfig{}, false
+}
+
+// RedisDatabases is the number of databases the lab's Redis serves (the
+// Redis default), which bounds the redisDB a network can use.
+const RedisDatabases = 16
+
+// RedisDB returns the Redis database index CBT uses for a network. Networks
+// keep the redisDB they were given when added; enabled networks without one,
+// from configs written before it existed, take the free indexes in config
+// order. It reports false for an unknown network and for a disabled network
+// without a redisDB.
+func (c *LabConfig) RedisDB(network string) (int, bool) {
+	db, ok := c.redisDBs()[network]
+
+	return db, ok
+}
+
+// redisDBs resolves the Redis database of every network that has one.
+func (c *LabConfig) redisDBs() map[string]int {
+	dbs := make(map[string]int, len(c.Networks))
+	used := make(map[int]bool, len(c.Networks))
+
+	for _, net := range c.Networks {
+		if net.RedisDB != nil {
+			dbs[net.Name] = *net.RedisDB
+			used[*net.RedisDB] = true
+		}
+	}
+
+	next := 0
+
+	for _, net := range c.Networks {
+		if net.RedisDB != nil || !net.Enabled {
+			continue
+		}
+
+		for used[next] {
+			next++
+		}
+
+		dbs[net.Name] = next
+		used[next] = true
+	}
+
+	return dbs
+}
+
+// PinRedisDBs writes each network's current Redis database into its
+// redisDB, giving disabled networks without one the lowest free index, so
+// adding, removing, enabling or disabling networks later moves none of them.
+func (c *LabConfig) PinRedisDBs() error {
+	dbs := c.redisDBs()
+
+	for i := range c.Networks {
+		net := &c.Networks[i]
+		if net.RedisDB != nil {
+			continue
+		}
+
+		db, ok := dbs[net.Name]
+		if !ok {
+			free, err := c.FreeRedisDB()
+			if err != nil {
+				return fmt.Errorf("network %q: %w", net.Name, err)
+			}
+
+			db = free
+		}
+
+		net.RedisDB = new(db)
+		dbs[net.Name] = db
+	}
+
+	return nil
+}
+
+// FreeRedisDB returns the lowest Redis database index no network uses.
+func (c *LabConfig) FreeRedisDB() (int, error) {
+	used := make(map[int]bool, len(c.Networks))
+	for _, db := range c.redisDBs() {
+		used[db] = true
+	}
+
+	for db := range RedisDatabases {
+		if !used[db] {
+			return db, nil
+		}
+	}
+
+	return 0, fmt.Errorf("all %d Redis databases are in use", RedisDatabases)
+}
+
+// XatuDatabase returns the external Xatu database holding a network's data.
+func (c *LabConfig) XatuDatabase(network string) string {
+	if net, ok := c.Network(network); ok && net.Database != "" {
+		return net.Database
+	}
+
+	if db := c.Infrastructure.ClickHouse.Xatu.ExternalDatabase; db != "" {
+		return db
+	}
+
+	return "default"
+}
+
+// ValidateNetworks checks network names and the settings custom networks need.
+func ValidateNetworks(networks []NetworkConfig) error {
+	seen := make(map[string]bool, len(networks))
+	redisDBs := make(map[int]string, len(networks))
+
+	for _, net := range networks {
+		if !networkNamePattern.MatchString(net.Name) {
+			return fmt.Errorf("invalid network name %q (use lowercase letters, digits and '_')", net.Name)
+		}
+
+		if seen[net.Name] {
+			return fmt.Errorf("network %q is defined more than once", net.Name)
+		}
+
+		seen[net.Name] = true
+
+		if net.Genesis() == 0 {
+			return fmt.Errorf("network %q is not a built-in network and needs genesisTimestamp", net.Name)
+		}
+
+		if net.PortOffset < 0 {
+			return fmt.Errorf("network %q: portOffset must not be negative", net.Name)
+		}
+
+		if net.RedisDB != nil {
+			if *net.RedisDB < 0 || *net.RedisDB >= RedisDatabases {
+				return fmt.Errorf("network %q: redisDB must be between 0 and %d", net.Name, RedisDatabases-1)
+			}
+
+			if other, taken := redisDBs[*net.RedisDB]; taken {
+				return fmt.Errorf("networks %q and %q both use redisDB %d", other, net.Name, *net.RedisDB)
+			}
+
+			redisDBs[*net.RedisDB] = net.Name
+		}
+
+		if net.SecondsPerSlot < 0 {
+			return fmt.Errorf("network %q: secondsPerSlot must be positive", net.Name)
+		}
+
+		if net.Backfill.Window != "" {
+			if d, err := time.ParseDuration(net.Backfill.Window); err != nil || d <= 0 {
+				return fmt.Errorf("network %q: invalid backfill.window %q (use a duration such as 6h)", net.Name, net.Backfill.Window)
+			}
+		}
+	}
+
+	return nil
+}
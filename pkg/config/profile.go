@@ -0,0 +1,363 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ProfilesDirName is the directory of profile files next to .xcli.yaml.
+	ProfilesDirName = ".xcli.profiles"
+
+	// NoProfile selects the base config, ignoring any profile recorded for
+	// an instance.
+	NoProfile = "none"
+
+	// ProfileSourceInline marks profiles defined under profiles: in .xcli.yaml.
+	ProfileSourceInline = "inline"
+)
+
+var activeProfile string
+
+// SetActiveProfile records the profile selected by the --profile flag.
+func SetActiveProfile(name string) {
+	activeProfile = strings.TrimSpace(name)
+}
+
+// ActiveProfile returns the profile selected on the command line, if any.
+func ActiveProfile() string {
+	return activeProfile
+}
+
+// Profile is a named lab config fragment deep-merged over the base config.
+type Profile struct {
+	Name string
+	// Source is the profile file, or ProfileSourceInline.
+	Source string
+	// Fragment holds the lab settings the profile overrides.
+	Fragment map[string]any
+}
+
+// ListProfiles returns the profiles available to a config file, sorted by
+// name. A profile may not be defined both inline and as a file.
+func ListProfiles(configPath string) ([]Profile, error) {
+	root, err := readRawConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return listProfiles(configPath, root)
+}
+
+// LoadProfile returns one named profile.
+func LoadProfile(configPath, name string) (*Profile, error) {
+	profiles, err := ListProfiles(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return findProfile(profiles, name)
+}
+
+func findProfile(profiles []Profile, name string) (*Profile, error) {
+	names := make([]string, 0, len(profiles))
+
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return &profiles[i], nil
+		}
+
+		names = append(names, profiles[i].Name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("profile %q not found (no profiles defined)", name)
+	}
+
+	return nil, fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(names, ", "))
+}
+
+func listProfiles(configPath string, root map[string]any) ([]Profile, error) {
+	byName := make(map[string]Profile)
+
+	inline, _ := root["profiles"].(map[string]any)
+	for name, raw := range inline {
+		fragment, ok := raw.(map[string]any)
+		if !ok && raw != nil {
+			return nil, fmt.Errorf("profile %q in %s must be a map of lab settings", name, configPath)
+		}
+
+		byName[name] = Profile{Name: name, Source: ProfileSourceInline, Fragment: fragment}
+	}
+
+	dir := filepath.Join(filepath.Dir(configPath), ProfilesDirName)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), ext)
+		path := filepath.Join(dir, entry.Name())
+
+		if existing, ok := byName[name]; ok {
+			return nil, fmt.Errorf("profile %q is defined in both %s and %s", name, existing.Source, path)
+		}
+
+		fragment, err := readProfileFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		byName[name] = Profile{Name: name, Source: path, Fragment: fragment}
+	}
+
+	profiles := make([]Profile, 0, len(byName))
+	for _, profile := range byName {
+		if profile.Name == NoProfile {
+			return nil, fmt.Errorf("profile name %q is reserved", NoProfile)
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+
+	return profiles, nil
+}
+
+// applyProfile deep-merges a profile over the lab section of a raw config.
+func applyProfile(configPath string, root map[string]any, name string) (map[string]any, error) {
+	profiles, err := listProfiles(configPath, root)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := findProfile(profiles, name)
+	if err != nil {
+		return nil, err
+	}
+
+	lab, _ := root["lab"].(map[string]any)
+	if lab == nil {
+		lab = map[string]any{}
+	}
+
+	root["lab"] = MergeYAMLMaps(lab, profile.Fragment)
+
+	return root, nil
+}
+
+// mergeProfile returns raw config data with the named profile applied.
+func mergeProfile(configPath string, data []byte, name string) ([]byte, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	if root == nil {
+		root = map[string]any{}
+	}
+
+	root, err := applyProfile(configPath, root, name)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+
+	return merged, nil
+}
+
+// MergeYAMLMaps deep-merges src over dst and returns dst. Nested maps merge
+// key by key; every other value, including lists and false or zero values,
+// replaces the base value.
+func MergeYAMLMaps(dst, src map[string]any) map[string]any {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+
+		if srcIsMap && dstIsMap {
+			dst[key] = MergeYAMLMaps(dstMap, srcMap)
+
+			continue
+		}
+
+		dst[key] = value
+	}
+
+	return dst
+}
+
+func readRawConfig(path string) (map[string]any, error) {
+	root, err := readYAMLMap(path)
+	if os.IsNotExist(err) {
+		return map[string]any{}, nil
+	}
+
+	return root, err
+}
+
+// readProfileFile reads a profile file, checking it against the lab schema.
+func readProfileFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSchema(path, data, labConfigType); err != nil {
+		return nil, err
+	}
+
+	return parseYAMLMap(path, data)
+}
+
+func readYAMLMap(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseYAMLMap(path, data)
+}
+
+func parseYAMLMap(path string, data []byte) (map[string]any, error) {
+	var out map[string]any
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if out == nil {
+		out = map[string]any{}
+	}
+
+	return out, nil
+}
+
+// ConfigChange is one setting that differs between two lab configs.
+type ConfigChange struct {
+	Path string
+	From string
+	To   string
+}
+
+// DiffLabConfigs lists the settings that differ between two lab configs,
+// sorted by dotted path. Lists compare as a whole. Credentials are compared
+// as set but reported redacted.
+func DiffLabConfigs(from, to *LabConfig) ([]ConfigChange, error) {
+	fromFlat, err := flattenLabConfig(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toFlat, err := flattenLabConfig(to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromShown, err := flattenLabConfig(from.Redacted())
+	if err != nil {
+		return nil, err
+	}
+
+	toShown, err := flattenLabConfig(to.Redacted())
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool, len(fromFlat)+len(toFlat))
+	for path := range fromFlat {
+		paths[path] = true
+	}
+
+	for path := range toFlat {
+		paths[path] = true
+	}
+
+	changes := make([]ConfigChange, 0)
+
+	for path := range paths {
+		if fromFlat[path] != toFlat[path] {
+			changes = append(changes, ConfigChange{Path: path, From: fromShown[path], To: toShown[path]})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+
+	return changes, nil
+}
+
+// flattenLabConfig renders a lab config as dotted paths to YAML scalars.
+func flattenLabConfig(cfg *LabConfig) (map[string]string, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal lab config: %w", err)
+	}
+
+	var tree map[string]any
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse lab config: %w", err)
+	}
+
+	flat := make(map[string]string)
+	if err := flattenInto(flat, "", tree); err != nil {
+		return nil, err
+	}
+
+	return flat, nil
+}
+
+func flattenInto(flat map[string]string, prefix string, value any) error {
+	if m, ok := value.(map[string]any); ok && len(m) > 0 {
+		for key, child := range m {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+
+			if err := flattenInto(flat, path, child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Lists render in YAML flow style so each stays on one line.
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return err
+	}
+
+	setFlowStyle(&node)
+
+	data, err := yaml.Marshal(&node)
+	if err != nil {
+		return err
+	}
+
+	flat[prefix] = strings.TrimSpace(string(data))
+
+	return nil
+}
+
+func setFlowStyle(node *yaml.Node) {
+	node.Style |= yaml.FlowStyle
+	for _, child := range node.Content {
+		setFlowStyle(child)
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+)
+
+// ResourceLimitsConfig caps one service's resources. Limits are enforced
+// with a cgroup v2 scope where systemd-run is available; elsewhere the
+// service runs under nice and ionice instead and memory is not capped.
+type ResourceLimitsConfig struct {
+	// CPUs is the CPU quota in cores, e.g. 1.5.
+	CPUs float64 `yaml:"cpus,omitempty"`
+	// Memory is the memory cap, e.g. 512m or 4g.
+	Memory string `yaml:"memory,omitempty"`
+	// Nice is the scheduling niceness (0-19) the service runs at.
+	Nice int `yaml:"nice,omitempty"`
+}
+
+// IsZero reports whether no limit is set.
+func (r ResourceLimitsConfig) IsZero() bool {
+	return r.CPUs == 0 && r.Memory == "" && r.Nice == 0
+}
+
+// MemoryBytes returns the memory cap in bytes, or 0 when none is set.
+func (r ResourceLimitsConfig) MemoryBytes() int64 {
+	bytes, _ := ParseMemory(r.Memory)
+
+	return bytes
+}
+
+// ResourceLimits returns the limits for a service: its own entry, or else
+// the entry for its kind (cbt, cbt-api).
+func (c *LabConfig) ResourceLimits(service string) ResourceLimitsConfig {
+	if limits, ok := c.Resources[service]; ok {
+		return limits
+	}
+
+	switch {
+	case strings.HasPrefix(service, constants.ServicePrefixCBTAPI):
+		return c.Resources[strings.TrimSuffix(constants.ServicePrefixCBTAPI, "-")]
+	case strings.HasPrefix(service, constants.ServicePrefixCBT):
+		return c.Resources[strings.TrimSuffix(constants.ServicePrefixCBT, "-")]
+	default:
+		return ResourceLimitsConfig{}
+	}
+}
+
+// ValidateResources checks per-service resource limits.
+func ValidateResources(resources map[string]ResourceLimitsConfig) error {
+	for service, limits := range resources {
+		if limits.CPUs < 0 {
+			return fmt.Errorf("resources.%s: cpus must not be negative", service)
+		}
+
+		// The cgroup CPU quota is set in whole percent of a core.
+		if limits.CPUs > 0 && limits.CPUs < 0.01 {
+			return fmt.Errorf("resources.%s: cpus must be at least 0.01", service)
+		}
+
+		if limits.Nice < 0 || limits.Nice > 19 {
+			return fmt.Errorf("resources.%s: nice must be between 0 and 19", service)
+		}
+
+		if _, err := ParseMemory(limits.Memory); err != nil {
+			return fmt.Errorf("resources.%s: %w", service, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseMemory parses a memory size such as 512m, 4g or 1073741824. Suffixes
+// k, m and g (optionally followed by b or ib) are binary multiples.
+func ParseMemory(value string) (int64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return 0, nil
+	}
+
+	number := strings.TrimRight(value, "kmgib")
+	unit := strings.TrimSuffix(strings.TrimSuffix(value[len(number):], "b"), "i")
+
+	var multiplier int64
+
+	switch unit {
+	case "":
+		multiplier = 1
+	case "k":
+		multiplier = 1 << 10
+	case "m":
+		multiplier = 1 << 20
+	case "g":
+		multiplier = 1 << 30
+	default:
+		return 0, fmt.Errorf("invalid memory %q (use a size such as 512m or 4g)", value)
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid memory %q (use a size such as 512m or 4g)", value)
+	}
+
+	return int64(n * float64(multiplier)), nil
+}
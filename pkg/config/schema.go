@@ -0,0 +1,374 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is one schema problem in a config file.
+type Issue struct {
+	// Path is the dotted path of the offending key, e.g. lab.infrastructure.redis.
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String formats the issue as line:column: path: message.
+func (i Issue) String() string {
+	if i.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", i.Line, i.Column, i.Message)
+	}
+
+	return fmt.Sprintf("%d:%d: %s: %s", i.Line, i.Column, i.Path, i.Message)
+}
+
+// SchemaError lists every schema issue found in a config file.
+type SchemaError struct {
+	File   string
+	Issues []Issue
+}
+
+func (e *SchemaError) Error() string {
+	lines := make([]string, 0, len(e.Issues)+1)
+	lines = append(lines, fmt.Sprintf("%s does not match the config schema:", e.File))
+
+	for _, issue := range e.Issues {
+		lines = append(lines, fmt.Sprintf("  %s:%s", e.File, issue))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var (
+	configType    = reflect.TypeFor[Config]()
+	labConfigType = reflect.TypeFor[LabConfig]()
+)
+
+// schemaField is a YAML key of a config struct.
+type schemaField struct {
+	name string
+	typ  reflect.Type
+}
+
+// schemaFields returns the YAML keys of a config struct in declaration order.
+// Profiles are free-form in Config but are checked as lab config fragments.
+func schemaFields(t reflect.Type) []schemaField {
+	fields := make([]schemaField, 0, t.NumField())
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		typ := f.Type
+		if t == configType && f.Name == "Profiles" {
+			typ = reflect.MapOf(reflect.TypeFor[string](), labConfigType)
+		}
+
+		fields = append(fields, schemaField{name: name, typ: typ})
+	}
+
+	return fields
+}
+
+// CheckSchema strictly checks config file data against the Config schema.
+// Unknown keys and values of the wrong type are reported with their line and
+// column; a nil error means the data decodes cleanly.
+func CheckSchema(file string, data []byte) error {
+	return checkSchema(file, data, configType)
+}
+
+// checkSchema checks YAML data against a config type.
+func checkSchema(file string, data []byte, t reflect.Type) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+
+	var issues []Issue
+
+	checkNode(&root, t, "", &issues)
+
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return &SchemaError{File: file, Issues: issues}
+}
+
+func checkNode(node *yaml.Node, t reflect.Type, path string, issues *[]Issue) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			checkNode(node.Content[0], t, path, issues)
+		}
+
+		return
+	case yaml.AliasNode:
+		checkNode(node.Alias, t, path, issues)
+
+		return
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	report := func(n *yaml.Node, format string, args ...any) {
+		*issues = append(*issues, Issue{Path: path, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			report(node, "expected a map, got %s", describeNode(node))
+
+			return
+		}
+
+		fields := schemaFields(t)
+		names := fieldNames(t)
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := joinPath(path, key.Value)
+
+			field, ok := lookupField(fields, key.Value)
+			if !ok {
+				msg := fmt.Sprintf("unknown field %q", key.Value)
+				if suggestion := suggest(key.Value, names); suggestion != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				} else if t == configType {
+					// Lab settings at the top level are usually meant for lab:.
+					if suggestion := suggest(key.Value, fieldNames(labConfigType)); suggestion != "" {
+						msg += fmt.Sprintf(" (did you mean \"lab.%s\"?)", suggestion)
+					}
+				}
+
+				*issues = append(*issues, Issue{Path: keyPath, Line: key.Line, Column: key.Column, Message: msg})
+
+				continue
+			}
+
+			checkNode(value, field.typ, keyPath, issues)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			report(node, "expected a map, got %s", describeNode(node))
+
+			return
+		}
+
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkNode(node.Content[i+1], t.Elem(), joinPath(path, node.Content[i].Value), issues)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			report(node, "expected a list, got %s", describeNode(node))
+
+			return
+		}
+
+		for i, item := range node.Content {
+			checkNode(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), issues)
+		}
+	default:
+		if node.Kind != yaml.ScalarNode {
+			report(node, "expected %s, got %s", schemaTypeName(t), describeNode(node))
+
+			return
+		}
+
+		if err := node.Decode(reflect.New(t).Interface()); err != nil {
+			report(node, "expected %s, got %q", schemaTypeName(t), node.Value)
+		}
+	}
+}
+
+func fieldNames(t reflect.Type) []string {
+	fields := schemaFields(t)
+	names := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		names = append(names, f.name)
+	}
+
+	return names
+}
+
+func lookupField(fields []schemaField, name string) (schemaField, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+
+	return schemaField{}, false
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a map"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return strconv.Quote(node.Value)
+	}
+}
+
+func schemaTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return "a string"
+	}
+}
+
+// suggest returns the candidate closest to name, or "" when none is close
+// enough to be a likely typo.
+func suggest(name string, candidates []string) string {
+	best, bestDist := "", -1
+
+	for _, candidate := range candidates {
+		dist := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = candidate, dist
+		}
+	}
+
+	if bestDist < 0 || bestDist > max(2, len(name)/3) {
+		return ""
+	}
+
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) for .xcli.yaml, for
+// editor validation and autocompletion.
+func JSONSchema() ([]byte, error) {
+	schema := jsonSchemaFor(configType)
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "xcli configuration"
+
+	if props, ok := schema["properties"].(map[string]any); ok {
+		props["version"] = map[string]any{
+			"type":        "integer",
+			"minimum":     1,
+			"maximum":     SchemaVersion,
+			"description": "Config schema version; older files are migrated automatically.",
+		}
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+func jsonSchemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		props := make(map[string]any)
+		for _, f := range schemaFields(t) {
+			props[f.name] = jsonSchemaFor(f.typ)
+		}
+
+		return map[string]any{
+			"type":                 []string{"object", "null"},
+			"properties":           props,
+			"additionalProperties": false,
+		}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 []string{"object", "null"},
+			"additionalProperties": jsonSchemaFor(t.Elem()),
+		}
+	case reflect.Slice:
+		return map[string]any{
+			"type":  []string{"array", "null"},
+			"items": jsonSchemaFor(t.Elem()),
+		}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	default:
+		return map[string]any{}
+	}
+}
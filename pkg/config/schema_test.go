@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSchemaReportsPositionsAndSuggestions(t *testing.T) {
+	data := []byte(`version: 2
+lab:
+  mdoe: local
+  networks:
+    - name: mainnet
+      portOffset: one
+  infrastructure:
+    redis:
+      prot: 6380
+profiles:
+  fast:
+    tuii:
+      maxLogLines: 10
+colour: blue
+`)
+
+	err := CheckSchema(".xcli.yaml", data)
+
+	var schemaErr *SchemaError
+	require.True(t, errors.As(err, &schemaErr))
+	require.Equal(t, []Issue{
+		{Path: "lab.mdoe", Line: 3, Column: 3, Message: `unknown field "mdoe" (did you mean "mode"?)`},
+		{Path: "lab.networks[0].portOffset", Line: 6, Column: 19, Message: `expected an integer, got "one"`},
+		{Path: "lab.infrastructure.redis.prot", Line: 9, Column: 7, Message: `unknown field "prot" (did you mean "port"?)`},
+		{Path: "profiles.fast.tuii", Line: 12, Column: 5, Message: `unknown field "tuii" (did you mean "tui"?)`},
+		{Path: "colour", Line: 14, Column: 1, Message: `unknown field "colour"`},
+	}, schemaErr.Issues)
+	require.Contains(t, err.Error(), ".xcli.yaml:3:3: lab.mdoe")
+
+	require.NoError(t, CheckSchema(".xcli.yaml", []byte("version: 2\nlab:\n  mode: local\n  instance:\n")))
+}
+
+func TestLoadMigratesLegacyConfigInMemory(t *testing.T) {
+	legacy := `# generated by xcli lab init
+repos:
+  cbt: ../cbt
+mode: local
+networks:
+  - name: mainnet
+    enabled: true
+`
+	path := filepath.Join(t.TempDir(), DefaultConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0600))
+
+	result, err := LoadWithProfile(path, "")
+	require.NoError(t, err)
+	require.NotNil(t, result.Migration)
+	require.Equal(t, 1, result.Migration.From)
+	require.Equal(t, SchemaVersion, result.Migration.To)
+	require.Empty(t, result.Migration.Backup)
+	require.Equal(t, "local", result.Config.Lab.Mode)
+	require.Equal(t, "../cbt", result.Config.Lab.Repos.CBT)
+
+	// Loading never rewrites the file.
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, legacy, string(data))
+	require.NoFileExists(t, path+".v1.bak")
+}
+
+func TestMigrateFileKeepsBackup(t *testing.T) {
+	legacy := "# generated by xcli lab init\nmode: local\n"
+	path := filepath.Join(t.TempDir(), DefaultConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte(legacy), 0600))
+
+	result, err := MigrateFile(path)
+	require.NoError(t, err)
+	require.Equal(t, path+".v1.bak", result.Backup)
+	require.Equal(t, []string{"move top-level lab settings under lab:"}, result.Applied)
+
+	backup, err := os.ReadFile(result.Backup)
+	require.NoError(t, err)
+	require.Equal(t, legacy, string(backup))
+
+	migrated, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(migrated), "version: 2\nlab:\n")
+	require.Contains(t, string(migrated), "# generated by xcli lab init")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A current file is left alone.
+	result, err = MigrateFile(path)
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func TestMigrateFileSkipsUnchangedLegacyConfig(t *testing.T) {
+	// Version 1 files already using lab: only lack the version key.
+	path := filepath.Join(t.TempDir(), DefaultConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte("lab:\n  mode: local\n"), 0600))
+
+	result, err := MigrateFile(path)
+	require.NoError(t, err)
+	require.Nil(t, result)
+	require.NoFileExists(t, path+".v1.bak")
+
+	loaded, err := LoadWithProfile(path, "")
+	require.NoError(t, err)
+	require.Nil(t, loaded.Migration)
+}
+
+func TestExampleConfigIsCurrent(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", ".xcli.example.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, CheckSchema(".xcli.example.yaml", data))
+
+	_, result, err := migrateData(data)
+	require.NoError(t, err)
+	require.Nil(t, result)
+}
+
+func TestLoadRejectsNewerSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultConfigFileName)
+	require.NoError(t, os.WriteFile(path, []byte("version: 99\nlab:\n  mode: local\n"), 0600))
+
+	_, err := LoadWithProfile(path, "")
+	require.ErrorContains(t, err, "config schema version 99 is newer than this xcli supports (2); upgrade xcli with 'xcli self update'")
+}
+
+func TestJSONSchemaDescribesConfig(t *testing.T) {
+	data, err := JSONSchema()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	props := schema["properties"].(map[string]any)
+	require.Contains(t, props, "version")
+	require.Contains(t, props, "lab")
+	require.Equal(t, false, schema["additionalProperties"])
+
+	lab := props["lab"].(map[string]any)["properties"].(map[string]any)
+	require.Equal(t, map[string]any{"type": "string"}, lab["mode"])
+	require.NotContains(t, lab, "profile")
+
+	profiles := props["profiles"].(map[string]any)["additionalProperties"].(map[string]any)
+	require.Contains(t, profiles["properties"], "networks")
+}
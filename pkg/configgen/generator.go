@@ -16,6 +16,7 @@ import (
 	"dario.cat/mergo"
 	"github.com/ethpandaops/xcli/pkg/config"
 	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/dockerhost"
 	"github.com/ethpandaops/xcli/pkg/instance"
 	"github.com/ethpandaops/xcli/pkg/seeddata"
 	"github.com/sirupsen/logrus"
@@ -53,12 +54,10 @@ func NewGenerator(log logrus.FieldLogger, cfg *config.LabConfig) *Generator {
 }
 
 // NewRuntimeGenerator creates a Generator bound to a resolved instance runtime.
-func NewRuntimeGenerator(log logrus.FieldLogger, runtime *instance.Runtime) *Generator {
-	var cfg *config.LabConfig
-	if runtime != nil {
-		cfg = runtime.LabConfig
-	}
-
+// cfg is the configuration the instance runs with: runtime.LabConfig, or the
+// orchestrator's copy once xatu-local mode has resolved the xatu stack's
+// ClickHouse into it.
+func NewRuntimeGenerator(log logrus.FieldLogger, cfg *config.LabConfig, runtime *instance.Runtime) *Generator {
 	return &Generator{
 		log:     log.WithField("component", "config-generator"),
 		cfg:     cfg,
@@ -94,6 +93,29 @@ func (g *Generator) prometheusPort() int {
 	return g.portPlan().Prometheus
 }
 
+// infraHost returns the host services use to reach ClickHouse and Redis,
+// which is the remote Docker host when it is reached without a tunnel.
+func (g *Generator) infraHost() string {
+	if g.cfg == nil {
+		return dockerhost.LocalServiceHost
+	}
+
+	target, err := dockerhost.Resolve(g.cfg.Infrastructure.Docker)
+	if err != nil {
+		g.log.WithError(err).Warn("failed to resolve Docker host, using localhost")
+
+		return dockerhost.LocalServiceHost
+	}
+
+	return target.ServiceHost()
+}
+
+// networkStopped reports whether a network was stopped on the bound instance,
+// so lab-backend stops routing to it.
+func (g *Generator) networkStopped(network string) bool {
+	return g.runtime != nil && g.runtime.Manifest.NetworkStopped(network)
+}
+
 func (g *Generator) portPlan() instance.PortPlan {
 	fallback := instance.DefaultPortPlan()
 
@@ -115,11 +137,19 @@ func (g *Generator) portPlan() instance.PortPlan {
 	return plan.WithDefaults(fallback)
 }
 
-func (g *Generator) xatuCBTModelsPath(kind string) string {
-	if g.runtime != nil && g.runtime.LabConfig != nil && g.runtime.LabConfig.Repos.XatuCBT != "" {
-		return filepath.Join(g.runtime.LabConfig.Repos.XatuCBT, keyModels, kind)
+// network returns the config of a network; unknown names get the defaults of
+// a built-in network with that name, if any.
+func (g *Generator) network(name string) config.NetworkConfig {
+	if g.cfg != nil {
+		if net, ok := g.cfg.Network(name); ok {
+			return net
+		}
 	}
 
+	return config.NetworkConfig{Name: name}
+}
+
+func (g *Generator) xatuCBTModelsPath(kind string) string {
 	if g.cfg != nil && g.cfg.Repos.XatuCBT != "" {
 		return filepath.Join(g.cfg.Repos.XatuCBT, keyModels, kind)
 	}
@@ -131,42 +161,33 @@ func (g *Generator) xatuCBTModelsPath(kind string) string {
 // It generates a base config from template, then deep merges auto-generated
 // defaults and user overrides on top. User overrides take ultimate precedence.
 func (g *Generator) GenerateCBTConfig(network string, userOverridesPath string) (string, error) {
-	redisDB := 0
-
-	for i, net := range g.cfg.EnabledNetworks() {
-		if net.Name == network {
-			redisDB = i // mainnet=0, sepolia=1, holesky=2, etc.
-
-			break
-		}
+	redisDB, ok := g.cfg.RedisDB(network)
+	if !ok {
+		return "", fmt.Errorf("network %s has no Redis database (not configured or not enabled)", network)
 	}
 
 	// Determine the external ClickHouse database name
 	// - If Xatu mode is "local", use "default" (local Xatu cluster uses default database)
-	// - If Xatu mode is "external", use configured ExternalDatabase (or "default" if not set)
+	// - If Xatu mode is "external", use the network's database, then the
+	//   configured ExternalDatabase (or "default" if neither is set)
 	externalDatabase := "default"
-
-	xatuCfg := g.cfg.Infrastructure.ClickHouse.Xatu
-	if xatuCfg.Mode == constants.InfraModeExternal &&
-		xatuCfg.ExternalDatabase != "" {
-		externalDatabase = g.cfg.Infrastructure.ClickHouse.Xatu.ExternalDatabase
+	if g.cfg.Infrastructure.ClickHouse.Xatu.Mode == constants.InfraModeExternal {
+		externalDatabase = g.cfg.XatuDatabase(network)
 	}
 
 	networkPorts := g.networkPorts(network)
-
-	var genesisTimestamp uint64
-	if timestamp, ok := constants.NetworkGenesisTimestamps[network]; ok {
-		genesisTimestamp = timestamp
-	}
+	networkCfg := g.network(network)
 
 	data := map[string]any{
 		"Network":                    network,
+		"InfraHost":                  g.infraHost(),
 		"ClickHouseHTTPPort":         g.clickHouseCBTHTTPPort(),
 		"MetricsPort":                networkPorts.CBTMetrics,
 		"RedisDB":                    redisDB,
 		"RedisPort":                  g.redisPort(),
 		"FrontendPort":               networkPorts.CBTFrontend,
-		"GenesisTimestamp":           genesisTimestamp,
+		"GenesisTimestamp":           networkCfg.Genesis(),
+		"SecondsPerSlot":             networkCfg.SlotSeconds(),
 		"ExternalClickHouseDatabase": externalDatabase,
 		"ExternalModelsPath":         g.xatuCBTModelsPath("external"),
 		"TransformationModelsPath":   g.xatuCBTModelsPath("transformations"),
@@ -244,6 +265,7 @@ func (g *Generator) GenerateCBTAPIConfig(network string) (string, error) {
 		"Network":              network,
 		keyPort:                networkPorts.CBTAPI,
 		"MetricsPort":          networkPorts.CBTAPIMetrics,
+		"InfraHost":            g.infraHost(),
 		"ClickHouseNativePort": g.clickHouseCBTNativePort(),
 	}
 
@@ -266,7 +288,10 @@ func (g *Generator) GenerateCBTAPIConfig(network string) (string, error) {
 func (g *Generator) GenerateLabBackendConfig(
 	userOverridesPath string,
 ) (string, error) {
-	isHybrid := g.cfg.Infrastructure.ClickHouse.Xatu.Mode == constants.InfraModeExternal
+	// The xatu stack is external to the lab but has no production cbt-api, so
+	// xatu-local mode routes every table locally.
+	isHybrid := g.cfg.Infrastructure.ClickHouse.Xatu.Mode == constants.InfraModeExternal &&
+		g.cfg.Mode != constants.ModeXatuLocal
 
 	var localTables []string
 
@@ -289,7 +314,20 @@ func (g *Generator) GenerateLabBackendConfig(
 		entry := map[string]any{
 			"Name":    net.Name,
 			keyPort:   g.networkPorts(net.Name).CBTAPI,
-			"Enabled": net.Enabled,
+			"Enabled": net.Enabled && !g.networkStopped(net.Name),
+		}
+
+		// Built-in networks come from cartographoor; custom devnets are not
+		// listed there, so lab-backend needs their genesis and fork schedule.
+		if !net.IsBuiltin() {
+			entry["GenesisTime"] = net.Genesis()
+
+			forks := make([]map[string]any, 0, len(net.Forks))
+			for _, name := range net.SortedForks() {
+				forks = append(forks, map[string]any{"Name": name, "Epoch": net.Forks[name]})
+			}
+
+			entry["Forks"] = forks
 		}
 
 		if isHybrid {
@@ -310,6 +348,7 @@ func (g *Generator) GenerateLabBackendConfig(
 		keyPort:        g.labBackendPort(),
 		"FrontendPort": g.labFrontendPort(),
 		"RedisPort":    g.redisPort(),
+		"InfraHost":    g.infraHost(),
 	}
 
 	tmpl, err := template.New("lab-backend-config").ParseFS(
@@ -328,14 +367,12 @@ func (g *Generator) GenerateLabBackendConfig(
 }
 
 // generateAutoDefaults creates xcli-generated defaults for models (env, overrides).
+// The backfill window and first block come from the network config, with
+// per-network defaults for the built-in networks.
 func (g *Generator) generateAutoDefaults(network string) (map[string]any, error) {
-	externalModelMinTimestamp := time.Now().Add(-1 * time.Hour).Unix()
-
-	// Set sane default for mainnet
-	externalModelMinBlock := 0
-	if network == networkMainnet {
-		externalModelMinBlock = 23800000
-	}
+	networkCfg := g.network(network)
+	externalModelMinTimestamp := time.Now().Add(-networkCfg.BackfillWindow()).Unix()
+	externalModelMinBlock := networkCfg.MinBlock()
 
 	// Build models section with env
 	modelsSection := map[string]any{
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"dario.cat/mergo"
 	"github.com/ethpandaops/xcli/pkg/config"
@@ -615,11 +616,11 @@ func TestRuntimeGeneratorUsesAllocatedPathsAndPorts(t *testing.T) {
 	require.Equal(t, 1, secondRuntime.Ports.Slot)
 	require.Empty(t, firstRuntime.Ports.Overlaps(secondRuntime.Ports))
 
-	firstDir, err := NewRuntimeGenerator(logrus.New(), firstRuntime).GenerateRuntimeConfigs()
+	firstDir, err := NewRuntimeGenerator(logrus.New(), firstRuntime.LabConfig, firstRuntime).GenerateRuntimeConfigs()
 	require.NoError(t, err)
 	require.Equal(t, filepath.Join(firstRuntime.Manifest.StateDir, constants.DirConfigs), firstDir)
 
-	secondDir, err := NewRuntimeGenerator(logrus.New(), secondRuntime).GenerateRuntimeConfigs()
+	secondDir, err := NewRuntimeGenerator(logrus.New(), secondRuntime.LabConfig, secondRuntime).GenerateRuntimeConfigs()
 	require.NoError(t, err)
 	require.Equal(t, filepath.Join(secondRuntime.Manifest.StateDir, constants.DirConfigs), secondDir)
 
@@ -644,7 +645,7 @@ func TestRuntimeGeneratorCopiesCustomConfigsFromRootState(t *testing.T) {
 	)
 	require.NoError(t, os.WriteFile(customAPIPath, []byte(customAPIConfig), 0600))
 
-	configsDir, err := NewRuntimeGenerator(logrus.New(), runtime).GenerateRuntimeConfigs()
+	configsDir, err := NewRuntimeGenerator(logrus.New(), runtime.LabConfig, runtime).GenerateRuntimeConfigs()
 	require.NoError(t, err)
 
 	renderedAPIConfig := readGeneratedConfig(
@@ -655,6 +656,54 @@ func TestRuntimeGeneratorCopiesCustomConfigsFromRootState(t *testing.T) {
 	require.Equal(t, customAPIConfig, renderedAPIConfig)
 }
 
+func TestRuntimeGeneratorDisablesStoppedNetworksInLabBackend(t *testing.T) {
+	runtime := &instance.Runtime{
+		LabConfig: &config.LabConfig{
+			Networks: []config.NetworkConfig{
+				{Name: networkMainnet, Enabled: true},
+				{Name: "sepolia", Enabled: true, PortOffset: 1},
+			},
+		},
+		Manifest: &instance.Manifest{StoppedNetworks: []string{"sepolia"}},
+	}
+
+	out, err := NewRuntimeGenerator(logrus.New(), runtime.LabConfig, runtime).GenerateLabBackendConfig("")
+	require.NoError(t, err)
+
+	var parsed struct {
+		Networks []struct {
+			Name    string `yaml:"name"`
+			Enabled bool   `yaml:"enabled"`
+		} `yaml:"networks"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte(out), &parsed))
+	require.Len(t, parsed.Networks, 2)
+	require.Equal(t, networkMainnet, parsed.Networks[0].Name)
+	require.True(t, parsed.Networks[0].Enabled)
+	require.Equal(t, "sepolia", parsed.Networks[1].Name)
+	require.False(t, parsed.Networks[1].Enabled)
+}
+
+func TestRuntimeGeneratorUsesResolvedConfig(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	runtime := &instance.Runtime{LabConfig: config.DefaultLab(), Manifest: &instance.Manifest{}}
+
+	// xatu-local mode resolves the xatu stack's ClickHouse into a copy of
+	// runtime.LabConfig.
+	resolved := *runtime.LabConfig
+	resolved.Infrastructure.ClickHouse.Xatu = config.ClickHouseClusterConfig{
+		Mode:             constants.InfraModeExternal,
+		ExternalURL:      "http://localhost:8125",
+		ExternalDatabase: "xatu_stack",
+	}
+
+	out, err := NewRuntimeGenerator(logrus.New(), &resolved, runtime).GenerateCBTConfig(networkMainnet, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "defaultDatabase: xatu_stack")
+}
+
 func newConfiggenRuntime(
 	t *testing.T,
 	registry *instance.Registry,
@@ -947,3 +996,77 @@ func planContainsPort(plan instance.PortPlan, port int) bool {
 
 	return false
 }
+
+func TestGeneratorPointsServicesAtRemoteDockerHost(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	cfg := &config.LabConfig{}
+	cfg.Infrastructure.Docker.Host = "tcp://devbox:2375"
+
+	out, err := NewGenerator(logrus.New(), cfg).GenerateCBTAPIConfig(networkMainnet)
+	require.NoError(t, err)
+	assert.Contains(t, out, "@devbox:")
+
+	// ssh:// hosts are tunnelled, so services keep dialling localhost.
+	cfg.Infrastructure.Docker.Host = "ssh://me@devbox"
+
+	out, err = NewGenerator(logrus.New(), cfg).GenerateCBTAPIConfig(networkMainnet)
+	require.NoError(t, err)
+	assert.Contains(t, out, "@localhost:")
+}
+
+func TestGeneratorUsesCustomNetworkSettings(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	cfg := config.DefaultLab()
+	cfg.Infrastructure.ClickHouse.Xatu.Mode = constants.InfraModeExternal
+	cfg.Networks = append(cfg.Networks, config.NetworkConfig{
+		Name:             "fusaka_devnet_3",
+		Enabled:          true,
+		PortOffset:       3,
+		GenesisTimestamp: 1753300800,
+		SecondsPerSlot:   6,
+		Forks:            map[string]uint64{"fulu": 256, "electra": 0},
+		Database:         "fusaka_devnet_3",
+		Backfill:         config.NetworkBackfillConfig{Window: "6h", MinBlock: 42},
+	})
+
+	gen := NewGenerator(logrus.New(), cfg)
+
+	out, err := gen.GenerateCBTConfig("fusaka_devnet_3", "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "math.floor((value - 1753300800) / 6)")
+	assert.Contains(t, out, "defaultDatabase: fusaka_devnet_3")
+
+	defaults, err := gen.generateAutoDefaults("fusaka_devnet_3")
+	require.NoError(t, err)
+
+	env := defaults[keyModels].(map[string]any)[keyEnv].(map[string]any)
+	assert.Equal(t, "42", env[envExternalModelMinBlock])
+
+	minTimestamp, err := strconv.ParseInt(env[envExternalModelMinTimestamp].(string), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(-6*time.Hour).Unix(), minTimestamp, 60)
+
+	// Built-in networks keep their defaults.
+	out, err = gen.GenerateCBTConfig(networkMainnet, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "math.floor((value - 1606824023) / 12)")
+	assert.Contains(t, out, "defaultDatabase: default")
+
+	backend, err := gen.GenerateLabBackendConfig("")
+	require.NoError(t, err)
+	assert.Contains(t, backend, `  - name: fusaka_devnet_3
+    enabled: true
+    genesis_time: 1753300800
+    forks:
+      consensus:
+        electra:
+          epoch: 0
+        fulu:
+          epoch: 256
+`)
+	assert.NotContains(t, backend, "genesis_time: 1606824023")
+}
@@ -11,8 +11,14 @@ import (
 const (
 	ModeLocal  = "local"
 	ModeHybrid = "hybrid"
+	// ModeXatuLocal reads Xatu data from the ClickHouse of a running xatu
+	// docker-compose stack ('xcli xatu up').
+	ModeXatuLocal = "xatu-local"
 )
 
+// Modes lists the valid stack modes.
+var Modes = []string{ModeLocal, ModeHybrid, ModeXatuLocal}
+
 // Infrastructure modes.
 const (
 	InfraModeLocal    = "local"
@@ -27,6 +33,14 @@ const (
 	ServicePrefixCBTAPI = "cbt-api-"
 )
 
+// Infrastructure components services depend on in the service dependency
+// graph.
+const (
+	InfraClickHouseCBT  = "clickhouse-cbt"
+	InfraClickHouseXatu = "clickhouse-xatu"
+	InfraRedis          = "redis"
+)
+
 // Binary names.
 const (
 	BinaryCBT        = "cbt"
@@ -40,6 +54,7 @@ const (
 	DirConfigs          = "configs"
 	DirCustomConfigs    = "custom-configs"
 	DirCustomDashboards = "custom-dashboards"
+	DirErrors           = "errors"
 	DirLogs             = "logs"
 	DirPIDs             = "pids"
 )
@@ -58,13 +73,15 @@ const (
 
 // GitHub repository URLs.
 const (
-	GitHubOrg         = "ethpandaops"
-	RepoCBT           = "cbt"
-	RepoXatuCBT       = "xatu-cbt"
-	RepoCBTAPI        = "cbt-api"
-	RepoLabBackend    = "lab-backend"
-	RepoLab           = "lab"
-	GitHubURLTemplate = "https://github.com/%s/%s.git"
+	GitHubOrg            = "ethpandaops"
+	RepoCBT              = "cbt"
+	RepoXatuCBT          = "xatu-cbt"
+	RepoCBTAPI           = "cbt-api"
+	RepoLabBackend       = "lab-backend"
+	RepoLab              = "lab"
+	RepoXCLI             = "xcli"
+	GitHubURLTemplate    = "https://github.com/%s/%s.git"
+	GitHubSSHURLTemplate = "git@github.com:%s/%s.git"
 )
 
 // Releasable projects.
@@ -81,6 +98,10 @@ const (
 	WorkflowXatuCBTDocker = "docker.yml"
 )
 
+// VolumeCopyImage is the helper image used to copy Docker volumes between
+// lab instances.
+const VolumeCopyImage = "alpine:3.20"
+
 // Observability stack.
 const (
 	// Service names.
@@ -131,6 +152,18 @@ var NetworkGenesisTimestamps = map[string]uint64{
 	"holesky": 1695902400, // Sep 28, 2023 (legacy, use hoodi)
 }
 
+// NetworkMinBlocks are the default first execution blocks external models
+// backfill from, for networks where starting at block 0 is impractical.
+var NetworkMinBlocks = map[string]uint64{
+	"mainnet": 23800000,
+}
+
+// Network defaults for networks without explicit settings.
+const (
+	DefaultSecondsPerSlot = 12
+	DefaultBackfillWindow = "1h"
+)
+
 // ReleasableProjects lists all projects that can be released via xcli.
 var ReleasableProjects = []string{
 	ProjectCBT,
@@ -152,6 +185,7 @@ var SemverProjects = []string{
 // Key is the dependent project, value is slice of projects it depends on.
 var ProjectDependencies = map[string][]string{
 	ProjectXatuCBT:    {ProjectCBT}, // xatu-cbt imports cbt
+	ProjectCBTAPI:     {ProjectCBT}, // cbt-api imports cbt
 	ProjectLabBackend: {ProjectLab}, // lab-backend bundles lab frontend
 }
 
@@ -169,6 +203,11 @@ func GetGitHubURL(repo string) string {
 	return fmt.Sprintf(GitHubURLTemplate, GitHubOrg, repo)
 }
 
+// GetGitHubSSHURL returns the GitHub SSH clone URL for an owner's repository.
+func GetGitHubSSHURL(owner, repo string) string {
+	return fmt.Sprintf(GitHubSSHURLTemplate, owner, repo)
+}
+
 // GetDependencies returns the projects that the given project depends on.
 func GetDependencies(project string) []string {
 	if deps, ok := ProjectDependencies[project]; ok {
@@ -0,0 +1,402 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// incidentsDir is the store subdirectory holding runtime incidents.
+	incidentsDir = "incidents"
+
+	// defaultIncidentLogLines is how many trailing log lines an incident keeps.
+	defaultIncidentLogLines = 100
+	// defaultIncidentCooldown suppresses repeat incidents for the same service,
+	// e.g. a health transition right after the process exited.
+	defaultIncidentCooldown = time.Minute
+	// maxIncidentLogBytes bounds how much of a log file is read for the tail.
+	maxIncidentLogBytes = 256 * 1024
+	// maxIncidentConfigBytes bounds how much of each config file is captured.
+	maxIncidentConfigBytes = 16 * 1024
+)
+
+// IncidentTrigger identifies what caused an incident to be recorded.
+type IncidentTrigger string
+
+const (
+	// IncidentExit is recorded when a service process exits unexpectedly.
+	IncidentExit IncidentTrigger = "exit"
+	// IncidentUnhealthy is recorded when a running service becomes unhealthy.
+	IncidentUnhealthy IncidentTrigger = "unhealthy"
+)
+
+// IncidentPort is the state of a service port when the incident was recorded.
+type IncidentPort struct {
+	Port    int    `json:"port"`
+	Bound   bool   `json:"bound"`
+	PID     int    `json:"pid,omitempty"`
+	Process string `json:"process,omitempty"`
+}
+
+// IncidentConfig is a config file captured with an incident.
+type IncidentConfig struct {
+	Path      string `json:"path"`
+	Content   string `json:"content,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// IncidentEvent describes a runtime failure as seen by the caller. The
+// recorder reads the log tail and config files it points at.
+type IncidentEvent struct {
+	// Service is the name of the failed service.
+	Service string
+	// Trigger is what caused the incident.
+	Trigger IncidentTrigger
+	// PID is the process ID of the service, if known.
+	PID int
+	// ExitCode is the process exit code, or nil when unknown (e.g. the process
+	// was not started by this xcli session, or only its health changed).
+	ExitCode *int
+	// Error is the exit error or the last health check error.
+	Error string
+	// LogFile is the service log file.
+	LogFile string
+	// ConfigFiles are the generated config files relevant to the service.
+	ConfigFiles []string
+	// Ports is the state of the service's ports.
+	Ports []IncidentPort
+	// Infra maps infrastructure component names to whether they are running.
+	Infra map[string]bool
+}
+
+// Incident is a recorded runtime failure of a service.
+type Incident struct {
+	ID        string           `json:"id"`
+	Service   string           `json:"service"`
+	Trigger   IncidentTrigger  `json:"trigger"`
+	Time      time.Time        `json:"time"`
+	PID       int              `json:"pid,omitempty"`
+	ExitCode  *int             `json:"exitCode,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	LogFile   string           `json:"logFile,omitempty"`
+	LogLines  []string         `json:"logLines,omitempty"`
+	Configs   []IncidentConfig `json:"configs,omitempty"`
+	Ports     []IncidentPort   `json:"ports,omitempty"`
+	Infra     map[string]bool  `json:"infra,omitempty"`
+	Diagnosis *Diagnosis       `json:"diagnosis,omitempty"`
+}
+
+// BuildResult adapts the incident for the pattern matcher. Runtime failures
+// are matched as restart-phase output, since the restart patterns are the ones
+// describing running services (ports, ClickHouse, Redis, timeouts).
+func (i *Incident) BuildResult() *BuildResult {
+	result := &BuildResult{
+		Phase:     PhaseRestart,
+		Service:   i.Service,
+		ErrorMsg:  i.Error,
+		Stderr:    strings.Join(i.LogLines, "\n"),
+		StartTime: i.Time,
+		EndTime:   i.Time,
+	}
+
+	if i.Error != "" {
+		result.Stderr += "\n" + i.Error
+	}
+
+	if i.ExitCode != nil {
+		result.ExitCode = *i.ExitCode
+	}
+
+	return result
+}
+
+// Summary returns a one-line description of what happened.
+func (i *Incident) Summary() string {
+	switch {
+	case i.Trigger == IncidentExit && i.ExitCode != nil:
+		return fmt.Sprintf("exited with code %d", *i.ExitCode)
+	case i.Trigger == IncidentExit:
+		return "exited unexpectedly"
+	case i.Error != "":
+		return "unhealthy: " + i.Error
+	default:
+		return "unhealthy"
+	}
+}
+
+// IncidentRecorder turns runtime failures into incidents: it captures the log
+// tail and configs, runs the pattern matcher, and saves the incident.
+type IncidentRecorder struct {
+	log       logrus.FieldLogger
+	store     *Store
+	matcher   *PatternMatcher
+	logLines  int
+	cooldown  time.Duration
+	mu        sync.Mutex
+	last      map[string]time.Time
+	listeners []func(*Incident)
+}
+
+// NewIncidentRecorder creates a recorder saving to store. A nil matcher uses
+// the built-in patterns only.
+func NewIncidentRecorder(log logrus.FieldLogger, store *Store, matcher *PatternMatcher) *IncidentRecorder {
+	if matcher == nil {
+		matcher = NewPatternMatcher()
+	}
+
+	return &IncidentRecorder{
+		log:      log.WithField("component", "incident-recorder"),
+		store:    store,
+		matcher:  matcher,
+		logLines: defaultIncidentLogLines,
+		cooldown: defaultIncidentCooldown,
+		last:     make(map[string]time.Time, 8),
+	}
+}
+
+// OnIncident registers fn to be called for every recorded incident.
+func (r *IncidentRecorder) OnIncident(fn func(*Incident)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.listeners = append(r.listeners, fn)
+}
+
+// Record captures and saves an incident. It returns nil without error when an
+// incident for the same service was recorded within the cooldown.
+func (r *IncidentRecorder) Record(event IncidentEvent) (*Incident, error) {
+	now := time.Now()
+
+	r.mu.Lock()
+
+	if last, ok := r.last[event.Service]; ok && now.Sub(last) < r.cooldown {
+		r.mu.Unlock()
+
+		return nil, nil //nolint:nilnil // Within the cooldown nothing is recorded; documented above
+	}
+
+	r.last[event.Service] = now
+	listeners := append([]func(*Incident){}, r.listeners...)
+
+	r.mu.Unlock()
+
+	incident := &Incident{
+		ID:       generateID(),
+		Service:  event.Service,
+		Trigger:  event.Trigger,
+		Time:     now,
+		PID:      event.PID,
+		ExitCode: event.ExitCode,
+		Error:    event.Error,
+		LogFile:  event.LogFile,
+		Ports:    event.Ports,
+		Infra:    event.Infra,
+	}
+
+	if event.LogFile != "" {
+		lines, err := tailLogLines(event.LogFile, r.logLines)
+		if err != nil {
+			r.log.WithError(err).WithField(logFieldFile, event.LogFile).Debug("failed to read incident log tail")
+		}
+
+		incident.LogLines = lines
+	}
+
+	for _, path := range event.ConfigFiles {
+		incident.Configs = append(incident.Configs, readIncidentConfig(path))
+	}
+
+	if diag := r.matcher.Match(incident.BuildResult()); diag != nil && diag.Matched {
+		incident.Diagnosis = diag
+	}
+
+	if err := r.store.SaveIncident(incident); err != nil {
+		return incident, err
+	}
+
+	r.log.WithFields(logrus.Fields{
+		"service": incident.Service,
+		"trigger": incident.Trigger,
+		"id":      incident.ID,
+	}).Warn("recorded runtime incident")
+
+	for _, fn := range listeners {
+		fn(incident)
+	}
+
+	return incident, nil
+}
+
+// tailLogLines returns the last n lines of a log file.
+func tailLogLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := max(info.Size()-maxIncidentLogBytes, 0)
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Drop a partial first line when reading from the middle of the file.
+	if offset > 0 {
+		if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+			data = data[idx+1:]
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil, nil
+	}
+
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return lines, nil
+}
+
+// readIncidentConfig captures a config file, truncated to a bounded size.
+func readIncidentConfig(path string) IncidentConfig {
+	cfg := IncidentConfig{Path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		cfg.Error = err.Error()
+
+		return cfg
+	}
+
+	if len(data) > maxIncidentConfigBytes {
+		data = data[:maxIncidentConfigBytes]
+		cfg.Truncated = true
+	}
+
+	cfg.Content = string(data)
+
+	return cfg
+}
+
+// SaveIncident persists an incident next to the rebuild reports.
+func (s *Store) SaveIncident(incident *Incident) error {
+	dir := filepath.Join(s.baseDir, incidentsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create incidents directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(incident, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal incident: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s-%s.json", incident.Time.Format(timestampFormat), incident.ID)
+
+	if err := os.WriteFile(filepath.Join(dir, filename), data, 0600); err != nil {
+		return fmt.Errorf("failed to write incident file: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"id":         incident.ID,
+		logFieldFile: filename,
+	}).Debug("saved runtime incident")
+
+	return nil
+}
+
+// ListIncidents returns recent incidents, newest first. A limit of 0 or less
+// returns all of them.
+func (s *Store) ListIncidents(limit int) ([]*Incident, error) {
+	dir := filepath.Join(s.baseDir, incidentsDir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Incident{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to read incidents directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+
+	incidents := make([]*Incident, 0, len(names))
+
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			s.log.WithFields(logrus.Fields{
+				logFieldFile:  name,
+				logFieldError: err,
+			}).Warn("failed to read incident file, skipping")
+
+			continue
+		}
+
+		var incident Incident
+		if err := json.Unmarshal(data, &incident); err != nil {
+			s.log.WithFields(logrus.Fields{
+				logFieldFile:  name,
+				logFieldError: err,
+			}).Warn("failed to unmarshal incident, skipping")
+
+			continue
+		}
+
+		incidents = append(incidents, &incident)
+	}
+
+	return incidents, nil
+}
+
+// LoadIncident retrieves an incident by ID.
+func (s *Store) LoadIncident(id string) (*Incident, error) {
+	incidents, err := s.ListIncidents(0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, incident := range incidents {
+		if incident.ID == id {
+			return incident, nil
+		}
+	}
+
+	return nil, fmt.Errorf("incident not found: %s", id)
+}
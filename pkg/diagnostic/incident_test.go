@@ -0,0 +1,85 @@
+package diagnostic
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncidentRecorder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	log := logrus.New()
+	store := NewStore(log, filepath.Join(dir, "errors"))
+
+	var lines []string
+	for i := range 150 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	lines = append(lines, "level=fatal msg=\"listen tcp :9101: bind: address already in use\"")
+
+	logFile := filepath.Join(dir, "cbt-sepolia.log")
+	require.NoError(t, os.WriteFile(logFile, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+	configFile := filepath.Join(dir, "cbt-sepolia.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("network: sepolia\n"), 0600))
+
+	recorder := NewIncidentRecorder(log, store, nil)
+
+	var notified []*Incident
+
+	recorder.OnIncident(func(incident *Incident) { notified = append(notified, incident) })
+
+	exitCode := 1
+
+	incident, err := recorder.Record(IncidentEvent{
+		Service:     "cbt-sepolia",
+		Trigger:     IncidentExit,
+		ExitCode:    &exitCode,
+		Error:       "exit status 1",
+		LogFile:     logFile,
+		ConfigFiles: []string{configFile, filepath.Join(dir, "missing.yaml")},
+		Ports:       []IncidentPort{{Port: 9101, Bound: true, PID: 42, Process: "cbt"}},
+		Infra:       map[string]bool{"ClickHouse": true},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, incident)
+	require.Len(t, notified, 1)
+
+	require.Len(t, incident.LogLines, defaultIncidentLogLines)
+	require.Contains(t, incident.LogLines[len(incident.LogLines)-1], "address already in use")
+	require.Equal(t, "network: sepolia\n", incident.Configs[0].Content)
+	require.NotEmpty(t, incident.Configs[1].Error)
+	require.NotNil(t, incident.Diagnosis)
+	require.Equal(t, "port-already-in-use", incident.Diagnosis.PatternName)
+	require.Equal(t, "exited with code 1", incident.Summary())
+
+	// A health transition right after the exit is not recorded again.
+	again, err := recorder.Record(IncidentEvent{Service: "cbt-sepolia", Trigger: IncidentUnhealthy})
+	require.NoError(t, err)
+	require.Nil(t, again)
+
+	_, err = recorder.Record(IncidentEvent{Service: "cbt-api-sepolia", Trigger: IncidentUnhealthy, Error: "HTTP 503"})
+	require.NoError(t, err)
+
+	incidents, err := store.ListIncidents(0)
+	require.NoError(t, err)
+	require.Len(t, incidents, 2)
+
+	loaded, err := store.LoadIncident(incident.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, *loaded.ExitCode)
+	require.Equal(t, "port-already-in-use", loaded.Diagnosis.PatternName)
+
+	// Incidents live in a subdirectory so they are not listed as rebuild reports.
+	reports, err := store.List(0)
+	require.NoError(t, err)
+	require.Empty(t, reports)
+}
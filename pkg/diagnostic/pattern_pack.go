@@ -0,0 +1,338 @@
+package diagnostic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PatternsDir is the directory, relative to a repo root or the user's home
+// xcli directory, that holds user-defined pattern packs.
+const PatternsDir = ".xcli/patterns"
+
+// validPhases lists the build phases a pattern may be scoped to.
+var validPhases = map[BuildPhase]bool{
+	PhaseProtoGen:    true,
+	PhaseBuild:       true,
+	PhaseConfigGen:   true,
+	PhaseRestart:     true,
+	PhaseFrontendGen: true,
+}
+
+// PatternPack is a YAML file of user-defined error patterns.
+//
+// Example:
+//
+//	name: team-patterns
+//	patterns:
+//	  - name: clickhouse-too-many-parts
+//	    regex: 'Too many parts \(\d+\)'
+//	    service: cbt-mainnet
+//	    confidence: high
+//	    hint: ClickHouse is merging slower than CBT inserts.
+//	    suggestion: Lower the CBT insert concurrency.
+//	    fixCommands:
+//	      - xcli lab restart cbt-mainnet
+//	    examples:
+//	      - "DB::Exception: Too many parts (300)"
+type PatternPack struct {
+	// Name identifies the pack in lint output.
+	Name string `yaml:"name"`
+	// Description optionally explains what the pack covers.
+	Description string `yaml:"description,omitempty"`
+	// Patterns are the error patterns defined by the pack.
+	Patterns []PatternDefinition `yaml:"patterns"`
+	// Path is the file the pack was loaded from.
+	Path string `yaml:"-"`
+}
+
+// PatternDefinition is the YAML form of an ErrorPattern.
+type PatternDefinition struct {
+	// Name is a unique identifier for the pattern. A pattern with the same name
+	// as a built-in pattern replaces it.
+	Name string `yaml:"name"`
+	// Keywords must all appear (case-insensitively) in the output.
+	Keywords []string `yaml:"keywords,omitempty"`
+	// Regex is an RE2 expression matched against the output.
+	Regex string `yaml:"regex,omitempty"`
+	// Phase optionally restricts the pattern to a build phase.
+	Phase BuildPhase `yaml:"phase,omitempty"`
+	// Service optionally restricts the pattern to a service.
+	Service string `yaml:"service,omitempty"`
+	// Confidence is high, medium or low. Defaults to medium.
+	Confidence string `yaml:"confidence,omitempty"`
+	// Hint explains what went wrong.
+	Hint string `yaml:"hint"`
+	// Suggestion describes how to fix the issue.
+	Suggestion string `yaml:"suggestion,omitempty"`
+	// FixCommands lists shell commands that may fix the issue.
+	FixCommands []string `yaml:"fixCommands,omitempty"`
+	// Examples are sample outputs the pattern must match, checked by lint.
+	Examples []string `yaml:"examples,omitempty"`
+}
+
+// PatternSearchDirs returns the directories user pattern packs are loaded
+// from: ~/.xcli/patterns, then the repo's .xcli/patterns. Packs loaded later
+// win when two packs define the same pattern name, so repo packs override
+// personal ones.
+func PatternSearchDirs(repoRoot string) []string {
+	dirs := make([]string, 0, 2)
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(homeDir, PatternsDir))
+	}
+
+	if repoRoot != "" {
+		dirs = append(dirs, filepath.Join(repoRoot, PatternsDir))
+	}
+
+	return dirs
+}
+
+// LoadPatternPackFile parses a single pattern pack. Unknown fields are
+// rejected so typos do not silently disable a pattern.
+func LoadPatternPackFile(path string) (*PatternPack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pattern pack: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var pack PatternPack
+	if err := decoder.Decode(&pack); err != nil {
+		return nil, fmt.Errorf("failed to parse pattern pack %s: %w", path, err)
+	}
+
+	pack.Path = path
+	if pack.Name == "" {
+		pack.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return &pack, nil
+}
+
+// PatternPackFiles lists the *.yaml and *.yml files in dirs, in directory
+// order and sorted by name within each directory. Missing directories are
+// skipped.
+func PatternPackFiles(dirs ...string) ([]string, error) {
+	files := make([]string, 0, 8)
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to read patterns directory %s: %w", dir, err)
+		}
+
+		names := make([]string, 0, len(entries))
+
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+
+			names = append(names, entry.Name())
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+
+	return files, nil
+}
+
+// LoadPatternPacks loads every pack in dirs. Packs that fail to parse or
+// validate are skipped and reported in the returned errors.
+func LoadPatternPacks(dirs ...string) ([]*PatternPack, []error) {
+	files, err := PatternPackFiles(dirs...)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	packs := make([]*PatternPack, 0, len(files))
+	errs := make([]error, 0)
+
+	for _, file := range files {
+		pack, loadErr := LoadPatternPackFile(file)
+		if loadErr != nil {
+			errs = append(errs, loadErr)
+
+			continue
+		}
+
+		if validationErrs := pack.Validate(); len(validationErrs) > 0 {
+			errs = append(errs, fmt.Errorf("%s: %w", file, errors.Join(validationErrs...)))
+
+			continue
+		}
+
+		packs = append(packs, pack)
+	}
+
+	return packs, errs
+}
+
+// Validate checks every pattern in the pack, including that each example
+// matches its pattern. It returns all problems found.
+func (p *PatternPack) Validate() []error {
+	errs := make([]error, 0)
+
+	if len(p.Patterns) == 0 {
+		errs = append(errs, errors.New("pack defines no patterns"))
+	}
+
+	seen := make(map[string]bool, len(p.Patterns))
+
+	for i := range p.Patterns {
+		def := &p.Patterns[i]
+
+		label := def.Name
+		if label == "" {
+			label = fmt.Sprintf("patterns[%d]", i)
+		}
+
+		if def.Name != "" && seen[def.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicate pattern name", label))
+		}
+
+		seen[def.Name] = true
+
+		pattern, err := def.Compile()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", label, err))
+
+			continue
+		}
+
+		matcher := &PatternMatcher{}
+
+		for j, example := range def.Examples {
+			if matcher.calculateMatchScore(&pattern, strings.ToLower(example), example) == 0 {
+				errs = append(errs, fmt.Errorf("%s: examples[%d] does not match", label, j))
+			}
+		}
+	}
+
+	return errs
+}
+
+// Compile converts the definition into an ErrorPattern.
+func (d *PatternDefinition) Compile() (ErrorPattern, error) {
+	if strings.TrimSpace(d.Name) == "" {
+		return ErrorPattern{}, errors.New("name is required")
+	}
+
+	if strings.TrimSpace(d.Hint) == "" {
+		return ErrorPattern{}, errors.New("hint is required")
+	}
+
+	if d.Regex == "" && len(d.Keywords) == 0 {
+		return ErrorPattern{}, errors.New("at least one of regex or keywords is required")
+	}
+
+	if d.Phase != "" && !validPhases[d.Phase] {
+		return ErrorPattern{}, fmt.Errorf("unknown phase %q", d.Phase)
+	}
+
+	confidence := strings.ToLower(d.Confidence)
+
+	switch confidence {
+	case "":
+		confidence = confidenceMedium
+	case confidenceHigh, confidenceMedium, confidenceLow:
+	default:
+		return ErrorPattern{}, fmt.Errorf("unknown confidence %q (use high, medium or low)", d.Confidence)
+	}
+
+	pattern := ErrorPattern{
+		Name:        d.Name,
+		Contains:    d.Keywords,
+		Service:     d.Service,
+		Phase:       d.Phase,
+		Hint:        d.Hint,
+		Suggestion:  d.Suggestion,
+		Confidence:  confidence,
+		FixCommands: d.FixCommands,
+	}
+
+	if d.Regex != "" {
+		re, err := regexp.Compile(d.Regex)
+		if err != nil {
+			return ErrorPattern{}, fmt.Errorf("invalid regex: %w", err)
+		}
+
+		pattern.Pattern = re
+	}
+
+	return pattern, nil
+}
+
+// AddPack compiles and adds every pattern in the pack. A pattern replaces an
+// existing pattern with the same name, so packs can override built-ins.
+func (m *PatternMatcher) AddPack(pack *PatternPack) error {
+	for i := range pack.Patterns {
+		pattern, err := pack.Patterns[i].Compile()
+		if err != nil {
+			return fmt.Errorf("%s: %w", pack.Path, err)
+		}
+
+		pattern.Source = pack.Path
+
+		if idx := m.indexOf(pattern.Name); idx >= 0 {
+			m.patterns[idx] = pattern
+
+			continue
+		}
+
+		m.AddPattern(pattern)
+	}
+
+	return nil
+}
+
+// HasPattern reports whether a pattern with the given name is registered.
+func (m *PatternMatcher) HasPattern(name string) bool {
+	return m.indexOf(name) >= 0
+}
+
+func (m *PatternMatcher) indexOf(name string) int {
+	for i := range m.patterns {
+		if m.patterns[i].Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// NewPatternMatcherWithPacks creates a matcher with the built-in patterns plus
+// the user pattern packs found in dirs. Invalid packs are skipped and
+// returned as errors so callers can warn without failing.
+func NewPatternMatcherWithPacks(dirs ...string) (*PatternMatcher, []error) {
+	m := NewPatternMatcher()
+
+	packs, errs := LoadPatternPacks(dirs...)
+	for _, pack := range packs {
+		if err := m.AddPack(pack); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return m, errs
+}
@@ -0,0 +1,103 @@
+package diagnostic
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPatternPackValidate(t *testing.T) {
+	t.Parallel()
+
+	pack := &PatternPack{
+		Name: "broken",
+		Patterns: []PatternDefinition{
+			{Name: "bad-regex", Regex: "([", Hint: "x"},
+			{Name: "bad-phase", Keywords: []string{"x"}, Phase: "deploy", Hint: "x"},
+			{Name: "no-matcher", Hint: "x"},
+			{Name: "example-miss", Keywords: []string{"disk full"}, Hint: "x", Examples: []string{"out of memory"}},
+			{Name: "example-miss", Keywords: []string{"x"}, Hint: "x"},
+			{Name: "bad-confidence", Keywords: []string{"x"}, Hint: "x", Confidence: "certain"},
+		},
+	}
+
+	errs := pack.Validate()
+
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	joined := strings.Join(msgs, "\n")
+	require.Contains(t, joined, "bad-regex: invalid regex")
+	require.Contains(t, joined, `bad-phase: unknown phase "deploy"`)
+	require.Contains(t, joined, "no-matcher: at least one of regex or keywords is required")
+	require.Contains(t, joined, "example-miss: examples[0] does not match")
+	require.Contains(t, joined, "example-miss: duplicate pattern name")
+	require.Contains(t, joined, `bad-confidence: unknown confidence "certain"`)
+}
+
+func TestLoadPatternPackRejectsUnknownFields(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "typo.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("patterns:\n  - name: x\n    regexp: foo\n    hint: x\n"), 0600))
+
+	_, err := LoadPatternPackFile(path)
+	require.ErrorContains(t, err, "field regexp not found")
+}
+
+func TestPatternPackOverridesBuiltin(t *testing.T) {
+	t.Parallel()
+
+	m := NewPatternMatcher()
+	require.True(t, m.HasPattern("go-undefined-identifier"))
+
+	require.NoError(t, m.AddPack(&PatternPack{
+		Path: "custom.yaml",
+		Patterns: []PatternDefinition{
+			{Name: "go-undefined-identifier", Regex: `undefined:\s*\w+`, Phase: PhaseBuild, Hint: "regenerate code"},
+		},
+	}))
+
+	diag := m.Match(&BuildResult{Phase: PhaseBuild, Stderr: "undefined: Foo"})
+	require.NotNil(t, diag)
+	require.Equal(t, "regenerate code", diag.Hint)
+	require.Equal(t, "custom.yaml", diag.Source)
+	require.Equal(t, confidenceMedium, diag.Confidence)
+}
+
+// TestReplaySavedReports replays the saved reports in testdata/replay against
+// the built-in patterns plus testdata/patterns. Add a report JSON and its
+// expected pattern names to expected.yaml to cover a new failure.
+func TestReplaySavedReports(t *testing.T) {
+	t.Parallel()
+
+	m, errs := NewPatternMatcherWithPacks(filepath.Join("testdata", "patterns"))
+	require.Empty(t, errs)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "replay", "expected.yaml"))
+	require.NoError(t, err)
+
+	var expected map[string][]string
+	require.NoError(t, yaml.Unmarshal(data, &expected))
+	require.NotEmpty(t, expected)
+
+	for file, want := range expected {
+		report, err := LoadReportFile(filepath.Join("testdata", "replay", file))
+		require.NoError(t, err, file)
+
+		results := m.ReplayReport(report)
+
+		got := make([]string, 0, len(results))
+		for _, result := range results {
+			got = append(got, result.PatternName())
+		}
+
+		require.Equal(t, want, got, file)
+	}
+}
@@ -36,20 +36,28 @@ type ErrorPattern struct {
 	Suggestion string
 	// Confidence indicates how confident we are that this pattern correctly identifies the issue.
 	Confidence string
+	// FixCommands lists shell commands that may fix the issue.
+	FixCommands []string
+	// Source is the pattern pack file this pattern was loaded from (empty for built-ins).
+	Source string
 }
 
 // Diagnosis contains the result of pattern matching against a build result.
 type Diagnosis struct {
 	// PatternName is the name of the matched pattern.
-	PatternName string
+	PatternName string `json:"patternName"`
 	// Matched indicates whether any pattern matched.
-	Matched bool
+	Matched bool `json:"matched"`
 	// Hint provides a clear explanation of what went wrong.
-	Hint string
+	Hint string `json:"hint"`
 	// Suggestion provides specific commands or actions to fix the issue.
-	Suggestion string
+	Suggestion string `json:"suggestion,omitempty"`
 	// Confidence indicates how confident we are in this diagnosis ("high", "medium", "low").
-	Confidence string
+	Confidence string `json:"confidence"`
+	// FixCommands lists shell commands that may fix the issue.
+	FixCommands []string `json:"fixCommands,omitempty"`
+	// Source is the pattern pack file the matched pattern came from (empty for built-ins).
+	Source string `json:"source,omitempty"`
 }
 
 // PatternMatcher matches errors against known patterns to provide diagnostics.
@@ -112,13 +120,7 @@ func (m *PatternMatcher) Match(result *BuildResult) *Diagnosis {
 		return nil
 	}
 
-	return &Diagnosis{
-		PatternName: bestMatch.Name,
-		Matched:     true,
-		Hint:        bestMatch.Hint,
-		Suggestion:  bestMatch.Suggestion,
-		Confidence:  bestMatch.Confidence,
-	}
+	return bestMatch.diagnosis()
 }
 
 // AddPattern adds a custom pattern to the matcher.
@@ -156,13 +158,7 @@ func (m *PatternMatcher) MatchAll(result *BuildResult) []*Diagnosis {
 
 		// Check if pattern matches
 		if m.calculateMatchScore(pattern, lowerOutput, output) > 0 {
-			diagnoses = append(diagnoses, &Diagnosis{
-				PatternName: pattern.Name,
-				Matched:     true,
-				Hint:        pattern.Hint,
-				Suggestion:  pattern.Suggestion,
-				Confidence:  pattern.Confidence,
-			})
+			diagnoses = append(diagnoses, pattern.diagnosis())
 		}
 	}
 
@@ -172,6 +168,19 @@ func (m *PatternMatcher) MatchAll(result *BuildResult) []*Diagnosis {
 	return diagnoses
 }
 
+// diagnosis builds the Diagnosis reported when this pattern matches.
+func (p *ErrorPattern) diagnosis() *Diagnosis {
+	return &Diagnosis{
+		PatternName: p.Name,
+		Matched:     true,
+		Hint:        p.Hint,
+		Suggestion:  p.Suggestion,
+		Confidence:  p.Confidence,
+		FixCommands: p.FixCommands,
+		Source:      p.Source,
+	}
+}
+
 // DiagnoseOutput is a convenience function that creates a PatternMatcher and matches output.
 func DiagnoseOutput(service string, phase BuildPhase, stderr, stdout string) *Diagnosis {
 	matcher := NewPatternMatcher()
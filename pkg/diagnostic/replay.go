@@ -0,0 +1,62 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ReplayResult is the outcome of matching one failed build result from a
+// saved RebuildReport against the current patterns.
+type ReplayResult struct {
+	// ReportID is the ID of the replayed report.
+	ReportID string
+	// Service is the service of the failed build result.
+	Service string
+	// Phase is the phase of the failed build result.
+	Phase BuildPhase
+	// Diagnosis is the best match, or nil when no pattern matched.
+	Diagnosis *Diagnosis
+}
+
+// PatternName returns the matched pattern name, or "" when nothing matched.
+func (r ReplayResult) PatternName() string {
+	if r.Diagnosis == nil {
+		return ""
+	}
+
+	return r.Diagnosis.PatternName
+}
+
+// LoadReportFile reads a RebuildReport saved by Store.
+func LoadReportFile(path string) (*RebuildReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report file: %w", err)
+	}
+
+	var report RebuildReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report file %s: %w", path, err)
+	}
+
+	return &report, nil
+}
+
+// ReplayReport matches every failed result in a saved report, so pattern
+// changes can be checked against real failures.
+func (m *PatternMatcher) ReplayReport(report *RebuildReport) []ReplayResult {
+	failed := report.Failed()
+	results := make([]ReplayResult, 0, len(failed))
+
+	for i := range failed {
+		results = append(results, ReplayResult{
+			ReportID:  report.ID,
+			Service:   failed[i].Service,
+			Phase:     failed[i].Phase,
+			Diagnosis: m.Match(&failed[i]),
+		})
+	}
+
+	return results
+}
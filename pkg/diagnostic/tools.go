@@ -0,0 +1,194 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// toolBlockFence is the fenced code block language used for tool calls.
+const toolBlockFence = "xcli-tool"
+
+// toolBlockPattern matches ```xcli-tool ... ``` blocks in provider output.
+var toolBlockPattern = regexp.MustCompile("(?s)```" + toolBlockFence + "\\s*\\n(.*?)```")
+
+// ToolSpec describes a read-only tool offered to the AI during diagnosis.
+type ToolSpec struct {
+	Name        string
+	Description string
+	// Args maps argument names to a short description, including defaults.
+	Args map[string]string
+	// ArgOrder lists Args keys in the order they are documented.
+	ArgOrder []string
+}
+
+// ToolCall is a tool invocation requested by the AI.
+type ToolCall struct {
+	ID   string         `json:"id"`
+	Tool string         `json:"tool"`
+	Args map[string]any `json:"args,omitempty"`
+	// ParseError is set when the block could not be decoded.
+	ParseError string `json:"parseError,omitempty"`
+}
+
+// ToolResult is the outcome of a tool call, sent back to the AI.
+type ToolResult struct {
+	Call     ToolCall `json:"call"`
+	Approved bool     `json:"approved"`
+	Output   string   `json:"output,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// StringArg returns a string argument, or def when missing.
+func (c *ToolCall) StringArg(name, def string) string {
+	v, ok := c.Args[name]
+	if !ok || v == nil {
+		return def
+	}
+
+	switch t := v.(type) {
+	case string:
+		if strings.TrimSpace(t) == "" {
+			return def
+		}
+
+		return t
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// IntArg returns an integer argument clamped to [1, maxValue], or def when missing.
+func (c *ToolCall) IntArg(name string, def, maxValue int) int {
+	n := def
+
+	switch t := c.Args[name].(type) {
+	case float64:
+		n = int(t)
+	case int:
+		n = t
+	case string:
+		if _, err := fmt.Sscanf(t, "%d", &n); err != nil {
+			n = def
+		}
+	}
+
+	if n < 1 {
+		n = def
+	}
+
+	if maxValue > 0 && n > maxValue {
+		n = maxValue
+	}
+
+	return n
+}
+
+// BuildToolInstructions describes the tool protocol and available tools for a prompt.
+func BuildToolInstructions(specs []ToolSpec) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Tools\n\n")
+	sb.WriteString("You can inspect the running stack with read-only tools that xcli executes ")
+	sb.WriteString("after the user approves each call. To call tools, reply with one or more blocks like:\n\n")
+	sb.WriteString("```" + toolBlockFence + "\n")
+	sb.WriteString(`{"tool": "grep_logs", "args": {"service": "cbt-mainnet", "pattern": "error"}}`)
+	sb.WriteString("\n```\n\n")
+	sb.WriteString("Results are sent back in the next message. Only call tools when the logs above are ")
+	sb.WriteString("not enough. When you have enough information, respond with the final diagnosis ")
+	sb.WriteString("and no tool blocks.\n\n")
+	sb.WriteString("Available tools:\n")
+
+	for _, spec := range specs {
+		sb.WriteString("- `")
+		sb.WriteString(spec.Name)
+		sb.WriteString("`: ")
+		sb.WriteString(spec.Description)
+		sb.WriteString("\n")
+
+		for _, arg := range spec.ArgOrder {
+			sb.WriteString("  - `")
+			sb.WriteString(arg)
+			sb.WriteString("`: ")
+			sb.WriteString(spec.Args[arg])
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// ParseToolCalls extracts tool calls from a provider response.
+// Blocks that are not valid JSON are returned with ParseError set so the
+// model can be told what went wrong.
+func ParseToolCalls(response string) []ToolCall {
+	matches := toolBlockPattern.FindAllStringSubmatch(response, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	calls := make([]ToolCall, 0, len(matches))
+
+	for i, match := range matches {
+		body := strings.TrimSpace(match[1])
+
+		var call ToolCall
+		if err := json.Unmarshal([]byte(body), &call); err != nil {
+			call = ToolCall{ParseError: fmt.Sprintf("invalid tool call JSON: %v", err)}
+		} else if strings.TrimSpace(call.Tool) == "" {
+			call.ParseError = "tool call is missing the \"tool\" field"
+		}
+
+		call.ID = fmt.Sprintf("tool-%d", i+1)
+		calls = append(calls, call)
+	}
+
+	return calls
+}
+
+// BuildToolResultsPrompt formats tool results as the next user turn.
+func BuildToolResultsPrompt(results []ToolResult) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Tool Results\n\n")
+
+	for _, result := range results {
+		sb.WriteString("### ")
+		sb.WriteString(result.Call.Tool)
+
+		if len(result.Call.Args) > 0 {
+			if args, err := json.Marshal(result.Call.Args); err == nil {
+				sb.WriteString(" ")
+				sb.Write(args)
+			}
+		}
+
+		sb.WriteString("\n\n")
+
+		switch {
+		case !result.Approved:
+			sb.WriteString("The user denied this tool call. Do not retry it.\n\n")
+		case result.Error != "":
+			sb.WriteString("Error: ")
+			sb.WriteString(result.Error)
+			sb.WriteString("\n\n")
+		default:
+			sb.WriteString("```\n")
+			sb.WriteString(result.Output)
+
+			if !strings.HasSuffix(result.Output, "\n") {
+				sb.WriteString("\n")
+			}
+
+			sb.WriteString("```\n\n")
+		}
+	}
+
+	sb.WriteString("Continue the diagnosis. Call more tools if needed, otherwise give the final answer ")
+	sb.WriteString("using the structured format.\n")
+
+	return sb.String()
+}
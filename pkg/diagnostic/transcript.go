@@ -0,0 +1,105 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// transcriptsDir is the store subdirectory holding diagnosis transcripts.
+const transcriptsDir = "transcripts"
+
+// TranscriptEntryKind categorizes a transcript entry.
+type TranscriptEntryKind string
+
+const (
+	TranscriptPrompt     TranscriptEntryKind = "prompt"
+	TranscriptResponse   TranscriptEntryKind = "response"
+	TranscriptToolCall   TranscriptEntryKind = "tool_call"
+	TranscriptToolResult TranscriptEntryKind = "tool_result"
+	TranscriptError      TranscriptEntryKind = "error"
+)
+
+// TranscriptEntry is a single step of an AI diagnosis session.
+type TranscriptEntry struct {
+	Time     time.Time           `json:"time"`
+	Kind     TranscriptEntryKind `json:"kind"`
+	Text     string              `json:"text,omitempty"`
+	Tool     *ToolCall           `json:"tool,omitempty"`
+	Approved *bool               `json:"approved,omitempty"`
+}
+
+// Transcript records an AI diagnosis session, including every tool call and
+// the user's approval decision for it.
+type Transcript struct {
+	ID        string            `json:"id"`
+	Service   string            `json:"service"`
+	Provider  string            `json:"provider"`
+	StartTime time.Time         `json:"startTime"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	Entries   []TranscriptEntry `json:"entries"`
+
+	mu sync.Mutex
+}
+
+// NewTranscript creates an empty transcript for a diagnosis session.
+func NewTranscript(id, service, provider string) *Transcript {
+	now := time.Now()
+
+	return &Transcript{
+		ID:        id,
+		Service:   service,
+		Provider:  provider,
+		StartTime: now,
+		UpdatedAt: now,
+		Entries:   make([]TranscriptEntry, 0, 16),
+	}
+}
+
+// Add appends an entry to the transcript.
+func (t *Transcript) Add(entry TranscriptEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	t.Entries = append(t.Entries, entry)
+	t.UpdatedAt = entry.Time
+}
+
+// SaveTranscript persists a transcript next to the rebuild reports. Saving the
+// same transcript again overwrites the previous snapshot.
+func (s *Store) SaveTranscript(t *Transcript) error {
+	dir := filepath.Join(s.baseDir, transcriptsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create transcripts directory: %w", err)
+	}
+
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t, "", "  ")
+	t.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal transcript: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s-%s.json", t.StartTime.Format(timestampFormat), t.ID)
+
+	if err := os.WriteFile(filepath.Join(dir, filename), data, 0600); err != nil {
+		return fmt.Errorf("failed to write transcript file: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{
+		"id":         t.ID,
+		logFieldFile: filename,
+	}).Debug("saved diagnosis transcript")
+
+	return nil
+}
@@ -29,18 +29,34 @@ func NewDiscovery(log logrus.FieldLogger, basePath string) *Discovery {
 	}
 }
 
+// Layout returns the lab repository paths of the side-by-side checkout layout
+// rooted at basePath.
+func Layout(basePath string) *config.LabReposConfig {
+	return &config.LabReposConfig{
+		CBT:        filepath.Join(basePath, constants.RepoCBT),
+		XatuCBT:    filepath.Join(basePath, constants.RepoXatuCBT),
+		CBTAPI:     filepath.Join(basePath, constants.RepoCBTAPI),
+		LabBackend: filepath.Join(basePath, constants.RepoLabBackend),
+		Lab:        filepath.Join(basePath, constants.RepoLab),
+	}
+}
+
+// CloneBranch returns the branch a repository is cloned at, or "" for its
+// default branch.
+func CloneBranch(repoName string) string {
+	if repoName == constants.RepoLab {
+		return "release/frontend"
+	}
+
+	return ""
+}
+
 // DiscoverRepos attempts to find all required lab repositories.
 // If a repository is missing, it will prompt the user to clone it.
 func (d *Discovery) DiscoverRepos(ctx context.Context) (*config.LabReposConfig, error) {
 	d.log.Info("discovering lab repositories")
 
-	repos := &config.LabReposConfig{
-		CBT:        filepath.Join(d.basePath, "cbt"),
-		XatuCBT:    filepath.Join(d.basePath, constants.RepoXatuCBT),
-		CBTAPI:     filepath.Join(d.basePath, "cbt-api"),
-		LabBackend: filepath.Join(d.basePath, "lab-backend"),
-		Lab:        filepath.Join(d.basePath, constants.RepoLab),
-	}
+	repos := Layout(d.basePath)
 
 	// Map of repo names to their paths, GitHub repo names, and optional branches
 	repoMap := map[string]struct {
@@ -52,7 +68,7 @@ func (d *Discovery) DiscoverRepos(ctx context.Context) (*config.LabReposConfig,
 		constants.RepoXatuCBT: {&repos.XatuCBT, constants.RepoXatuCBT, ""},
 		"cbt-api":             {&repos.CBTAPI, constants.RepoCBTAPI, ""},
 		"lab-backend":         {&repos.LabBackend, constants.RepoLabBackend, ""},
-		constants.RepoLab:     {&repos.Lab, constants.RepoLab, "release/frontend"},
+		constants.RepoLab:     {&repos.Lab, constants.RepoLab, CloneBranch(constants.RepoLab)},
 	}
 
 	// Check each repository
@@ -61,7 +77,7 @@ func (d *Discovery) DiscoverRepos(ctx context.Context) (*config.LabReposConfig,
 		var validationErr error
 
 		err := ui.WithSpinner(fmt.Sprintf("Checking repository: %s", name), func() error {
-			validationErr = d.validateRepo(name, *info.path)
+			validationErr = d.ValidateRepo(name, *info.path)
 
 			return nil // Don't fail spinner on validation error, we handle it below
 		})
@@ -96,7 +112,7 @@ func (d *Discovery) DiscoverRepos(ctx context.Context) (*config.LabReposConfig,
 					}
 
 					// Validate again after cloning
-					if validateErr := d.validateRepo(name, *info.path); validateErr != nil {
+					if validateErr := d.ValidateRepo(name, *info.path); validateErr != nil {
 						return nil, fmt.Errorf("cloned repository %s failed validation: %w", name, validateErr)
 					}
 				} else {
@@ -117,8 +133,8 @@ func (d *Discovery) DiscoverRepos(ctx context.Context) (*config.LabReposConfig,
 	return repos, nil
 }
 
-// validateRepo checks if a repository exists and has expected structure.
-func (d *Discovery) validateRepo(name, path string) error {
+// ValidateRepo checks if a repository exists and has expected structure.
+func (d *Discovery) ValidateRepo(name, path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
@@ -0,0 +1,333 @@
+// Package dockerhost resolves which Docker daemon runs lab infrastructure,
+// builds clients for it (including ssh:// endpoints), and describes how local
+// services reach containers running there.
+package dockerhost
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/ethpandaops/xcli/pkg/config"
+)
+
+const (
+	// LocalServiceHost is the host local services use for local or tunnelled infrastructure.
+	LocalServiceHost = "localhost"
+
+	// defaultContext is the docker context that means "use the local daemon".
+	defaultContext = "default"
+
+	// sshDialHost is a placeholder HTTP host for clients that dial over SSH.
+	sshDialHost = "http://docker.example.com"
+
+	// probeReadTimeout is how long a tunnelled probe waits for the remote
+	// end to close a forwarded connection before treating it as open.
+	probeReadTimeout = 300 * time.Millisecond
+)
+
+// Target is a resolved Docker endpoint.
+type Target struct {
+	// Host is the Docker endpoint, empty for the local default daemon.
+	Host string
+	// Context is the docker context the endpoint was read from, if any.
+	Context string
+	// Address is the host that local services dial when not tunnelled.
+	Address string
+	// Tunnel reports whether infrastructure ports are forwarded over SSH.
+	Tunnel bool
+
+	envHost bool
+}
+
+// Resolve picks the Docker endpoint from the lab config, DOCKER_HOST,
+// DOCKER_CONTEXT and the current docker context, in that order.
+func Resolve(cfg config.DockerHostConfig) (Target, error) {
+	if err := cfg.Validate(); err != nil {
+		return Target{}, err
+	}
+
+	target := Target{Host: cfg.Host, Context: cfg.Context}
+
+	switch {
+	case target.Host != "":
+	case target.Context != "":
+		host, err := contextHost(target.Context)
+		if err != nil {
+			return Target{}, err
+		}
+
+		target.Host = host
+	case os.Getenv("DOCKER_HOST") != "":
+		target.Host = os.Getenv("DOCKER_HOST")
+		target.envHost = true
+	default:
+		name := os.Getenv("DOCKER_CONTEXT")
+		if name == "" {
+			name = currentContext()
+		}
+
+		if name != "" && name != defaultContext {
+			host, err := contextHost(name)
+			if err != nil {
+				return Target{}, err
+			}
+
+			target.Host = host
+			target.Context = name
+		}
+	}
+
+	if target.IsRemote() {
+		target.Tunnel = target.scheme() == "ssh"
+		if cfg.Tunnel != nil {
+			target.Tunnel = *cfg.Tunnel
+		}
+
+		target.Address = cfg.Address
+		if target.Address == "" {
+			target.Address = target.hostname()
+		}
+	}
+
+	return target, nil
+}
+
+// IsRemote reports whether the endpoint is on another machine.
+func (t Target) IsRemote() bool {
+	switch t.scheme() {
+	case "", "unix", "npipe":
+		return false
+	}
+
+	switch t.hostname() {
+	case "", "localhost", "127.0.0.1", "::1":
+		return false
+	}
+
+	return true
+}
+
+// ServiceHost returns the host local services use to reach infrastructure.
+func (t Target) ServiceHost() string {
+	if t.IsRemote() && !t.Tunnel {
+		return t.Address
+	}
+
+	return LocalServiceHost
+}
+
+// BindAddress returns the address remote containers publish their ports on.
+// Tunnelled and local infrastructure stays on loopback; direct remote access
+// needs the ports published on every interface.
+func (t Target) BindAddress() string {
+	if t.IsRemote() && !t.Tunnel {
+		return "0.0.0.0"
+	}
+
+	return "127.0.0.1"
+}
+
+// Env returns the variables that point docker subprocesses at this endpoint.
+func (t Target) Env() map[string]string {
+	switch {
+	case t.Host == "" || t.envHost:
+		return map[string]string{}
+	case t.Context != "":
+		return map[string]string{"DOCKER_CONTEXT": t.Context}
+	default:
+		return map[string]string{"DOCKER_HOST": t.Host}
+	}
+}
+
+// String describes the endpoint for logs and status output.
+func (t Target) String() string {
+	if t.Host == "" {
+		return "local"
+	}
+
+	if t.Context != "" {
+		return fmt.Sprintf("%s (context %s)", t.Host, t.Context)
+	}
+
+	return t.Host
+}
+
+// NewClient creates a Docker API client for the endpoint.
+func (t Target) NewClient() (*client.Client, error) {
+	opts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
+
+	switch {
+	case t.Host == "":
+	case t.scheme() == "ssh":
+		dest, port, err := t.sshDestination()
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts,
+			client.WithHost(sshDialHost),
+			client.WithDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialStdio(ctx, dest, port)
+			}),
+		)
+	default:
+		opts = append(opts, client.WithHost(t.Host))
+	}
+
+	dockerClient, err := client.NewClientWithOpts(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client for %s: %w", t, err)
+	}
+
+	return dockerClient, nil
+}
+
+// Probe reports whether an infrastructure port answers. Through a tunnel the
+// local listener always accepts, so the probe also waits briefly for SSH to
+// close the connection when the remote port refuses it.
+func (t Target) Probe(ctx context.Context, port int) bool {
+	d := net.Dialer{Timeout: 1 * time.Second}
+
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(t.ServiceHost(), strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+
+	defer conn.Close()
+
+	if !t.Tunnel {
+		return true
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(probeReadTimeout))
+
+	var buf [1]byte
+
+	_, err = conn.Read(buf[:])
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (t Target) scheme() string {
+	scheme, _, found := strings.Cut(t.Host, "://")
+	if !found {
+		return ""
+	}
+
+	return scheme
+}
+
+func (t Target) hostname() string {
+	parsed, err := url.Parse(t.Host)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Hostname()
+}
+
+// sshDestination returns the ssh destination (user@host) and port for the
+// endpoint. tcp:// endpoints tunnel to their hostname.
+func (t Target) sshDestination() (string, string, error) {
+	parsed, err := url.Parse(t.Host)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid Docker host %q: %w", t.Host, err)
+	}
+
+	if parsed.Hostname() == "" {
+		return "", "", fmt.Errorf("docker host %q has no hostname", t.Host)
+	}
+
+	dest := parsed.Hostname()
+	if parsed.User != nil && parsed.User.Username() != "" {
+		dest = parsed.User.Username() + "@" + dest
+	}
+
+	if t.scheme() != "ssh" {
+		return dest, "", nil
+	}
+
+	return dest, parsed.Port(), nil
+}
+
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".docker")
+}
+
+// currentContext returns the currentContext from the docker CLI config.
+func currentContext() string {
+	dir := dockerConfigDir()
+	if dir == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return ""
+	}
+
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+
+	return cfg.CurrentContext
+}
+
+// contextHost reads the docker endpoint of a named docker context.
+func contextHost(name string) (string, error) {
+	if name == defaultContext {
+		return "", nil
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	path := filepath.Join(dockerConfigDir(), "contexts", "meta", hex.EncodeToString(sum[:]), "meta.json")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("docker context %q not found: %w", name, err)
+	}
+
+	var meta struct {
+		Endpoints map[string]struct {
+			Host string `json:"Host"`
+		} `json:"Endpoints"`
+	}
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return "", fmt.Errorf("failed to parse docker context %q: %w", name, err)
+	}
+
+	host := meta.Endpoints["docker"].Host
+	if host == "" {
+		return "", fmt.Errorf("docker context %q has no docker endpoint", name)
+	}
+
+	return host, nil
+}
@@ -0,0 +1,81 @@
+package dockerhost
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+
+	writeContext(t, dir, "devbox", "ssh://me@devbox:2222")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"currentContext":"devbox"}`), 0600))
+
+	target, err := Resolve(config.DockerHostConfig{})
+	require.NoError(t, err)
+	require.Equal(t, "ssh://me@devbox:2222", target.Host)
+	require.Equal(t, "devbox", target.Context)
+	require.True(t, target.IsRemote())
+	require.True(t, target.Tunnel)
+	require.Equal(t, LocalServiceHost, target.ServiceHost())
+	require.Equal(t, "127.0.0.1", target.BindAddress())
+	require.Equal(t, map[string]string{"DOCKER_CONTEXT": "devbox"}, target.Env())
+
+	t.Setenv("DOCKER_HOST", "unix:///var/run/docker.sock")
+
+	target, err = Resolve(config.DockerHostConfig{})
+	require.NoError(t, err)
+	require.False(t, target.IsRemote())
+	require.Empty(t, target.Env())
+
+	noTunnel := false
+
+	target, err = Resolve(config.DockerHostConfig{Host: "tcp://devbox:2376", Address: "10.0.0.5", Tunnel: &noTunnel})
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.5", target.ServiceHost())
+	require.Equal(t, "0.0.0.0", target.BindAddress())
+	require.Equal(t, map[string]string{"DOCKER_HOST": "tcp://devbox:2376"}, target.Env())
+
+	_, err = Resolve(config.DockerHostConfig{Context: "missing"})
+	require.ErrorContains(t, err, `docker context "missing" not found`)
+
+	_, err = Resolve(config.DockerHostConfig{Host: "devbox"})
+	require.ErrorContains(t, err, "must include a scheme")
+}
+
+func TestTunnelArgs(t *testing.T) {
+	tunnel := NewTunnel(Target{Host: "ssh://me@devbox:2222", Tunnel: true}, t.TempDir(), []int{6379, 8123, 6379})
+
+	args, err := tunnel.Args()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"-o", "BatchMode=yes", "-o", "ConnectTimeout=10", "-p", "2222",
+		"-N", "-o", "ExitOnForwardFailure=yes", "-o", "ServerAliveInterval=30",
+		"-L", "127.0.0.1:6379:127.0.0.1:6379",
+		"-L", "127.0.0.1:8123:127.0.0.1:8123",
+		"--", "me@devbox",
+	}, args)
+
+	require.Zero(t, tunnel.PID())
+	require.NoError(t, tunnel.Stop())
+}
+
+func writeContext(t *testing.T, dir, name, host string) {
+	t.Helper()
+
+	sum := sha256.Sum256([]byte(name))
+	metaDir := filepath.Join(dir, "contexts", "meta", hex.EncodeToString(sum[:]))
+	require.NoError(t, os.MkdirAll(metaDir, 0755))
+
+	meta := `{"Name":"` + name + `","Endpoints":{"docker":{"Host":"` + host + `"}}}`
+	require.NoError(t, os.WriteFile(filepath.Join(metaDir, "meta.json"), []byte(meta), 0600))
+}
@@ -0,0 +1,267 @@
+package dockerhost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// tunnelStartupGrace is how long a new tunnel must stay up before it is
+// considered established. ExitOnForwardFailure makes ssh exit within it when
+// a local port cannot be bound.
+const tunnelStartupGrace = 750 * time.Millisecond
+
+// sshBaseArgs are the options shared by every ssh invocation.
+func sshBaseArgs(port string) []string {
+	args := []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=10"}
+	if port != "" {
+		args = append(args, "-p", port)
+	}
+
+	return args
+}
+
+// dialStdio connects to the remote daemon through `docker system dial-stdio`,
+// the same transport the docker CLI uses for ssh:// endpoints.
+func dialStdio(ctx context.Context, dest, port string) (net.Conn, error) {
+	args := append(sshBaseArgs(port), "--", dest, "docker", "system", "dial-stdio")
+
+	//nolint:gosec // G204: destination comes from the user's Docker host setting
+	cmd := exec.Command("ssh", args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ssh to %s: %w", dest, err)
+	}
+
+	if ctx.Err() != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+
+		return nil, ctx.Err()
+	}
+
+	return &stdioConn{cmd: cmd, stdin: stdin, stdout: stdout, dest: dest}, nil
+}
+
+// stdioConn is a net.Conn over an ssh subprocess's stdin and stdout.
+type stdioConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	dest   string
+}
+
+func (c *stdioConn) Read(p []byte) (int, error)  { return c.stdout.Read(p) }
+func (c *stdioConn) Write(p []byte) (int, error) { return c.stdin.Write(p) }
+
+func (c *stdioConn) Close() error {
+	_ = c.stdin.Close()
+	_ = c.cmd.Process.Kill()
+	_ = c.cmd.Wait()
+
+	return nil
+}
+
+func (c *stdioConn) LocalAddr() net.Addr  { return stdioAddr("local") }
+func (c *stdioConn) RemoteAddr() net.Addr { return stdioAddr(c.dest) }
+
+// Deadlines are not supported on pipes; the HTTP client relies on context
+// cancellation instead.
+func (c *stdioConn) SetDeadline(time.Time) error      { return nil }
+func (c *stdioConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *stdioConn) SetWriteDeadline(time.Time) error { return nil }
+
+type stdioAddr string
+
+func (a stdioAddr) Network() string { return "ssh" }
+func (a stdioAddr) String() string  { return string(a) }
+
+// Tunnel is a background ssh process forwarding infrastructure ports from
+// the remote Docker host to localhost. Its PID and ports are kept in
+// StateFile so later xcli invocations can reuse or stop it.
+type Tunnel struct {
+	Target    Target
+	StateFile string
+	LogFile   string
+	Ports     []int
+}
+
+type tunnelState struct {
+	PID   int   `json:"pid"`
+	Ports []int `json:"ports"`
+}
+
+// NewTunnel creates a tunnel for the given ports, keeping its state in stateDir.
+func NewTunnel(target Target, stateDir string, ports []int) *Tunnel {
+	sorted := slices.Clone(ports)
+	slices.Sort(sorted)
+
+	return &Tunnel{
+		Target:    target,
+		StateFile: filepath.Join(stateDir, "docker-tunnel.json"),
+		LogFile:   filepath.Join(stateDir, "logs", "docker-tunnel.log"),
+		Ports:     slices.Compact(sorted),
+	}
+}
+
+// Args returns the ssh arguments that forward every tunnel port.
+func (t *Tunnel) Args() ([]string, error) {
+	dest, port, err := t.Target.sshDestination()
+	if err != nil {
+		return nil, err
+	}
+
+	args := append(sshBaseArgs(port),
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+	)
+
+	for _, p := range t.Ports {
+		args = append(args, "-L", fmt.Sprintf("127.0.0.1:%d:127.0.0.1:%d", p, p))
+	}
+
+	return append(args, "--", dest), nil
+}
+
+// PID returns the PID of the running tunnel, or 0.
+func (t *Tunnel) PID() int {
+	state, err := t.readState()
+	if err != nil || !processAlive(state.PID) {
+		return 0
+	}
+
+	return state.PID
+}
+
+// Start launches the tunnel unless one with the same ports is already
+// running. A tunnel forwarding different ports is replaced.
+func (t *Tunnel) Start() error {
+	if state, err := t.readState(); err == nil && processAlive(state.PID) {
+		if slices.Equal(state.Ports, t.Ports) {
+			return nil
+		}
+
+		if err := t.Stop(); err != nil {
+			return err
+		}
+	}
+
+	args, err := t.Args()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.LogFile), 0755); err != nil {
+		return fmt.Errorf("failed to create tunnel log directory: %w", err)
+	}
+
+	logFile, err := os.OpenFile(t.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open tunnel log: %w", err)
+	}
+	defer logFile.Close()
+
+	//nolint:gosec // G204: destination comes from the user's Docker host setting
+	cmd := exec.Command("ssh", args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// Own process group so the tunnel outlives this invocation and
+	// is not killed by terminal signals aimed at xcli.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start ssh tunnel: %w", err)
+	}
+
+	exited := make(chan error, 1)
+
+	go func() { exited <- cmd.Wait() }()
+
+	select {
+	case waitErr := <-exited:
+		return fmt.Errorf("ssh tunnel to %s exited (see %s): %w", t.Target.Host, t.LogFile, waitErr)
+	case <-time.After(tunnelStartupGrace):
+	}
+
+	return t.writeState(tunnelState{PID: cmd.Process.Pid, Ports: t.Ports})
+}
+
+// Stop terminates the tunnel and removes its state file.
+func (t *Tunnel) Stop() error {
+	state, err := t.readState()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err == nil && processAlive(state.PID) {
+		if killErr := syscall.Kill(state.PID, syscall.SIGTERM); killErr != nil {
+			return fmt.Errorf("failed to stop ssh tunnel (pid %d): %w", state.PID, killErr)
+		}
+	}
+
+	if err := os.Remove(t.StateFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove tunnel state: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Tunnel) readState() (tunnelState, error) {
+	data, err := os.ReadFile(t.StateFile)
+	if err != nil {
+		return tunnelState{}, err
+	}
+
+	var state tunnelState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return tunnelState{}, fmt.Errorf("failed to parse %s: %w", t.StateFile, err)
+	}
+
+	return state, nil
+}
+
+func (t *Tunnel) writeState(state tunnelState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(t.StateFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write tunnel state: %w", err)
+	}
+
+	return nil
+}
+
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	return syscall.Kill(pid, 0) == nil
+}
+
+// String describes the forwarded ports.
+func (t *Tunnel) String() string {
+	return fmt.Sprintf("ssh tunnel to %s (ports %v)", t.Target.Host, t.Ports)
+}
@@ -0,0 +1,49 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PinFile records the exact commit of each repository in a set, keyed by
+// repository name. Its repos field matches the instance manifest's, so a
+// manifest.json can be read as a pin file too.
+type PinFile struct {
+	CreatedAt time.Time              `json:"createdAt"`
+	Repos     map[string]RepoVersion `json:"repos"`
+}
+
+// WritePinFile writes a pin file as indented JSON.
+func WritePinFile(path string, pins *PinFile) error {
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pins: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ReadPinFile reads a pin file or instance manifest.
+func ReadPinFile(path string) (*PinFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var pins PinFile
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if len(pins.Repos) == 0 {
+		return nil, fmt.Errorf("%s has no pinned repos", path)
+	}
+
+	return &pins, nil
+}
@@ -0,0 +1,45 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// Clone clones url into path, checking out branch when it is set. A clone
+// that fails is removed by git, so it can simply be retried.
+func Clone(ctx context.Context, url, path, branch string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	args := []string{"clone", "--quiet"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+
+	return gitRun(ctx, "", append(args, url, path)...)
+}
+
+// RemoteURL returns the URL of a named remote, or "" if it does not exist.
+func RemoteURL(ctx context.Context, repoPath, name string) string {
+	url, err := gitOutput(ctx, repoPath, "remote", "get-url", name)
+	if err != nil {
+		return ""
+	}
+
+	return url
+}
+
+// SetRemote adds a named remote, or points an existing one at url. It
+// reports whether the remote changed.
+func SetRemote(ctx context.Context, repoPath, name, url string) (bool, error) {
+	switch RemoteURL(ctx, repoPath, name) {
+	case url:
+		return false, nil
+	case "":
+		return true, gitRun(ctx, repoPath, "remote", "add", name, url)
+	default:
+		return true, gitRun(ctx, repoPath, "remote", "set-url", name, url)
+	}
+}
@@ -17,6 +17,19 @@ type RepoVersion struct {
 	Dirty  bool   `json:"dirty"`
 }
 
+// shortCommitLength is the number of hex digits ShortCommit keeps.
+const shortCommitLength = 12
+
+// ShortCommit abbreviates a commit hash for display.
+func ShortCommit(commit string) string {
+	commit = strings.TrimSpace(commit)
+	if len(commit) <= shortCommitLength {
+		return commit
+	}
+
+	return commit[:shortCommitLength]
+}
+
 // Snapshot reads branch, commit SHA, and dirty state without fetching.
 func Snapshot(ctx context.Context, repoPath string) (RepoVersion, error) {
 	absPath, err := filepath.Abs(repoPath)
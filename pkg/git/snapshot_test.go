@@ -42,6 +42,12 @@ func TestSnapshotReadsBranchCommitAndDirtyState(t *testing.T) {
 	require.True(t, version.Dirty)
 }
 
+func TestShortCommit(t *testing.T) {
+	require.Equal(t, "0123456789ab", ShortCommit("0123456789abcdef0123456789abcdef01234567\n"))
+	require.Equal(t, "abc123", ShortCommit("abc123"))
+	require.Empty(t, ShortCommit(""))
+}
+
 func runGit(t *testing.T, repoDir string, args ...string) {
 	t.Helper()
 
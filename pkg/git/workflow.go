@@ -0,0 +1,217 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrDirty is returned when an operation refuses to touch a worktree with
+// uncommitted changes.
+var ErrDirty = errors.New("repository has uncommitted changes")
+
+// Commit summarizes a repository's HEAD commit.
+type Commit struct {
+	ShortHash string
+	Subject   string
+	Date      string // Committer date, YYYY-MM-DD
+}
+
+// LastCommit returns the HEAD commit of a repository.
+func LastCommit(ctx context.Context, repoPath string) (Commit, error) {
+	out, err := gitOutput(ctx, repoPath, "log", "-1", "--format=%h%x00%s%x00%cs")
+	if err != nil {
+		return Commit{}, fmt.Errorf("failed to read last commit: %w", err)
+	}
+
+	parts := strings.SplitN(out, "\x00", 3)
+	if len(parts) != 3 {
+		return Commit{}, fmt.Errorf("unexpected git log output: %q", out)
+	}
+
+	return Commit{ShortHash: parts[0], Subject: parts[1], Date: parts[2]}, nil
+}
+
+// PullFastForward pulls the current branch's upstream, fast-forward only.
+// It refuses dirty worktrees with ErrDirty and reports whether HEAD moved.
+func PullFastForward(ctx context.Context, repoPath string) (bool, error) {
+	if err := requireClean(ctx, repoPath); err != nil {
+		return false, err
+	}
+
+	before, err := gitOutput(ctx, repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		return false, fmt.Errorf("failed to read commit: %w", err)
+	}
+
+	if err := gitRun(ctx, repoPath, "pull", "--ff-only", "--quiet"); err != nil {
+		return false, err
+	}
+
+	after, err := gitOutput(ctx, repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		return false, fmt.Errorf("failed to read commit: %w", err)
+	}
+
+	return before != after, nil
+}
+
+// Fetch fetches origin's refs quietly.
+func Fetch(ctx context.Context, repoPath string) error {
+	return gitRun(ctx, repoPath, "fetch", "--quiet", "origin")
+}
+
+// HasBranch reports whether a branch exists locally or as a remote-tracking
+// branch of origin.
+func HasBranch(ctx context.Context, repoPath, branch string) bool {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		if _, err := gitOutput(ctx, repoPath, "rev-parse", "--verify", "--quiet", ref); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checkout switches a clean worktree to a branch. A branch that only exists
+// on origin is created locally, tracking the remote one.
+func Checkout(ctx context.Context, repoPath, branch string) error {
+	if err := requireClean(ctx, repoPath); err != nil {
+		return err
+	}
+
+	return gitRun(ctx, repoPath, "checkout", "--quiet", branch)
+}
+
+// Restore checks out the exact commit of a RepoVersion in a clean worktree,
+// fetching when the commit is not available locally. The recorded branch is
+// checked out when it still points at the commit; otherwise HEAD is
+// detached. It reports whether HEAD changed.
+func Restore(ctx context.Context, repoPath string, version RepoVersion) (bool, error) {
+	if version.Commit == "" {
+		return false, fmt.Errorf("no commit recorded")
+	}
+
+	current, err := gitOutput(ctx, repoPath, "rev-parse", "HEAD")
+	if err != nil {
+		return false, fmt.Errorf("failed to read commit: %w", err)
+	}
+
+	if current == version.Commit {
+		return false, nil
+	}
+
+	if err := requireClean(ctx, repoPath); err != nil {
+		return false, err
+	}
+
+	if !hasCommit(ctx, repoPath, version.Commit) {
+		if err := Fetch(ctx, repoPath); err != nil {
+			return false, err
+		}
+
+		if !hasCommit(ctx, repoPath, version.Commit) {
+			return false, fmt.Errorf("commit %s not found locally or on origin", version.Commit)
+		}
+	}
+
+	if version.Branch != "" && version.Branch != "HEAD" {
+		tip, err := gitOutput(ctx, repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+version.Branch)
+		if err == nil && tip == version.Commit {
+			return true, gitRun(ctx, repoPath, "checkout", "--quiet", version.Branch)
+		}
+	}
+
+	return true, gitRun(ctx, repoPath, "checkout", "--quiet", "--detach", version.Commit)
+}
+
+// AddWorktree checks out branch at startPoint in a new worktree at path,
+// resetting the branch if it already exists. The repository's own checkout
+// is left untouched.
+func AddWorktree(ctx context.Context, repoPath, path, branch, startPoint string) error {
+	return gitRun(ctx, repoPath, "worktree", "add", "--quiet", "-B", branch, path, startPoint)
+}
+
+// RemoveWorktree removes a worktree added by AddWorktree, discarding its
+// changes, and deletes its local branch.
+func RemoveWorktree(ctx context.Context, repoPath, path, branch string) error {
+	if err := gitRun(ctx, repoPath, "worktree", "remove", "--force", path); err != nil {
+		return err
+	}
+
+	return gitRun(ctx, repoPath, "branch", "--quiet", "-D", branch)
+}
+
+// IsDirty reports whether a worktree has uncommitted changes.
+func IsDirty(ctx context.Context, repoPath string) (bool, error) {
+	status, err := gitOutput(ctx, repoPath, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to read dirty state: %w", err)
+	}
+
+	return status != "", nil
+}
+
+// CommitPaths commits the changes to paths. It reports false, without
+// committing, when the paths are unchanged.
+func CommitPaths(ctx context.Context, repoPath, message string, paths ...string) (bool, error) {
+	if err := gitRun(ctx, repoPath, append([]string{"add", "--"}, paths...)...); err != nil {
+		return false, err
+	}
+
+	staged, err := gitOutput(ctx, repoPath, "diff", "--cached", "--name-only")
+	if err != nil {
+		return false, fmt.Errorf("failed to read staged changes: %w", err)
+	}
+
+	if staged == "" {
+		return false, nil
+	}
+
+	return true, gitRun(ctx, repoPath, "commit", "--quiet", "-m", message)
+}
+
+// PushBranch pushes a branch to origin and sets it as the upstream. An
+// existing remote branch is only overwritten if it is still where the last
+// fetch saw it.
+func PushBranch(ctx context.Context, repoPath, branch string) error {
+	return gitRun(ctx, repoPath, "push", "--quiet", "--force-with-lease", "--set-upstream", "origin", branch)
+}
+
+func hasCommit(ctx context.Context, repoPath, commit string) bool {
+	_, err := gitOutput(ctx, repoPath, "cat-file", "-e", commit+"^{commit}")
+
+	return err == nil
+}
+
+func requireClean(ctx context.Context, repoPath string) error {
+	dirty, err := IsDirty(ctx, repoPath)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return ErrDirty
+	}
+
+	return nil
+}
+
+// gitRun runs a git command, returning its stderr in the error.
+func gitRun(ctx context.Context, repoPath string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w (stderr: %s)", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullFastForwardSkipsDirtyRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	ctx := context.Background()
+	origin := newTestRepo(t)
+	clone := filepath.Join(t.TempDir(), "clone")
+
+	runGit(t, origin, "clone", "--quiet", origin, clone)
+	commitFile(t, origin, "second.txt", "second")
+
+	require.NoError(t, os.WriteFile(filepath.Join(clone, "dirty.txt"), []byte("dirty\n"), 0644))
+
+	_, err := PullFastForward(ctx, clone)
+	require.ErrorIs(t, err, ErrDirty)
+
+	require.NoError(t, os.Remove(filepath.Join(clone, "dirty.txt")))
+
+	pulled, err := PullFastForward(ctx, clone)
+	require.NoError(t, err)
+	require.True(t, pulled)
+
+	pulled, err = PullFastForward(ctx, clone)
+	require.NoError(t, err)
+	require.False(t, pulled)
+}
+
+func TestRestoreChecksOutPinnedCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	ctx := context.Background()
+	repoDir := newTestRepo(t)
+
+	pinned, err := Snapshot(ctx, repoDir)
+	require.NoError(t, err)
+
+	commitFile(t, repoDir, "second.txt", "second")
+
+	changed, err := Restore(ctx, repoDir, pinned)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	restored, err := Snapshot(ctx, repoDir)
+	require.NoError(t, err)
+	require.Equal(t, pinned.Commit, restored.Commit)
+	require.Equal(t, "HEAD", restored.Branch, "branch moved on, so HEAD is detached")
+
+	changed, err = Restore(ctx, repoDir, pinned)
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestWorktreeLeavesCheckoutUntouched(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	ctx := context.Background()
+	repoDir := newTestRepo(t)
+
+	before, err := Snapshot(ctx, repoDir)
+	require.NoError(t, err)
+
+	worktree := filepath.Join(t.TempDir(), "bump")
+	require.NoError(t, AddWorktree(ctx, repoDir, worktree, "xcli/bump", before.Commit))
+
+	commitFile(t, worktree, "go.mod", "module example")
+
+	after, err := Snapshot(ctx, repoDir)
+	require.NoError(t, err)
+	require.Equal(t, before.Commit, after.Commit)
+	require.Equal(t, before.Branch, after.Branch)
+	require.NoFileExists(t, filepath.Join(repoDir, "go.mod"))
+
+	require.NoError(t, RemoveWorktree(ctx, repoDir, worktree, "xcli/bump"))
+	require.NoDirExists(t, worktree)
+
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/xcli/bump")
+	cmd.Dir = repoDir
+	require.Error(t, cmd.Run(), "bump branch deleted")
+}
+
+func TestReadPinFileAcceptsManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	manifest := `{"schemaVersion":1,"instanceId":"abc","repos":{"cbt":{"path":"/src/cbt","branch":"master","commit":"0123456789abcdef","dirty":false}}}`
+
+	require.NoError(t, os.WriteFile(path, []byte(manifest), 0644))
+
+	pins, err := ReadPinFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "0123456789abcdef", pins.Repos["cbt"].Commit)
+}
+
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir := t.TempDir()
+
+	runGit(t, repoDir, "init", "--quiet")
+	runGit(t, repoDir, "config", "user.email", "test@example.com")
+	runGit(t, repoDir, "config", "user.name", "Test User")
+	runGit(t, repoDir, "config", "commit.gpgsign", "false")
+	commitFile(t, repoDir, "README.md", "hello")
+
+	return repoDir
+}
+
+func commitFile(t *testing.T, repoDir, name, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, name), []byte(content+"\n"), 0644))
+	runGit(t, repoDir, "add", name)
+	runGit(t, repoDir, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "--quiet", "-m", name)
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/ethpandaops/xcli/pkg/config"
 	"github.com/ethpandaops/xcli/pkg/configgen"
 	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/dockerhost"
 	executil "github.com/ethpandaops/xcli/pkg/exec"
 	"github.com/ethpandaops/xcli/pkg/instance"
 	"github.com/ethpandaops/xcli/pkg/mode"
@@ -39,6 +40,11 @@ const (
 
 	defaultXatuCBTProjectName = "xatu-cbt-platform"
 
+	// Infrastructure component names reported by Status.
+	componentClickHouseCBT  = "ClickHouse CBT"
+	componentClickHouseXatu = "ClickHouse Xatu"
+	componentRedis          = "Redis"
+
 	// cmdInfra is the xatu-cbt "infra" subcommand.
 	cmdInfra = "infra"
 	// flagProjectName is the xatu-cbt "--project-name" flag.
@@ -59,6 +65,9 @@ type Manager struct {
 	xcliDir       string
 	runtime       *instance.Runtime
 	runCmd        func(*exec.Cmd, bool) error
+	docker        dockerhost.Target
+	dockerErr     error
+	usage         usageCache
 }
 
 // NewManager creates a new infrastructure manager.
@@ -81,6 +90,8 @@ func NewManagerWithRuntime(
 		xcliDir = runtime.Manifest.StateDir
 	}
 
+	docker, dockerErr := dockerhost.Resolve(cfg.Infrastructure.Docker)
+
 	return &Manager{
 		log:         log.WithField("component", "infrastructure"),
 		cfg:         cfg,
@@ -90,6 +101,8 @@ func NewManagerWithRuntime(
 		xcliDir:     xcliDir,
 		runtime:     runtime,
 		runCmd:      executil.RunCmd,
+		docker:      docker,
+		dockerErr:   dockerErr,
 	}
 }
 
@@ -184,12 +197,25 @@ func (m *Manager) runXatuCBT(ctx context.Context, args ...string) error {
 }
 
 func (m *Manager) xatuCBTEnv(base []string) ([]string, error) {
+	if m.dockerErr != nil {
+		return nil, fmt.Errorf("failed to resolve Docker host: %w", m.dockerErr)
+	}
+
 	plan, err := m.xatuCBTPortPlan()
 	if err != nil {
 		return nil, err
 	}
 
-	return mergeEnv(base, plan.XatuCBTEnv(m.xatuCBTProjectName())), nil
+	env := plan.XatuCBTEnv(m.xatuCBTProjectName())
+	for _, name := range instance.XatuCBTBindAddressVars() {
+		env[name] = m.docker.BindAddress()
+	}
+
+	for name, value := range m.docker.Env() {
+		env[name] = value
+	}
+
+	return mergeEnv(base, env), nil
 }
 
 func (m *Manager) xatuCBTPortPlan() (instance.PortPlan, error) {
@@ -255,6 +281,15 @@ func mergeEnv(base []string, overrides map[string]string) []string {
 
 // Start starts infrastructure via xatu-cbt.
 func (m *Manager) Start(ctx context.Context) error {
+	if m.dockerErr != nil {
+		return fmt.Errorf("failed to resolve Docker host: %w", m.dockerErr)
+	}
+
+	// Forward ports first so the running check sees remote infrastructure.
+	if err := m.startTunnel(); err != nil {
+		return fmt.Errorf("failed to start Docker host tunnel: %w", err)
+	}
+
 	// Check if infrastructure is already running
 	if m.IsRunning(ctx) {
 		m.log.Info("infrastructure is already running")
@@ -273,9 +308,16 @@ func (m *Manager) Start(ctx context.Context) error {
 		xatuSource = constants.InfraModeExternal
 	}
 
+	if m.mode.UsesXatuStack() {
+		if _, err := m.ResolveXatuStack(ctx); err != nil {
+			return err
+		}
+	}
+
 	m.log.WithFields(logrus.Fields{
 		"mode":        m.mode.Name(),
 		"xatu_source": xatuSource,
+		"docker_host": m.docker.String(),
 	}).Info("starting infrastructure")
 
 	// Create spinner for infrastructure startup
@@ -304,6 +346,15 @@ func (m *Manager) Start(ctx context.Context) error {
 			return fmt.Errorf("failed to build external Xatu URL: %w", urlErr)
 		}
 
+		// xatu-cbt's ClickHouse reaches the xatu stack through the host.
+		if m.mode.UsesXatuStack() {
+			if xatuURL, urlErr = containerURL(xatuURL); urlErr != nil {
+				spinner.Fail("Failed to start infrastructure services")
+
+				return fmt.Errorf("failed to build xatu stack URL: %w", urlErr)
+			}
+		}
+
 		args = append(args, "--xatu-url", xatuURL)
 	}
 
@@ -366,6 +417,10 @@ func (m *Manager) Stop(ctx context.Context) error {
 		return fmt.Errorf("failed to stop infrastructure: %w", err)
 	}
 
+	if err := m.stopTunnel(); err != nil {
+		m.log.WithError(err).Warn("failed to stop Docker host tunnel")
+	}
+
 	m.log.WithField("mode", m.mode.Name()).Info("infrastructure stopped")
 
 	return nil
@@ -399,6 +454,10 @@ func (m *Manager) Destroy(ctx context.Context) error {
 		return fmt.Errorf("failed to reset infrastructure: %w", err)
 	}
 
+	if err := m.stopTunnel(); err != nil {
+		m.log.WithError(err).Warn("failed to stop Docker host tunnel")
+	}
+
 	m.log.WithField("mode", m.mode.Name()).Info("infrastructure reset complete")
 
 	return nil
@@ -406,32 +465,51 @@ func (m *Manager) Destroy(ctx context.Context) error {
 
 // ResetRedis intentionally clears Redis data for this instance only.
 func (m *Manager) ResetRedis(ctx context.Context) error {
+	if err := m.runRedisCLI(ctx, "FLUSHALL"); err != nil {
+		return fmt.Errorf("failed to reset Redis for instance %s: %w", m.xatuCBTProjectName(), err)
+	}
+
+	return nil
+}
+
+// ResetNetworkRedis clears one network's Redis database, dropping the bounds
+// and task state its CBT engine keeps there.
+func (m *Manager) ResetNetworkRedis(ctx context.Context, network string) error {
+	redisDB, ok := m.cfg.RedisDB(network)
+	if !ok {
+		return fmt.Errorf("network %s has no Redis database (not configured or not enabled)", network)
+	}
+
+	if err := m.runRedisCLI(ctx, "-n", strconv.Itoa(redisDB), "FLUSHDB"); err != nil {
+		return fmt.Errorf("failed to reset Redis for network %s: %w", network, err)
+	}
+
+	return nil
+}
+
+// runRedisCLI runs redis-cli against this instance's Redis.
+func (m *Manager) runRedisCLI(ctx context.Context, args ...string) error {
 	plan, err := m.xatuCBTPortPlan()
 	if err != nil {
 		return err
 	}
 
+	host := "127.0.0.1"
+	if m.docker.ServiceHost() != dockerhost.LocalServiceHost {
+		host = m.docker.ServiceHost()
+	}
+
+	cmdArgs := append([]string{"-h", host, "-p", strconv.Itoa(plan.Redis)}, args...)
+
 	//nolint:gosec // G204: args are internally constructed, not user input
-	cmd := exec.CommandContext(
-		ctx,
-		"redis-cli",
-		"-h",
-		"127.0.0.1",
-		"-p",
-		strconv.Itoa(plan.Redis),
-		"FLUSHALL",
-	)
+	cmd := exec.CommandContext(ctx, "redis-cli", cmdArgs...)
 
 	runCmd := m.runCmd
 	if runCmd == nil {
 		runCmd = executil.RunCmd
 	}
 
-	if err := runCmd(cmd, m.verbose); err != nil {
-		return fmt.Errorf("failed to reset Redis for instance %s: %w", m.xatuCBTProjectName(), err)
-	}
-
-	return nil
+	return runCmd(cmd, m.verbose)
 }
 
 // SetupNetwork runs migrations for a network.
@@ -468,8 +546,7 @@ func (m *Manager) IsRunning(ctx context.Context) bool {
 	}
 
 	for _, port := range ports {
-		addr := fmt.Sprintf("localhost:%d", port)
-		if !m.checkPort(ctx, addr) {
+		if !m.docker.Probe(ctx, port) {
 			return false
 		}
 	}
@@ -509,8 +586,7 @@ func (m *Manager) WaitForReady(ctx context.Context, timeout time.Duration, spinn
 			allReady := true
 
 			for _, port := range ports {
-				addr := fmt.Sprintf("localhost:%d", port)
-				if !m.checkPort(ctx, addr) {
+				if !m.docker.Probe(ctx, port) {
 					m.log.WithField("port", port).Debug("waiting for port")
 
 					allReady = false
@@ -540,27 +616,72 @@ func (m *Manager) Status(ctx context.Context) map[string]bool {
 	}
 
 	portNames := map[int]string{
-		plan.ClickHouseCBT01HTTP: "ClickHouse CBT",
-		plan.Redis:               "Redis",
+		plan.ClickHouseCBT01HTTP: componentClickHouseCBT,
+		plan.Redis:               componentRedis,
 	}
 
 	// Local mode also runs a local ClickHouse Xatu instance.
 	if !m.mode.NeedsExternalClickHouse() {
-		portNames[plan.ClickHouseXatu01HTTP] = "ClickHouse Xatu"
+		portNames[plan.ClickHouseXatu01HTTP] = componentClickHouseXatu
 	}
 
 	status := make(map[string]bool, len(portNames))
 
 	for port, name := range portNames {
-		addr := fmt.Sprintf("localhost:%d", port)
-		status[name] = m.checkPort(ctx, addr)
+		status[name] = m.docker.Probe(ctx, port)
 	}
 
 	return status
 }
 
+// WaitForComponent waits until one infrastructure component
+// (constants.InfraClickHouseCBT, InfraClickHouseXatu or InfraRedis) answers on
+// its port.
+func (m *Manager) WaitForComponent(ctx context.Context, component string, timeout time.Duration) error {
+	plan, err := m.xatuCBTPortPlan()
+	if err != nil {
+		return fmt.Errorf("failed to resolve infrastructure ports: %w", err)
+	}
+
+	var port int
+
+	switch component {
+	case constants.InfraClickHouseCBT:
+		port = plan.ClickHouseCBT01HTTP
+	case constants.InfraClickHouseXatu:
+		port = plan.ClickHouseXatu01HTTP
+	case constants.InfraRedis:
+		port = plan.Redis
+	default:
+		return fmt.Errorf("unknown infrastructure component: %s", component)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if m.docker.Probe(ctx, port) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s is not ready on port %d after %v", component, port, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
 // TestExternalConnection tests connectivity to external ClickHouse using docker.
+// In xatu-local mode it queries the xatu stack's ClickHouse over HTTP instead.
 func (m *Manager) TestExternalConnection(ctx context.Context) error {
+	if m.mode.UsesXatuStack() {
+		return m.testXatuStackConnection(ctx)
+	}
+
 	// Parse the external URL to extract host, port, and credentials
 	externalURL := m.cfg.Infrastructure.ClickHouse.Xatu.ExternalURL
 
@@ -742,9 +863,10 @@ func (m *Manager) StopObservabilityService(ctx context.Context, service string)
 // AutoSeedBoundsIfNeeded checks if local Redis has external model bounds and seeds them
 // from production if missing. External bounds tell CBT the min/max range of data available
 // on the external ClickHouse, avoiding slow initial full scans.
-func (m *Manager) AutoSeedBoundsIfNeeded(ctx context.Context, spinner ui.Task) error {
-	// Only seed in hybrid mode (external xatu + local xatu-cbt)
-	if !m.mode.NeedsExternalClickHouse() {
+func (m *Manager) AutoSeedBoundsIfNeeded(ctx context.Context, spinner ui.Task, networks []config.NetworkConfig) error {
+	// Only seed in hybrid mode (external xatu + local xatu-cbt); the xatu
+	// stack has no production bounds.
+	if !m.mode.NeedsExternalClickHouse() || m.mode.UsesXatuStack() {
 		return nil
 	}
 
@@ -752,8 +874,7 @@ func (m *Manager) AutoSeedBoundsIfNeeded(ctx context.Context, spinner ui.Task) e
 
 	seeder := NewBoundsSeederWithRuntime(m.log, m.runtime)
 
-	enabledNetworks := m.cfg.EnabledNetworks()
-	if len(enabledNetworks) == 0 {
+	if len(networks) == 0 {
 		m.log.Warn("No networks enabled, skipping bounds seeding")
 
 		return nil
@@ -762,8 +883,13 @@ func (m *Manager) AutoSeedBoundsIfNeeded(ctx context.Context, spinner ui.Task) e
 	seededCount := 0
 	skippedCount := 0
 
-	for i, network := range enabledNetworks {
-		redisDB := i // mainnet=0, sepolia=1, hoodi=2, etc.
+	for _, network := range networks {
+		redisDB, ok := m.cfg.RedisDB(network.Name)
+		if !ok {
+			m.log.WithField("network", network.Name).Warn("Network has no Redis database, skipping bounds seeding")
+
+			continue
+		}
 
 		spinner.UpdateText(fmt.Sprintf("Checking external bounds for %s", network.Name))
 
@@ -809,20 +935,6 @@ func (m *Manager) AutoSeedBoundsIfNeeded(ctx context.Context, spinner ui.Task) e
 	return nil
 }
 
-// checkPort checks if a port is open.
-func (m *Manager) checkPort(ctx context.Context, addr string) bool {
-	d := net.Dialer{Timeout: 1 * time.Second}
-
-	conn, err := d.DialContext(ctx, "tcp", addr)
-	if err != nil {
-		return false
-	}
-
-	conn.Close()
-
-	return true
-}
-
 // checkClusterShardDNS performs DNS lookups for individual shard endpoints
 // when the configured host matches a ClickHouse cluster (e.g., chendpoint-clickhouse-raw).
 // This helps diagnose connectivity issues to specific shards in the cluster.
@@ -909,7 +1021,7 @@ func (m *Manager) startObservability(ctx context.Context) error {
 
 	generator := configgen.NewGenerator(m.log, m.cfg)
 	if m.runtime != nil {
-		generator = configgen.NewRuntimeGenerator(m.log, m.runtime)
+		generator = configgen.NewRuntimeGenerator(m.log, m.cfg, m.runtime)
 	}
 
 	m.log.Debug("generating observability configs")
@@ -6,7 +6,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net"
 	"path/filepath"
 	"strings"
 	"time"
@@ -24,6 +23,7 @@ import (
 
 	"github.com/ethpandaops/xcli/pkg/config"
 	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/dockerhost"
 	"github.com/ethpandaops/xcli/pkg/instance"
 	"github.com/ethpandaops/xcli/pkg/ui"
 )
@@ -45,6 +45,7 @@ type ContainerStatus struct {
 type ObservabilityManager struct {
 	log       logrus.FieldLogger
 	docker    *client.Client
+	target    dockerhost.Target
 	cfg       *config.LabConfig
 	xcliDir   string
 	resources observabilityResources
@@ -74,9 +75,14 @@ func NewObservabilityManagerWithRuntime(
 	xcliDir string,
 	runtime *instance.Runtime,
 ) (*ObservabilityManager, error) {
-	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	target, err := dockerhost.Resolve(cfg.Infrastructure.Docker)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+		return nil, fmt.Errorf("failed to resolve Docker host: %w", err)
+	}
+
+	dockerClient, err := target.NewClient()
+	if err != nil {
+		return nil, err
 	}
 
 	if runtime != nil && runtime.Manifest != nil && runtime.Manifest.StateDir != "" {
@@ -86,6 +92,7 @@ func NewObservabilityManagerWithRuntime(
 	return &ObservabilityManager{
 		log:       log.WithField("component", "observability"),
 		docker:    dockerClient,
+		target:    target,
 		cfg:       cfg,
 		xcliDir:   xcliDir,
 		resources: newObservabilityResources(cfg, runtime),
@@ -205,8 +212,8 @@ func (m *ObservabilityManager) Start(ctx context.Context) error {
 	grafanaPort := m.servicePort(constants.ServiceGrafana)
 
 	m.log.WithFields(logrus.Fields{
-		"prometheus_url": fmt.Sprintf("http://localhost:%d", promPort),
-		"grafana_url":    fmt.Sprintf("http://localhost:%d", grafanaPort),
+		"prometheus_url": fmt.Sprintf("http://%s:%d", m.target.ServiceHost(), promPort),
+		"grafana_url":    fmt.Sprintf("http://%s:%d", m.target.ServiceHost(), grafanaPort),
 	}).Info("observability stack started")
 
 	return nil
@@ -385,7 +392,9 @@ func (m *ObservabilityManager) startPrometheus(ctx context.Context) error {
 	}
 
 	// Prepare config path
-	configPath := filepath.Join(m.xcliDir, "configs", "prometheus.yml")
+	configs := []configMount{
+		{source: filepath.Join(m.xcliDir, "configs", "prometheus.yml"), target: "/etc/prometheus/prometheus.yml"},
+	}
 
 	promPort := m.servicePort(constants.ServicePrometheus)
 	if err := m.ensureVolume(ctx, volumeName); err != nil {
@@ -407,9 +416,7 @@ func (m *ObservabilityManager) startPrometheus(ctx context.Context) error {
 	}
 
 	hostConfig := &container.HostConfig{
-		Binds: []string{
-			fmt.Sprintf("%s:/etc/prometheus/prometheus.yml:ro", configPath),
-		},
+		Binds: m.configBinds(configs),
 		Mounts: []mount.Mount{
 			{
 				Type:   mount.TypeVolume,
@@ -440,6 +447,10 @@ func (m *ObservabilityManager) startPrometheus(ctx context.Context) error {
 		return fmt.Errorf("failed to create Prometheus container: %w", err)
 	}
 
+	if err := m.copyConfigs(ctx, resp.ID, configs); err != nil {
+		return fmt.Errorf("failed to copy Prometheus config: %w", err)
+	}
+
 	// Start container
 	if err := m.docker.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
 		return fmt.Errorf("failed to start Prometheus container: %w", err)
@@ -473,8 +484,10 @@ func (m *ObservabilityManager) startGrafana(ctx context.Context) error {
 	}
 
 	// Prepare paths
-	provisioningPath := filepath.Join(m.xcliDir, "configs", "grafana", "provisioning")
-	dashboardsPath := filepath.Join(m.xcliDir, "configs", "grafana", "dashboards")
+	configs := []configMount{
+		{source: filepath.Join(m.xcliDir, "configs", "grafana", "provisioning"), target: "/etc/grafana/provisioning"},
+		{source: filepath.Join(m.xcliDir, "configs", "grafana", "dashboards"), target: "/var/lib/grafana/dashboards"},
+	}
 
 	grafanaPort := m.servicePort(constants.ServiceGrafana)
 	if err := m.ensureVolume(ctx, volumeName); err != nil {
@@ -497,10 +510,7 @@ func (m *ObservabilityManager) startGrafana(ctx context.Context) error {
 	}
 
 	hostConfig := &container.HostConfig{
-		Binds: []string{
-			fmt.Sprintf("%s:/etc/grafana/provisioning:ro", provisioningPath),
-			fmt.Sprintf("%s:/var/lib/grafana/dashboards:ro", dashboardsPath),
-		},
+		Binds: m.configBinds(configs),
 		Mounts: []mount.Mount{
 			{
 				Type:   mount.TypeVolume,
@@ -531,6 +541,10 @@ func (m *ObservabilityManager) startGrafana(ctx context.Context) error {
 		return fmt.Errorf("failed to create Grafana container: %w", err)
 	}
 
+	if err := m.copyConfigs(ctx, resp.ID, configs); err != nil {
+		return fmt.Errorf("failed to copy Grafana config: %w", err)
+	}
+
 	// Start container
 	if err := m.docker.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
 		return fmt.Errorf("failed to start Grafana container: %w", err)
@@ -562,16 +576,11 @@ func (m *ObservabilityManager) waitForHealth(ctx context.Context, timeout time.D
 			allReady := true
 
 			for _, port := range ports {
-				addr := fmt.Sprintf("localhost:%d", port)
-
-				conn, err := net.DialTimeout("tcp", addr, 1*time.Second)
-				if err != nil {
+				if !m.target.Probe(ctx, port) {
 					allReady = false
 
 					break
 				}
-
-				conn.Close()
 			}
 
 			if allReady {
@@ -0,0 +1,170 @@
+package infrastructure
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/ethpandaops/xcli/pkg/dockerhost"
+)
+
+// configMount is a local config file or directory a container reads.
+type configMount struct {
+	source string
+	target string
+}
+
+// configBinds returns read-only bind mounts for local daemons. A remote
+// daemon cannot see local paths, so its configs are copied in after create.
+func (m *ObservabilityManager) configBinds(mounts []configMount) []string {
+	if m.target.IsRemote() {
+		return nil
+	}
+
+	binds := make([]string, 0, len(mounts))
+	for _, mnt := range mounts {
+		binds = append(binds, fmt.Sprintf("%s:%s:ro", mnt.source, mnt.target))
+	}
+
+	return binds
+}
+
+// copyConfigs copies config mounts into a created container on a remote daemon.
+func (m *ObservabilityManager) copyConfigs(ctx context.Context, containerID string, mounts []configMount) error {
+	if !m.target.IsRemote() {
+		return nil
+	}
+
+	for _, mnt := range mounts {
+		archive, err := tarPath(mnt.source, path.Base(mnt.target))
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", mnt.source, err)
+		}
+
+		if err := m.docker.CopyToContainer(ctx, containerID, path.Dir(mnt.target), archive, container.CopyToContainerOptions{}); err != nil {
+			return fmt.Errorf("failed to copy %s into container: %w", mnt.source, err)
+		}
+	}
+
+	return nil
+}
+
+// tarPath archives a file or directory tree under the given name.
+func tarPath(source, name string) (io.Reader, error) {
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+
+	err := filepath.WalkDir(source, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, p)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write(data)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return &buf, nil
+}
+
+// tunnel returns the SSH port forward for remote infrastructure, or nil when
+// the Docker host is local or reached directly.
+func (m *Manager) tunnel() (*dockerhost.Tunnel, error) {
+	if !m.docker.IsRemote() || !m.docker.Tunnel {
+		return nil, nil //nolint:nilnil // no tunnel is needed for this Docker host
+	}
+
+	plan, err := m.xatuCBTPortPlan()
+	if err != nil {
+		return nil, err
+	}
+
+	ports := []int{
+		plan.ClickHouseCBT01HTTP, plan.ClickHouseCBT01TCP,
+		plan.ClickHouseCBT02HTTP, plan.ClickHouseCBT02TCP,
+		plan.Redis,
+	}
+
+	if !m.mode.NeedsExternalClickHouse() {
+		ports = append(ports,
+			plan.ClickHouseXatu01HTTP, plan.ClickHouseXatu01TCP,
+			plan.ClickHouseXatu02HTTP, plan.ClickHouseXatu02TCP,
+		)
+	}
+
+	if m.cfg.Infrastructure.Observability.Enabled {
+		ports = append(ports, plan.Prometheus, plan.Grafana)
+	}
+
+	return dockerhost.NewTunnel(m.docker, m.xcliDir, ports), nil
+}
+
+// startTunnel forwards the infrastructure ports from a remote Docker host.
+func (m *Manager) startTunnel() error {
+	tunnel, err := m.tunnel()
+	if err != nil || tunnel == nil {
+		return err
+	}
+
+	if err := tunnel.Start(); err != nil {
+		return err
+	}
+
+	m.log.WithField("pid", tunnel.PID()).Infof("forwarding infrastructure ports via %s", tunnel)
+
+	return nil
+}
+
+// stopTunnel stops the port forward started by startTunnel.
+func (m *Manager) stopTunnel() error {
+	tunnel, err := m.tunnel()
+	if err != nil || tunnel == nil {
+		return err
+	}
+
+	return tunnel.Stop()
+}
@@ -0,0 +1,232 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// usageCacheTTL keeps docker stats results for this long; each sample takes
+// the daemon about a second per container.
+const usageCacheTTL = 10 * time.Second
+
+// ContainerUsage is a resource usage sample of one infrastructure container,
+// as shown by docker stats.
+type ContainerUsage struct {
+	// CPUPercent is CPU use over the daemon's sampling interval; 100 means
+	// one full core.
+	CPUPercent float64
+	// MemoryBytes is the memory in use, excluding the page cache.
+	MemoryBytes uint64
+	// MemoryLimitBytes is the container's memory limit (the host's memory
+	// when unlimited).
+	MemoryLimitBytes uint64
+}
+
+// usageCache holds the last ContainerUsage result.
+type usageCache struct {
+	mu         sync.Mutex
+	at         time.Time
+	usage      map[string]ContainerUsage
+	refreshing bool
+}
+
+// ContainerUsage samples docker stats for the instance's infrastructure and
+// observability containers, keyed by container name. Results are cached for
+// a few seconds so polling dashboards do not pile up stats requests.
+func (m *Manager) ContainerUsage(ctx context.Context) (map[string]ContainerUsage, error) {
+	m.usage.mu.Lock()
+	if m.usage.usage != nil && time.Since(m.usage.at) < usageCacheTTL {
+		usage := m.usage.usage
+		m.usage.mu.Unlock()
+
+		return usage, nil
+	}
+	m.usage.mu.Unlock()
+
+	usage, err := m.sampleContainers(ctx)
+	if err != nil {
+		// Back off failed samples as well, so pollers do not retry each tick.
+		m.usage.mu.Lock()
+		m.usage.at = time.Now()
+		m.usage.mu.Unlock()
+
+		return nil, err
+	}
+
+	m.usage.mu.Lock()
+	m.usage.at = time.Now()
+	m.usage.usage = usage
+	m.usage.mu.Unlock()
+
+	return usage, nil
+}
+
+// CachedContainerUsage returns the last ContainerUsage result without
+// waiting, refreshing it in the background when it is stale. It is meant for
+// dashboards that poll; the first call returns nil.
+func (m *Manager) CachedContainerUsage() map[string]ContainerUsage {
+	m.usage.mu.Lock()
+	defer m.usage.mu.Unlock()
+
+	if time.Since(m.usage.at) >= usageCacheTTL && !m.usage.refreshing {
+		m.usage.refreshing = true
+
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), usageCacheTTL)
+			defer cancel()
+
+			if _, err := m.ContainerUsage(ctx); err != nil {
+				m.log.WithError(err).Debug("failed to sample container usage")
+			}
+
+			m.usage.mu.Lock()
+			m.usage.refreshing = false
+			m.usage.mu.Unlock()
+		}()
+	}
+
+	return m.usage.usage
+}
+
+// sampleContainers reads one docker stats sample per container.
+func (m *Manager) sampleContainers(ctx context.Context) (map[string]ContainerUsage, error) {
+	if m.dockerErr != nil {
+		return nil, fmt.Errorf("failed to resolve Docker host: %w", m.dockerErr)
+	}
+
+	cli, err := m.docker.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project="+m.xatuCBTProjectName())),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list infrastructure containers: %w", err)
+	}
+
+	if m.cfg.Infrastructure.Observability.Enabled {
+		for _, name := range newObservabilityResources(m.cfg, m.runtime).containers {
+			observability, err := cli.ContainerList(ctx, container.ListOptions{
+				Filters: filters.NewArgs(filters.Arg("name", name)),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list observability containers: %w", err)
+			}
+
+			containers = append(containers, observability...)
+		}
+	}
+
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		usage = make(map[string]ContainerUsage, len(containers))
+	)
+
+	for _, c := range containers {
+		if len(c.Names) == 0 {
+			continue
+		}
+
+		name := strings.TrimPrefix(c.Names[0], "/")
+
+		wg.Go(func() {
+			resp, err := cli.ContainerStats(ctx, c.ID, false)
+			if err != nil {
+				m.log.WithError(err).WithField("container", name).Debug("failed to read container stats")
+
+				return
+			}
+			defer resp.Body.Close()
+
+			var stats container.StatsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+				m.log.WithError(err).WithField("container", name).Debug("failed to decode container stats")
+
+				return
+			}
+
+			mu.Lock()
+			usage[name] = containerUsage(stats)
+			mu.Unlock()
+		})
+	}
+
+	wg.Wait()
+
+	return usage, nil
+}
+
+// containerUsage derives CPU and memory use from a stats response the same
+// way the docker CLI does.
+func containerUsage(stats container.StatsResponse) ContainerUsage {
+	usage := ContainerUsage{
+		MemoryBytes:      stats.MemoryStats.Usage,
+		MemoryLimitBytes: stats.MemoryStats.Limit,
+	}
+
+	// cgroup v2 reports inactive_file, v1 total_inactive_file.
+	for _, key := range []string{"inactive_file", "total_inactive_file"} {
+		if cache, ok := stats.MemoryStats.Stats[key]; ok && cache < usage.MemoryBytes {
+			usage.MemoryBytes -= cache
+
+			break
+		}
+	}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+
+	cpus := float64(stats.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta > 0 && systemDelta > 0 {
+		usage.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	return usage
+}
+
+// ComponentUsage sums container usage per infrastructure component, keyed by
+// the names Status reports (ClickHouse CBT, ClickHouse Xatu, Redis).
+func (m *Manager) ComponentUsage(usage map[string]ContainerUsage) map[string]ContainerUsage {
+	project := m.xatuCBTProjectName()
+	components := make(map[string]ContainerUsage, 3)
+
+	for name, u := range usage {
+		short := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(name, project), "-"))
+
+		var component string
+
+		switch {
+		case strings.Contains(short, "redis"):
+			component = componentRedis
+		case strings.Contains(short, "clickhouse") && strings.Contains(short, "xatu"):
+			component = componentClickHouseXatu
+		case strings.Contains(short, "clickhouse"):
+			component = componentClickHouseCBT
+		default:
+			continue
+		}
+
+		total := components[component]
+		total.CPUPercent += u.CPUPercent
+		total.MemoryBytes += u.MemoryBytes
+		total.MemoryLimitBytes = max(total.MemoryLimitBytes, u.MemoryLimitBytes)
+		components[component] = total
+	}
+
+	return components
+}
@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerUsageMatchesDockerStats(t *testing.T) {
+	var stats container.StatsResponse
+
+	stats.MemoryStats.Usage = 300 << 20
+	stats.MemoryStats.Limit = 1 << 30
+	stats.MemoryStats.Stats = map[string]uint64{"inactive_file": 100 << 20}
+	stats.CPUStats.OnlineCPUs = 4
+	stats.CPUStats.CPUUsage.TotalUsage = 2_000
+	stats.CPUStats.SystemUsage = 20_000
+	stats.PreCPUStats.CPUUsage.TotalUsage = 1_000
+	stats.PreCPUStats.SystemUsage = 10_000
+
+	usage := containerUsage(stats)
+
+	require.Equal(t, uint64(200<<20), usage.MemoryBytes)
+	require.Equal(t, uint64(1<<30), usage.MemoryLimitBytes)
+	require.InDelta(t, 40.0, usage.CPUPercent, 0.001)
+}
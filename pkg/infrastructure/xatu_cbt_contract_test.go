@@ -154,6 +154,21 @@ func TestSafeStopDestroyAndRedisResetUseSeparateCommands(t *testing.T) {
 		strconv.Itoa(ports.Redis),
 		"FLUSHALL",
 	}, captured[0].Args)
+
+	captured = nil
+
+	require.NoError(t, manager.ResetNetworkRedis(context.Background(), "mainnet"))
+	require.Len(t, captured, 1)
+	require.Equal(t, []string{
+		"redis-cli",
+		"-h",
+		"127.0.0.1",
+		"-p",
+		strconv.Itoa(ports.Redis),
+		"-n",
+		"0",
+		"FLUSHDB",
+	}, captured[0].Args)
 }
 
 func TestBoundsSeederUsesRuntimeRedisPort(t *testing.T) {
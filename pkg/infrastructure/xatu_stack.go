@@ -0,0 +1,235 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/compose"
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// xatuStackClickHouseHTTPPort is the ClickHouse HTTP port inside the
+	// xatu stack's containers.
+	xatuStackClickHouseHTTPPort = 8123
+
+	// xatuStackDatabase is the database the xatu stack's ClickHouse keeps
+	// events in.
+	xatuStackDatabase = "default"
+
+	// Variables the xatu stack's docker-compose.yml reads ClickHouse
+	// credentials from.
+	xatuStackEnvUser     = "CLICKHOUSE_USER"
+	xatuStackEnvPassword = "CLICKHOUSE_PASSWORD"
+
+	// dockerHostAlias is how containers reach ports published on the host.
+	dockerHostAlias = "host.docker.internal"
+)
+
+// XatuStackClickHouse is the ClickHouse of a running xatu stack.
+type XatuStackClickHouse struct {
+	// Service is the docker-compose service name.
+	Service string
+	// Port is the host port its HTTP interface is published on.
+	Port int
+}
+
+// FindXatuStackClickHouse discovers the xatu stack's ClickHouse and the host
+// port its HTTP interface is published on, from 'docker compose ps'.
+func FindXatuStackClickHouse(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	xatuCfg *config.XatuConfig,
+) (*XatuStackClickHouse, error) {
+	if xatuCfg == nil {
+		return nil, fmt.Errorf("xatu stack is not configured - run 'xcli xatu init' first")
+	}
+
+	runner, err := compose.NewRunner(log, xatuCfg.Repos.Xatu, xatuCfg.Profiles, xatuCfg.EnvOverrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create xatu compose runner: %w", err)
+	}
+
+	statuses, err := runner.PS(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list xatu stack services: %w", err)
+	}
+
+	return findXatuStackClickHouse(statuses)
+}
+
+// findXatuStackClickHouse picks the first running ClickHouse server (not a
+// keeper) that publishes its HTTP port.
+func findXatuStackClickHouse(statuses []compose.ServiceStatus) (*XatuStackClickHouse, error) {
+	statuses = slices.Clone(statuses)
+	slices.SortFunc(statuses, func(a, b compose.ServiceStatus) int {
+		return strings.Compare(a.Service, b.Service)
+	})
+
+	var unpublished string
+
+	for _, st := range statuses {
+		name := strings.ToLower(st.Service)
+		if !strings.Contains(name, "clickhouse") || strings.Contains(name, "keeper") {
+			continue
+		}
+
+		if st.State != "running" {
+			continue
+		}
+
+		port, ok := st.PublishedPort(xatuStackClickHouseHTTPPort)
+		if !ok {
+			unpublished = st.Service
+
+			continue
+		}
+
+		return &XatuStackClickHouse{Service: st.Service, Port: port}, nil
+	}
+
+	if unpublished != "" {
+		return nil, fmt.Errorf("xatu stack service %s does not publish port %d", unpublished, xatuStackClickHouseHTTPPort)
+	}
+
+	return nil, fmt.Errorf("no ClickHouse is running in the xatu stack - start it with 'xcli xatu up'")
+}
+
+// ResolveXatuStack points the lab's external Xatu ClickHouse at the running
+// xatu stack and returns its URL. Only the in-memory config changes, so a
+// hybrid externalUrl in .xcli.yaml is kept.
+func (m *Manager) ResolveXatuStack(ctx context.Context) (string, error) {
+	clickhouse, err := FindXatuStackClickHouse(ctx, m.log, m.cfg.XatuStack)
+	if err != nil {
+		return "", err
+	}
+
+	username, password := xatuStackCredentials(m.cfg.XatuStack)
+	xatuURL := "http://localhost:" + strconv.Itoa(clickhouse.Port)
+
+	m.cfg.Infrastructure.ClickHouse.Xatu = config.ClickHouseClusterConfig{
+		Mode:             constants.InfraModeExternal,
+		ExternalURL:      xatuURL,
+		ExternalDatabase: xatuStackDatabase,
+		ExternalUsername: username,
+		ExternalPassword: password,
+	}
+
+	m.log.WithFields(logrus.Fields{
+		"service": clickhouse.Service,
+		"url":     xatuURL,
+	}).Info("using xatu stack ClickHouse as Xatu source")
+
+	return xatuURL, nil
+}
+
+// xatuStackCredentials returns the xatu stack's ClickHouse user and password:
+// xatu.envOverrides first, then the repo's .env file, then ClickHouse's
+// default user.
+func xatuStackCredentials(xatuCfg *config.XatuConfig) (username, password string) {
+	env := readEnvFile(filepath.Join(xatuCfg.Repos.Xatu, ".env"))
+
+	for key, value := range xatuCfg.EnvOverrides {
+		env[key] = value
+	}
+
+	username = env[xatuStackEnvUser]
+	if username == "" {
+		username = "default"
+	}
+
+	return username, env[xatuStackEnvPassword]
+}
+
+// readEnvFile parses KEY=VALUE lines of a docker-compose .env file. A
+// missing file yields an empty map.
+func readEnvFile(path string) map[string]string {
+	env := make(map[string]string)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return env
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+
+	return env
+}
+
+// testXatuStackConnection runs SELECT 1 over the xatu stack ClickHouse's
+// HTTP interface.
+func (m *Manager) testXatuStackConnection(ctx context.Context) error {
+	xatu := m.cfg.Infrastructure.ClickHouse.Xatu
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, xatu.ExternalURL+"/?query=SELECT%201", nil)
+	if err != nil {
+		return fmt.Errorf("failed to build connection test request: %w", err)
+	}
+
+	req.SetBasicAuth(xatu.ExternalUsername, xatu.ExternalPassword)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	result := strings.TrimSpace(string(body))
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("connection test failed: %s: %s", resp.Status, result)
+	}
+
+	if result != "1" {
+		return fmt.Errorf("unexpected response from ClickHouse (expected '1', got '%s')", result)
+	}
+
+	return nil
+}
+
+// containerURL rewrites a localhost URL so it is reachable from inside
+// docker containers, such as xatu-cbt's ClickHouse.
+func containerURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		u.Host = net.JoinHostPort(dockerHostAlias, u.Port())
+	}
+
+	return u.String(), nil
+}
@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/portutil"
 )
 
 const defaultMaxAllocationSlots = 100
@@ -88,9 +90,16 @@ func (a *Allocator) Allocate(ctx context.Context, req AllocationRequest) (PortPl
 		return PortPlan{}, err
 	}
 
+	firstSlot, lastSlot := 0, a.maxSlots-1
+
+	pinnedSlot := req.LabConfig.Instance.Slot
+	if pinnedSlot != nil {
+		firstSlot, lastSlot = *pinnedSlot, *pinnedSlot
+	}
+
 	var rejected []string
 
-	for slot := 0; slot < a.maxSlots; slot++ {
+	for slot := firstSlot; slot <= lastSlot; slot++ {
 		if err := ctx.Err(); err != nil {
 			return PortPlan{}, err
 		}
@@ -100,19 +109,22 @@ func (a *Allocator) Allocate(ctx context.Context, req AllocationRequest) (PortPl
 			return PortPlan{}, planErr
 		}
 
-		if overlap := overlapWithSet(plan.AllPorts(), activePorts); len(overlap) > 0 {
-			rejected = append(rejected, fmt.Sprintf("slot %d registry ports in use: %v", slot, overlap))
-
-			continue
+		conflicts := registryConflicts(plan, activePorts)
+		if len(conflicts) == 0 && a.probePorts {
+			conflicts = boundConflicts(plan)
 		}
 
-		if a.probePorts {
-			boundPorts := boundPorts(plan.AllPorts())
-			if len(boundPorts) > 0 {
-				rejected = append(rejected, fmt.Sprintf("slot %d bound ports in use: %v", slot, boundPorts))
-
-				continue
+		if len(conflicts) > 0 {
+			// Pinned ports and slots are the same for every slot tried, so
+			// a conflict on one of them cannot be resolved by moving on.
+			if pinnedSlot != nil || conflicts.pinned(req.LabConfig.Instance.Ports) {
+				return PortPlan{}, fmt.Errorf("pinned ports for instance %q are unavailable in slot %d: %s",
+					req.InstanceID, slot, conflicts)
 			}
+
+			rejected = append(rejected, fmt.Sprintf("slot %d: %s", slot, conflicts))
+
+			continue
 		}
 
 		if req.Claim {
@@ -132,7 +144,77 @@ func (a *Allocator) Allocate(ctx context.Context, req AllocationRequest) (PortPl
 		return plan, nil
 	}
 
-	return PortPlan{}, fmt.Errorf("no available port slot found after %d slots (%v)", a.maxSlots, rejected)
+	return PortPlan{}, fmt.Errorf("no available port slot found after %d slots (%s)", a.maxSlots, strings.Join(rejected, "; "))
+}
+
+// portConflict is one planned port that is already taken.
+type portConflict struct {
+	Port  int
+	Name  string
+	Owner string
+}
+
+// portConflicts lists why a candidate slot was rejected.
+type portConflicts []portConflict
+
+func (c portConflicts) String() string {
+	parts := make([]string, 0, len(c))
+	for _, conflict := range c {
+		parts = append(parts, fmt.Sprintf("%s %d %s", conflict.Name, conflict.Port, conflict.Owner))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (c portConflicts) pinned(pins map[string]int) bool {
+	for _, conflict := range c {
+		if _, ok := pins[conflict.Name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registryConflicts returns planned ports claimed by other active instances.
+func registryConflicts(plan PortPlan, active map[int]string) portConflicts {
+	names := plan.PortNames()
+
+	var conflicts portConflicts
+
+	for _, port := range plan.AllPorts() {
+		if owner, ok := active[port]; ok {
+			conflicts = append(conflicts, portConflict{
+				Port:  port,
+				Name:  names[port],
+				Owner: fmt.Sprintf("used by instance %s", owner),
+			})
+		}
+	}
+
+	return conflicts
+}
+
+// boundConflicts returns planned ports already bound on the host, with the
+// owning process when it can be identified.
+func boundConflicts(plan PortPlan) portConflicts {
+	names := plan.PortNames()
+
+	var conflicts portConflicts
+
+	for _, port := range boundPorts(plan.AllPorts()) {
+		owner := "bound by another process"
+		if proc := portutil.CheckPort(port); proc != nil {
+			owner = fmt.Sprintf("bound by PID %d", proc.PID)
+			if proc.Process != "" {
+				owner += fmt.Sprintf(" (%s)", proc.Process)
+			}
+		}
+
+		conflicts = append(conflicts, portConflict{Port: port, Name: names[port], Owner: owner})
+	}
+
+	return conflicts
 }
 
 // DefaultRegistryLockPath returns ~/.xcli/lab/registry.lock.
@@ -145,13 +227,15 @@ func DefaultRegistryLockPath() (string, error) {
 	return filepath.Join(homeDir, ".xcli", "lab", "registry.lock"), nil
 }
 
-func (a *Allocator) activeRegistryPorts(instanceID string) (map[int]bool, error) {
+// activeRegistryPorts maps each port held by another active instance to that
+// instance's id.
+func (a *Allocator) activeRegistryPorts(instanceID string) (map[int]string, error) {
 	manifests, err := a.registry.LoadAll()
 	if err != nil {
 		return nil, err
 	}
 
-	ports := make(map[int]bool)
+	ports := make(map[int]string)
 
 	for _, manifest := range manifests {
 		if manifest.InstanceID == instanceID || !isActiveManifest(manifest) {
@@ -159,7 +243,7 @@ func (a *Allocator) activeRegistryPorts(instanceID string) (map[int]bool, error)
 		}
 
 		for _, port := range manifest.Ports.AllPorts() {
-			ports[port] = true
+			ports[port] = manifest.InstanceID
 		}
 	}
 
@@ -251,17 +335,3 @@ func isPortFree(port int) bool {
 
 	return true
 }
-
-func overlapWithSet(ports []int, used map[int]bool) []int {
-	overlap := make([]int, 0)
-
-	for _, port := range ports {
-		if used[port] {
-			overlap = append(overlap, port)
-		}
-	}
-
-	sort.Ints(overlap)
-
-	return overlap
-}
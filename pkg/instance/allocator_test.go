@@ -151,3 +151,56 @@ func listenOnFreePort(t *testing.T, start int, end int) (int, func()) {
 
 	return 0, func() {}
 }
+
+func TestAllocatorHonoursPinnedSlotAndPorts(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), "lab", "instances"))
+	allocator := NewAllocator(registry, false)
+
+	pinnedSlot := 3
+	labCfg := config.DefaultLab()
+	labCfg.Instance.Slot = &pinnedSlot
+	labCfg.Instance.Ports = map[string]int{"grafana": 3999}
+
+	manifest := testManifest(t, "pinned")
+	plan, err := allocator.Allocate(context.Background(), AllocationRequest{
+		InstanceID: manifest.InstanceID,
+		LabConfig:  labCfg,
+		Manifest:   manifest,
+		Claim:      true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, plan.Slot)
+	require.Equal(t, 3999, plan.Grafana)
+
+	// A second instance pinning the same Grafana port fails with the owner
+	// instead of moving on to another slot.
+	other := config.DefaultLab()
+	other.Instance.Ports = map[string]int{"grafana": 3999}
+
+	_, err = allocator.Allocate(context.Background(), AllocationRequest{
+		InstanceID: "other",
+		LabConfig:  other,
+	})
+	require.ErrorContains(t, err, "grafana 3999 used by instance pinned")
+
+	// Without pins the conflicting slot is skipped and explained.
+	allocator.maxSlots = 4
+	unpinned := config.DefaultLab()
+	unpinned.Instance.Slot = nil
+
+	plan, err = allocator.Allocate(context.Background(), AllocationRequest{InstanceID: "free", LabConfig: unpinned})
+	require.NoError(t, err)
+	require.Equal(t, 0, plan.Slot)
+
+	_, err = allocator.Allocate(context.Background(), AllocationRequest{InstanceID: "slot", LabConfig: labCfg})
+	require.ErrorContains(t, err, "pinned ports for instance \"slot\" are unavailable in slot 3")
+	require.ErrorContains(t, err, "used by instance pinned")
+}
+
+func TestBuildPortPlanRejectsUnknownPin(t *testing.T) {
+	labCfg := config.DefaultLab()
+	labCfg.Instance.Ports = map[string]int{"grafna": 3000}
+
+	_, err := BuildPortPlan(labCfg, 0)
+	require.ErrorContains(t, err, `unknown port name "grafna"`)
+}
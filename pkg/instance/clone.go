@@ -0,0 +1,562 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+)
+
+// DockerVolumeCopier copies the contents of one Docker volume into a new one.
+type DockerVolumeCopier interface {
+	CopyVolume(ctx context.Context, source, target string, labels map[string]string) error
+	// PauseVolumeUsers pauses the running containers that mount any of the
+	// volumes and returns a function that unpauses them.
+	PauseVolumeUsers(ctx context.Context, volumes []string) (func(context.Context) error, error)
+}
+
+// FileCopy is one file or directory copied into the clone's workspace.
+type FileCopy struct {
+	Source string
+	Target string
+}
+
+// VolumeCopy is one Docker volume copied for the clone.
+type VolumeCopy struct {
+	Source string
+	Target string
+	Labels map[string]string
+}
+
+// ClonePlan describes how a clone instance is created from a source instance.
+type ClonePlan struct {
+	Source    *Manifest
+	Manifest  *Manifest
+	Workspace *workspace.Workspace
+	LabConfig *config.LabConfig
+	Files     []FileCopy
+	Volumes   []VolumeCopy
+}
+
+// Cloner creates new instances from the state of existing ones.
+type Cloner struct {
+	Registry  *Registry
+	Allocator *Allocator
+	Docker    DockerResourceProvider
+}
+
+// NewCloner creates a cloner for a registry. Volume copies need a Docker
+// provider that also implements DockerVolumeCopier.
+func NewCloner(registry *Registry, docker DockerResourceProvider) *Cloner {
+	return &Cloner{
+		Registry:  registry,
+		Allocator: NewAllocator(registry, true),
+		Docker:    docker,
+	}
+}
+
+// Plan resolves the clone target and lists the files and volumes to copy.
+// to is either a directory (a new worktree) or a new instance id sharing the
+// source's workspace. The ports shown are a preview; Apply claims the slot.
+func (c *Cloner) Plan(ctx context.Context, source *Manifest, to string) (*ClonePlan, error) {
+	if source == nil {
+		return nil, fmt.Errorf("source manifest is required")
+	}
+
+	if source.ConfigPath == "" {
+		return nil, fmt.Errorf("source instance %q has no config path", source.InstanceID)
+	}
+
+	ws, labCfg, cliID, err := resolveCloneTarget(source, to)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := NewManifest(ctx, ws, labCfg, cliID)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.InstanceID == source.InstanceID {
+		return nil, fmt.Errorf("clone target resolves to the source instance id %q; pass a new id", source.InstanceID)
+	}
+
+	if _, statErr := os.Stat(c.Registry.ManifestPath(manifest.InstanceID)); statErr == nil {
+		return nil, fmt.Errorf("instance %q already exists", manifest.InstanceID)
+	}
+
+	ports, err := c.Allocator.Allocate(ctx, AllocationRequest{
+		InstanceID: manifest.InstanceID,
+		LabConfig:  labCfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate ports: %w", err)
+	}
+
+	manifest.Ports = ports
+
+	plan := &ClonePlan{
+		Source:    source,
+		Manifest:  manifest,
+		Workspace: ws,
+		LabConfig: labCfg,
+		Files:     cloneFiles(source, ws),
+	}
+
+	volumes, err := c.cloneVolumes(ctx, source, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Volumes = volumes
+
+	return plan, nil
+}
+
+// Apply claims a port slot for the clone, copies the files and volumes, and
+// registers the clone as stopped. The source's containers are paused while
+// its volumes are copied and are otherwise left untouched.
+func (c *Cloner) Apply(ctx context.Context, plan *ClonePlan) error {
+	if plan == nil {
+		return fmt.Errorf("clone plan is required")
+	}
+
+	manifest := plan.Manifest
+
+	if _, err := c.Allocator.Allocate(ctx, AllocationRequest{
+		InstanceID: manifest.InstanceID,
+		LabConfig:  plan.LabConfig,
+		Manifest:   manifest,
+		Claim:      true,
+	}); err != nil {
+		return fmt.Errorf("failed to allocate ports: %w", err)
+	}
+
+	for _, file := range plan.Files {
+		if err := copyPath(file.Source, file.Target); err != nil {
+			return c.fail(manifest, fmt.Errorf("failed to copy %s: %w", file.Source, err))
+		}
+	}
+
+	if len(plan.Volumes) > 0 {
+		copier, ok := c.Docker.(DockerVolumeCopier)
+		if !ok {
+			return c.fail(manifest, fmt.Errorf("docker provider cannot copy volumes"))
+		}
+
+		if err := copyVolumesPaused(ctx, copier, plan.Volumes); err != nil {
+			return c.fail(manifest, err)
+		}
+	}
+
+	manifest.Status = StatusStopped
+
+	return c.Registry.Save(manifest)
+}
+
+// copyVolumesPaused copies volumes while the containers using them are
+// paused. The copy reads files one at a time, so without the pause a running
+// ClickHouse could merge parts mid-copy, leaving both the merged part and its
+// sources in the clone or failing on a part removed under cp. Paused, every
+// volume is copied as of the same moment.
+func copyVolumesPaused(ctx context.Context, copier DockerVolumeCopier, volumes []VolumeCopy) error {
+	sources := make([]string, 0, len(volumes))
+	for _, vol := range volumes {
+		sources = append(sources, vol.Source)
+	}
+
+	resume, err := copier.PauseVolumeUsers(ctx, sources)
+	if err != nil {
+		return fmt.Errorf("failed to pause the source containers: %w", err)
+	}
+
+	var copyErr error
+
+	for _, vol := range volumes {
+		if copyErr = copier.CopyVolume(ctx, vol.Source, vol.Target, vol.Labels); copyErr != nil {
+			break
+		}
+	}
+
+	if err := resume(context.WithoutCancel(ctx)); err != nil {
+		return errors.Join(copyErr, fmt.Errorf("failed to unpause the source containers (run 'docker unpause' on them): %w", err))
+	}
+
+	return copyErr
+}
+
+// fail records err on the clone manifest so lab list shows the partial clone.
+func (c *Cloner) fail(manifest *Manifest, err error) error {
+	manifest.Status = StatusStopped
+	manifest.LastError = err.Error()
+
+	if saveErr := c.Registry.Save(manifest); saveErr != nil {
+		return fmt.Errorf("%w (also failed to save manifest: %v)", err, saveErr)
+	}
+
+	return err
+}
+
+// cloneVolumes maps the source's existing planned volumes and its compose
+// project volumes (ClickHouse, Redis) to the clone's names. Without a Docker
+// provider no volumes are copied.
+func (c *Cloner) cloneVolumes(ctx context.Context, source, target *Manifest) ([]VolumeCopy, error) {
+	if c.Docker == nil {
+		return nil, nil
+	}
+
+	sourcePlan := source.EffectiveDockerPlan()
+	targetPlan := target.EffectiveDockerPlan()
+
+	volumes := make([]VolumeCopy, 0, len(sourcePlan.Volumes))
+	planned := make(map[string]bool, len(sourcePlan.Volumes))
+
+	for _, service := range sortedKeys(sourcePlan.Volumes) {
+		name := sourcePlan.Volumes[service]
+		planned[name] = true
+
+		if targetName := targetPlan.Volumes[service]; name != "" && targetName != "" {
+			volumes = append(volumes, VolumeCopy{Source: name, Target: targetName, Labels: targetPlan.Labels})
+		}
+	}
+
+	resources, err := c.Docker.ListXCLIResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(resources))
+
+	for _, resource := range resources {
+		if resource.Kind != DockerResourceKindVolume {
+			continue
+		}
+
+		existing[resource.Name] = true
+
+		if planned[resource.Name] || resource.Labels[DockerComposeProjectLabel] != sourcePlan.ProjectName {
+			continue
+		}
+
+		volumes = append(volumes, VolumeCopy{
+			Source: resource.Name,
+			Target: targetPlan.ProjectName + strings.TrimPrefix(resource.Name, sourcePlan.ProjectName),
+			Labels: cloneVolumeLabels(resource.Labels, targetPlan),
+		})
+	}
+
+	// Planned volumes are only created once the observability stack has run.
+	copies := volumes[:0]
+	for _, vol := range volumes {
+		if existing[vol.Source] {
+			copies = append(copies, vol)
+		}
+	}
+
+	sort.Slice(copies, func(i, j int) bool { return copies[i].Source < copies[j].Source })
+
+	return copies, nil
+}
+
+// cloneVolumeLabels rewrites the instance and compose project labels of a
+// source volume for the clone so compose adopts the copied volume.
+func cloneVolumeLabels(labels map[string]string, targetPlan DockerPlan) map[string]string {
+	cloned := make(map[string]string, len(labels)+len(targetPlan.Labels))
+	for key, value := range labels {
+		cloned[key] = value
+	}
+
+	for key, value := range targetPlan.Labels {
+		cloned[key] = value
+	}
+
+	cloned[DockerComposeProjectLabel] = targetPlan.ProjectName
+
+	return cloned
+}
+
+// resolveCloneTarget returns the clone's workspace and lab config, plus the
+// CLI instance id override when to names an id rather than a directory.
+func resolveCloneTarget(source *Manifest, to string) (*workspace.Workspace, *config.LabConfig, string, error) {
+	to = strings.TrimSpace(to)
+	if to == "" {
+		return nil, nil, "", fmt.Errorf("clone target is required")
+	}
+
+	if !isCloneDir(to) {
+		id, err := SanitizeID(to)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		labCfg, ws, err := workspace.LoadLabConfig(source.ConfigPath, false)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		return ws, labCfg, id, nil
+	}
+
+	ws, err := workspace.Resolve(filepath.Join(to, config.DefaultConfigFileName), false, false)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if info, statErr := os.Stat(ws.RootDir); statErr != nil || !info.IsDir() {
+		return nil, nil, "", fmt.Errorf("clone target directory %s does not exist", ws.RootDir)
+	}
+
+	// A worktree without its own config gets a copy of the source config.
+	configPath := ws.ConfigPath
+	if !ws.ConfigExists {
+		configPath = source.ConfigPath
+	}
+
+	labCfg, _, err := workspace.LoadLabConfig(configPath, false)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	workspace.ResolveLabRepoPaths(labCfg, ws.RootDir)
+
+	return ws, labCfg, "", nil
+}
+
+// isCloneDir reports whether a clone target names a directory.
+func isCloneDir(to string) bool {
+	if strings.ContainsRune(to, filepath.Separator) || strings.HasPrefix(to, ".") {
+		return true
+	}
+
+	info, err := os.Stat(to)
+
+	return err == nil && info.IsDir()
+}
+
+// cloneFiles lists the config, overrides and custom config directories to
+// copy into a different workspace. Existing target files are kept.
+func cloneFiles(source *Manifest, ws *workspace.Workspace) []FileCopy {
+	sourceRoot := filepath.Dir(source.ConfigPath)
+	if sameConfigPath(sourceRoot, ws.RootDir) {
+		return nil
+	}
+
+	sourceOverrides := source.OverridesPath
+	if sourceOverrides == "" {
+		sourceOverrides = filepath.Join(sourceRoot, constants.CBTOverridesFile)
+	}
+
+	sourceState := filepath.Join(sourceRoot, ".xcli")
+
+	candidates := []FileCopy{
+		{Source: source.ConfigPath, Target: ws.ConfigPath},
+		{Source: sourceOverrides, Target: ws.OverridesPath},
+		{
+			Source: filepath.Join(sourceState, constants.DirCustomConfigs),
+			Target: filepath.Join(ws.StateDir, constants.DirCustomConfigs),
+		},
+		{
+			Source: filepath.Join(sourceState, constants.DirCustomDashboards),
+			Target: filepath.Join(ws.StateDir, constants.DirCustomDashboards),
+		},
+	}
+
+	files := make([]FileCopy, 0, len(candidates))
+
+	for _, file := range candidates {
+		if _, err := os.Stat(file.Source); err != nil {
+			continue
+		}
+
+		if _, err := os.Stat(file.Target); err == nil {
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return files
+}
+
+// copyPath copies a file, or a directory tree, from src to dst.
+func copyPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, entry os.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// CopyVolume creates target with labels and copies source into it using a
+// short-lived helper container. Source is mounted read-only, but cp reads it
+// file by file: containers writing to it must be paused (PauseVolumeUsers)
+// for the copy to reflect a single moment.
+func (p *dockerResourceProvider) CopyVolume(
+	ctx context.Context,
+	source, target string,
+	labels map[string]string,
+) error {
+	if _, err := p.client.VolumeInspect(ctx, target); err == nil {
+		return fmt.Errorf("volume %s already exists", target)
+	}
+
+	if _, err := p.client.VolumeCreate(ctx, volume.CreateOptions{Name: target, Labels: labels}); err != nil {
+		return fmt.Errorf("failed to create volume %s: %w", target, err)
+	}
+
+	if _, err := p.client.ImageInspect(ctx, constants.VolumeCopyImage); err != nil {
+		reader, pullErr := p.client.ImagePull(ctx, constants.VolumeCopyImage, image.PullOptions{})
+		if pullErr != nil {
+			return fmt.Errorf("failed to pull image %s: %w", constants.VolumeCopyImage, pullErr)
+		}
+
+		_, _ = io.Copy(io.Discard, reader)
+		_ = reader.Close()
+	}
+
+	created, err := p.client.ContainerCreate(ctx, &container.Config{
+		Image: constants.VolumeCopyImage,
+		Cmd:   []string{"sh", "-c", "cp -a /from/. /to/"},
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{
+			{Type: mount.TypeVolume, Source: source, Target: "/from", ReadOnly: true},
+			{Type: mount.TypeVolume, Source: target, Target: "/to"},
+		},
+	}, nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to create copy container for %s: %w", source, err)
+	}
+
+	defer func() {
+		_ = p.client.ContainerRemove(context.WithoutCancel(ctx), created.ID, container.RemoveOptions{Force: true})
+	}()
+
+	if err := p.client.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start copy container for %s: %w", source, err)
+	}
+
+	statusCh, errCh := p.client.ContainerWait(ctx, created.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to copy volume %s: %w", source, err)
+	case status := <-statusCh:
+		if status.StatusCode != 0 {
+			return fmt.Errorf("copying volume %s to %s exited with code %d", source, target, status.StatusCode)
+		}
+	}
+
+	return nil
+}
+
+// PauseVolumeUsers pauses the running containers that mount any of volumes.
+// The returned function unpauses exactly the containers it paused.
+func (p *dockerResourceProvider) PauseVolumeUsers(ctx context.Context, volumes []string) (func(context.Context) error, error) {
+	var paused []container.Summary
+
+	resume := func(ctx context.Context) error {
+		var errs []error
+
+		for _, item := range paused {
+			if err := p.client.ContainerUnpause(ctx, item.ID); err != nil {
+				errs = append(errs, fmt.Errorf("failed to unpause container %s: %w", containerName(item), err))
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+
+	seen := make(map[string]bool)
+
+	for _, name := range volumes {
+		containers, err := p.client.ContainerList(ctx, container.ListOptions{
+			Filters: filters.NewArgs(filters.Arg("volume", name), filters.Arg("status", "running")),
+		})
+		if err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("failed to list containers using volume %s: %w", name, err),
+				resume(context.WithoutCancel(ctx)),
+			)
+		}
+
+		for _, item := range containers {
+			if seen[item.ID] {
+				continue
+			}
+
+			seen[item.ID] = true
+
+			if err := p.client.ContainerPause(ctx, item.ID); err != nil {
+				return nil, errors.Join(
+					fmt.Errorf("failed to pause container %s: %w", containerName(item), err),
+					resume(context.WithoutCancel(ctx)),
+				)
+			}
+
+			paused = append(paused, item)
+		}
+	}
+
+	return resume, nil
+}
+
+// containerName returns a container's name without the leading slash, or its
+// id when it has none.
+func containerName(item container.Summary) string {
+	if len(item.Names) > 0 {
+		return strings.TrimPrefix(item.Names[0], "/")
+	}
+
+	return item.ID
+}
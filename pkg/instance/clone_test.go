@@ -0,0 +1,152 @@
+package instance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClonerCopiesVolumesAndFilesIntoNewWorktree(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), "lab", "instances"))
+	allocator := NewAllocator(registry, false)
+
+	sourceConfig := writeRuntimeConfig(t, "main")
+	sourceRoot := filepath.Dir(sourceConfig)
+	require.NoError(t, os.WriteFile(filepath.Join(sourceRoot, constants.CBTOverridesFile), []byte("models: {}\n"), 0600))
+
+	customDir := filepath.Join(sourceRoot, ".xcli", constants.DirCustomConfigs)
+	require.NoError(t, os.MkdirAll(customDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(customDir, "cbt-mainnet.yaml"), []byte("x: 1\n"), 0600))
+
+	labCfg, ws, err := workspace.LoadLabConfig(sourceConfig, false)
+	require.NoError(t, err)
+
+	source, err := NewRuntimeFromWorkspace(context.Background(), ws, labCfg, "main", RuntimeOptions{
+		Registry:   registry,
+		Allocator:  allocator,
+		ClaimPorts: true,
+	})
+	require.NoError(t, err)
+
+	sourcePlan := source.Manifest.EffectiveDockerPlan()
+	clickhouse := DockerResource{
+		Kind:   DockerResourceKindVolume,
+		Name:   sourcePlan.ProjectName + "_clickhouse-cbt-01-data",
+		Labels: map[string]string{DockerComposeProjectLabel: sourcePlan.ProjectName},
+	}
+	prometheus := DockerResource{
+		Kind:   DockerResourceKindVolume,
+		Name:   sourcePlan.Volumes[constants.ServicePrometheus],
+		Labels: sourcePlan.Labels,
+	}
+	docker := &fakeVolumeCopier{fakeDockerResources: fakeDockerResources{resources: []DockerResource{clickhouse, prometheus}}}
+
+	worktree := filepath.Join(t.TempDir(), "feature-x")
+	require.NoError(t, os.MkdirAll(worktree, 0755))
+
+	cloner := &Cloner{Registry: registry, Allocator: allocator, Docker: docker}
+
+	plan, err := cloner.Plan(context.Background(), source.Manifest, worktree)
+	require.NoError(t, err)
+	require.NotEqual(t, source.InstanceID, plan.Manifest.InstanceID)
+	require.Equal(t, worktree, plan.Manifest.RootDir)
+	require.Len(t, plan.Files, 3)
+	require.Len(t, plan.Volumes, 2)
+
+	require.NoError(t, cloner.Apply(context.Background(), plan))
+
+	clone, err := registry.Load(plan.Manifest.InstanceID)
+	require.NoError(t, err)
+	require.Equal(t, StatusStopped, clone.Status)
+	require.Equal(t, 1, clone.Ports.Slot)
+	require.Empty(t, clone.Ports.Overlaps(source.Ports))
+
+	clonePlan := clone.EffectiveDockerPlan()
+	require.Equal(t, map[string]string{
+		clickhouse.Name: clonePlan.ProjectName + "_clickhouse-cbt-01-data",
+		prometheus.Name: clonePlan.Volumes[constants.ServicePrometheus],
+	}, docker.copied)
+	require.Empty(t, docker.copiedUnpaused, "volumes must be copied while the source containers are paused")
+	require.Empty(t, docker.paused, "the source containers must be unpaused after the copy")
+	require.Equal(t, clonePlan.ProjectName, docker.labels[clickhouse.Name][DockerComposeProjectLabel])
+	require.Equal(t, clone.InstanceID, docker.labels[clickhouse.Name][DockerLabelInstance])
+
+	require.FileExists(t, filepath.Join(worktree, ".xcli.yaml"))
+	require.FileExists(t, filepath.Join(worktree, constants.CBTOverridesFile))
+	require.FileExists(t, filepath.Join(worktree, ".xcli", constants.DirCustomConfigs, "cbt-mainnet.yaml"))
+
+	// The source manifest is untouched.
+	reloaded, err := registry.Load(source.InstanceID)
+	require.NoError(t, err)
+	require.Equal(t, source.Ports, reloaded.Ports)
+}
+
+func TestClonerNewIDSharesSourceWorkspace(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), "lab", "instances"))
+	allocator := NewAllocator(registry, false)
+
+	sourceConfig := writeRuntimeConfig(t, "main")
+	labCfg, ws, err := workspace.LoadLabConfig(sourceConfig, false)
+	require.NoError(t, err)
+
+	source, err := NewRuntimeFromWorkspace(context.Background(), ws, labCfg, "main", RuntimeOptions{
+		Registry:   registry,
+		Allocator:  allocator,
+		ClaimPorts: true,
+	})
+	require.NoError(t, err)
+
+	cloner := &Cloner{Registry: registry, Allocator: allocator}
+
+	plan, err := cloner.Plan(context.Background(), source.Manifest, "Scratch")
+	require.NoError(t, err)
+	require.Equal(t, "scratch", plan.Manifest.InstanceID)
+	require.Equal(t, source.Manifest.ConfigPath, plan.Manifest.ConfigPath)
+	require.Empty(t, plan.Files)
+	require.Empty(t, plan.Volumes)
+
+	_, err = cloner.Plan(context.Background(), source.Manifest, "main")
+	require.ErrorContains(t, err, "source instance id")
+}
+
+type fakeVolumeCopier struct {
+	fakeDockerResources
+
+	copied map[string]string
+	labels map[string]map[string]string
+	paused []string
+	// copiedUnpaused lists volumes copied while their users were not paused.
+	copiedUnpaused []string
+}
+
+func (f *fakeVolumeCopier) PauseVolumeUsers(_ context.Context, volumes []string) (func(context.Context) error, error) {
+	f.paused = volumes
+
+	return func(context.Context) error {
+		f.paused = nil
+
+		return nil
+	}, nil
+}
+
+func (f *fakeVolumeCopier) CopyVolume(_ context.Context, source, target string, labels map[string]string) error {
+	if !slices.Contains(f.paused, source) {
+		f.copiedUnpaused = append(f.copiedUnpaused, source)
+	}
+
+	if f.copied == nil {
+		f.copied = map[string]string{}
+		f.labels = map[string]map[string]string{}
+	}
+
+	f.copied[source] = target
+	f.labels[source] = labels
+
+	return nil
+}
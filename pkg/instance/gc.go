@@ -0,0 +1,361 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GC action kinds.
+const (
+	GCActionRemoveManifest     = "remove-manifest"
+	GCActionRemoveContainer    = "remove-container"
+	GCActionRemoveVolume       = "remove-volume"
+	GCActionReleaseReservation = "release-reservation"
+	GCActionAdopt              = "adopt"
+)
+
+// DockerResourceKindVolume is the DockerResource.Kind value for volumes.
+const DockerResourceKindVolume = "volume"
+
+// DockerResourceRemover stops and removes labeled Docker resources.
+type DockerResourceRemover interface {
+	RemoveXCLIResource(ctx context.Context, resource DockerResource) error
+}
+
+// DockerResourceSizer reports the disk usage of labeled Docker resources,
+// keyed by DockerResourceKey.
+type DockerResourceSizer interface {
+	XCLIResourceSizes(ctx context.Context) (map[string]int64, error)
+}
+
+// GCOptions filters what garbage collection may touch.
+type GCOptions struct {
+	// OlderThan only collects manifests last updated, and Docker resources
+	// created, at least this long ago. Zero disables the age filter.
+	OlderThan time.Duration
+	// MinSize only removes orphan Docker resources using at least this many
+	// bytes. Zero disables the size filter.
+	MinSize int64
+	// Adopt lists orphan instance ids to register from their Docker labels
+	// instead of removing their resources.
+	Adopt []string
+}
+
+// GCAction is one planned garbage collection step.
+type GCAction struct {
+	Kind       string
+	InstanceID string
+	Reason     string
+	Manifest   *Manifest
+	Resource   *DockerResource
+}
+
+// Target returns what the action operates on, for display.
+func (a GCAction) Target() string {
+	if a.Resource != nil {
+		return a.Resource.Name
+	}
+
+	if a.Manifest != nil && a.Manifest.RootDir != "" {
+		return a.Manifest.RootDir
+	}
+
+	return a.InstanceID
+}
+
+// GCPlan is the ordered list of actions garbage collection would take.
+// Skipped explains resources that matched a category but were filtered out.
+type GCPlan struct {
+	Actions []GCAction
+	Skipped []string
+}
+
+// GarbageCollector plans and applies cleanup of stale lab instances and
+// orphan Docker resources found by the reconciler.
+type GarbageCollector struct {
+	Registry *Registry
+	Docker   DockerResourceProvider
+	Now      func() time.Time
+}
+
+// NewGarbageCollector creates a collector. Docker removal and sizing need a
+// provider that also implements DockerResourceRemover and DockerResourceSizer.
+func NewGarbageCollector(registry *Registry, docker DockerResourceProvider) *GarbageCollector {
+	return &GarbageCollector{Registry: registry, Docker: docker}
+}
+
+// Plan builds the garbage collection plan for a reconcile result without
+// changing anything.
+func (g *GarbageCollector) Plan(ctx context.Context, result *ReconcileResult, opts GCOptions) (*GCPlan, error) {
+	if result == nil {
+		return nil, fmt.Errorf("reconcile result is required")
+	}
+
+	adopt := make(map[string]bool, len(opts.Adopt))
+	for _, id := range opts.Adopt {
+		adopt[id] = true
+	}
+
+	byID := make(map[string]*ReconciledInstance, len(result.Instances))
+	for _, item := range result.Instances {
+		byID[item.InstanceID] = item
+	}
+
+	for id := range adopt {
+		item, ok := byID[id]
+		if !ok || !item.Orphan {
+			return nil, fmt.Errorf("instance %q is not an orphan and cannot be adopted", id)
+		}
+	}
+
+	sizes, err := g.resourceSizes(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &GCPlan{}
+	now := g.now()
+
+	for _, item := range result.Instances {
+		switch {
+		case item.Orphan && adopt[item.InstanceID]:
+			manifest, err := AdoptedManifest(item)
+			if err != nil {
+				return nil, err
+			}
+
+			plan.Actions = append(plan.Actions, GCAction{
+				Kind:       GCActionAdopt,
+				InstanceID: item.InstanceID,
+				Reason:     "reconstructed from Docker labels",
+				Manifest:   manifest,
+			})
+
+		case item.Orphan:
+			g.planResourceRemoval(plan, item, "orphan (no registry manifest)", opts, sizes, now)
+
+		case item.RegistryPresent && rootDirMissing(item.Manifest) && !liveProcesses(item):
+			if !olderThan(item.Manifest.UpdatedAt, opts.OlderThan, now) {
+				plan.Skipped = append(plan.Skipped, item.InstanceID+": manifest newer than --older-than")
+
+				continue
+			}
+
+			plan.Actions = append(plan.Actions, GCAction{
+				Kind:       GCActionRemoveManifest,
+				InstanceID: item.InstanceID,
+				Reason:     "root dir no longer exists",
+				Manifest:   item.Manifest,
+			})
+
+			g.planResourceRemoval(plan, item, "belongs to removed manifest", opts, sizes, now)
+
+		case item.RegistryPresent && item.Manifest.Status == StatusReserved && !item.Live.BackingExists:
+			if !olderThan(item.Manifest.UpdatedAt, max(opts.OlderThan, reservationGracePeriod), now) {
+				plan.Skipped = append(plan.Skipped, item.InstanceID+": reservation still within grace period")
+
+				continue
+			}
+
+			plan.Actions = append(plan.Actions, GCAction{
+				Kind:       GCActionReleaseReservation,
+				InstanceID: item.InstanceID,
+				Reason:     "reserved without running processes, ports or containers",
+				Manifest:   item.Manifest,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply executes a plan. It keeps going after failures and returns them joined.
+func (g *GarbageCollector) Apply(ctx context.Context, plan *GCPlan) error {
+	if plan == nil {
+		return nil
+	}
+
+	var errs []error
+
+	for _, action := range plan.Actions {
+		if err := g.apply(ctx, action); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", action.Kind, action.Target(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (g *GarbageCollector) apply(ctx context.Context, action GCAction) error {
+	switch action.Kind {
+	case GCActionRemoveManifest:
+		return g.Registry.Delete(action.Manifest)
+
+	case GCActionReleaseReservation:
+		manifest := *action.Manifest
+		manifest.Status = StatusStopped
+		manifest.PIDs = map[string]int{}
+		manifest.LastError = "reservation released by lab gc"
+
+		return g.Registry.Save(&manifest)
+
+	case GCActionAdopt:
+		return g.Registry.Save(action.Manifest)
+
+	case GCActionRemoveContainer, GCActionRemoveVolume:
+		remover, ok := g.Docker.(DockerResourceRemover)
+		if !ok {
+			return fmt.Errorf("docker provider cannot remove resources")
+		}
+
+		return remover.RemoveXCLIResource(ctx, *action.Resource)
+
+	default:
+		return fmt.Errorf("unknown gc action %q", action.Kind)
+	}
+}
+
+// planResourceRemoval adds removal actions for an instance's Docker
+// resources that pass the filters. Containers are removed before volumes so
+// volumes are no longer in use when they are removed.
+func (g *GarbageCollector) planResourceRemoval(
+	plan *GCPlan,
+	item *ReconciledInstance,
+	reason string,
+	opts GCOptions,
+	sizes map[string]int64,
+	now time.Time,
+) {
+	resources := append([]DockerResource(nil), item.Live.DockerResources...)
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].Kind == DockerResourceKindContainer && resources[j].Kind != DockerResourceKindContainer
+	})
+
+	for i := range resources {
+		resource := resources[i]
+
+		if opts.OlderThan > 0 && !olderThan(resource.CreatedAt, opts.OlderThan, now) {
+			plan.Skipped = append(plan.Skipped, resource.Name+": newer than --older-than")
+
+			continue
+		}
+
+		if opts.MinSize > 0 {
+			size, ok := sizes[DockerResourceKey(resource)]
+			if !ok || size < opts.MinSize {
+				plan.Skipped = append(plan.Skipped, resource.Name+": smaller than --min-size")
+
+				continue
+			}
+
+			resource.Size = size
+		}
+
+		kind := GCActionRemoveVolume
+		if resource.Kind == DockerResourceKindContainer {
+			kind = GCActionRemoveContainer
+		}
+
+		plan.Actions = append(plan.Actions, GCAction{
+			Kind:       kind,
+			InstanceID: item.InstanceID,
+			Reason:     reason,
+			Resource:   &resource,
+		})
+	}
+}
+
+func (g *GarbageCollector) resourceSizes(ctx context.Context, opts GCOptions) (map[string]int64, error) {
+	if opts.MinSize <= 0 {
+		return map[string]int64{}, nil
+	}
+
+	sizer, ok := g.Docker.(DockerResourceSizer)
+	if !ok {
+		return nil, fmt.Errorf("docker provider cannot report resource sizes for --min-size")
+	}
+
+	return sizer.XCLIResourceSizes(ctx)
+}
+
+func (g *GarbageCollector) now() time.Time {
+	if g.Now != nil {
+		return g.Now()
+	}
+
+	return time.Now()
+}
+
+// AdoptedManifest reconstructs a registry manifest for an orphan from the
+// config path recorded in its Docker labels. Ports are left empty and are
+// re-allocated on the next start.
+func AdoptedManifest(item *ReconciledInstance) (*Manifest, error) {
+	configPath := ""
+	if item.Manifest != nil {
+		configPath = item.Manifest.ConfigPath
+	}
+
+	if configPath == "" {
+		return nil, fmt.Errorf("orphan %q has no %s label to adopt from", item.InstanceID, DockerLabelConfig)
+	}
+
+	rootDir := filepath.Dir(configPath)
+	if _, err := os.Stat(rootDir); err != nil {
+		return nil, fmt.Errorf("cannot adopt %q: root dir %s: %w", item.InstanceID, rootDir, err)
+	}
+
+	return &Manifest{
+		SchemaVersion: SchemaVersion,
+		InstanceID:    item.InstanceID,
+		Status:        StatusStopped,
+		RootDir:       rootDir,
+		ConfigPath:    configPath,
+		StateDir:      InstanceStateDir(rootDir, item.InstanceID),
+		Docker:        NewDockerPlan(item.InstanceID, configPath),
+		PIDs:          map[string]int{},
+		URLs:          map[string]string{},
+	}, nil
+}
+
+// DockerResourceKey identifies a Docker resource across list and size calls.
+func DockerResourceKey(resource DockerResource) string {
+	if resource.Kind == DockerResourceKindContainer {
+		return resource.Kind + ":" + resource.ID
+	}
+
+	return resource.Kind + ":" + resource.Name
+}
+
+func rootDirMissing(manifest *Manifest) bool {
+	if manifest == nil || strings.TrimSpace(manifest.RootDir) == "" {
+		return false
+	}
+
+	_, err := os.Stat(manifest.RootDir)
+
+	return os.IsNotExist(err)
+}
+
+func liveProcesses(item *ReconciledInstance) bool {
+	return anyTrue(item.Live.PIDs) || anyTrue(item.Live.Ports)
+}
+
+// olderThan reports whether t is at least age before now. A zero age always
+// passes; an unknown (zero) time only passes a zero age.
+func olderThan(t time.Time, age time.Duration, now time.Time) bool {
+	if age <= 0 {
+		return true
+	}
+
+	if t.IsZero() {
+		return false
+	}
+
+	return now.Sub(t) >= age
+}
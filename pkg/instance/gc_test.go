@@ -0,0 +1,149 @@
+package instance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGarbageCollectorPlansAndApplies(t *testing.T) {
+	now := time.Now()
+	registry := NewRegistry(filepath.Join(t.TempDir(), "instances"))
+
+	// The local manifest copy is written under the root, so remove the root
+	// after saving to simulate a deleted working copy.
+	goneRoot := filepath.Join(t.TempDir(), "gone")
+	gone := &Manifest{
+		SchemaVersion: SchemaVersion,
+		InstanceID:    "gone",
+		Status:        StatusStopped,
+		RootDir:       goneRoot,
+		ConfigPath:    filepath.Join(goneRoot, ".xcli.yaml"),
+		PIDs:          map[string]int{},
+	}
+	require.NoError(t, registry.Save(gone))
+	require.NoError(t, os.RemoveAll(goneRoot))
+
+	stuckRoot := t.TempDir()
+	stuck := &Manifest{
+		SchemaVersion: SchemaVersion,
+		InstanceID:    "stuck",
+		Status:        StatusReserved,
+		RootDir:       stuckRoot,
+		ConfigPath:    filepath.Join(stuckRoot, ".xcli.yaml"),
+		PIDs:          map[string]int{"lab-backend": 999999},
+		Ports:         PortPlan{LabBackend: 18080},
+	}
+	require.NoError(t, registry.Save(stuck))
+
+	adoptRoot := t.TempDir()
+	adoptResource := orphanDockerResource("adoptme")
+	adoptResource.Labels[DockerLabelConfig] = filepath.Join(adoptRoot, ".xcli.yaml")
+
+	oldVolume := DockerResource{
+		Kind:      DockerResourceKindVolume,
+		Name:      "xcli-orphan-prometheus-data",
+		State:     "present",
+		Labels:    map[string]string{DockerLabelInstance: "orphan"},
+		CreatedAt: now.Add(-48 * time.Hour),
+	}
+	newContainer := orphanDockerResource("orphan")
+	newContainer.CreatedAt = now.Add(-time.Hour)
+
+	docker := &fakeDockerGC{
+		fakeDockerResources: fakeDockerResources{resources: []DockerResource{oldVolume, newContainer, adoptResource}},
+		sizes:               map[string]int64{DockerResourceKey(oldVolume): 2 << 30},
+	}
+
+	result, err := (&Reconciler{
+		Registry:  registry,
+		Docker:    docker,
+		PIDAlive:  func(int) bool { return false },
+		PortBound: func(int) bool { return false },
+	}).ReconcileAll(context.Background())
+	require.NoError(t, err)
+
+	// Make the reservation older than the grace period and --older-than.
+	for _, item := range result.Instances {
+		if item.InstanceID == "stuck" {
+			item.Manifest.UpdatedAt = now.Add(-48 * time.Hour)
+		}
+	}
+
+	gc := &GarbageCollector{Registry: registry, Docker: docker, Now: func() time.Time { return now }}
+
+	plan, err := gc.Plan(context.Background(), result, GCOptions{
+		OlderThan: 24 * time.Hour,
+		MinSize:   1 << 30,
+		Adopt:     []string{"adoptme"},
+	})
+	require.NoError(t, err)
+
+	kinds := map[string]string{}
+	for _, action := range plan.Actions {
+		kinds[action.InstanceID+"/"+action.Target()] = action.Kind
+	}
+
+	require.Equal(t, GCActionAdopt, kinds["adoptme/"+adoptRoot])
+	require.Equal(t, GCActionRemoveVolume, kinds["orphan/"+oldVolume.Name])
+	require.Equal(t, GCActionReleaseReservation, kinds["stuck/"+stuckRoot])
+	require.Len(t, plan.Actions, 3)
+	// The gone manifest was just updated, and the orphan container is too new.
+	require.Len(t, plan.Skipped, 2)
+
+	require.NoError(t, gc.Apply(context.Background(), plan))
+	require.Equal(t, []string{oldVolume.Name}, docker.removed)
+
+	adopted, err := registry.Load("adoptme")
+	require.NoError(t, err)
+	require.Equal(t, StatusStopped, adopted.Status)
+	require.Equal(t, InstanceStateDir(adoptRoot, "adoptme"), adopted.StateDir)
+
+	released, err := registry.Load("stuck")
+	require.NoError(t, err)
+	require.Equal(t, StatusStopped, released.Status)
+	require.Empty(t, released.PIDs)
+
+	// Without the age filter the gone manifest is collected.
+	plan, err = gc.Plan(context.Background(), &ReconcileResult{Instances: []*ReconciledInstance{
+		{Manifest: gone, InstanceID: "gone", Status: StatusStopped, RegistryPresent: true},
+	}}, GCOptions{})
+	require.NoError(t, err)
+	require.Len(t, plan.Actions, 1)
+	require.Equal(t, GCActionRemoveManifest, plan.Actions[0].Kind)
+
+	require.NoError(t, gc.Apply(context.Background(), plan))
+
+	_, err = registry.Load("gone")
+	require.Error(t, err)
+}
+
+func TestGarbageCollectorRejectsAdoptingRegisteredInstance(t *testing.T) {
+	gc := &GarbageCollector{}
+
+	_, err := gc.Plan(context.Background(), &ReconcileResult{Instances: []*ReconciledInstance{
+		{Manifest: &Manifest{InstanceID: "main"}, InstanceID: "main", RegistryPresent: true},
+	}}, GCOptions{Adopt: []string{"main"}})
+	require.ErrorContains(t, err, "not an orphan")
+}
+
+type fakeDockerGC struct {
+	fakeDockerResources
+
+	sizes   map[string]int64
+	removed []string
+}
+
+func (f *fakeDockerGC) RemoveXCLIResource(_ context.Context, resource DockerResource) error {
+	f.removed = append(f.removed, resource.Name)
+
+	return nil
+}
+
+func (f *fakeDockerGC) XCLIResourceSizes(context.Context) (map[string]int64, error) {
+	return f.sizes, nil
+}
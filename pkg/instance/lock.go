@@ -0,0 +1,243 @@
+package instance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
+	"github.com/ethpandaops/xcli/pkg/version"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+)
+
+const (
+	// LockFileName is the lockfile 'lab lock' writes in the workspace root.
+	LockFileName = "xcli.lock"
+
+	// LockSchemaVersion is the lockfile schema version written by this build.
+	LockSchemaVersion = 1
+
+	// LockRepoXatu keys the xatu stack repository in lock diffs.
+	LockRepoXatu = "xatu"
+)
+
+// Lock pins everything needed to reproduce a lab stack: the commit of every
+// repository plus the settings that shape the generated configs. Unlike the
+// manifest it holds no machine-specific paths, so it can be committed.
+type Lock struct {
+	SchemaVersion int                   `json:"schemaVersion"`
+	XcliVersion   string                `json:"xcliVersion"`
+	Mode          string                `json:"mode"`
+	Profile       string                `json:"profile,omitempty"`
+	Networks      []string              `json:"networks"`
+	OverridesHash string                `json:"overridesHash,omitempty"`
+	Repos         map[string]LockedRepo `json:"repos"`
+	Xatu          *LockedRepo           `json:"xatu,omitempty"`
+	CreatedAt     time.Time             `json:"createdAt"`
+}
+
+// LockedRepo is one repository's pinned commit.
+type LockedRepo struct {
+	Branch string `json:"branch"`
+	Commit string `json:"commit"`
+	Dirty  bool   `json:"dirty,omitempty"`
+}
+
+// LockDiff is one setting where the workspace differs from the lockfile.
+type LockDiff struct {
+	Setting   string
+	Locked    string
+	Workspace string
+}
+
+// NewLock builds a lock for a workspace from repository snapshots, such as
+// SnapshotLabRepos or a manifest's Repos. The xatu stack repository is
+// snapshotted when one is configured.
+func NewLock(
+	ctx context.Context,
+	ws *workspace.Workspace,
+	labCfg *config.LabConfig,
+	repos map[string]xcligit.RepoVersion,
+) (*Lock, error) {
+	overridesHash, err := hashFile(ws.OverridesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]string, 0, len(labCfg.Networks))
+	for _, network := range labCfg.EnabledNetworks() {
+		networks = append(networks, network.Name)
+	}
+
+	lock := &Lock{
+		SchemaVersion: LockSchemaVersion,
+		XcliVersion:   version.GetVersion(),
+		Mode:          labCfg.Mode,
+		Profile:       labCfg.Profile,
+		Networks:      networks,
+		OverridesHash: overridesHash,
+		Repos:         make(map[string]LockedRepo, len(repos)),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	for name, repo := range repos {
+		lock.Repos[name] = lockedRepo(repo)
+	}
+
+	if labCfg.XatuStack != nil && labCfg.XatuStack.Repos.Xatu != "" {
+		repo, snapshotErr := xcligit.Snapshot(ctx, labCfg.XatuStack.Repos.Xatu)
+		if snapshotErr != nil {
+			return nil, fmt.Errorf("failed to snapshot xatu repo: %w", snapshotErr)
+		}
+
+		xatu := lockedRepo(repo)
+		lock.Xatu = &xatu
+	}
+
+	return lock, nil
+}
+
+// ReadLock reads a lockfile.
+func ReadLock(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s not found - run 'xcli lab lock' first", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if lock.SchemaVersion > LockSchemaVersion {
+		return nil, fmt.Errorf("%s has schema version %d, newer than this xcli supports (%d) - upgrade xcli",
+			path, lock.SchemaVersion, LockSchemaVersion)
+	}
+
+	return &lock, nil
+}
+
+// Write saves the lock as indented JSON.
+func (l *Lock) Write(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Diff lists the settings and repositories where current differs from the
+// lock. The xcli version is not compared; a different build can reproduce
+// the same stack.
+func (l *Lock) Diff(current *Lock) []LockDiff {
+	var diffs []LockDiff
+
+	add := func(setting, locked, workspace string) {
+		if locked != workspace {
+			diffs = append(diffs, LockDiff{Setting: setting, Locked: locked, Workspace: workspace})
+		}
+	}
+
+	add("mode", l.Mode, current.Mode)
+	add("profile", l.Profile, current.Profile)
+	add("networks", strings.Join(l.Networks, ", "), strings.Join(current.Networks, ", "))
+	add("overrides", hashLabel(l.OverridesHash), hashLabel(current.OverridesHash))
+
+	names := make([]string, 0, len(l.Repos))
+	for name := range l.Repos {
+		names = append(names, name)
+	}
+
+	for name := range current.Repos {
+		if _, ok := l.Repos[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		locked, inLock := l.Repos[name]
+		repo, inWorkspace := current.Repos[name]
+
+		add(name, describeLockedRepo(locked, inLock), describeLockedRepo(repo, inWorkspace))
+	}
+
+	if l.Xatu != nil {
+		add(LockRepoXatu, describeLockedRepo(*l.Xatu, true), describeLockedRepoPtr(current.Xatu))
+	}
+
+	return diffs
+}
+
+func lockedRepo(repo xcligit.RepoVersion) LockedRepo {
+	return LockedRepo{Branch: repo.Branch, Commit: repo.Commit, Dirty: repo.Dirty}
+}
+
+// RepoVersion converts a locked repository to the form git.Restore takes.
+func (r LockedRepo) RepoVersion(path string) xcligit.RepoVersion {
+	return xcligit.RepoVersion{Path: path, Branch: r.Branch, Commit: r.Commit, Dirty: r.Dirty}
+}
+
+func describeLockedRepo(repo LockedRepo, ok bool) string {
+	if !ok || repo.Commit == "" {
+		return "-"
+	}
+
+	description := xcligit.ShortCommit(repo.Commit)
+	if repo.Dirty {
+		description += " (uncommitted changes)"
+	}
+
+	return description
+}
+
+func describeLockedRepoPtr(repo *LockedRepo) string {
+	if repo == nil {
+		return "-"
+	}
+
+	return describeLockedRepo(*repo, true)
+}
+
+// hashLabel abbreviates a hash for a lock diff, or returns "-" when unset.
+func hashLabel(hash string) string {
+	if hash == "" {
+		return "-"
+	}
+
+	return xcligit.ShortCommit(hash)
+}
+
+// hashFile returns the hex sha256 of a file, or "" when it does not exist.
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
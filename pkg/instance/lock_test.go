@@ -0,0 +1,73 @@
+package instance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockDiffReportsDivergence(t *testing.T) {
+	rootDir := t.TempDir()
+	ws := &workspace.Workspace{
+		RootDir:       rootDir,
+		OverridesPath: filepath.Join(rootDir, constants.CBTOverridesFile),
+	}
+	labCfg := &config.LabConfig{
+		Mode: constants.ModeLocal,
+		Networks: []config.NetworkConfig{
+			{Name: "mainnet", Enabled: true},
+			{Name: "sepolia", Enabled: false},
+		},
+	}
+	repos := map[string]xcligit.RepoVersion{
+		constants.RepoCBT: {Path: "/src/cbt", Branch: "master", Commit: "1111111111111111"},
+		constants.RepoLab: {Path: "/src/lab", Branch: "main", Commit: "2222222222222222"},
+	}
+
+	locked, err := NewLock(context.Background(), ws, labCfg, repos)
+	require.NoError(t, err)
+	require.Equal(t, []string{"mainnet"}, locked.Networks)
+	require.Empty(t, locked.OverridesHash)
+
+	path := filepath.Join(rootDir, LockFileName)
+	require.NoError(t, locked.Write(path))
+
+	read, err := ReadLock(path)
+	require.NoError(t, err)
+	require.NotContains(t, mustReadFile(t, path), "/src/cbt", "lockfile must not hold machine paths")
+
+	current, err := NewLock(context.Background(), ws, labCfg, repos)
+	require.NoError(t, err)
+	require.Empty(t, read.Diff(current))
+
+	require.NoError(t, os.WriteFile(ws.OverridesPath, []byte("models: {}\n"), 0644))
+
+	labCfg.Mode = constants.ModeHybrid
+	repos[constants.RepoCBT] = xcligit.RepoVersion{Branch: "feature", Commit: "3333333333333333", Dirty: true}
+
+	current, err = NewLock(context.Background(), ws, labCfg, repos)
+	require.NoError(t, err)
+
+	diffs := read.Diff(current)
+	require.Equal(t, []LockDiff{
+		{Setting: "mode", Locked: constants.ModeLocal, Workspace: constants.ModeHybrid},
+		{Setting: "overrides", Locked: "-", Workspace: current.OverridesHash[:12]},
+		{Setting: constants.RepoCBT, Locked: "111111111111", Workspace: "333333333333 (uncommitted changes)"},
+	}, diffs)
+}
+
+func mustReadFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(data)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -35,6 +36,7 @@ type Manifest struct {
 	OverridesPath string                         `json:"overridesPath"`
 	StateDir      string                         `json:"stateDir"`
 	Mode          string                         `json:"mode"`
+	Profile       string                         `json:"profile,omitempty"`
 	Repos         map[string]xcligit.RepoVersion `json:"repos"`
 	Ports         PortPlan                       `json:"ports"`
 	Docker        DockerPlan                     `json:"docker"`
@@ -43,6 +45,10 @@ type Manifest struct {
 	CreatedAt     time.Time                      `json:"createdAt"`
 	UpdatedAt     time.Time                      `json:"updatedAt"`
 	LastError     string                         `json:"lastError,omitempty"`
+
+	// StoppedNetworks lists enabled networks whose cbt and cbt-api services
+	// were left down by 'lab up --network' or 'lab network stop'.
+	StoppedNetworks []string `json:"stoppedNetworks,omitempty"`
 }
 
 // PortPlan is the complete set of host ports managed for an instance.
@@ -124,6 +130,7 @@ func NewManifest(
 		OverridesPath: ws.OverridesPath,
 		StateDir:      InstanceStateDir(ws.RootDir, instanceID),
 		Mode:          labCfg.Mode,
+		Profile:       labCfg.Profile,
 		Repos:         repos,
 		Ports:         PortPlan{},
 		Docker:        NewDockerPlan(instanceID, ws.ConfigPath),
@@ -205,6 +212,12 @@ func (m *Manifest) EffectiveDockerPlan() DockerPlan {
 	return m.Docker.WithDefaults(NewDockerPlan(m.InstanceID, m.ConfigPath))
 }
 
+// NetworkStopped reports whether an enabled network's services were left
+// stopped on this instance.
+func (m *Manifest) NetworkStopped(network string) bool {
+	return m != nil && slices.Contains(m.StoppedNetworks, network)
+}
+
 // WithDefaults fills missing Docker plan fields from fallback.
 func (p DockerPlan) WithDefaults(fallback DockerPlan) DockerPlan {
 	if p.ProjectName == "" {
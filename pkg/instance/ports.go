@@ -29,22 +29,22 @@ var xatuCBTBindAddressVars = []string{
 
 var namedPortFields = []struct {
 	name string
-	get  func(PortPlan) int
+	ptr  func(*PortPlan) *int
 }{
-	{"lab-backend", func(p PortPlan) int { return p.LabBackend }},
-	{"lab-frontend", func(p PortPlan) int { return p.LabFrontend }},
-	{"command-center", func(p PortPlan) int { return p.CommandCenter }},
-	{"clickhouse-cbt-01-http", func(p PortPlan) int { return p.ClickHouseCBT01HTTP }},
-	{"clickhouse-cbt-01-native", func(p PortPlan) int { return p.ClickHouseCBT01TCP }},
-	{"clickhouse-cbt-02-http", func(p PortPlan) int { return p.ClickHouseCBT02HTTP }},
-	{"clickhouse-cbt-02-native", func(p PortPlan) int { return p.ClickHouseCBT02TCP }},
-	{"clickhouse-xatu-01-http", func(p PortPlan) int { return p.ClickHouseXatu01HTTP }},
-	{"clickhouse-xatu-01-native", func(p PortPlan) int { return p.ClickHouseXatu01TCP }},
-	{"clickhouse-xatu-02-http", func(p PortPlan) int { return p.ClickHouseXatu02HTTP }},
-	{"clickhouse-xatu-02-native", func(p PortPlan) int { return p.ClickHouseXatu02TCP }},
-	{"redis", func(p PortPlan) int { return p.Redis }},
-	{"prometheus", func(p PortPlan) int { return p.Prometheus }},
-	{"grafana", func(p PortPlan) int { return p.Grafana }},
+	{"lab-backend", func(p *PortPlan) *int { return &p.LabBackend }},
+	{"lab-frontend", func(p *PortPlan) *int { return &p.LabFrontend }},
+	{"command-center", func(p *PortPlan) *int { return &p.CommandCenter }},
+	{"clickhouse-cbt-01-http", func(p *PortPlan) *int { return &p.ClickHouseCBT01HTTP }},
+	{"clickhouse-cbt-01-native", func(p *PortPlan) *int { return &p.ClickHouseCBT01TCP }},
+	{"clickhouse-cbt-02-http", func(p *PortPlan) *int { return &p.ClickHouseCBT02HTTP }},
+	{"clickhouse-cbt-02-native", func(p *PortPlan) *int { return &p.ClickHouseCBT02TCP }},
+	{"clickhouse-xatu-01-http", func(p *PortPlan) *int { return &p.ClickHouseXatu01HTTP }},
+	{"clickhouse-xatu-01-native", func(p *PortPlan) *int { return &p.ClickHouseXatu01TCP }},
+	{"clickhouse-xatu-02-http", func(p *PortPlan) *int { return &p.ClickHouseXatu02HTTP }},
+	{"clickhouse-xatu-02-native", func(p *PortPlan) *int { return &p.ClickHouseXatu02TCP }},
+	{"redis", func(p *PortPlan) *int { return &p.Redis }},
+	{"prometheus", func(p *PortPlan) *int { return &p.Prometheus }},
+	{"grafana", func(p *PortPlan) *int { return &p.Grafana }},
 }
 
 var xatuCBTPortEnvFields = []struct {
@@ -150,6 +150,12 @@ func BuildPortPlan(labCfg *config.LabConfig, slot int) (PortPlan, error) {
 		}
 	}
 
+	for _, name := range sortedPinNames(labCfg.Instance.Ports) {
+		if err := plan.SetNamedPort(name, labCfg.Instance.Ports[name]); err != nil {
+			return PortPlan{}, fmt.Errorf("invalid instance.ports pin: %w", err)
+		}
+	}
+
 	if duplicates := plan.DuplicatePorts(); len(duplicates) > 0 {
 		return PortPlan{}, fmt.Errorf("port plan has duplicate ports: %v", duplicates)
 	}
@@ -157,6 +163,148 @@ func BuildPortPlan(labCfg *config.LabConfig, slot int) (PortPlan, error) {
 	return plan, nil
 }
 
+// FreeNetworkPortOffset returns the smallest portOffset a new network can use
+// without its CBT, cbt-api or CBT frontend port colliding with another
+// configured network (enabled or not) or a fixed port.
+func FreeNetworkPortOffset(labCfg *config.LabConfig) (int, error) {
+	plan, err := BuildPortPlan(labCfg, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	taken := make(map[int]bool)
+	for _, port := range plan.AllPorts() {
+		taken[port] = true
+	}
+
+	networkPorts := func(offset int) []int {
+		return []int{plan.CBTBase + offset, plan.CBTAPIBase + offset, plan.CBTFrontendBase + offset}
+	}
+
+	for _, network := range labCfg.Networks {
+		for _, port := range networkPorts(network.PortOffset) {
+			taken[port] = true
+		}
+	}
+
+	for offset := 0; offset < portStride; offset++ {
+		free := true
+
+		for _, port := range networkPorts(offset) {
+			if taken[port] {
+				free = false
+
+				break
+			}
+		}
+
+		if free {
+			return offset, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free network port offset below %d", portStride)
+}
+
+// SetNamedPort sets one port by its NamedPorts name.
+func (p *PortPlan) SetNamedPort(name string, port int) error {
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("%s: port %d is out of range", name, port)
+	}
+
+	for _, field := range namedPortFields {
+		if field.name == name {
+			*field.ptr(p) = port
+
+			return nil
+		}
+	}
+
+	for network, networkPorts := range p.Networks {
+		target := networkPortField(&networkPorts, network, name)
+		if target == nil {
+			continue
+		}
+
+		*target = port
+		p.Networks[network] = networkPorts
+
+		return nil
+	}
+
+	return fmt.Errorf("unknown port name %q (see 'xcli lab ports' for names)", name)
+}
+
+// OrderedPortNames returns the NamedPorts names in display order: the fixed
+// ports first, then each network's ports by network name.
+func (p PortPlan) OrderedPortNames() []string {
+	names := make([]string, 0, len(namedPortFields)+len(p.Networks)*5)
+	for _, field := range namedPortFields {
+		names = append(names, field.name)
+	}
+
+	networks := make([]string, 0, len(p.Networks))
+	for network := range p.Networks {
+		networks = append(networks, network)
+	}
+
+	sort.Strings(networks)
+
+	for _, network := range networks {
+		names = append(names,
+			"cbt-"+network,
+			"cbt-api-"+network,
+			"cbt-frontend-"+network,
+			"cbt-metrics-"+network,
+			"cbt-api-metrics-"+network,
+		)
+	}
+
+	return names
+}
+
+// PortNames maps each concrete port in the plan to its NamedPorts name.
+func (p PortPlan) PortNames() map[int]string {
+	named := p.NamedPorts()
+
+	names := make(map[int]string, len(named))
+	for name, port := range named {
+		if port > 0 {
+			names[port] = name
+		}
+	}
+
+	return names
+}
+
+func networkPortField(ports *NetworkPortPlan, network, name string) *int {
+	switch name {
+	case "cbt-" + network:
+		return &ports.CBT
+	case "cbt-api-" + network:
+		return &ports.CBTAPI
+	case "cbt-frontend-" + network:
+		return &ports.CBTFrontend
+	case "cbt-metrics-" + network:
+		return &ports.CBTMetrics
+	case "cbt-api-metrics-" + network:
+		return &ports.CBTAPIMetrics
+	default:
+		return nil
+	}
+}
+
+func sortedPinNames(pins map[string]int) []string {
+	names := make([]string, 0, len(pins))
+	for name := range pins {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
 // WithDefaults fills zero fields in p from fallback.
 func (p PortPlan) WithDefaults(fallback PortPlan) PortPlan {
 	if p.LabBackend == 0 {
@@ -283,7 +431,7 @@ func (p PortPlan) AllPorts() []int {
 func (p PortPlan) NamedPorts() map[string]int {
 	ports := make(map[string]int, len(namedPortFields)+len(p.Networks)*5)
 	for _, field := range namedPortFields {
-		ports[field.name] = field.get(p)
+		ports[field.name] = *field.ptr(&p)
 	}
 
 	for network, networkPorts := range p.Networks {
@@ -63,3 +63,20 @@ func TestBuildPortPlanAppliesStrideAcrossFullPlan(t *testing.T) {
 	require.Equal(t, slot0.Networks["mainnet"].CBTMetrics+2*portStride, slot2.Networks["mainnet"].CBTMetrics)
 	require.Equal(t, slot0.Networks["mainnet"].CBTAPIMetrics+2*portStride, slot2.Networks["mainnet"].CBTAPIMetrics)
 }
+
+func TestFreeNetworkPortOffsetSkipsCollisions(t *testing.T) {
+	labCfg := config.DefaultLab()
+
+	// mainnet, sepolia and hoodi use offsets 0-2, so CBT frontends occupy
+	// 8085-8087. Offset 3 is free; 4-7 would put a CBT port on a frontend.
+	offset, err := FreeNetworkPortOffset(labCfg)
+	require.NoError(t, err)
+	require.Equal(t, 3, offset)
+
+	// With offsets 0-3 taken, 8-13 put a frontend or CBT port on cbt-api.
+	labCfg.Networks = append(labCfg.Networks, config.NetworkConfig{Name: "devnet", PortOffset: 3})
+
+	offset, err = FreeNetworkPortOffset(labCfg)
+	require.NoError(t, err)
+	require.Equal(t, 14, offset)
+}
@@ -11,10 +11,13 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/dockerhost"
 )
 
 const (
@@ -36,6 +39,10 @@ type DockerResource struct {
 	Name   string
 	State  string
 	Labels map[string]string
+	// CreatedAt is when Docker created the resource, zero when unknown.
+	CreatedAt time.Time
+	// Size is the disk usage in bytes, only filled in when requested.
+	Size int64
 }
 
 // DockerResourceProvider lists Docker resources labeled with an xcli instance id.
@@ -45,8 +52,14 @@ type DockerResourceProvider interface {
 
 // Reconciler compares persisted manifests with live host state.
 type Reconciler struct {
-	Registry    *Registry
-	Docker      DockerResourceProvider
+	Registry *Registry
+	// DockerHost is the Docker host lab infrastructure runs on, used when
+	// Docker is nil.
+	DockerHost config.DockerHostConfig
+	Docker     DockerResourceProvider
+	// NewDocker creates the provider for DockerHost, defaulting to
+	// NewDockerResourceProvider.
+	NewDocker   func(cfg config.DockerHostConfig) (DockerResourceProvider, error)
 	PIDAlive    func(pid int) bool
 	PortBound   func(port int) bool
 	DockerError error
@@ -76,9 +89,10 @@ type LiveState struct {
 	BackingExists   bool
 }
 
-// NewReconciler creates a reconciler backed by the default host checks.
-func NewReconciler(registry *Registry) *Reconciler {
-	return &Reconciler{Registry: registry}
+// NewReconciler creates a reconciler backed by the default host checks and
+// the Docker host lab infrastructure runs on.
+func NewReconciler(registry *Registry, dockerHost config.DockerHostConfig) *Reconciler {
+	return &Reconciler{Registry: registry, DockerHost: dockerHost}
 }
 
 // ReconcileAll reads the registry and reconciles it against live resources.
@@ -211,9 +225,14 @@ func (r *Reconciler) reconcileOrphan(instanceID string, resources []DockerResour
 func (r *Reconciler) listDockerResources(ctx context.Context) ([]DockerResource, error) {
 	provider := r.Docker
 	if provider == nil {
+		newDocker := r.NewDocker
+		if newDocker == nil {
+			newDocker = NewDockerResourceProvider
+		}
+
 		var err error
 
-		provider, err = NewDockerResourceProvider()
+		provider, err = newDocker(r.DockerHost)
 		if err != nil {
 			return nil, err
 		}
@@ -321,11 +340,17 @@ type dockerResourceProvider struct {
 	client *client.Client
 }
 
-// NewDockerResourceProvider returns a Docker-backed resource provider.
-func NewDockerResourceProvider() (DockerResourceProvider, error) {
-	dockerClient, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+// NewDockerResourceProvider returns a resource provider for the Docker host
+// configured under lab.infrastructure.docker, which may be remote.
+func NewDockerResourceProvider(cfg config.DockerHostConfig) (DockerResourceProvider, error) {
+	target, err := dockerhost.Resolve(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve Docker host: %w", err)
+	}
+
+	dockerClient, err := target.NewClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+		return nil, err
 	}
 
 	return &dockerResourceProvider{client: dockerClient}, nil
@@ -344,11 +369,6 @@ func (p *dockerResourceProvider) ListXCLIResources(ctx context.Context) ([]Docke
 		}
 
 		for _, item := range containers {
-			name := item.ID
-			if len(item.Names) > 0 {
-				name = strings.TrimPrefix(item.Names[0], "/")
-			}
-
 			key := "container:" + item.ID
 			if seen[key] {
 				continue
@@ -357,11 +377,12 @@ func (p *dockerResourceProvider) ListXCLIResources(ctx context.Context) ([]Docke
 			seen[key] = true
 
 			resources = append(resources, DockerResource{
-				Kind:   DockerResourceKindContainer,
-				ID:     item.ID,
-				Name:   name,
-				State:  item.State,
-				Labels: item.Labels,
+				Kind:      DockerResourceKindContainer,
+				ID:        item.ID,
+				Name:      containerName(item),
+				State:     item.State,
+				Labels:    item.Labels,
+				CreatedAt: time.Unix(item.Created, 0),
 			})
 		}
 
@@ -378,14 +399,69 @@ func (p *dockerResourceProvider) ListXCLIResources(ctx context.Context) ([]Docke
 
 			seen[key] = true
 
+			createdAt, _ := time.Parse(time.RFC3339, item.CreatedAt)
+
 			resources = append(resources, DockerResource{
-				Kind:   "volume",
-				Name:   item.Name,
-				State:  "present",
-				Labels: item.Labels,
+				Kind:      DockerResourceKindVolume,
+				Name:      item.Name,
+				State:     "present",
+				Labels:    item.Labels,
+				CreatedAt: createdAt,
 			})
 		}
 	}
 
 	return resources, nil
 }
+
+// RemoveXCLIResource stops and removes a container, or removes a volume.
+func (p *dockerResourceProvider) RemoveXCLIResource(ctx context.Context, resource DockerResource) error {
+	switch resource.Kind {
+	case DockerResourceKindContainer:
+		if resource.State == "running" {
+			if err := p.client.ContainerStop(ctx, resource.ID, container.StopOptions{}); err != nil {
+				return fmt.Errorf("failed to stop container %s: %w", resource.Name, err)
+			}
+		}
+
+		if err := p.client.ContainerRemove(ctx, resource.ID, container.RemoveOptions{}); err != nil {
+			return fmt.Errorf("failed to remove container %s: %w", resource.Name, err)
+		}
+	case DockerResourceKindVolume:
+		if err := p.client.VolumeRemove(ctx, resource.Name, false); err != nil {
+			return fmt.Errorf("failed to remove volume %s: %w", resource.Name, err)
+		}
+	default:
+		return fmt.Errorf("unsupported Docker resource kind %q", resource.Kind)
+	}
+
+	return nil
+}
+
+// XCLIResourceSizes returns the disk usage of xcli containers and volumes,
+// keyed by DockerResourceKey. Volumes not known to Docker's usage data and
+// containers without a writable layer are omitted.
+func (p *dockerResourceProvider) XCLIResourceSizes(ctx context.Context) (map[string]int64, error) {
+	usage, err := p.client.DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.ContainerObject, types.VolumeObject},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Docker disk usage: %w", err)
+	}
+
+	sizes := make(map[string]int64, len(usage.Containers)+len(usage.Volumes))
+
+	for _, item := range usage.Containers {
+		if item != nil && item.SizeRw > 0 {
+			sizes[DockerResourceKey(DockerResource{Kind: DockerResourceKindContainer, ID: item.ID})] = item.SizeRw
+		}
+	}
+
+	for _, item := range usage.Volumes {
+		if item != nil && item.UsageData != nil && item.UsageData.Size >= 0 {
+			sizes[DockerResourceKey(DockerResource{Kind: DockerResourceKindVolume, Name: item.Name})] = item.UsageData.Size
+		}
+	}
+
+	return sizes, nil
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/ethpandaops/xcli/pkg/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -97,6 +98,27 @@ func TestReconcilerMarksReservedWithoutBackingStale(t *testing.T) {
 	require.Equal(t, StatusStale, result.Instances[0].Status)
 }
 
+func TestReconcilerUsesConfiguredDockerHost(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), "instances"))
+	dockerHost := config.DockerHostConfig{Host: "ssh://lab@devbox"}
+
+	var used []config.DockerHostConfig
+
+	reconciler := NewReconciler(registry, dockerHost)
+	reconciler.NewDocker = func(cfg config.DockerHostConfig) (DockerResourceProvider, error) {
+		used = append(used, cfg)
+
+		return fakeDockerResources{resources: []DockerResource{orphanDockerResource("remote")}}, nil
+	}
+
+	result, err := reconciler.ReconcileAll(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, result.DockerError)
+	require.Equal(t, []config.DockerHostConfig{dockerHost}, used)
+	require.Len(t, result.Instances, 1)
+	require.Equal(t, "remote", result.Instances[0].InstanceID)
+}
+
 type fakeDockerResources struct {
 	resources []DockerResource
 }
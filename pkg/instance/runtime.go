@@ -100,7 +100,9 @@ func ResolveRuntimeFromWorkspace(
 			return nil, loadErr
 		}
 
-		if cliInstanceID != "" {
+		// Reload the config when the instance was started with a different
+		// profile than the one already applied.
+		if cliInstanceID != "" || ManifestProfile(manifest) != labCfg.Profile {
 			return NewRuntimeFromManifestConfig(ctx, manifest, registry)
 		}
 
@@ -187,7 +189,7 @@ func NewRuntimeFromManifestConfig(
 		return nil, fmt.Errorf("manifest %q has no config path", manifest.InstanceID)
 	}
 
-	labCfg, ws, err := workspace.LoadLabConfig(manifest.ConfigPath, false)
+	labCfg, ws, err := workspace.LoadLabConfigWithProfile(manifest.ConfigPath, false, ManifestProfile(manifest))
 	if err != nil {
 		return nil, fmt.Errorf(
 			"failed to load config for instance %q from %s: %w",
@@ -200,6 +202,21 @@ func NewRuntimeFromManifestConfig(
 	return NewRuntimeFromManifest(manifest, labCfg, ws, registry)
 }
 
+// ManifestProfile returns the profile to load an instance's config with: the
+// --profile flag when given, otherwise the profile recorded in the manifest.
+func ManifestProfile(manifest *Manifest) string {
+	profile := config.ActiveProfile()
+	if profile == "" && manifest != nil {
+		profile = manifest.Profile
+	}
+
+	if profile == config.NoProfile {
+		return ""
+	}
+
+	return profile
+}
+
 // NewRuntimeFromManifest assembles a runtime from persisted instance state.
 func NewRuntimeFromManifest(
 	manifest *Manifest,
@@ -79,6 +79,40 @@ func TestResolveRuntimeExplicitInstanceLoadsManifestWorkspace(t *testing.T) {
 	require.NotEqual(t, currentWorkspace.ConfigPath, resolved.Workspace.ConfigPath)
 }
 
+func TestResolveRuntimeReusesRecordedProfile(t *testing.T) {
+	registry := NewRegistry(filepath.Join(t.TempDir(), "lab", "instances"))
+
+	configPath := writeRuntimeConfig(t, "profiled")
+	profilesDir := filepath.Join(filepath.Dir(configPath), config.ProfilesDirName)
+	require.NoError(t, os.MkdirAll(profilesDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(profilesDir, "quiet.yaml"), []byte(`infrastructure:
+  observability:
+    enabled: false
+`), 0600))
+
+	labCfg, ws, err := workspace.LoadLabConfigWithProfile(configPath, false, "quiet")
+	require.NoError(t, err)
+
+	started, err := NewRuntimeFromWorkspace(context.Background(), ws, labCfg, "", RuntimeOptions{
+		Registry:   registry,
+		Allocator:  NewAllocator(registry, false),
+		ClaimPorts: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "quiet", started.Manifest.Profile)
+	require.NoError(t, registry.Save(started.Manifest))
+
+	// Without --profile, the base config is swapped for the recorded profile.
+	baseCfg, baseWS, err := workspace.LoadLabConfig(configPath, false)
+	require.NoError(t, err)
+	require.True(t, baseCfg.Infrastructure.Observability.Enabled)
+
+	resolved, err := ResolveRuntimeFromWorkspace(context.Background(), baseWS, baseCfg, "", RuntimeOptions{Registry: registry})
+	require.NoError(t, err)
+	require.Equal(t, "quiet", resolved.LabConfig.Profile)
+	require.False(t, resolved.LabConfig.Infrastructure.Observability.Enabled)
+}
+
 func writeRuntimeConfig(t *testing.T, name string) string {
 	t.Helper()
 
@@ -45,6 +45,11 @@ func (m *HybridMode) NeedsExternalClickHouse() bool {
 	return true
 }
 
+// UsesXatuStack returns false as hybrid mode uses the configured external URL.
+func (m *HybridMode) UsesXatuStack() bool {
+	return false
+}
+
 // GetObservabilityPorts returns ports for observability services (Prometheus, Grafana).
 // Returns nil if observability is disabled.
 func (m *HybridMode) GetObservabilityPorts() []int {
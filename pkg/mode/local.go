@@ -44,6 +44,11 @@ func (m *LocalMode) NeedsExternalClickHouse() bool {
 	return false
 }
 
+// UsesXatuStack returns false as local mode runs its own Xatu ClickHouse.
+func (m *LocalMode) UsesXatuStack() bool {
+	return false
+}
+
 // GetObservabilityPorts returns ports for observability services (Prometheus, Grafana).
 // Returns nil if observability is disabled.
 func (m *LocalMode) GetObservabilityPorts() []int {
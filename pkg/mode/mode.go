@@ -1,4 +1,4 @@
-// Package mode defines operational modes (local vs hybrid) for the lab stack,
+// Package mode defines operational modes (local, hybrid, xatu-local) for the lab stack,
 // determining which infrastructure services to use and how they're configured.
 package mode
 
@@ -6,11 +6,12 @@ import (
 	"fmt"
 
 	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
 )
 
-// Mode defines the interface for local vs hybrid stack operation.
+// Mode defines the interface for local, hybrid and xatu-local stack operation.
 type Mode interface {
-	// Name returns "local" or "hybrid"
+	// Name returns "local", "hybrid" or "xatu-local"
 	Name() string
 
 	// GetInfrastructureServices returns the list of docker services to start
@@ -27,11 +28,16 @@ type Mode interface {
 	// Returns nil if observability is disabled
 	GetObservabilityPorts() []int
 
-	// NeedsExternalClickHouse returns true if hybrid mode
+	// NeedsExternalClickHouse returns true if hybrid or xatu-local mode
 	NeedsExternalClickHouse() bool
 
+	// UsesXatuStack returns true if the external ClickHouse is discovered
+	// from the xatu docker-compose stack (xatu-local mode)
+	UsesXatuStack() bool
+
 	// ValidateConfig validates mode-specific config requirements
 	// Hybrid: ensures external_clickhouse.host is set
+	// Xatu-local: ensures the xatu stack is configured
 	// Local: no special validation
 	ValidateConfig(cfg *config.Config) error
 }
@@ -43,7 +49,9 @@ func NewMode(cfg *config.Config) (Mode, error) {
 		return NewLocalMode(cfg), nil
 	case "hybrid":
 		return NewHybridMode(cfg), nil
+	case constants.ModeXatuLocal:
+		return NewXatuLocalMode(cfg), nil
 	default:
-		return nil, fmt.Errorf("unknown mode: %s (expected 'local' or 'hybrid')", cfg.Lab.Mode)
+		return nil, fmt.Errorf("unknown mode: %s (expected 'local', 'hybrid' or 'xatu-local')", cfg.Lab.Mode)
 	}
 }
@@ -0,0 +1,75 @@
+package mode
+
+import (
+	"fmt"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+)
+
+// XatuLocalMode implements a local stack fed by the ClickHouse of a running
+// xatu docker-compose stack, so events from local sentries and cannon flow
+// straight into CBT.
+type XatuLocalMode struct {
+	config *config.Config
+}
+
+// NewXatuLocalMode creates a new xatu-local mode instance.
+func NewXatuLocalMode(cfg *config.Config) *XatuLocalMode {
+	return &XatuLocalMode{config: cfg}
+}
+
+// Name returns the mode identifier "xatu-local".
+func (m *XatuLocalMode) Name() string {
+	return constants.ModeXatuLocal
+}
+
+// GetInfrastructureServices returns the list of docker services required for xatu-local mode.
+// Like hybrid mode it excludes clickhouse-xatu-local; Xatu data comes from the xatu stack.
+func (m *XatuLocalMode) GetInfrastructureServices() []string {
+	return []string{
+		"clickhouse-cbt",
+		"redis",
+	}
+}
+
+// GetHealthCheckPorts returns the ports to health check for infrastructure readiness.
+// The xatu stack's ClickHouse is checked separately when its port is discovered.
+func (m *XatuLocalMode) GetHealthCheckPorts() []int {
+	return []int{
+		m.config.Lab.Infrastructure.ClickHouseCBTPort, // ClickHouse CBT
+		m.config.Lab.Infrastructure.RedisPort,         // Redis
+	}
+}
+
+// NeedsExternalClickHouse returns true as the Xatu ClickHouse is outside the lab's infrastructure.
+func (m *XatuLocalMode) NeedsExternalClickHouse() bool {
+	return true
+}
+
+// UsesXatuStack returns true as the Xatu ClickHouse is discovered from the xatu stack.
+func (m *XatuLocalMode) UsesXatuStack() bool {
+	return true
+}
+
+// GetObservabilityPorts returns ports for observability services (Prometheus, Grafana).
+// Returns nil if observability is disabled.
+func (m *XatuLocalMode) GetObservabilityPorts() []int {
+	if !m.config.Lab.Infrastructure.Observability.Enabled {
+		return nil
+	}
+
+	return []int{
+		m.config.Lab.Infrastructure.Observability.PrometheusPort,
+		m.config.Lab.Infrastructure.Observability.GrafanaPort,
+	}
+}
+
+// ValidateConfig validates that the xatu stack is configured.
+func (m *XatuLocalMode) ValidateConfig(cfg *config.Config) error {
+	if cfg.Xatu == nil || cfg.Xatu.Repos.Xatu == "" {
+		return fmt.Errorf("xatu-local mode requires the xatu stack to be configured - run 'xcli xatu init' first")
+	}
+
+	return nil
+}
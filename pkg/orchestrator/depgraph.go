@@ -0,0 +1,357 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/process"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// infraReadyTimeout bounds the wait for an infrastructure component
+	// before the services that depend on it start.
+	infraReadyTimeout = 2 * time.Minute
+	// serviceHealthTimeout bounds the wait for a started service's port.
+	serviceHealthTimeout = 2 * time.Minute
+)
+
+// serviceGraph declares which services and infrastructure components each
+// service needs before it can start. Nodes are kept in declaration order so
+// walks and error messages are stable.
+type serviceGraph struct {
+	order []string
+	deps  map[string][]string
+}
+
+func newServiceGraph() *serviceGraph {
+	return &serviceGraph{deps: make(map[string][]string)}
+}
+
+// add declares a node and the nodes it depends on.
+func (g *serviceGraph) add(node string, deps ...string) {
+	if _, ok := g.deps[node]; !ok {
+		g.order = append(g.order, node)
+	}
+
+	g.deps[node] = append(g.deps[node], deps...)
+}
+
+// dependents returns every node that directly or transitively depends on
+// node, in dependency order.
+func (g *serviceGraph) dependents(node string) []string {
+	affected := map[string]bool{node: true}
+
+	order, _ := g.sorted()
+	for _, name := range order {
+		for _, dep := range g.deps[name] {
+			if affected[dep] {
+				affected[name] = true
+
+				break
+			}
+		}
+	}
+
+	result := make([]string, 0, len(affected)-1)
+
+	for _, name := range order {
+		if affected[name] && name != node {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// subgraph returns the graph restricted to nodes. Dependencies outside the
+// set are dropped, as they are assumed to be up already.
+func (g *serviceGraph) subgraph(nodes []string) *serviceGraph {
+	sub := newServiceGraph()
+
+	for _, node := range g.order {
+		if !slices.Contains(nodes, node) {
+			continue
+		}
+
+		sub.add(node)
+
+		for _, dep := range g.deps[node] {
+			if slices.Contains(nodes, dep) {
+				sub.deps[node] = append(sub.deps[node], dep)
+			}
+		}
+	}
+
+	return sub
+}
+
+// sorted returns the nodes with every node after its dependencies, or an
+// error naming the nodes on a dependency cycle.
+func (g *serviceGraph) sorted() ([]string, error) {
+	indegree := make(map[string]int, len(g.order))
+
+	for _, node := range g.order {
+		for _, dep := range g.deps[node] {
+			if _, ok := g.deps[dep]; !ok {
+				return nil, fmt.Errorf("%s depends on undeclared %s", node, dep)
+			}
+
+			indegree[node]++
+		}
+	}
+
+	order := make([]string, 0, len(g.order))
+	done := make(map[string]bool, len(g.order))
+
+	for len(order) < len(g.order) {
+		progressed := false
+
+		for _, node := range g.order {
+			if done[node] || indegree[node] > 0 {
+				continue
+			}
+
+			done[node] = true
+			order = append(order, node)
+			progressed = true
+
+			for _, other := range g.order {
+				for _, dep := range g.deps[other] {
+					if dep == node {
+						indegree[other]--
+					}
+				}
+			}
+		}
+
+		if !progressed {
+			var cycle []string
+
+			for _, node := range g.order {
+				if !done[node] {
+					cycle = append(cycle, node)
+				}
+			}
+
+			return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+		}
+	}
+
+	return order, nil
+}
+
+// walk calls fn for every node, running nodes in parallel as soon as the
+// nodes they wait for have finished. Forward walks wait for dependencies and
+// skip nodes whose dependencies failed; reverse walks wait for dependents and
+// carry on past failures so everything gets a chance to stop.
+func (g *serviceGraph) walk(ctx context.Context, reverse bool, fn func(ctx context.Context, node string) error) error {
+	if _, err := g.sorted(); err != nil {
+		return err
+	}
+
+	waitFor := g.deps
+	if reverse {
+		waitFor = make(map[string][]string, len(g.order))
+
+		for _, node := range g.order {
+			for _, dep := range g.deps[node] {
+				waitFor[dep] = append(waitFor[dep], node)
+			}
+		}
+	}
+
+	finished := make(map[string]chan struct{}, len(g.order))
+	for _, node := range g.order {
+		finished[node] = make(chan struct{})
+	}
+
+	var (
+		mu     sync.Mutex
+		failed = make(map[string]bool)
+		errs   []error
+		wg     sync.WaitGroup
+	)
+
+	for _, node := range g.order {
+		wg.Go(func() {
+			defer close(finished[node])
+
+			for _, other := range waitFor[node] {
+				select {
+				case <-finished[other]:
+				case <-ctx.Done():
+					mu.Lock()
+					failed[node] = true
+					mu.Unlock()
+
+					return
+				}
+			}
+
+			if !reverse {
+				mu.Lock()
+				blocked := slices.ContainsFunc(waitFor[node], func(dep string) bool { return failed[dep] })
+
+				if blocked {
+					failed[node] = true
+				}
+				mu.Unlock()
+
+				if blocked {
+					return
+				}
+			}
+
+			if err := fn(ctx, node); err != nil {
+				mu.Lock()
+				failed[node] = true
+				errs = append(errs, fmt.Errorf("%s: %w", node, err))
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// serviceGraph declares the lab stack's dependencies for the given networks:
+// CBT engines need ClickHouse CBT and Redis (and the local Xatu ClickHouse
+// when it runs), each cbt-api needs ClickHouse CBT and its network's CBT
+// engine, lab-backend needs Redis and every cbt-api, and lab-frontend needs
+// lab-backend.
+func (o *Orchestrator) serviceGraph(networks []config.NetworkConfig) *serviceGraph {
+	g := newServiceGraph()
+
+	infra := []string{constants.InfraClickHouseCBT, constants.InfraRedis}
+	if !o.mode.NeedsExternalClickHouse() {
+		infra = append(infra, constants.InfraClickHouseXatu)
+	}
+
+	for _, component := range infra {
+		g.add(component)
+	}
+
+	backendDeps := []string{constants.InfraRedis}
+
+	for _, net := range networks {
+		cbt := constants.ServiceNameCBT(net.Name)
+		cbtAPI := constants.ServiceNameCBTAPI(net.Name)
+
+		g.add(cbt, infra...)
+		g.add(cbtAPI, constants.InfraClickHouseCBT, cbt)
+
+		backendDeps = append(backendDeps, cbtAPI)
+	}
+
+	g.add(constants.ServiceLabBackend, backendDeps...)
+	g.add(constants.ServiceLabFrontend, constants.ServiceLabBackend)
+
+	return g
+}
+
+// isInfraNode reports whether a graph node is an infrastructure component
+// rather than a managed process.
+func isInfraNode(node string) bool {
+	switch node {
+	case constants.InfraClickHouseCBT, constants.InfraClickHouseXatu, constants.InfraRedis:
+		return true
+	default:
+		return false
+	}
+}
+
+// startNode waits for an infrastructure component, or starts a service and
+// waits for it to become healthy.
+func (o *Orchestrator) startNode(ctx context.Context, node string) error {
+	if isInfraNode(node) {
+		return o.infra.WaitForComponent(ctx, node, infraReadyTimeout)
+	}
+
+	o.log.WithField(logFieldService, node).Debug("dependencies ready, starting service")
+
+	return o.StartService(ctx, node)
+}
+
+// stopNode stops a service. Infrastructure is left to Down and Destroy.
+func (o *Orchestrator) stopNode(ctx context.Context, node string) error {
+	if isInfraNode(node) {
+		return nil
+	}
+
+	return o.StopService(ctx, node)
+}
+
+// stopServices stops the lab services in reverse dependency order, then any
+// managed process left over from PID files.
+func (o *Orchestrator) stopServices(ctx context.Context) error {
+	graphErr := o.serviceGraph(o.cfg.EnabledNetworks()).walk(ctx, true, o.stopNode)
+
+	return errors.Join(graphErr, o.proc.StopAll(ctx))
+}
+
+// serviceHealthCheck returns the check a service must pass after starting:
+// services with a listening port must accept connections on it. CBT engines
+// have no port to wait on.
+func (o *Orchestrator) serviceHealthCheck(service string) process.HealthChecker {
+	var port int
+
+	switch {
+	case service == constants.ServiceLabBackend:
+		port = o.portPlan().LabBackend
+	case service == constants.ServiceLabFrontend:
+		port = o.portPlan().LabFrontend
+	case strings.HasPrefix(service, constants.ServicePrefixCBTAPI):
+		port = o.networkPortPlan(strings.TrimPrefix(service, constants.ServicePrefixCBTAPI)).CBTAPI
+	}
+
+	if port == 0 {
+		return nil
+	}
+
+	return process.NewPortHealthChecker("localhost", port, serviceHealthTimeout)
+}
+
+// RestartWithDependents restarts a service and every service that depends
+// on it. Dependents stop first and start again once the service is healthy.
+func (o *Orchestrator) RestartWithDependents(ctx context.Context, service string) ([]string, error) {
+	if !o.IsValidService(service) {
+		return nil, fmt.Errorf("unknown service: %s", service)
+	}
+
+	g := o.serviceGraph(o.runningNetworks())
+	if _, ok := g.deps[service]; !ok {
+		return nil, o.Restart(ctx, service)
+	}
+
+	dependents := g.dependents(service)
+	sub := g.subgraph(append([]string{service}, dependents...))
+
+	o.log.WithFields(logrus.Fields{
+		logFieldService: service,
+		"dependents":    dependents,
+	}).Info("restarting service with dependents")
+
+	if err := sub.walk(ctx, true, o.stopNode); err != nil {
+		return dependents, fmt.Errorf("failed to stop services: %w", err)
+	}
+
+	if err := sub.walk(ctx, false, o.startNode); err != nil {
+		return dependents, fmt.Errorf("failed to start services: %w", err)
+	}
+
+	return dependents, nil
+}
@@ -0,0 +1,125 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
+	"github.com/ethpandaops/xcli/pkg/portutil"
+	"github.com/ethpandaops/xcli/pkg/process"
+)
+
+// incidentInfraTimeout bounds the infrastructure status check of an incident.
+const incidentInfraTimeout = 10 * time.Second
+
+// newIncidentRecorder creates the runtime incident recorder for a state
+// directory, matching incidents against the built-in patterns plus the user
+// pattern packs of repoRoot.
+func (o *Orchestrator) newIncidentRecorder(repoRoot string) *diagnostic.IncidentRecorder {
+	matcher, packErrs := diagnostic.NewPatternMatcherWithPacks(diagnostic.PatternSearchDirs(repoRoot)...)
+	for _, packErr := range packErrs {
+		o.log.WithError(packErr).Debug("skipping pattern pack for incident matching")
+	}
+
+	store := diagnostic.NewStore(o.log, filepath.Join(o.stateDir, constants.DirErrors))
+
+	return diagnostic.NewIncidentRecorder(o.log, store, matcher)
+}
+
+// Incidents returns the runtime incident recorder.
+func (o *Orchestrator) Incidents() *diagnostic.IncidentRecorder {
+	return o.incidents
+}
+
+// RecordUnhealthy records an incident for a running service whose health
+// checks keep failing. Recording happens in the background.
+func (o *Orchestrator) RecordUnhealthy(service, lastError string) {
+	pid := 0
+	if p, ok := o.proc.Get(service); ok {
+		pid = p.PID
+	}
+
+	go o.recordIncident(diagnostic.IncidentEvent{
+		Service: service,
+		Trigger: diagnostic.IncidentUnhealthy,
+		PID:     pid,
+		Error:   lastError,
+		LogFile: o.LogFilePath(service),
+	})
+}
+
+// handleProcessExit records an incident for a service process that exited
+// without being stopped.
+func (o *Orchestrator) handleProcessExit(event process.ExitEvent) {
+	incident := diagnostic.IncidentEvent{
+		Service: event.Name,
+		Trigger: diagnostic.IncidentExit,
+		PID:     event.PID,
+		LogFile: event.LogFile,
+	}
+
+	if event.ExitCode >= 0 {
+		exitCode := event.ExitCode
+		incident.ExitCode = &exitCode
+	}
+
+	if event.Err != nil {
+		incident.Error = event.Err.Error()
+	}
+
+	if incident.LogFile == "" {
+		incident.LogFile = o.LogFilePath(event.Name)
+	}
+
+	go o.recordIncident(incident)
+}
+
+// recordIncident adds the service's config, port and infrastructure state to
+// event and records it.
+func (o *Orchestrator) recordIncident(event diagnostic.IncidentEvent) {
+	event.ConfigFiles = o.serviceConfigFiles(event.Service)
+
+	for _, port := range o.getServicePorts(event.Service) {
+		state := diagnostic.IncidentPort{Port: port}
+
+		if conflict := portutil.CheckPort(port); conflict != nil {
+			state.Bound = true
+			state.PID = conflict.PID
+			state.Process = conflict.Process
+		}
+
+		event.Ports = append(event.Ports, state)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), incidentInfraTimeout)
+	defer cancel()
+
+	event.Infra = o.infra.Status(ctx)
+
+	if _, err := o.incidents.Record(event); err != nil {
+		o.log.WithError(err).WithField(logFieldService, event.Service).Warn("failed to record runtime incident")
+	}
+}
+
+// serviceConfigFiles returns the generated config files a service runs with.
+func (o *Orchestrator) serviceConfigFiles(service string) []string {
+	configsDir := filepath.Join(o.stateDir, constants.DirConfigs)
+
+	if service == constants.ServiceLabBackend {
+		return []string{filepath.Join(configsDir, constants.ConfigFileLabBackend)}
+	}
+
+	for _, network := range o.cfg.EnabledNetworks() {
+		switch service {
+		case constants.ServiceNameCBT(network.Name):
+			return []string{filepath.Join(configsDir, fmt.Sprintf(constants.ConfigFileCBT, network.Name))}
+		case constants.ServiceNameCBTAPI(network.Name):
+			return []string{filepath.Join(configsDir, fmt.Sprintf(constants.ConfigFileCBTAPI, network.Name))}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/ethpandaops/xcli/pkg/constants"
+)
+
+// SetUpNetworks limits the next Up to the named networks. The other enabled
+// networks are recorded as stopped and can be started later with
+// StartNetwork. An empty list starts every enabled network.
+func (o *Orchestrator) SetUpNetworks(networks []string) error {
+	for _, network := range networks {
+		if !o.isNetworkEnabled(network) {
+			return fmt.Errorf("network %s is not enabled in config", network)
+		}
+	}
+
+	if len(networks) > 0 && o.runtime == nil {
+		return fmt.Errorf("selecting networks requires a lab instance runtime")
+	}
+
+	o.upNetworks = slices.Clone(networks)
+
+	return nil
+}
+
+// runningNetworks returns the enabled networks that are not stopped.
+func (o *Orchestrator) runningNetworks() []config.NetworkConfig {
+	networks := o.cfg.EnabledNetworks()
+	if o.runtime == nil {
+		return networks
+	}
+
+	return slices.DeleteFunc(networks, func(net config.NetworkConfig) bool {
+		return o.runtime.Manifest.NetworkStopped(net.Name)
+	})
+}
+
+// applyUpNetworks records the networks left out of SetUpNetworks as stopped,
+// so config generation and service startup skip them.
+func (o *Orchestrator) applyUpNetworks() {
+	if o.runtime == nil || o.runtime.Manifest == nil {
+		return
+	}
+
+	var stopped []string
+
+	if len(o.upNetworks) > 0 {
+		for _, net := range o.cfg.EnabledNetworks() {
+			if !slices.Contains(o.upNetworks, net.Name) {
+				stopped = append(stopped, net.Name)
+			}
+		}
+	}
+
+	o.runtime.Manifest.StoppedNetworks = stopped
+}
+
+// StartNetwork brings one network's cbt and cbt-api services up on a running
+// stack: it runs the network's migrations, seeds its Redis bounds when
+// needed, adds it back to lab-backend's network list and restarts
+// lab-backend. Other networks keep running.
+func (o *Orchestrator) StartNetwork(ctx context.Context, network string) error {
+	net, err := o.checkNetworkAction(network)
+	if err != nil {
+		return err
+	}
+
+	o.log.WithField("network", network).Info("starting network")
+
+	o.setNetworkStopped(network, false)
+
+	setupTask := o.render.Task(fmt.Sprintf("Setting up %s network", network))
+
+	if err := o.infra.SetupNetwork(ctx, network); err != nil {
+		setupTask.Warning(fmt.Sprintf("Network setup for %s failed (may already be set up)", network))
+		o.log.WithError(err).Warnf("Failed to setup network %s (may already be setup)", network)
+	} else {
+		setupTask.Success(fmt.Sprintf("Network %s configured", network))
+	}
+
+	boundsTask := o.render.Task(fmt.Sprintf("Checking bounds for %s", network))
+
+	if err := o.infra.AutoSeedBoundsIfNeeded(ctx, boundsTask, []config.NetworkConfig{net}); err != nil {
+		boundsTask.Warning("Bounds seeding skipped (CBT will run full scans)")
+		o.log.WithError(err).Warn("Failed to seed bounds from production, CBT will run full scans")
+	} else {
+		boundsTask.Success("Bounds seeding complete")
+	}
+
+	if err := o.GenerateConfigs(ctx); err != nil {
+		return fmt.Errorf("failed to generate configs: %w", err)
+	}
+
+	for _, service := range []string{constants.ServiceNameCBT(network), constants.ServiceNameCBTAPI(network)} {
+		if _, running := o.proc.Get(service); running {
+			continue
+		}
+
+		task := o.render.Task(fmt.Sprintf("Starting %s", service))
+
+		if err := o.StartService(ctx, service); err != nil {
+			task.Fail(fmt.Sprintf("Failed to start %s", service))
+
+			return fmt.Errorf("failed to start %s: %w", service, err)
+		}
+
+		task.Success(fmt.Sprintf("%s started", service))
+	}
+
+	return o.finishNetworkAction(ctx)
+}
+
+// StopNetwork takes one network's cbt and cbt-api services down on a running
+// stack, drops it from lab-backend's network list and restarts lab-backend.
+// With resetBounds the network's Redis database is flushed as well, so the
+// next start re-seeds its bounds. Other networks keep running.
+func (o *Orchestrator) StopNetwork(ctx context.Context, network string, resetBounds bool) error {
+	if _, err := o.checkNetworkAction(network); err != nil {
+		return err
+	}
+
+	running := o.runningNetworks()
+	if len(running) == 1 && running[0].Name == network {
+		return fmt.Errorf("cannot stop %s: it is the only running network; use 'xcli lab down' to stop the stack", network)
+	}
+
+	o.log.WithField("network", network).Info("stopping network")
+
+	for _, service := range []string{constants.ServiceNameCBTAPI(network), constants.ServiceNameCBT(network)} {
+		task := o.render.Task(fmt.Sprintf("Stopping %s", service))
+
+		if err := o.StopService(ctx, service); err != nil {
+			task.Warning(fmt.Sprintf("%s was not running", service))
+			o.log.WithError(err).WithField(logFieldService, service).Debug("service stop failed")
+
+			continue
+		}
+
+		task.Success(fmt.Sprintf("%s stopped", service))
+	}
+
+	if resetBounds {
+		task := o.render.Task(fmt.Sprintf("Resetting Redis bounds for %s", network))
+
+		if err := o.infra.ResetNetworkRedis(ctx, network); err != nil {
+			task.Fail(fmt.Sprintf("Failed to reset Redis bounds for %s", network))
+
+			return err
+		}
+
+		task.Success(fmt.Sprintf("Redis bounds for %s reset", network))
+	}
+
+	o.setNetworkStopped(network, true)
+
+	if err := o.GenerateConfigs(ctx); err != nil {
+		return fmt.Errorf("failed to generate configs: %w", err)
+	}
+
+	return o.finishNetworkAction(ctx)
+}
+
+// ResetNetworkRedis flushes a network's Redis database, as 'xcli lab network
+// remove' does before the network leaves the config, so a network given the
+// same redisDB later does not inherit its bounds.
+func (o *Orchestrator) ResetNetworkRedis(ctx context.Context, network string) error {
+	return o.infra.ResetNetworkRedis(ctx, network)
+}
+
+// checkNetworkAction validates that a network can be started or stopped on
+// its own: it must be enabled and the stack must be running.
+func (o *Orchestrator) checkNetworkAction(network string) (config.NetworkConfig, error) {
+	net, ok := o.cfg.Network(network)
+	if !ok || !net.Enabled {
+		return config.NetworkConfig{}, fmt.Errorf("network %s is not enabled in config", network)
+	}
+
+	if o.runtime == nil || o.runtime.Manifest == nil {
+		return config.NetworkConfig{}, fmt.Errorf("starting or stopping a network requires a lab instance runtime")
+	}
+
+	if _, running := o.proc.Get(constants.ServiceLabBackend); !running {
+		return config.NetworkConfig{}, fmt.Errorf(
+			"lab stack is not running; use 'xcli lab up --network %s' to start it with selected networks", network,
+		)
+	}
+
+	return net, nil
+}
+
+func (o *Orchestrator) setNetworkStopped(network string, stopped bool) {
+	manifest := o.runtime.Manifest
+	manifest.StoppedNetworks = slices.DeleteFunc(manifest.StoppedNetworks, func(name string) bool {
+		return name == network
+	})
+
+	if stopped {
+		manifest.StoppedNetworks = append(manifest.StoppedNetworks, network)
+	}
+}
+
+// finishNetworkAction restarts lab-backend so it picks up the regenerated
+// network list, then records the new network state and PIDs.
+func (o *Orchestrator) finishNetworkAction(ctx context.Context) error {
+	task := o.render.Task("Restarting lab-backend")
+
+	if err := o.Restart(ctx, constants.ServiceLabBackend); err != nil {
+		task.Fail("Failed to restart lab-backend")
+
+		return fmt.Errorf("failed to restart lab-backend: %w", err)
+	}
+
+	task.Success("lab-backend restarted")
+
+	o.runtime.Manifest.PIDs = o.managedPIDs()
+
+	registry, err := o.runtimeRegistry()
+	if err != nil {
+		return err
+	}
+
+	return registry.Save(o.runtime.Manifest)
+}
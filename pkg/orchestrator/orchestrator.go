@@ -19,6 +19,7 @@ import (
 	"github.com/ethpandaops/xcli/pkg/config"
 	"github.com/ethpandaops/xcli/pkg/configgen"
 	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/diagnostic"
 	"github.com/ethpandaops/xcli/pkg/git"
 	"github.com/ethpandaops/xcli/pkg/infrastructure"
 	"github.com/ethpandaops/xcli/pkg/instance"
@@ -52,6 +53,12 @@ type Orchestrator struct {
 	runtime  *instance.Runtime
 	verbose  bool
 	render   ui.Renderer
+
+	incidents *diagnostic.IncidentRecorder
+	usage     *process.UsageSampler
+
+	// upNetworks limits Up to these networks; empty starts all enabled ones.
+	upNetworks []string
 }
 
 // NewOrchestrator creates a new Orchestrator instance.
@@ -113,7 +120,7 @@ func newOrchestrator(
 	}
 
 	// Create mode from config (wrapping LabConfig to get Config)
-	fullConfig := &config.Config{Lab: cfg}
+	fullConfig := &config.Config{Lab: cfg, Xatu: cfg.XatuStack}
 
 	m, err := mode.NewMode(fullConfig)
 	if err != nil {
@@ -125,9 +132,16 @@ func newOrchestrator(
 		return nil, fmt.Errorf("mode validation failed: %w", err)
 	}
 
+	// xatu-local mode discovers the Xatu ClickHouse at runtime; work on a copy
+	// so the discovered endpoint never reaches the loaded, saveable config.
+	if m.UsesXatuStack() {
+		resolved := *cfg
+		cfg = &resolved
+	}
+
 	log.WithField("mode", m.Name()).Info("initialized orchestrator")
 
-	return &Orchestrator{
+	o := &Orchestrator{
 		log:      log.WithField("component", "orchestrator"),
 		cfg:      cfg,
 		mode:     m,
@@ -138,7 +152,18 @@ func newOrchestrator(
 		runtime:  runtime,
 		verbose:  false,
 		render:   ui.NewPlainRenderer(),
-	}, nil
+		usage:    process.NewUsageSampler(),
+	}
+
+	repoRoot := configDir
+	if runtime != nil && runtime.Manifest != nil && runtime.Manifest.RootDir != "" {
+		repoRoot = runtime.Manifest.RootDir
+	}
+
+	o.incidents = o.newIncidentRecorder(repoRoot)
+	o.proc.SetExitHandler(o.handleProcessExit)
+
+	return o, nil
 }
 
 // SetRenderer overrides the renderer used for the Up flow's progress output.
@@ -233,6 +258,8 @@ func (o *Orchestrator) Up(
 
 	o.log.Info("starting lab stack")
 
+	o.applyUpNetworks()
+
 	// Fast prerequisite validation (read-only checks, no fixing)
 	// Fails fast with helpful error if prerequisites not satisfied
 	reportProgress(progress, "prerequisites", "Validating prerequisites...")
@@ -253,6 +280,19 @@ func (o *Orchestrator) Up(
 	if o.mode.NeedsExternalClickHouse() {
 		reportProgress(progress, "external_ch", "Testing external ClickHouse...")
 
+		if o.mode.UsesXatuStack() {
+			task := o.render.Task("Discovering xatu stack ClickHouse")
+
+			xatuURL, err := o.infra.ResolveXatuStack(ctx)
+			if err != nil {
+				task.Fail("Xatu stack ClickHouse not found")
+
+				return fmt.Errorf("failed to find xatu stack ClickHouse: %w", err)
+			}
+
+			task.Success(fmt.Sprintf("Xatu stack ClickHouse at %s", xatuURL))
+		}
+
 		if o.cfg.Infrastructure.ClickHouse.Xatu.ExternalURL == "" {
 			return fmt.Errorf("external ClickHouse URL is required when using hybrid mode")
 		}
@@ -412,7 +452,7 @@ func (o *Orchestrator) Up(
 
 	task := o.render.Task("Setting up networks")
 
-	for _, network := range o.cfg.EnabledNetworks() {
+	for _, network := range o.runningNetworks() {
 		task.UpdateText(fmt.Sprintf("Setting up %s network", network.Name))
 
 		if err := o.infra.SetupNetwork(ctx, network.Name); err != nil {
@@ -425,7 +465,7 @@ func (o *Orchestrator) Up(
 	// Seed bounds from production after networks are configured (admin tables now exist)
 	boundsSpinner := o.render.Task("Checking bounds tables")
 
-	if err := o.infra.AutoSeedBoundsIfNeeded(ctx, boundsSpinner); err != nil {
+	if err := o.infra.AutoSeedBoundsIfNeeded(ctx, boundsSpinner, o.runningNetworks()); err != nil {
 		boundsSpinner.Warning("Bounds seeding skipped (CBT will run full scans)")
 		o.log.WithError(err).Warn("Failed to seed bounds from production, CBT will run full scans")
 	} else {
@@ -533,7 +573,7 @@ func (o *Orchestrator) serviceSummary() []ui.Service {
 		},
 	}
 
-	for _, net := range o.cfg.EnabledNetworks() {
+	for _, net := range o.runningNetworks() {
 		services = append(services, ui.Service{
 			Name:   fmt.Sprintf("CBT API (%s)", net.Name),
 			URL:    o.getServiceURL(constants.ServiceNameCBTAPI(net.Name)),
@@ -592,7 +632,7 @@ func (o *Orchestrator) displayRuntimeSummary() {
 			branch = "unknown"
 		}
 
-		commit := shortCommit(version.Commit)
+		commit := git.ShortCommit(version.Commit)
 		if commit == "" {
 			commit = "unknown"
 		}
@@ -738,14 +778,6 @@ func (o *Orchestrator) runtimeURLs() map[string]string {
 	return urls
 }
 
-func shortCommit(commit string) string {
-	if len(commit) <= 12 {
-		return commit
-	}
-
-	return commit[:12]
-}
-
 // Down stops all services and infrastructure while preserving data.
 // progress is an optional callback for reporting teardown phase updates.
 // Pass nil to disable progress reporting (e.g. from CLI callers).
@@ -759,7 +791,7 @@ func (o *Orchestrator) Down(ctx context.Context, progress ProgressFunc) error {
 
 	o.log.Info("stopping services")
 
-	if err := o.proc.StopAll(ctx); err != nil {
+	if err := o.stopServices(ctx); err != nil {
 		o.log.WithError(err).Warn("failed to stop services")
 		spinner.Warning("Services stopped (with warnings)")
 	} else {
@@ -828,7 +860,7 @@ func (o *Orchestrator) Destroy(ctx context.Context, progress ProgressFunc) error
 	reportProgress(progress, "stop_services", "Stopping services...")
 
 	spinner := o.render.Task("Stopping services")
-	if err := o.proc.StopAll(ctx); err != nil {
+	if err := o.stopServices(ctx); err != nil {
 		o.log.WithError(err).Warn("failed to stop services")
 		spinner.Warning("Services stopped (with warnings)")
 	} else {
@@ -891,6 +923,7 @@ func (o *Orchestrator) ResetRedis(ctx context.Context) error {
 }
 
 // StopServices stops all running services without tearing down infrastructure.
+// Services stop in reverse dependency order.
 // progress is an optional callback for reporting stop phase updates.
 // Pass nil to disable progress reporting (e.g. from CLI callers).
 func (o *Orchestrator) StopServices(ctx context.Context, progress ProgressFunc) error {
@@ -900,7 +933,7 @@ func (o *Orchestrator) StopServices(ctx context.Context, progress ProgressFunc)
 
 	o.log.Info("stopping all services")
 
-	if err := o.proc.StopAll(ctx); err != nil {
+	if err := o.stopServices(ctx); err != nil {
 		spinner.Fail("Failed to stop all services")
 
 		return fmt.Errorf("failed to stop services: %w", err)
@@ -991,7 +1024,7 @@ func (o *Orchestrator) observabilityConfigGenerator() (*configgen.Generator, str
 			userStateDir = o.runtime.Workspace.StateDir
 		}
 
-		return configgen.NewRuntimeGenerator(o.log, o.runtime), configsDir, userStateDir
+		return configgen.NewRuntimeGenerator(o.log, o.cfg, o.runtime), configsDir, userStateDir
 	}
 
 	return configgen.NewGenerator(o.log, o.cfg), filepath.Join(o.stateDir, constants.DirConfigs), o.stateDir
@@ -1129,6 +1162,13 @@ func (o *Orchestrator) Status(ctx context.Context) error {
 	// Show infrastructure status
 	infraStatus := o.infra.Status(ctx)
 
+	containerUsage, err := o.infra.ContainerUsage(ctx)
+	if err != nil {
+		o.log.WithError(err).Debug("container usage unavailable")
+	}
+
+	componentUsage := o.infra.ComponentUsage(containerUsage)
+
 	// Use mode interface to determine if external ClickHouse is used
 	needsExternal := o.mode.NeedsExternalClickHouse()
 
@@ -1145,15 +1185,36 @@ func (o *Orchestrator) Status(ctx context.Context) error {
 			status = statusRunning
 		}
 
-		infraServices = append(infraServices, ui.Service{
+		service := ui.Service{
 			Name:   name,
 			URL:    "-",
 			Status: status,
-		})
+		}
+
+		if usage, ok := componentUsage[name]; ok {
+			service.Usage = formatContainerUsage(usage)
+		}
+
+		infraServices = append(infraServices, service)
 	}
 
-	// In hybrid mode, show external Xatu connection info
-	if needsExternal {
+	switch {
+	case o.mode.UsesXatuStack():
+		// In xatu-local mode, show the xatu stack's ClickHouse
+		service := ui.Service{
+			Name:   "ClickHouse Xatu (xatu stack)",
+			URL:    "-",
+			Status: "down",
+		}
+
+		if xatuURL, err := o.infra.ResolveXatuStack(ctx); err == nil {
+			service.URL = xatuURL
+			service.Status = statusRunning
+		}
+
+		infraServices = append(infraServices, service)
+	case needsExternal:
+		// In hybrid mode, show external Xatu connection info
 		externalInfo := o.sanitizeURL(o.cfg.Infrastructure.ClickHouse.Xatu.ExternalURL)
 		infraServices = append(infraServices, ui.Service{
 			Name:   "ClickHouse Xatu (external)",
@@ -1183,11 +1244,19 @@ func (o *Orchestrator) Status(ctx context.Context) error {
 
 				url := fmt.Sprintf("http://localhost:%d", status.Port)
 
-				obsServices = append(obsServices, ui.Service{
+				service := ui.Service{
 					Name:   name,
 					URL:    url,
 					Status: state,
-				})
+				}
+
+				if container, ok := o.infra.DockerContainerName(name); ok {
+					if usage, ok := containerUsage[container]; ok {
+						service.Usage = formatContainerUsage(usage)
+					}
+				}
+
+				obsServices = append(obsServices, service)
 			}
 
 			ui.StatusPanel("Observability", obsServices)
@@ -1204,15 +1273,22 @@ func (o *Orchestrator) Status(ctx context.Context) error {
 		fmt.Println("  No services running")
 	} else {
 		services := make([]ui.Service, 0, len(processes))
+		serviceUsage := o.ServiceUsage()
 
 		for _, p := range processes {
 			// Determine URL based on service name
 			url := o.getServiceURL(p.Name)
-			services = append(services, ui.Service{
+			service := ui.Service{
 				Name:   p.Name,
 				URL:    url,
 				Status: statusRunning,
-			})
+			}
+
+			if usage, ok := serviceUsage[p.Name]; ok {
+				service.Usage = formatProcessUsage(p, usage)
+			}
+
+			services = append(services, service)
 		}
 
 		ui.StatusPanel("Services", services)
@@ -1248,7 +1324,7 @@ func (o *Orchestrator) Status(ctx context.Context) error {
 // Public method so it can be called by rebuild commands.
 func (o *Orchestrator) GenerateConfigs(ctx context.Context) error {
 	if o.runtime != nil {
-		_, err := configgen.NewRuntimeGenerator(o.log, o.runtime).GenerateRuntimeConfigs()
+		_, err := configgen.NewRuntimeGenerator(o.log, o.cfg, o.runtime).GenerateRuntimeConfigs()
 
 		return err
 	}
@@ -1373,14 +1449,14 @@ func (o *Orchestrator) AreServicesRunning() bool {
 }
 
 // WaitForCBTAPIReady waits for cbt-api services to be ready after restart.
-// Checks the health endpoint of the first enabled network's cbt-api.
+// Checks the health endpoint of the first running network's cbt-api.
 func (o *Orchestrator) WaitForCBTAPIReady(ctx context.Context) error {
-	networks := o.cfg.EnabledNetworks()
+	networks := o.runningNetworks()
 	if len(networks) == 0 {
-		return fmt.Errorf("no networks enabled")
+		return fmt.Errorf("no networks running")
 	}
 
-	// Use the first enabled network's cbt-api.
+	// Use the first running network's cbt-api.
 	port := o.networkPortPlan(networks[0].Name).CBTAPI
 	healthURL := fmt.Sprintf("http://localhost:%d/health", port)
 
@@ -1443,57 +1519,31 @@ func (o *Orchestrator) RestartAllServices(ctx context.Context, verbose bool) err
 		fmt.Printf("Services to restart: %v\n", servicesToRestart)
 	}
 
-	// Stop all target services first
-	for _, serviceName := range servicesToRestart {
-		if verbose {
-			fmt.Printf("Stopping %s...\n", serviceName)
-		}
+	// Stop all target services first, dependents before their dependencies.
+	stopGraph := o.serviceGraph(enabledNetworks).subgraph(servicesToRestart)
 
-		// Use existing process manager Stop method
-		if err := o.proc.Stop(ctx, serviceName); err != nil {
-			// Log warning but continue - service might not be running
-			if verbose {
-				fmt.Printf("Warning: Failed to stop %s: %v\n", serviceName, err)
-			}
-		}
-	}
-
-	// Wait for cleanup
-	time.Sleep(500 * time.Millisecond)
-
-	// Use background context for long-running processes
-	processCtx := context.Background()
-
-	// Restart services in proper order
-	// 1. CBT engines first
-	for _, network := range o.cfg.EnabledNetworks() {
+	if err := stopGraph.walk(ctx, true, func(ctx context.Context, service string) error {
 		if verbose {
-			fmt.Printf("Starting CBT engine for %s...\n", network.Name)
+			fmt.Printf("Stopping %s...\n", service)
 		}
 
-		if err := o.startCBTEngine(processCtx, network.Name); err != nil {
-			return fmt.Errorf("failed to restart CBT engine for %s: %w", network.Name, err)
-		}
+		return o.proc.Stop(ctx, service)
+	}); err != nil && verbose {
+		// Log warning but continue - services might not be running
+		fmt.Printf("Warning: Failed to stop services: %v\n", err)
 	}
 
-	// 2. cbt-api second
-	for _, network := range o.cfg.EnabledNetworks() {
-		if verbose {
-			fmt.Printf("Starting cbt-api for %s...\n", network.Name)
-		}
+	// Start the running networks' services again in dependency order.
+	startGraph := o.serviceGraph(o.runningNetworks()).subgraph(servicesToRestart)
 
-		if err := o.startCBTAPI(processCtx, network.Name); err != nil {
-			return fmt.Errorf("failed to restart cbt-api for %s: %w", network.Name, err)
+	if err := startGraph.walk(ctx, false, func(ctx context.Context, service string) error {
+		if verbose {
+			fmt.Printf("Starting %s...\n", service)
 		}
-	}
-
-	// 3. lab-backend last
-	if verbose {
-		fmt.Printf("Starting lab-backend...\n")
-	}
 
-	if err := o.startLabBackend(processCtx); err != nil {
-		return fmt.Errorf("failed to restart lab-backend: %w", err)
+		return o.StartService(ctx, service)
+	}); err != nil {
+		return fmt.Errorf("failed to restart services: %w", err)
 	}
 
 	if verbose {
@@ -1571,7 +1621,7 @@ func (o *Orchestrator) checkGitStatus(ctx context.Context) {
 		}
 
 		o.render.GitStatusTable(gitStatuses)
-		o.render.Info("Consider running 'git pull' in the affected repositories.")
+		o.render.Info("Run 'xcli lab repos pull' to fast-forward the affected repositories.")
 		o.render.Blank()
 	} else {
 		spinner.Success("All repositories are up to date")
@@ -1917,39 +1967,13 @@ func (o *Orchestrator) copyCustomConfig(customPath, destPath string) error {
 	return nil
 }
 
-// startServices starts all service processes.
+// startServices starts all service processes. Each service starts as soon as
+// the services and infrastructure it depends on are healthy, so independent
+// services (such as the CBT engines of different networks) start in parallel.
 func (o *Orchestrator) startServices(ctx context.Context) error {
 	o.log.Info("starting services")
 
-	// Use background context for long-running processes
-	// The parent context is only for the startup phase
-	processCtx := context.Background()
-
-	// Start CBT engines for each network
-	for _, network := range o.cfg.EnabledNetworks() {
-		if err := o.startCBTEngine(processCtx, network.Name); err != nil {
-			return fmt.Errorf("failed to start CBT engine for %s: %w", network.Name, err)
-		}
-	}
-
-	// Start cbt-api for each network
-	for _, network := range o.cfg.EnabledNetworks() {
-		if err := o.startCBTAPI(processCtx, network.Name); err != nil {
-			return fmt.Errorf("failed to start cbt-api for %s: %w", network.Name, err)
-		}
-	}
-
-	// Start lab-backend
-	if err := o.startLabBackend(processCtx); err != nil {
-		return fmt.Errorf("failed to start lab-backend: %w", err)
-	}
-
-	// Start lab frontend
-	if err := o.startLabFrontend(processCtx); err != nil {
-		return fmt.Errorf("failed to start lab frontend: %w", err)
-	}
-
-	return nil
+	return o.serviceGraph(o.runningNetworks()).walk(ctx, false, o.startNode)
 }
 
 // startCBTEngine starts a CBT engine for a network.
@@ -1973,7 +1997,7 @@ func (o *Orchestrator) startCBTEngine(ctx context.Context, network string) error
 
 	cmd.Env = append(os.Environ(), fmt.Sprintf("NETWORK=%s", network))
 
-	return o.proc.Start(ctx, constants.ServiceNameCBT(network), cmd, nil)
+	return o.startProcess(ctx, constants.ServiceNameCBT(network), cmd)
 }
 
 // startCBTAPI starts cbt-api for a network.
@@ -1999,7 +2023,7 @@ func (o *Orchestrator) startCBTAPI(ctx context.Context, network string) error {
 	cmd := exec.CommandContext(ctx, apiBinary, "--config", configPath)
 	cmd.Dir = o.cfg.Repos.CBTAPI
 
-	return o.proc.Start(ctx, constants.ServiceNameCBTAPI(network), cmd, nil)
+	return o.startProcess(ctx, constants.ServiceNameCBTAPI(network), cmd)
 }
 
 // startLabBackend starts lab-backend.
@@ -2030,7 +2054,7 @@ func (o *Orchestrator) startLabBackend(ctx context.Context) error {
 	cmd := exec.CommandContext(ctx, backendBinary, "--config", configPath)
 	cmd.Dir = o.cfg.Repos.LabBackend
 
-	return o.proc.Start(ctx, constants.ServiceLabBackend, cmd, nil)
+	return o.startProcess(ctx, constants.ServiceLabBackend, cmd)
 }
 
 // startLabFrontend starts the lab frontend dev server.
@@ -2074,7 +2098,7 @@ func (o *Orchestrator) startLabFrontend(ctx context.Context) error {
 		fmt.Sprintf("BACKEND=http://localhost:%d", plan.LabBackend),
 	)
 
-	return o.proc.Start(ctx, constants.ServiceLabFrontend, cmd, nil)
+	return o.startProcess(ctx, constants.ServiceLabFrontend, cmd)
 }
 
 // reportProgress calls the progress callback if non-nil.
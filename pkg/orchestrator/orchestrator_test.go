@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -109,6 +110,132 @@ func TestFinalizeRuntimeManifestWritesRunningInstance(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestUpNetworksSelectionStopsOtherNetworks(t *testing.T) {
+	runtime := newOrchestratorTestRuntime(t, "iota", 1)
+	runtime.LabConfig.Networks = append(runtime.LabConfig.Networks,
+		config.NetworkConfig{Name: "sepolia", Enabled: true, PortOffset: 1},
+		config.NetworkConfig{Name: "hoodi", Enabled: false, PortOffset: 2},
+	)
+
+	orch, err := NewOrchestratorWithRuntime(logrus.New(), runtime)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, orch.SetUpNetworks([]string{"hoodi"}), "network hoodi is not enabled")
+	require.NoError(t, orch.SetUpNetworks([]string{"sepolia"}))
+
+	orch.applyUpNetworks()
+	require.Equal(t, []string{"mainnet"}, runtime.Manifest.StoppedNetworks)
+	require.Equal(t, []string{"sepolia"}, networkNames(orch.runningNetworks()))
+
+	// Redis databases follow the configured networks, not the running ones.
+	redisDB, ok := runtime.LabConfig.RedisDB("sepolia")
+	require.True(t, ok)
+	require.Equal(t, 1, redisDB)
+
+	// Stopping or starting a single network needs a running stack.
+	orch.proc = &fakeProcessManager{}
+	require.ErrorContains(t, orch.StartNetwork(context.Background(), "mainnet"), "lab stack is not running")
+
+	orch.proc = &fakeProcessManager{
+		processes: []*process.Process{{Name: constants.ServiceLabBackend, PID: 1234}},
+	}
+	require.ErrorContains(t, orch.StopNetwork(context.Background(), "sepolia", false), "only running network")
+	require.ErrorContains(t, orch.StopNetwork(context.Background(), "hoodi", false), "network hoodi is not enabled")
+
+	// A plain Up starts every enabled network again.
+	require.NoError(t, orch.SetUpNetworks(nil))
+	orch.applyUpNetworks()
+	require.Empty(t, runtime.Manifest.StoppedNetworks)
+	require.Equal(t, []string{"mainnet", "sepolia"}, networkNames(orch.runningNetworks()))
+}
+
+func networkNames(networks []config.NetworkConfig) []string {
+	names := make([]string, 0, len(networks))
+	for _, net := range networks {
+		names = append(names, net.Name)
+	}
+
+	return names
+}
+
+func TestServiceGraphOrdersServicesByDependency(t *testing.T) {
+	runtime := newOrchestratorTestRuntime(t, "kappa", 0)
+	runtime.LabConfig.Networks = append(runtime.LabConfig.Networks,
+		config.NetworkConfig{Name: "sepolia", Enabled: true, PortOffset: 1},
+	)
+
+	orch, err := NewOrchestratorWithRuntime(logrus.New(), runtime)
+	require.NoError(t, err)
+
+	g := orch.serviceGraph(orch.runningNetworks())
+
+	order, err := g.sorted()
+	require.NoError(t, err)
+
+	position := make(map[string]int, len(order))
+	for i, node := range order {
+		position[node] = i
+	}
+
+	for node, deps := range g.deps {
+		for _, dep := range deps {
+			require.Less(t, position[dep], position[node], "%s must start after %s", node, dep)
+		}
+	}
+
+	require.Equal(t, []string{
+		"cbt-api-mainnet",
+		constants.ServiceLabBackend,
+		constants.ServiceLabFrontend,
+	}, g.dependents("cbt-mainnet"))
+	require.Empty(t, g.dependents(constants.ServiceLabFrontend))
+
+	// Starting waits for dependencies; independent services overlap, and a
+	// failure skips only the services that depend on it.
+	var (
+		mu      sync.Mutex
+		started []string
+	)
+
+	sub := g.subgraph([]string{"cbt-mainnet", "cbt-sepolia", "cbt-api-mainnet", "cbt-api-sepolia", constants.ServiceLabBackend})
+	err = sub.walk(context.Background(), false, func(_ context.Context, node string) error {
+		if node == "cbt-sepolia" {
+			return fmt.Errorf("boom")
+		}
+
+		mu.Lock()
+		started = append(started, node)
+		mu.Unlock()
+
+		return nil
+	})
+	require.ErrorContains(t, err, "cbt-sepolia: boom")
+	require.ElementsMatch(t, []string{"cbt-mainnet", "cbt-api-mainnet"}, started)
+
+	// Stopping runs in reverse and carries on past failures.
+	var stopped []string
+
+	err = sub.walk(context.Background(), true, func(_ context.Context, node string) error {
+		mu.Lock()
+		stopped = append(stopped, node)
+		mu.Unlock()
+
+		if node == constants.ServiceLabBackend {
+			return fmt.Errorf("still running")
+		}
+
+		return nil
+	})
+	require.ErrorContains(t, err, "lab-backend: still running")
+	require.Len(t, stopped, 5)
+	require.Equal(t, constants.ServiceLabBackend, stopped[0])
+
+	cyclic := newServiceGraph()
+	cyclic.add("a", "b")
+	cyclic.add("b", "a")
+	require.ErrorContains(t, cyclic.walk(context.Background(), false, nil), "dependency cycle between a, b")
+}
+
 func TestMarkRuntimeStoppedPersistsStoppedManifest(t *testing.T) {
 	runtime := newOrchestratorTestRuntime(t, "stopped", 0)
 	runtime.Manifest.Status = instance.StatusRunning
@@ -333,6 +460,7 @@ type fakeProcessManager struct {
 	processes []*process.Process
 	lastName  string
 	lastCmd   *exec.Cmd
+	limits    map[string]process.Limits
 }
 
 func (f *fakeProcessManager) Start(
@@ -358,6 +486,13 @@ func (f *fakeProcessManager) StopAll(context.Context) error      { return nil }
 func (f *fakeProcessManager) Restart(context.Context, string) error {
 	return nil
 }
+func (f *fakeProcessManager) SetLimits(name string, limits process.Limits) {
+	if f.limits == nil {
+		f.limits = make(map[string]process.Limits)
+	}
+
+	f.limits[name] = limits
+}
 func (f *fakeProcessManager) List() []*process.Process { return f.processes }
 func (f *fakeProcessManager) Get(name string) (*process.Process, bool) {
 	for _, proc := range f.processes {
@@ -374,5 +509,6 @@ func (f *fakeProcessManager) IsRunning(name string) bool {
 	return ok
 }
 func (f *fakeProcessManager) ReloadPIDs()                                  {}
+func (f *fakeProcessManager) SetExitHandler(process.ExitHandler)           {}
 func (f *fakeProcessManager) TailLogs(context.Context, string, bool) error { return nil }
 func (f *fakeProcessManager) CleanLogs() error                             { return nil }
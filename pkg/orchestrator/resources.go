@@ -0,0 +1,98 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/ethpandaops/xcli/pkg/infrastructure"
+	"github.com/ethpandaops/xcli/pkg/process"
+	"github.com/ethpandaops/xcli/pkg/ui"
+)
+
+// startProcess starts a service process under its configured resource
+// limits and waits for its health check.
+func (o *Orchestrator) startProcess(ctx context.Context, service string, cmd *exec.Cmd) error {
+	o.proc.SetLimits(service, o.processLimits(service))
+
+	return o.proc.Start(ctx, service, cmd, o.serviceHealthCheck(service))
+}
+
+// processLimits converts a service's configured resource limits.
+func (o *Orchestrator) processLimits(service string) process.Limits {
+	limits := o.cfg.ResourceLimits(service)
+
+	return process.Limits{
+		CPUs:        limits.CPUs,
+		MemoryBytes: limits.MemoryBytes(),
+		Nice:        limits.Nice,
+	}
+}
+
+// ServiceUsage samples the resource usage of every managed process, keyed by
+// service name. It returns an empty map where /proc is not available.
+func (o *Orchestrator) ServiceUsage() map[string]process.Usage {
+	processes := o.proc.List()
+
+	pids := make([]int, 0, len(processes))
+	for _, p := range processes {
+		pids = append(pids, p.PID)
+	}
+
+	samples, err := o.usage.Sample(pids)
+	if err != nil {
+		o.log.WithError(err).Debug("resource usage sampling unavailable")
+
+		return map[string]process.Usage{}
+	}
+
+	usage := make(map[string]process.Usage, len(processes))
+
+	for _, p := range processes {
+		if sample, ok := samples[p.PID]; ok {
+			usage[p.Name] = sample
+		}
+	}
+
+	return usage
+}
+
+// MemoryLimit returns the memory cap enforced on a running process, or 0 when
+// it has none. Processes loaded from PID files report the limits they were
+// started with.
+func MemoryLimit(p *process.Process) uint64 {
+	if p.LimitMode != process.LimitModeCgroup {
+		return 0
+	}
+
+	return uint64(max(p.Limits.MemoryBytes, 0)) //nolint:gosec // clamped to non-negative
+}
+
+// formatProcessUsage renders a process usage sample for status output, with
+// the enforced limits and how they are enforced.
+func formatProcessUsage(p *process.Process, usage process.Usage) string {
+	cgroup := p.LimitMode == process.LimitModeCgroup
+
+	text := fmt.Sprintf("cpu %.1f%%", usage.CPUPercent)
+	if cgroup && p.Limits.CPUs > 0 {
+		text += fmt.Sprintf("/%.0f%%", p.Limits.CPUs*100)
+	}
+
+	text += "  mem " + ui.FormatBytes(usage.RSSBytes)
+	if limit := MemoryLimit(p); limit > 0 {
+		text += "/" + ui.FormatBytes(limit)
+	}
+
+	text += fmt.Sprintf("  fds %d", usage.OpenFiles)
+
+	if p.LimitMode == process.LimitModeNice {
+		text += "  (niced)"
+	}
+
+	return text
+}
+
+// formatContainerUsage renders a docker stats sample for status output.
+func formatContainerUsage(usage infrastructure.ContainerUsage) string {
+	return fmt.Sprintf("cpu %.1f%%  mem %s", usage.CPUPercent, ui.FormatBytes(usage.MemoryBytes))
+}
@@ -3,6 +3,7 @@
 package portutil
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -89,6 +90,68 @@ func FormatConflicts(conflicts []PortConflict) string {
 	return sb.String()
 }
 
+// ListeningPorts returns the owner of every listening TCP port using a single
+// lsof call, keyed by port. It returns an error when lsof is unavailable.
+func ListeningPorts() (map[int]PortConflict, error) {
+	cmd := exec.Command("lsof", "-nP", "-iTCP", "-sTCP:LISTEN", "-F", "pcn")
+
+	output, err := cmd.Output()
+	if err != nil {
+		// lsof exits 1 when nothing is listening.
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 && len(output) == 0 {
+			return map[int]PortConflict{}, nil
+		}
+
+		return nil, fmt.Errorf("failed to list listening ports: %w", err)
+	}
+
+	return parseLsofListeners(string(output)), nil
+}
+
+// parseLsofListeners parses `lsof -F pcn` output: a "p<pid>" line starts each
+// process, followed by "c<command>" and one "n<address>:<port>" per socket.
+func parseLsofListeners(output string) map[int]PortConflict {
+	ports := make(map[int]PortConflict)
+
+	var (
+		pid     int
+		process string
+	)
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		value := line[1:]
+
+		switch line[0] {
+		case 'p':
+			pid, _ = strconv.Atoi(value)
+			process = ""
+		case 'c':
+			process = value
+		case 'n':
+			idx := strings.LastIndex(value, ":")
+			if idx < 0 {
+				continue
+			}
+
+			port, err := strconv.Atoi(value[idx+1:])
+			if err != nil {
+				continue
+			}
+
+			if _, seen := ports[port]; !seen {
+				ports[port] = PortConflict{Port: port, PID: pid, Process: process}
+			}
+		}
+	}
+
+	return ports
+}
+
 // KillProcess attempts to kill a process by PID.
 // Sends SIGTERM first, then SIGKILL if needed.
 func KillProcess(pid int) error {
@@ -0,0 +1,17 @@
+package portutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLsofListeners(t *testing.T) {
+	output := "p101\ncgrafana\nn*:3000\nn[::1]:3000\np202\ncdocker-proxy\nn127.0.0.1:8123\nn[::]:9000\n"
+
+	ports := parseLsofListeners(output)
+	require.Len(t, ports, 3)
+	require.Equal(t, PortConflict{Port: 3000, PID: 101, Process: "grafana"}, ports[3000])
+	require.Equal(t, 202, ports[8123].PID)
+	require.Equal(t, "docker-proxy", ports[9000].Process)
+}
@@ -23,6 +23,11 @@ type Manager interface {
 	// Restart restarts a process (stops then starts with same command).
 	Restart(ctx context.Context, name string) error
 
+	// SetLimits sets the resource limits applied when a process is next
+	// started, through a cgroup v2 scope where available and nice/ionice
+	// otherwise.
+	SetLimits(name string, limits Limits)
+
 	// List returns all currently managed processes.
 	List() []*Process
 
@@ -35,6 +40,10 @@ type Manager interface {
 	// ReloadPIDs re-scans the PID directory for new or removed processes.
 	ReloadPIDs()
 
+	// SetExitHandler registers a callback for processes that exit without
+	// being stopped through the manager.
+	SetExitHandler(fn ExitHandler)
+
 	// TailLogs tails logs for a process.
 	TailLogs(ctx context.Context, name string, follow bool) error
 
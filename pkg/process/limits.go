@@ -0,0 +1,141 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limit enforcement modes reported on a Process.
+const (
+	// LimitModeCgroup means the process runs in a cgroup v2 scope created
+	// with systemd-run, so CPU and memory are capped by the kernel.
+	LimitModeCgroup = "cgroup"
+	// LimitModeNice means cgroups were unavailable and the process only runs
+	// at a lower CPU and I/O priority.
+	LimitModeNice = "nice"
+)
+
+// fallbackNice is the niceness used when a CPU cap cannot be enforced.
+const fallbackNice = 10
+
+// Limits caps the resources of a managed process.
+type Limits struct {
+	// CPUs is the CPU quota in cores; 0 means unlimited.
+	CPUs float64
+	// MemoryBytes is the memory cap; 0 means unlimited.
+	MemoryBytes int64
+	// Nice is the scheduling niceness; 0 keeps the default.
+	Nice int
+}
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l.CPUs == 0 && l.MemoryBytes == 0 && l.Nice == 0
+}
+
+var (
+	cgroupScopesOnce      sync.Once
+	cgroupScopesAvailable bool
+)
+
+// cgroupScopes reports whether processes can be placed in transient cgroup
+// v2 scopes: the unified hierarchy must be mounted and the user's systemd
+// instance must accept systemd-run --user --scope.
+func cgroupScopes() bool {
+	cgroupScopesOnce.Do(func() {
+		if _, err := os.Stat("/sys/fs/cgroup/cgroup.controllers"); err != nil {
+			return
+		}
+
+		path, err := exec.LookPath("systemd-run")
+		if err != nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		//nolint:gosec // G204: probe with a fixed command
+		cgroupScopesAvailable = exec.CommandContext(ctx, path, "--user", "--scope", "--quiet", "true").Run() == nil
+	})
+
+	return cgroupScopesAvailable
+}
+
+// applyLimits rewrites cmd so it runs under limits and returns how they are
+// enforced. systemd-run --scope, nice and ionice all exec the command in
+// place, so the PID still belongs to the service.
+func applyLimits(cmd *exec.Cmd, limits Limits) string {
+	if limits.IsZero() {
+		return ""
+	}
+
+	var (
+		prefix []string
+		mode   string
+	)
+
+	if (limits.CPUs > 0 || limits.MemoryBytes > 0) && cgroupScopes() {
+		mode = LimitModeCgroup
+		prefix = []string{"systemd-run", "--user", "--scope", "--quiet", "--collect"}
+
+		if limits.CPUs > 0 {
+			prefix = append(prefix, "-p", fmt.Sprintf("CPUQuota=%d%%", max(int(limits.CPUs*100), 1)))
+		}
+
+		if limits.MemoryBytes > 0 {
+			prefix = append(prefix, "-p", "MemoryMax="+strconv.FormatInt(limits.MemoryBytes, 10))
+		}
+
+		prefix = append(prefix, "--")
+	}
+
+	// Without cgroups a CPU or memory cap degrades to a lower CPU and I/O
+	// priority.
+	fallback := mode == "" && (limits.CPUs > 0 || limits.MemoryBytes > 0)
+
+	nice := limits.Nice
+	if fallback && nice == 0 {
+		nice = fallbackNice
+	}
+
+	if nice > 0 {
+		if _, err := exec.LookPath("nice"); err == nil {
+			prefix = append(prefix, "nice", "-n", strconv.Itoa(nice))
+		}
+	}
+
+	if fallback {
+		if _, err := exec.LookPath("ionice"); err == nil {
+			prefix = append(prefix, "ionice", "-c", "2", "-n", "7")
+		}
+	}
+
+	if len(prefix) == 0 {
+		return ""
+	}
+
+	if mode == "" {
+		mode = LimitModeNice
+	}
+
+	wrapper, err := exec.LookPath(prefix[0])
+	if err != nil {
+		return ""
+	}
+
+	args := make([]string, 0, len(prefix)+len(cmd.Args))
+	args = append(args, prefix...)
+	args = append(args, cmd.Path)
+	args = append(args, cmd.Args[1:]...)
+
+	cmd.Path = wrapper
+	cmd.Args = args
+
+	return mode
+}
@@ -22,7 +22,7 @@ const (
 	gracefulShutdownTimeout = 30 * time.Second
 	// shutdownPollInterval is how often to check if a process has stopped.
 	shutdownPollInterval = 100 * time.Millisecond
-	pidFileVersion       = 1
+	pidFileVersion       = 2
 
 	// logFieldName is the structured log field key for a process name.
 	logFieldName = "name"
@@ -40,17 +40,51 @@ type Process struct {
 	PID     int
 	LogFile string
 	Started time.Time
+	// LimitMode tells how resource limits are enforced (LimitModeCgroup or
+	// LimitModeNice), or is empty when the process runs unconstrained.
+	LimitMode string
+	// Limits are the resource limits the process was started with.
+	Limits Limits
+
+	// path and args are the command before limits wrapped it, used by Restart.
+	path string
+	args []string
+
+	// stopRequested is set by Stop so the exit is not reported as unexpected.
+	stopRequested bool
 }
 
+// ExitEvent describes a managed process that exited without being stopped
+// through the manager.
+type ExitEvent struct {
+	Name    string
+	PID     int
+	LogFile string
+	// ExitCode is the process exit code, or -1 when it is unknown (the process
+	// was loaded from a PID file) or the process was killed by a signal.
+	ExitCode int
+	// Err describes how the process exited, if known.
+	Err error
+}
+
+// ExitHandler is called when a managed process exits unexpectedly.
+type ExitHandler func(event ExitEvent)
+
 // PIDFileData represents the JSON structure of a persisted PID file
 // containing process metadata for crash recovery and monitoring.
 type PIDFileData struct {
-	Version   int       `json:"version"` // Format version (currently 1)
+	Version   int       `json:"version"` // Format version (currently 2)
 	PID       int       `json:"pid"`
 	LogFile   string    `json:"logFile"`
 	Command   string    `json:"command"`   // Binary path
 	Args      []string  `json:"args"`      // Command arguments
 	StartedAt time.Time `json:"startedAt"` // ISO8601 timestamp
+
+	// Resource limits the process was started with (version 2).
+	LimitMode   string  `json:"limitMode,omitempty"`
+	CPUs        float64 `json:"cpus,omitempty"`
+	MemoryBytes int64   `json:"memoryBytes,omitempty"`
+	Nice        int     `json:"nice,omitempty"`
 }
 
 // manager implements the Manager interface.
@@ -58,6 +92,8 @@ type manager struct {
 	log       logrus.FieldLogger
 	processes map[string]*Process
 	stateDir  string
+	onExit    ExitHandler
+	limits    map[string]Limits
 	mu        sync.RWMutex
 }
 
@@ -66,6 +102,7 @@ func NewManager(log logrus.FieldLogger, stateDir string) Manager {
 	m := &manager{
 		log:       log.WithField("component", "process-manager"),
 		processes: make(map[string]*Process, 10), // Typical: 5-10 services
+		limits:    make(map[string]Limits),
 		stateDir:  stateDir,
 	}
 
@@ -77,14 +114,54 @@ func NewManager(log logrus.FieldLogger, stateDir string) Manager {
 
 // Start starts a new process with optional health checking.
 // If healthCheck is nil, uses NoOpHealthChecker (existing behavior).
+// The health check runs without holding the manager lock, so several
+// processes can be started and checked in parallel.
 func (m *manager) Start(ctx context.Context, name string, cmd *exec.Cmd, healthCheck HealthChecker) error {
+	process, logFd, err := m.launch(name, cmd)
+	if err != nil {
+		return err
+	}
+
+	// Monitor process in background
+	go m.monitor(name, process, logFd)
+
+	// Default to no-op health checker if none provided
+	if healthCheck == nil {
+		healthCheck = &NoOpHealthChecker{}
+	}
+
+	// Run health check after starting
+	m.log.WithFields(logrus.Fields{
+		logFieldName:   name,
+		logFieldPID:    process.PID,
+		"health_check": healthCheck.Name(),
+	}).Debug("running health check")
+
+	if err := healthCheck.Check(ctx); err != nil {
+		// Health check failed - kill the process
+		m.log.WithError(err).Warn("health check failed, stopping process")
+
+		if stopErr := m.Stop(context.WithoutCancel(ctx), name); stopErr != nil {
+			m.log.WithError(stopErr).Warn("failed to stop unhealthy process")
+		}
+
+		return fmt.Errorf("health check failed: %w", err)
+	}
+
+	m.log.WithField(logFieldName, name).Info("process started and healthy")
+
+	return nil
+}
+
+// launch starts cmd and registers it under name.
+func (m *manager) launch(name string, cmd *exec.Cmd) (*Process, *os.File, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Check if already running
 	if p, exists := m.processes[name]; exists {
 		if m.isRunning(p) {
-			return fmt.Errorf("process %s is already running (PID %d)", name, p.PID)
+			return nil, nil, fmt.Errorf("process %s is already running (PID %d)", name, p.PID)
 		}
 		// Clean up stale entry
 		delete(m.processes, name)
@@ -93,12 +170,12 @@ func (m *manager) Start(ctx context.Context, name string, cmd *exec.Cmd, healthC
 	// Setup log file - truncate to start fresh
 	logFile := filepath.Join(m.stateDir, constants.DirLogs, fmt.Sprintf(constants.LogFileTemplate, name))
 	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
-		return fmt.Errorf("failed to create log directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
 	logFd, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
+		return nil, nil, fmt.Errorf("failed to open log file: %w", err)
 	}
 
 	// Setup stdout/stderr - only write to log file to avoid broken pipe when parent exits
@@ -108,68 +185,66 @@ func (m *manager) Start(ctx context.Context, name string, cmd *exec.Cmd, healthC
 	// Put child in its own process group so it survives parent (CC server) dying
 	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
+	path, args := cmd.Path, cmd.Args
+	limits := m.limits[name]
+
+	limitMode := applyLimits(cmd, limits)
+	if limitMode == "" {
+		limits = Limits{}
+	}
+
 	// Start the process
 	if err := cmd.Start(); err != nil {
 		logFd.Close()
 
-		return fmt.Errorf("failed to start process: %w", err)
+		return nil, nil, fmt.Errorf("failed to start process: %w", err)
 	}
 
 	process := &Process{
-		Name:    name,
-		Cmd:     cmd,
-		PID:     cmd.Process.Pid,
-		LogFile: logFile,
-		Started: time.Now(),
+		Name:      name,
+		Cmd:       cmd,
+		PID:       cmd.Process.Pid,
+		LogFile:   logFile,
+		Started:   time.Now(),
+		LimitMode: limitMode,
+		Limits:    limits,
+		path:      path,
+		args:      args,
 	}
 
 	m.processes[name] = process
 
-	// Save PID to disk (JSON format)
-	m.savePID(name, process, cmd)
-
-	// Default to no-op health checker if none provided
-	if healthCheck == nil {
-		healthCheck = &NoOpHealthChecker{}
-	}
-
-	// Run health check after starting
-	m.log.WithFields(logrus.Fields{
-		logFieldName:   name,
-		logFieldPID:    process.PID,
-		"health_check": healthCheck.Name(),
-	}).Debug("running health check")
-
-	if err := healthCheck.Check(ctx); err != nil {
-		// Health check failed - kill the process
-		m.log.WithError(err).Warn("health check failed, stopping process")
-
-		if stopErr := m.Stop(ctx, name); stopErr != nil {
-			m.log.WithError(stopErr).Warn("failed to stop unhealthy process")
-		}
-
-		return fmt.Errorf("health check failed: %w", err)
+	if limitMode != "" {
+		m.log.WithFields(logrus.Fields{
+			logFieldName: name,
+			"limits":     limitMode,
+		}).Debug("process started with resource limits")
 	}
 
-	m.log.WithField(logFieldName, name).Info("process started and healthy")
-
-	// Monitor process in background
-	go m.monitor(name, process, logFd)
+	// Save PID to disk (JSON format)
+	m.savePID(name, process)
 
-	return nil
+	return process, logFd, nil
 }
 
-// Stop stops a process gracefully.
+// Stop stops a process gracefully. The manager lock is only held while the
+// process is looked up and forgotten, so several processes can be stopped in
+// parallel.
 func (m *manager) Stop(ctx context.Context, name string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 
 	p, exists := m.processes[name]
 	if !exists {
+		m.mu.Unlock()
+
 		// Process is already stopped - goal achieved, return success
 		return nil
 	}
 
+	p.stopRequested = true
+
+	m.mu.Unlock()
+
 	m.log.WithFields(logrus.Fields{
 		logFieldName: name,
 		logFieldPID:  p.PID,
@@ -209,8 +284,7 @@ func (m *manager) Stop(ctx context.Context, name string) error {
 				m.log.WithError(err).Warn("failed to kill process")
 			}
 
-			delete(m.processes, name)
-			m.removePID(name)
+			m.forget(name, p)
 
 			return ctx.Err()
 		case <-timeout:
@@ -223,15 +297,13 @@ func (m *manager) Stop(ctx context.Context, name string) error {
 				return fmt.Errorf("failed to kill process: %w", err)
 			}
 
-			delete(m.processes, name)
-			m.removePID(name)
+			m.forget(name, p)
 
 			return nil
 		case <-ticker.C:
-			if err := process.Signal(syscall.Signal(0)); err != nil {
+			if !m.isRunning(p) {
 				// Process is gone
-				delete(m.processes, name)
-				m.removePID(name)
+				m.forget(name, p)
 
 				return nil
 			}
@@ -239,6 +311,18 @@ func (m *manager) Stop(ctx context.Context, name string) error {
 	}
 }
 
+// forget drops a stopped process and its PID file, unless a new process has
+// been registered under the same name in the meantime.
+func (m *manager) forget(name string, p *Process) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.processes[name]; ok && current == p {
+		delete(m.processes, name)
+		m.removePID(name)
+	}
+}
+
 // StopAll stops all managed processes, including orphaned processes from PID files.
 func (m *manager) StopAll(ctx context.Context) error {
 	m.log.Info("stopping all managed processes")
@@ -319,8 +403,9 @@ func (m *manager) Restart(ctx context.Context, name string) error {
 		return fmt.Errorf("cannot restart process %s: loaded from PID file without command info. Stop and restart the entire stack instead", name)
 	}
 
-	// Copy the command for restart
+	// Copy the command for restart, without the limits wrapper Start adds
 	oldCmd := p.Cmd
+	path, args := p.path, p.args
 
 	m.mu.RUnlock()
 
@@ -334,7 +419,7 @@ func (m *manager) Restart(ctx context.Context, name string) error {
 
 	// Create new command with same args
 	//nolint:gosec // Command is from previously validated process
-	newCmd := exec.CommandContext(ctx, oldCmd.Path, oldCmd.Args[1:]...)
+	newCmd := exec.CommandContext(ctx, path, args[1:]...)
 	newCmd.Dir = oldCmd.Dir
 	newCmd.Env = oldCmd.Env
 
@@ -342,6 +427,21 @@ func (m *manager) Restart(ctx context.Context, name string) error {
 	return m.Start(ctx, name, newCmd, nil)
 }
 
+// SetLimits sets the resource limits applied when the named process is next
+// started. Zero limits remove them.
+func (m *manager) SetLimits(name string, limits Limits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if limits.IsZero() {
+		delete(m.limits, name)
+
+		return
+	}
+
+	m.limits[name] = limits
+}
+
 // List returns all running processes.
 func (m *manager) List() []*Process {
 	m.mu.RLock()
@@ -433,10 +533,28 @@ func (m *manager) CleanLogs() error {
 // Safe to call concurrently. Discovers processes started externally (e.g. by `xcli lab up`)
 // and removes stale entries for processes that have exited.
 func (m *manager) ReloadPIDs() {
+	m.mu.Lock()
+	exited := m.loadPIDsLocked()
+	onExit := m.onExit
+	m.mu.Unlock()
+
+	if onExit == nil {
+		return
+	}
+
+	for _, event := range exited {
+		onExit(event)
+	}
+}
+
+// SetExitHandler registers fn to be called when a managed process exits
+// without being stopped through the manager. Processes loaded from PID files
+// are only noticed as exited on the next ReloadPIDs.
+func (m *manager) SetExitHandler(fn ExitHandler) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.loadPIDsLocked()
+	m.onExit = fn
 }
 
 // isRunning checks if a process is actually running.
@@ -466,11 +584,17 @@ func (m *manager) monitor(name string, p *Process, logFd *os.File) {
 	err := p.Cmd.Wait()
 
 	m.mu.Lock()
-	delete(m.processes, name)
-	m.mu.Unlock()
 
-	// Remove PID file
-	m.removePID(name)
+	stopRequested := p.stopRequested
+	onExit := m.onExit
+
+	// A restart may already have registered a new process under this name.
+	if current, ok := m.processes[name]; ok && current == p {
+		delete(m.processes, name)
+		m.removePID(name)
+	}
+
+	m.mu.Unlock()
 
 	if err != nil {
 		m.log.WithFields(logrus.Fields{
@@ -484,10 +608,27 @@ func (m *manager) monitor(name string, p *Process, logFd *os.File) {
 			logFieldPID:  p.PID,
 		}).Info("Process exited")
 	}
+
+	if stopRequested || onExit == nil {
+		return
+	}
+
+	exitCode := -1
+	if p.Cmd.ProcessState != nil {
+		exitCode = p.Cmd.ProcessState.ExitCode()
+	}
+
+	onExit(ExitEvent{
+		Name:     name,
+		PID:      p.PID,
+		LogFile:  p.LogFile,
+		ExitCode: exitCode,
+		Err:      err,
+	})
 }
 
 // savePID saves a process PID to disk in JSON format.
-func (m *manager) savePID(name string, p *Process, cmd *exec.Cmd) {
+func (m *manager) savePID(name string, p *Process) {
 	pidDir := filepath.Join(m.stateDir, constants.DirPIDs)
 	if err := os.MkdirAll(pidDir, 0755); err != nil {
 		m.log.WithError(err).Warn("failed to create PID directory")
@@ -499,9 +640,14 @@ func (m *manager) savePID(name string, p *Process, cmd *exec.Cmd) {
 		Version:   pidFileVersion,
 		PID:       p.PID,
 		LogFile:   p.LogFile,
-		Command:   cmd.Path,
-		Args:      cmd.Args[1:], // Skip binary name
+		Command:   p.path,
+		Args:      p.args[1:], // Skip binary name
 		StartedAt: p.Started,
+
+		LimitMode:   p.LimitMode,
+		CPUs:        p.Limits.CPUs,
+		MemoryBytes: p.Limits.MemoryBytes,
+		Nice:        p.Limits.Nice,
 	}
 
 	jsonData, err := json.MarshalIndent(data, "", "  ")
@@ -529,16 +675,19 @@ func (m *manager) loadPIDs() {
 	m.loadPIDsLocked()
 }
 
-// loadPIDsLocked scans the PID directory and updates the process map.
+// loadPIDsLocked scans the PID directory and updates the process map. It
+// returns the PID-loaded processes found to have exited since the last scan.
 // Caller must hold m.mu (or be in a context where no concurrent access occurs).
-func (m *manager) loadPIDsLocked() {
+func (m *manager) loadPIDsLocked() []ExitEvent {
 	pidDir := filepath.Join(m.stateDir, constants.DirPIDs)
 
 	entries, err := os.ReadDir(pidDir)
 	if err != nil {
-		return // Directory doesn't exist or can't be read
+		return nil // Directory doesn't exist or can't be read
 	}
 
+	var exited []ExitEvent
+
 	for _, entry := range entries {
 		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pid" {
 			continue
@@ -558,6 +707,15 @@ func (m *manager) loadPIDsLocked() {
 				m.removePID(name)
 
 				m.log.WithField(logFieldName, name).Debug("removed stale PID-loaded process")
+
+				// Stop removes the PID file, so a process still listed here
+				// exited on its own.
+				exited = append(exited, ExitEvent{
+					Name:     name,
+					PID:      existing.PID,
+					LogFile:  existing.LogFile,
+					ExitCode: -1,
+				})
 			}
 
 			continue
@@ -566,6 +724,8 @@ func (m *manager) loadPIDsLocked() {
 		// New PID file not yet in m.processes — load it
 		m.loadPID(name)
 	}
+
+	return exited
 }
 
 // loadPID loads a single PID from disk (JSON format only).
@@ -592,7 +752,8 @@ func (m *manager) loadPID(name string) {
 		return
 	}
 
-	if data.Version != pidFileVersion {
+	// Version 1 files predate resource limits and load without them.
+	if data.Version < 1 || data.Version > pidFileVersion {
 		m.log.WithField("version", data.Version).Warn("unknown PID file version")
 	}
 
@@ -613,11 +774,17 @@ func (m *manager) loadPID(name string) {
 
 	// Add to processes map (without Cmd since we can't reconstruct it perfectly)
 	m.processes[name] = &Process{
-		Name:    name,
-		Cmd:     nil, // Can't reconstruct
-		PID:     data.PID,
-		LogFile: data.LogFile,
-		Started: data.StartedAt,
+		Name:      name,
+		Cmd:       nil, // Can't reconstruct
+		PID:       data.PID,
+		LogFile:   data.LogFile,
+		Started:   data.StartedAt,
+		LimitMode: data.LimitMode,
+		Limits: Limits{
+			CPUs:        data.CPUs,
+			MemoryBytes: data.MemoryBytes,
+			Nice:        data.Nice,
+		},
 	}
 
 	m.log.WithFields(logrus.Fields{
@@ -0,0 +1,150 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerExitHandler(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager(logrus.New(), t.TempDir())
+
+	exits := make(chan ExitEvent, 2)
+	m.SetExitHandler(func(event ExitEvent) { exits <- event })
+
+	ctx := context.Background()
+
+	require.NoError(t, m.Start(ctx, "crasher", exec.Command("sh", "-c", "exit 3"), nil))
+
+	select {
+	case event := <-exits:
+		require.Equal(t, "crasher", event.Name)
+		require.Equal(t, 3, event.ExitCode)
+		require.Error(t, event.Err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("exit handler was not called")
+	}
+
+	// Stopping a process through the manager is not an unexpected exit.
+	require.NoError(t, m.Start(ctx, "sleeper", exec.Command("sleep", "30"), nil))
+	require.NoError(t, m.Stop(ctx, "sleeper"))
+
+	select {
+	case event := <-exits:
+		t.Fatalf("unexpected exit event for %s", event.Name)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+type failingHealthChecker struct{}
+
+func (failingHealthChecker) Check(context.Context) error { return errors.New("not listening") }
+func (failingHealthChecker) Name() string                { return "failing" }
+
+func TestManagerStopsProcessFailingHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	m := NewManager(logrus.New(), t.TempDir())
+	ctx := context.Background()
+
+	err := m.Start(ctx, "unhealthy", exec.Command("sleep", "30"), failingHealthChecker{})
+	require.ErrorContains(t, err, "health check failed: not listening")
+	require.False(t, m.IsRunning("unhealthy"))
+
+	// Stops of independent processes do not wait on each other.
+	require.NoError(t, m.Start(ctx, "a", exec.Command("sleep", "30"), nil))
+	require.NoError(t, m.Start(ctx, "b", exec.Command("sleep", "30"), nil))
+
+	errs := make(chan error, 2)
+	for _, name := range []string{"a", "b"} {
+		go func() { errs <- m.Stop(ctx, name) }()
+	}
+
+	for range 2 {
+		require.NoError(t, <-errs)
+	}
+
+	require.Empty(t, m.List())
+}
+
+func TestUsageSamplerCountsProcessGroup(t *testing.T) {
+	t.Parallel()
+
+	sampler := NewUsageSampler()
+	if _, err := sampler.Sample(nil); errors.Is(err, ErrUsageUnsupported) {
+		t.Skip(err)
+	}
+
+	// sh is the group leader; sleep is its child in the same process group.
+	cmd := exec.Command("sh", "-c", "sleep 30 & wait")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	require.NoError(t, cmd.Start())
+
+	t.Cleanup(func() {
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		_ = cmd.Wait()
+	})
+
+	require.Eventually(t, func() bool {
+		usage, err := sampler.Sample([]int{cmd.Process.Pid})
+		require.NoError(t, err)
+
+		return usage[cmd.Process.Pid].Processes == 2
+	}, 5*time.Second, 50*time.Millisecond)
+
+	usage, err := sampler.Sample([]int{cmd.Process.Pid, 1 << 30})
+	require.NoError(t, err)
+	require.Len(t, usage, 1)
+	require.Positive(t, usage[cmd.Process.Pid].RSSBytes)
+	require.Positive(t, usage[cmd.Process.Pid].OpenFiles)
+}
+
+func TestManagerPersistsLimitsInPIDFile(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("nice"); err != nil {
+		t.Skip("nice not installed")
+	}
+
+	stateDir := t.TempDir()
+	ctx := context.Background()
+
+	m := NewManager(logrus.New(), stateDir)
+	m.SetLimits("worker", Limits{Nice: 5})
+	require.NoError(t, m.Start(ctx, "worker", exec.Command("sleep", "30"), nil))
+
+	t.Cleanup(func() { _ = m.Stop(context.Background(), "worker") })
+
+	// Another process (lab status, the TUI, CC) sees the limits through the
+	// PID file.
+	loaded, ok := NewManager(logrus.New(), stateDir).Get("worker")
+	require.True(t, ok)
+	require.Nil(t, loaded.Cmd)
+	require.Equal(t, LimitModeNice, loaded.LimitMode)
+	require.Equal(t, Limits{Nice: 5}, loaded.Limits)
+}
+
+func TestApplyLimitsKeepsCommandWithoutLimits(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command("sleep", "1")
+	path := cmd.Path
+
+	require.Empty(t, applyLimits(cmd, Limits{}))
+	require.Equal(t, path, cmd.Path)
+
+	if _, err := exec.LookPath("nice"); err != nil {
+		t.Skip("nice not installed")
+	}
+
+	require.Equal(t, LimitModeNice, applyLimits(cmd, Limits{Nice: 5}))
+	require.Equal(t, []string{"nice", "-n", "5", path, "1"}, cmd.Args)
+}
@@ -0,0 +1,229 @@
+package process
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicks is the kernel's USER_HZ, the unit of CPU times in /proc.
+const clockTicks = 100
+
+// ErrUsageUnsupported is returned where /proc is not available.
+var ErrUsageUnsupported = errors.New("resource usage sampling needs /proc")
+
+// Usage is a resource usage sample for a managed process and the other
+// processes in its process group (e.g. the node children of pnpm).
+type Usage struct {
+	// Processes is the number of processes counted.
+	Processes int
+	// CPUPercent is CPU use since the previous sample, or since the process
+	// started on the first one; 100 means one full core.
+	CPUPercent float64
+	// RSSBytes is the resident memory.
+	RSSBytes uint64
+	// OpenFiles is the number of open file descriptors.
+	OpenFiles int
+}
+
+// UsageSampler samples process resource usage from /proc. It remembers the
+// previous CPU time per PID so CPU use reflects the last interval.
+type UsageSampler struct {
+	procDir  string
+	pageSize uint64
+
+	mu   sync.Mutex
+	last map[int]cpuSample
+}
+
+type cpuSample struct {
+	ticks uint64
+	at    time.Time
+}
+
+// NewUsageSampler creates a sampler reading /proc.
+func NewUsageSampler() *UsageSampler {
+	return &UsageSampler{
+		procDir:  "/proc",
+		pageSize: uint64(os.Getpagesize()), //nolint:gosec // page size is positive
+		last:     make(map[int]cpuSample),
+	}
+}
+
+// procStat holds the /proc/<pid>/stat fields the sampler needs.
+type procStat struct {
+	pgrp      int
+	ticks     uint64
+	startTime uint64
+	rssPages  uint64
+}
+
+// Sample returns the usage of each pid together with the other processes in
+// its process group. PIDs that are gone are left out.
+func (s *UsageSampler) Sample(pids []int) (map[int]Usage, error) {
+	if _, err := os.Stat(filepath.Join(s.procDir, "self")); err != nil {
+		return nil, ErrUsageUnsupported
+	}
+
+	entries, err := os.ReadDir(s.procDir)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		wanted[pid] = true
+	}
+
+	var (
+		roots  = make(map[int]procStat, len(pids))
+		totals = make(map[int]*Usage, len(pids))
+		ticks  = make(map[int]uint64, len(pids))
+	)
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		stat, err := s.readStat(pid)
+		if err != nil {
+			continue // exited since the scan
+		}
+
+		// A process counts towards its own PID when sampled, else towards
+		// the sampled leader of its process group.
+		owner := stat.pgrp
+		if wanted[pid] {
+			owner = pid
+			roots[pid] = stat
+		}
+
+		if !wanted[owner] {
+			continue
+		}
+
+		usage := totals[owner]
+		if usage == nil {
+			usage = &Usage{}
+			totals[owner] = usage
+		}
+
+		usage.Processes++
+		usage.RSSBytes += stat.rssPages * s.pageSize
+		ticks[owner] += stat.ticks
+
+		if fds, err := os.ReadDir(filepath.Join(s.procDir, entry.Name(), "fd")); err == nil {
+			usage.OpenFiles += len(fds)
+		}
+	}
+
+	result := make(map[int]Usage, len(roots))
+
+	for pid, root := range roots {
+		usage := *totals[pid]
+		usage.CPUPercent = s.cpuPercent(pid, root.startTime, ticks[pid])
+		result[pid] = usage
+	}
+
+	s.forget(wanted)
+
+	return result, nil
+}
+
+// cpuPercent turns cumulative CPU ticks into a percentage over the time
+// since the last sample of pid, or since the process started.
+func (s *UsageSampler) cpuPercent(pid int, startTime, ticks uint64) float64 {
+	now := time.Now()
+
+	s.mu.Lock()
+	prev, ok := s.last[pid]
+	s.last[pid] = cpuSample{ticks: ticks, at: now}
+	s.mu.Unlock()
+
+	var elapsed float64
+
+	if ok && ticks >= prev.ticks {
+		elapsed = now.Sub(prev.at).Seconds()
+		ticks -= prev.ticks
+	} else {
+		uptime, err := s.uptime()
+		if err != nil {
+			return 0
+		}
+
+		elapsed = uptime - float64(startTime)/clockTicks
+	}
+
+	if elapsed <= 0 {
+		return 0
+	}
+
+	return float64(ticks) / clockTicks / elapsed * 100
+}
+
+// forget drops the CPU history of PIDs that are no longer sampled.
+func (s *UsageSampler) forget(keep map[int]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for pid := range s.last {
+		if !keep[pid] {
+			delete(s.last, pid)
+		}
+	}
+}
+
+func (s *UsageSampler) uptime() (float64, error) {
+	data, err := os.ReadFile(filepath.Join(s.procDir, "uptime"))
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty uptime")
+	}
+
+	return strconv.ParseFloat(fields[0], 64)
+}
+
+// readStat parses /proc/<pid>/stat. The command name may contain spaces and
+// parentheses, so fields are read after its closing parenthesis.
+func (s *UsageSampler) readStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join(s.procDir, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStat{}, fmt.Errorf("malformed stat")
+	}
+
+	// fields[0] is the state (field 3 in proc(5)).
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return procStat{}, fmt.Errorf("malformed stat")
+	}
+
+	pgrp, _ := strconv.Atoi(fields[2])
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	startTime, _ := strconv.ParseUint(fields[19], 10, 64)
+	rss, _ := strconv.ParseUint(fields[21], 10, 64)
+
+	return procStat{
+		pgrp:      pgrp,
+		ticks:     utime + stime,
+		startTime: startTime,
+		rssPages:  rss,
+	}, nil
+}
@@ -0,0 +1,336 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/sirupsen/logrus"
+)
+
+// APIOptions configures the GitHub REST API release service.
+type APIOptions struct {
+	BaseURL    string       // API base URL (default: https://api.github.com)
+	Token      string       // Token sent as a bearer token
+	HTTPClient *http.Client // Client used for requests (default: 30s timeout)
+}
+
+// restRelease is a release in the REST API's release list.
+type restRelease struct {
+	TagName    string `json:"tag_name"`
+	Prerelease bool   `json:"prerelease"`
+	Draft      bool   `json:"draft"`
+}
+
+// restRun is a workflow run in the REST API.
+type restRun struct {
+	ID         int64     `json:"id"`
+	HTMLURL    string    `json:"html_url"`
+	HeadSha    string    `json:"head_sha"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// restRunList is the REST API's workflow run list.
+type restRunList struct {
+	WorkflowRuns []restRun `json:"workflow_runs"`
+}
+
+// apiService implements Service against the GitHub REST API.
+type apiService struct {
+	log    logrus.FieldLogger
+	client *githubClient
+
+	// runLookupDelay is how long to wait after creating a release or
+	// dispatching a workflow before looking up the run it triggered.
+	runLookupDelay time.Duration
+}
+
+// NewAPIService creates a release service backed by the GitHub REST API.
+func NewAPIService(log logrus.FieldLogger, opts APIOptions) Service {
+	log = log.WithField("package", "release")
+
+	return &apiService{
+		log:            log,
+		client:         newGitHubClient(log, opts.BaseURL, opts.Token, opts.HTTPClient),
+		runLookupDelay: 3 * time.Second,
+	}
+}
+
+// CheckPrerequisites verifies the token is accepted by the GitHub API.
+func (s *apiService) CheckPrerequisites(ctx context.Context) error {
+	var user struct {
+		Login string `json:"login"`
+	}
+
+	if err := s.client.get(ctx, "/user", &user); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+			return fmt.Errorf("GitHub token rejected: check GITHUB_TOKEN, GH_TOKEN or 'gh auth login': %w", err)
+		}
+
+		return fmt.Errorf("failed to reach GitHub API: %w", err)
+	}
+
+	s.log.WithField("user", user.Login).Debug("GitHub API authenticated")
+
+	return nil
+}
+
+// GetProjectInfo fetches current versions for all projects.
+func (s *apiService) GetProjectInfo(ctx context.Context) ([]ProjectInfo, error) {
+	projects := make([]ProjectInfo, 0, len(constants.ReleasableProjects))
+
+	for _, project := range constants.ReleasableProjects {
+		info, err := s.getProjectInfo(ctx, project)
+		if err != nil {
+			s.log.WithError(err).WithField("project", project).Warn("failed to get project info")
+
+			info = unknownProjectInfo(project)
+		}
+
+		projects = append(projects, *info)
+	}
+
+	return projects, nil
+}
+
+// ReleaseSemver creates a new semver release, which pushes its tag.
+func (s *apiService) ReleaseSemver(
+	ctx context.Context,
+	project string,
+	bumpType BumpType,
+	notes string,
+) (*ReleaseResult, error) {
+	repo := fmt.Sprintf("%s/%s", constants.GitHubOrg, project)
+
+	result := &ReleaseResult{
+		Project: project,
+		Repo:    repo,
+	}
+
+	info, err := s.getProjectInfo(ctx, project)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to get current version: %w", err)
+
+		return result, result.Error
+	}
+
+	result.Version, err = nextVersion(project, info, bumpType)
+	if err != nil {
+		result.Error = err
+
+		return result, result.Error
+	}
+
+	s.log.WithFields(map[string]any{
+		"project":        project,
+		"currentVersion": info.CurrentVersion,
+		"newVersion":     result.Version,
+		"bumpType":       bumpType,
+	}).Info("creating release")
+
+	body := map[string]any{
+		"tag_name": result.Version,
+		"name":     result.Version,
+	}
+
+	if notes != "" {
+		body["body"] = notes
+	} else {
+		body["generate_release_notes"] = true
+	}
+
+	if err := s.client.post(ctx, "/repos/"+repo+"/releases", body, nil); err != nil {
+		result.Error = fmt.Errorf("failed to create release: %w", err)
+
+		return result, result.Error
+	}
+
+	// The tag-triggered run's head branch is the tag.
+	s.lookupRun(ctx, result, fmt.Sprintf("/repos/%s/actions/runs?branch=%s&per_page=1",
+		repo, url.QueryEscape(result.Version)))
+
+	result.Success = true
+
+	return result, nil
+}
+
+// ReleaseWorkflow dispatches the release workflow of a non-semver project on
+// its default branch.
+func (s *apiService) ReleaseWorkflow(ctx context.Context, project string) (*ReleaseResult, error) {
+	repo := fmt.Sprintf("%s/%s", constants.GitHubOrg, project)
+
+	result := &ReleaseResult{
+		Project: project,
+		Repo:    repo,
+	}
+
+	workflowFile, ok := DispatchWorkflow(project)
+	if !ok {
+		result.Error = fmt.Errorf("unknown workflow for project: %s", project)
+
+		return result, result.Error
+	}
+
+	ref, err := s.DefaultBranch(ctx, repo)
+	if err != nil {
+		result.Error = err
+
+		return result, result.Error
+	}
+
+	s.log.WithFields(map[string]any{
+		"project":  project,
+		"workflow": workflowFile,
+		"ref":      ref,
+	}).Info("triggering workflow dispatch")
+
+	dispatchPath := fmt.Sprintf("/repos/%s/actions/workflows/%s/dispatches", repo, workflowFile)
+	if err := s.client.post(ctx, dispatchPath, map[string]string{"ref": ref}, nil); err != nil {
+		result.Error = fmt.Errorf("failed to trigger workflow: %w", err)
+
+		return result, result.Error
+	}
+
+	s.lookupRun(ctx, result, fmt.Sprintf("/repos/%s/actions/workflows/%s/runs?event=workflow_dispatch&per_page=1",
+		repo, workflowFile))
+
+	result.Success = true
+
+	return result, nil
+}
+
+// WatchRun polls a workflow run until completion or timeout. Polls are
+// conditional requests, so an unchanged run does not use up the rate limit.
+func (s *apiService) WatchRun(
+	ctx context.Context,
+	repo string,
+	runID string,
+	opts WatchOptions,
+) (*WatchResult, error) {
+	return watchRun(ctx, s.log, s.getRunStatus, repo, runID, opts)
+}
+
+// WatchMultiple watches multiple workflow runs concurrently.
+func (s *apiService) WatchMultiple(
+	ctx context.Context,
+	items []WatchItem,
+	opts WatchOptions,
+	onUpdate func(project string, status string),
+) (*MultiWatchResult, error) {
+	return watchMultiple(ctx, s.log, s.WatchRun, items, opts, onUpdate)
+}
+
+// getProjectInfo fetches info for a single project.
+func (s *apiService) getProjectInfo(ctx context.Context, project string) (*ProjectInfo, error) {
+	repo := fmt.Sprintf("%s/%s", constants.GitHubOrg, project)
+
+	info := &ProjectInfo{
+		Name:     project,
+		Repo:     repo,
+		IsSemver: slices.Contains(constants.SemverProjects, project),
+	}
+
+	if !info.IsSemver {
+		info.CurrentVersion, info.Description = s.getLatestWorkflowInfo(ctx, repo)
+
+		return info, nil
+	}
+
+	var releases []restRelease
+	if err := s.client.get(ctx, "/repos/"+repo+"/releases?per_page=10", &releases); err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	info.CurrentVersion = noReleases
+	info.Description = "no stable releases"
+
+	for _, rel := range releases {
+		if !rel.Prerelease && !rel.Draft {
+			info.CurrentVersion = rel.TagName
+			info.Description = fmt.Sprintf("current: %s", rel.TagName)
+
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// getLatestWorkflowInfo fetches the latest successful docker.yml workflow run.
+// Returns (version, description) for display.
+func (s *apiService) getLatestWorkflowInfo(ctx context.Context, repo string) (string, string) {
+	var runs restRunList
+
+	path := fmt.Sprintf("/repos/%s/actions/workflows/%s/runs?status=success&per_page=1",
+		repo, constants.WorkflowXatuCBTDocker)
+	if err := s.client.get(ctx, path, &runs); err != nil {
+		s.log.WithError(err).Debug("failed to get workflow runs")
+
+		return VersionNotAvailable, "workflow dispatch"
+	}
+
+	if len(runs.WorkflowRuns) == 0 {
+		return VersionNotAvailable, "no successful builds"
+	}
+
+	run := runs.WorkflowRuns[0]
+	shortSha := run.HeadSha
+
+	if len(shortSha) > 12 {
+		shortSha = shortSha[:12]
+	}
+
+	return shortSha, fmt.Sprintf("last build: %s", run.CreatedAt.Format(time.DateOnly))
+}
+
+// lookupRun waits for the run a release or dispatch triggered and records
+// it on the result. A missing run is logged, not returned: the release
+// itself succeeded.
+func (s *apiService) lookupRun(ctx context.Context, result *ReleaseResult, path string) {
+	if err := sleepContext(ctx, s.runLookupDelay); err != nil {
+		return
+	}
+
+	var runs restRunList
+	if err := s.client.get(ctx, path, &runs); err != nil {
+		s.log.WithError(err).Warn("failed to get workflow run info")
+
+		return
+	}
+
+	if len(runs.WorkflowRuns) == 0 {
+		s.log.Warn("failed to get workflow run info: no workflow runs found")
+
+		return
+	}
+
+	result.WorkflowURL = runs.WorkflowRuns[0].HTMLURL
+	result.RunID = strconv.FormatInt(runs.WorkflowRuns[0].ID, 10)
+}
+
+// getRunStatus fetches current status of a workflow run.
+func (s *apiService) getRunStatus(ctx context.Context, repo, runID string) (*runStatus, error) {
+	var run restRun
+	if err := s.client.get(ctx, fmt.Sprintf("/repos/%s/actions/runs/%s", repo, runID), &run); err != nil {
+		return nil, fmt.Errorf("failed to get run status: %w", err)
+	}
+
+	return &runStatus{
+		Status:     run.Status,
+		Conclusion: run.Conclusion,
+		CreatedAt:  run.CreatedAt,
+		UpdatedAt:  run.UpdatedAt,
+		HTMLURL:    run.HTMLURL,
+		HeadSha:    run.HeadSha,
+	}, nil
+}
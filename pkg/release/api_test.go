@@ -0,0 +1,237 @@
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves canned GitHub REST API responses.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	handlers map[string]http.HandlerFunc // Keyed by "METHOD /path"
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	handler, ok := f.handlers[r.Method+" "+r.URL.Path]
+	f.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+
+		return
+	}
+
+	handler(w, r)
+}
+
+func newFakeAPIService(t *testing.T, fake *fakeGitHub) *apiService {
+	t.Helper()
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	svc, ok := NewAPIService(logrus.New(), APIOptions{BaseURL: server.URL, Token: "test-token"}).(*apiService)
+	require.True(t, ok)
+
+	svc.runLookupDelay = 0
+
+	return svc
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestAPIServiceReleaseSemver(t *testing.T) {
+	var created map[string]any
+
+	fake := &fakeGitHub{handlers: map[string]http.HandlerFunc{
+		"GET /repos/ethpandaops/cbt/releases": func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, []map[string]any{
+				{"tag_name": "v0.6.0-rc.1", "prerelease": true},
+				{"tag_name": "v0.5.2"},
+			})
+		},
+		"POST /repos/ethpandaops/cbt/releases": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			writeJSON(w, map[string]any{"id": 1})
+		},
+		"GET /repos/ethpandaops/cbt/actions/runs": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "v0.6.0", r.URL.Query().Get("branch"))
+			writeJSON(w, map[string]any{"workflow_runs": []map[string]any{
+				{"id": 42, "html_url": "https://github.com/ethpandaops/cbt/actions/runs/42"},
+			}})
+		},
+	}}
+
+	svc := newFakeAPIService(t, fake)
+
+	result, err := svc.ReleaseSemver(context.Background(), "cbt", BumpMinor, "### Features\n")
+	require.NoError(t, err)
+	require.True(t, result.Success)
+	require.Equal(t, "v0.6.0", result.Version)
+	require.Equal(t, "42", result.RunID)
+	require.Equal(t, "https://github.com/ethpandaops/cbt/actions/runs/42", result.WorkflowURL)
+
+	require.Equal(t, map[string]any{
+		"tag_name": "v0.6.0",
+		"name":     "v0.6.0",
+		"body":     "### Features\n",
+	}, created)
+}
+
+func TestAPIServiceWatchRunUsesConditionalRequests(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		polls       int
+		notModified int
+	)
+
+	fake := &fakeGitHub{handlers: map[string]http.HandlerFunc{
+		"GET /repos/ethpandaops/cbt/actions/runs/42": func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			polls++
+
+			// The run stays in progress for three polls, then completes.
+			if polls <= 3 {
+				if r.Header.Get("If-None-Match") == `"running"` {
+					notModified++
+					w.WriteHeader(http.StatusNotModified)
+
+					return
+				}
+
+				w.Header().Set("ETag", `"running"`)
+				writeJSON(w, map[string]any{"status": "in_progress"})
+
+				return
+			}
+
+			w.Header().Set("ETag", `"done"`)
+			writeJSON(w, map[string]any{
+				"status":     "completed",
+				"conclusion": "success",
+				"head_sha":   "abc123",
+				"html_url":   "https://github.com/ethpandaops/cbt/actions/runs/42",
+			})
+		},
+	}}
+
+	svc := newFakeAPIService(t, fake)
+
+	result, err := svc.WatchRun(context.Background(), "ethpandaops/cbt", "42", WatchOptions{
+		Timeout:      5 * time.Second,
+		PollInterval: 5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.Equal(t, StatusSuccess, result.Conclusion)
+	require.Equal(t, "abc123", result.HeadSha)
+	require.Equal(t, 2, notModified, "unchanged polls should be answered with 304")
+}
+
+func TestAPIServiceRetriesAfterRateLimit(t *testing.T) {
+	var attempts int
+
+	fake := &fakeGitHub{handlers: map[string]http.HandlerFunc{
+		"GET /user": func(w http.ResponseWriter, _ *http.Request) {
+			attempts++
+
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit"}`))
+
+				return
+			}
+
+			writeJSON(w, map[string]any{"login": "octocat"})
+		},
+	}}
+
+	svc := newFakeAPIService(t, fake)
+
+	require.NoError(t, svc.CheckPrerequisites(context.Background()))
+	require.Equal(t, 2, attempts)
+}
+
+func TestResolveGitHubTokenReadsGHConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GH_CONFIG_DIR", dir)
+
+	_, err := ResolveGitHubToken()
+	require.ErrorIs(t, err, ErrNoGitHubToken)
+
+	hosts := "github.com:\n    user: octocat\n    oauth_token: gho_fromconfig\n    git_protocol: https\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hosts.yml"), []byte(hosts), 0644))
+
+	token, err := ResolveGitHubToken()
+	require.NoError(t, err)
+	require.Equal(t, "gho_fromconfig", token)
+
+	t.Setenv("GH_TOKEN", "ghp_fromenv")
+
+	token, err = ResolveGitHubToken()
+	require.NoError(t, err)
+	require.Equal(t, "ghp_fromenv", token)
+}
+
+func TestAPIServiceOpenPullRequestAndWaitForMerge(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		polls int
+	)
+
+	fake := &fakeGitHub{handlers: map[string]http.HandlerFunc{
+		// A pull request for the branch is already open from an earlier run.
+		"POST /repos/ethpandaops/xatu-cbt/pulls": func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"Validation Failed"}`))
+		},
+		"GET /repos/ethpandaops/xatu-cbt/pulls": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "ethpandaops:xcli/bump-cbt-v0.6.0", r.URL.Query().Get("head"))
+			writeJSON(w, []map[string]any{{"number": 7, "html_url": "https://github.com/ethpandaops/xatu-cbt/pull/7"}})
+		},
+		"GET /repos/ethpandaops/xatu-cbt/pulls/7": func(w http.ResponseWriter, _ *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			polls++
+			writeJSON(w, map[string]any{"number": 7, "state": "open", "merged": polls > 2})
+		},
+	}}
+
+	svc := newFakeAPIService(t, fake)
+
+	pr, err := svc.OpenPullRequest(context.Background(), "ethpandaops/xatu-cbt",
+		"xcli/bump-cbt-v0.6.0", "master", "chore(deps): bump cbt to v0.6.0", "")
+	require.NoError(t, err)
+	require.Equal(t, 7, pr.Number)
+
+	merged, err := svc.WaitForMerge(context.Background(), "ethpandaops/xatu-cbt", pr.Number, WatchOptions{
+		Timeout:      5 * time.Second,
+		PollInterval: 5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.True(t, merged.Merged)
+	require.Equal(t, 3, polls)
+}
@@ -0,0 +1,197 @@
+package release
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/ethpandaops/xcli/pkg/git"
+)
+
+// ErrNoBumpNeeded is returned when a dependent already requires the released
+// version.
+var ErrNoBumpNeeded = errors.New("go.mod already requires the released version")
+
+// DependencyBump updates a dependent project's go.mod to a released version
+// of one of its dependencies, on a branch that is opened as a pull request.
+type DependencyBump struct {
+	Project    string // Dependent project, e.g. "xatu-cbt"
+	RepoPath   string // Local checkout of the dependent
+	Dependency string // Released project, e.g. "cbt"
+	Version    string // Released version, e.g. "v0.6.0"
+	// WorkDir is the temporary worktree the bump is made in, set by
+	// PrepareDependencyBump.
+	WorkDir string
+}
+
+// Repo returns the dependent's GitHub repository.
+func (b DependencyBump) Repo() string {
+	return fmt.Sprintf("%s/%s", constants.GitHubOrg, b.Project)
+}
+
+// Module returns the Go module path being bumped.
+func (b DependencyBump) Module() string {
+	return GoModulePath(b.Dependency)
+}
+
+// Branch returns the branch the bump is committed to.
+func (b DependencyBump) Branch() string {
+	return fmt.Sprintf("xcli/bump-%s-%s", b.Dependency, b.Version)
+}
+
+// Title returns the bump's commit and pull request title.
+func (b DependencyBump) Title() string {
+	return fmt.Sprintf("chore(deps): bump %s to %s", b.Dependency, b.Version)
+}
+
+// Body returns the bump's pull request description.
+func (b DependencyBump) Body() string {
+	return fmt.Sprintf("Bumps `%s` to [%s](https://github.com/%s/%s/releases/tag/%s).\n\n"+
+		"Opened by `xcli lab release --bump-dependents`; the %s release continues once this is merged.\n",
+		b.Module(), b.Version, constants.GitHubOrg, b.Dependency, b.Version, b.Project)
+}
+
+// GoModulePath returns the Go module path of a project's repository.
+func GoModulePath(project string) string {
+	return fmt.Sprintf("github.com/%s/%s", constants.GitHubOrg, project)
+}
+
+// RequiresModule reports whether the go.mod in dir requires module.
+func RequiresModule(dir, module string) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "require (":
+			inBlock = true
+
+			continue
+		case line == ")":
+			inBlock = false
+
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inBlock:
+			continue
+		}
+
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == module {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// PrepareDependencyBump checks out the bump branch from origin's base branch
+// in a temporary worktree of the dependent, so its checkout (and the lab
+// running from it) is left alone, then updates go.mod with go get and go mod
+// tidy. Call CleanupDependencyBump once WorkDir is set, whatever the error.
+// ErrNoBumpNeeded is returned when go.mod does not change.
+func PrepareDependencyBump(ctx context.Context, bump *DependencyBump, base string) error {
+	if err := git.Fetch(ctx, bump.RepoPath); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "xcli-bump-")
+	if err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	workDir := filepath.Join(tmpDir, bump.Project)
+
+	if err := git.AddWorktree(ctx, bump.RepoPath, workDir, bump.Branch(), "origin/"+base); err != nil {
+		_ = os.RemoveAll(tmpDir)
+
+		return err
+	}
+
+	bump.WorkDir = workDir
+
+	if err := runGo(ctx, workDir, "get", bump.Module()+"@"+bump.Version); err != nil {
+		return err
+	}
+
+	if err := runGo(ctx, workDir, "mod", "tidy"); err != nil {
+		return err
+	}
+
+	changed, err := git.IsDirty(ctx, workDir)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		return ErrNoBumpNeeded
+	}
+
+	return nil
+}
+
+// PushDependencyBump commits go.mod and go.sum on the bump branch and pushes
+// it to origin.
+func PushDependencyBump(ctx context.Context, bump DependencyBump) error {
+	paths := []string{"go.mod"}
+	if _, err := os.Stat(filepath.Join(bump.WorkDir, "go.sum")); err == nil {
+		paths = append(paths, "go.sum")
+	}
+
+	committed, err := git.CommitPaths(ctx, bump.WorkDir, bump.Title(), paths...)
+	if err != nil {
+		return err
+	}
+
+	if !committed {
+		return ErrNoBumpNeeded
+	}
+
+	return git.PushBranch(ctx, bump.WorkDir, bump.Branch())
+}
+
+// CleanupDependencyBump removes the bump's worktree and local branch. The
+// pushed branch stays on origin for the pull request.
+func CleanupDependencyBump(ctx context.Context, bump DependencyBump) error {
+	if bump.WorkDir == "" {
+		return nil
+	}
+
+	err := git.RemoveWorktree(ctx, bump.RepoPath, bump.WorkDir, bump.Branch())
+
+	return errors.Join(err, os.RemoveAll(filepath.Dir(bump.WorkDir)))
+}
+
+// runGo runs a go command in dir, returning its stderr in the error.
+func runGo(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("go %s failed: %w (stderr: %s)", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiresModule(t *testing.T) {
+	dir := t.TempDir()
+
+	requires, err := RequiresModule(dir, "github.com/ethpandaops/cbt")
+	require.NoError(t, err)
+	require.False(t, requires, "a repo without go.mod requires nothing")
+
+	goMod := `module github.com/ethpandaops/xatu-cbt
+
+go 1.24
+
+require github.com/ethpandaops/ethwallclock v0.3.0
+
+require (
+	github.com/ethpandaops/cbt v0.5.2
+	github.com/sirupsen/logrus v1.9.3
+)
+
+require github.com/ethpandaops/cbt-api v1.0.0 // indirect
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644))
+
+	for module, want := range map[string]bool{
+		"github.com/ethpandaops/cbt":          true,
+		"github.com/ethpandaops/cbt-api":      true,
+		"github.com/ethpandaops/ethwallclock": true,
+		"github.com/ethpandaops/xatu-cbt":     false,
+		"github.com/ethpandaops/lab":          false,
+	} {
+		requires, err := RequiresModule(dir, module)
+		require.NoError(t, err)
+		require.Equal(t, want, requires, module)
+	}
+}
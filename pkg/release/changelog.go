@@ -0,0 +1,200 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// changelogSections lists conventional-commit types in changelog order. Any
+// other type, and subjects that are not conventional commits, go under
+// "Other Changes".
+var changelogSections = []struct {
+	Type  string
+	Title string
+}{
+	{Type: "feat", Title: "Features"},
+	{Type: "fix", Title: "Bug Fixes"},
+	{Type: "perf", Title: "Performance"},
+	{Type: "refactor", Title: "Refactoring"},
+	{Type: "docs", Title: "Documentation"},
+}
+
+// conventionalCommitRe matches "type(scope)!: subject".
+var conventionalCommitRe = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// ChangelogEntry is one commit in a changelog.
+type ChangelogEntry struct {
+	Hash     string // Short commit hash
+	Type     string // Conventional-commit type, lowercased; empty if not conventional
+	Scope    string
+	Subject  string
+	Breaking bool
+}
+
+// Changelog lists the commits a release will ship.
+type Changelog struct {
+	Project string
+	Since   string // Tag the changelog starts after
+	Entries []ChangelogEntry
+}
+
+// ParseCommitSubject splits a commit subject into its conventional-commit
+// parts. Subjects that don't follow the convention keep their full text.
+func ParseCommitSubject(hash, subject string) ChangelogEntry {
+	match := conventionalCommitRe.FindStringSubmatch(subject)
+	if match == nil {
+		return ChangelogEntry{Hash: hash, Subject: subject}
+	}
+
+	return ChangelogEntry{
+		Hash:     hash,
+		Type:     strings.ToLower(match[1]),
+		Scope:    match[2],
+		Subject:  match[4],
+		Breaking: match[3] == "!",
+	}
+}
+
+// GenerateRemoteChangelog fetches origin and builds a changelog from the
+// commits on origin's default branch since the latest tag reachable from it,
+// which is what a release cut from that branch ships. Local commits and an
+// outdated checkout do not affect it.
+func GenerateRemoteChangelog(ctx context.Context, project, repoPath string) (*Changelog, error) {
+	if _, err := gitOutput(ctx, repoPath, "fetch", "--quiet", "--tags", "origin"); err != nil {
+		return nil, fmt.Errorf("failed to fetch origin: %w", err)
+	}
+
+	head, err := gitOutput(ctx, repoPath, "rev-parse", "--abbrev-ref", "origin/HEAD")
+	if err != nil {
+		// Clones made with --single-branch or 'git remote add' lack
+		// origin/HEAD; ask origin for it.
+		if _, setErr := gitOutput(ctx, repoPath, "remote", "set-head", "origin", "--auto"); setErr != nil {
+			return nil, fmt.Errorf("failed to resolve origin's default branch: %w", setErr)
+		}
+
+		if head, err = gitOutput(ctx, repoPath, "rev-parse", "--abbrev-ref", "origin/HEAD"); err != nil {
+			return nil, fmt.Errorf("failed to resolve origin's default branch: %w", err)
+		}
+	}
+
+	tag, err := gitOutput(ctx, repoPath, "describe", "--tags", "--abbrev=0", head)
+	if err != nil {
+		return nil, fmt.Errorf("no tag reachable from %s: %w", head, err)
+	}
+
+	return GenerateChangelogBetween(ctx, project, repoPath, tag, head)
+}
+
+// GenerateChangelogBetween builds a changelog from the commits in a local
+// checkout after since, up to and including until. Merge commits are left
+// out.
+func GenerateChangelogBetween(ctx context.Context, project, repoPath, since, until string) (*Changelog, error) {
+	args := []string{"log", "--no-merges", "--format=%h%x00%s", until}
+	if since != "" {
+		args[len(args)-1] = since + ".." + until
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits since %s: %w", since, err)
+	}
+
+	changelog := &Changelog{Project: project, Since: since}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(string(output)), "\n") {
+		hash, subject, ok := strings.Cut(line, "\x00")
+		if !ok {
+			continue
+		}
+
+		changelog.Entries = append(changelog.Entries, ParseCommitSubject(hash, subject))
+	}
+
+	return changelog, nil
+}
+
+// Markdown renders the changelog grouped by commit type, for a GitHub
+// release body.
+func (c *Changelog) Markdown() string {
+	if c == nil || len(c.Entries) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	writeSection := func(title string, entries []ChangelogEntry) {
+		if len(entries) == 0 {
+			return
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+
+		fmt.Fprintf(&sb, "### %s\n\n", title)
+
+		for _, entry := range entries {
+			sb.WriteString("- ")
+
+			if entry.Scope != "" {
+				fmt.Fprintf(&sb, "**%s:** ", entry.Scope)
+			}
+
+			fmt.Fprintf(&sb, "%s (%s)\n", entry.Subject, entry.Hash)
+		}
+	}
+
+	var breaking, other []ChangelogEntry
+
+	grouped := make(map[string][]ChangelogEntry, len(changelogSections))
+	known := make(map[string]bool, len(changelogSections))
+
+	for _, section := range changelogSections {
+		known[section.Type] = true
+	}
+
+	for _, entry := range c.Entries {
+		if entry.Breaking {
+			breaking = append(breaking, entry)
+		}
+
+		if known[entry.Type] {
+			grouped[entry.Type] = append(grouped[entry.Type], entry)
+		} else {
+			other = append(other, entry)
+		}
+	}
+
+	writeSection("Breaking Changes", breaking)
+
+	for _, section := range changelogSections {
+		writeSection(section.Title, grouped[section.Type])
+	}
+
+	writeSection("Other Changes", other)
+
+	if c.Since != "" {
+		fmt.Fprintf(&sb, "\n%d commits since %s\n", len(c.Entries), c.Since)
+	}
+
+	return sb.String()
+}
+
+// gitOutput runs a git command in repoPath and returns its trimmed output.
+func gitOutput(ctx context.Context, repoPath string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
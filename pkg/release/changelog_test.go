@@ -0,0 +1,120 @@
+package release
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommitSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    ChangelogEntry
+	}{
+		{
+			subject: "feat(api): add /metrics endpoint",
+			want:    ChangelogEntry{Hash: "abc", Type: "feat", Scope: "api", Subject: "add /metrics endpoint"},
+		},
+		{
+			subject: "Fix!: drop legacy config keys",
+			want:    ChangelogEntry{Hash: "abc", Type: "fix", Subject: "drop legacy config keys", Breaking: true},
+		},
+		{
+			subject: "Merge branch 'master' into feature",
+			want:    ChangelogEntry{Hash: "abc", Subject: "Merge branch 'master' into feature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseCommitSubject("abc", tt.subject))
+		})
+	}
+}
+
+func TestChangelogMarkdownGroupsByType(t *testing.T) {
+	changelog := &Changelog{
+		Project: "cbt",
+		Since:   "v1.2.3",
+		Entries: []ChangelogEntry{
+			ParseCommitSubject("a1", "chore: bump deps"),
+			ParseCommitSubject("b2", "fix(scheduler): skip paused models"),
+			ParseCommitSubject("c3", "feat!: rename interval config"),
+			ParseCommitSubject("d4", "update README"),
+		},
+	}
+
+	want := `### Breaking Changes
+
+- rename interval config (c3)
+
+### Features
+
+- rename interval config (c3)
+
+### Bug Fixes
+
+- **scheduler:** skip paused models (b2)
+
+### Other Changes
+
+- bump deps (a1)
+- update README (d4)
+
+4 commits since v1.2.3
+`
+
+	require.Equal(t, want, changelog.Markdown())
+	require.Empty(t, (*Changelog)(nil).Markdown())
+}
+
+func TestGenerateRemoteChangelogUsesOriginDefaultBranch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	origin := t.TempDir()
+	runGit(t, origin, "init", "--quiet", "--initial-branch=main")
+	commitFile(t, origin, "a.txt", "feat: first")
+	runGit(t, origin, "tag", "v1.0.0")
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, origin, "clone", "--quiet", origin, clone)
+
+	// Shipped by the next release, although the clone has not pulled it.
+	commitFile(t, origin, "b.txt", "fix: on origin")
+	// Never pushed, so not part of the release.
+	commitFile(t, clone, "c.txt", "feat: local only")
+
+	changelog, err := GenerateRemoteChangelog(context.Background(), "cbt", clone)
+	require.NoError(t, err)
+	require.Equal(t, "v1.0.0", changelog.Since)
+	require.Len(t, changelog.Entries, 1)
+	require.Equal(t, "on origin", changelog.Entries[0].Subject)
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{
+		"-c", "user.email=test@example.com", "-c", "user.name=Test User", "-c", "commit.gpgsign=false",
+	}, args...)...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, strings.TrimSpace(string(out)))
+}
+
+func commitFile(t *testing.T, dir, name, subject string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(subject+"\n"), 0644))
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "--quiet", "-m", subject)
+}
@@ -0,0 +1,371 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultGitHubAPIURL is the GitHub REST API base URL.
+	DefaultGitHubAPIURL = "https://api.github.com"
+
+	githubAPIVersion   = "2022-11-28"
+	githubHost         = "github.com"
+	rateLimitLowWater  = 100             // Warn once fewer requests remain
+	maxRateLimitWait   = 5 * time.Minute // Fail rather than wait longer for a reset
+	maxRateLimitTries  = 3
+	maxErrorBodyLength = 64 * 1024
+)
+
+// ErrNoGitHubToken is returned when no GitHub token can be found.
+var ErrNoGitHubToken = errors.New("no GitHub token found: set GITHUB_TOKEN or GH_TOKEN, or run 'gh auth login'")
+
+// APIError is a non-2xx response from the GitHub REST API.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Message    string
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// cachedResponse is a GET response body kept for conditional requests.
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// githubClient is a minimal GitHub REST API client. GET responses are cached
+// by ETag and revalidated with If-None-Match, so polling an unchanged
+// resource is answered with 304 Not Modified, which GitHub does not count
+// against the rate limit. Requests rejected by the rate limit are retried
+// once it resets, if that is soon enough.
+type githubClient struct {
+	log     logrus.FieldLogger
+	baseURL string
+	token   string
+	http    *http.Client
+
+	mu        sync.Mutex
+	cache     map[string]cachedResponse
+	warnedLow bool
+}
+
+func newGitHubClient(log logrus.FieldLogger, baseURL, token string, httpClient *http.Client) *githubClient {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &githubClient{
+		log:     log,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    httpClient,
+		cache:   make(map[string]cachedResponse),
+	}
+}
+
+// get fetches path and decodes the JSON response into out.
+func (c *githubClient) get(ctx context.Context, path string, out any) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// post sends body as JSON to path and decodes the JSON response into out,
+// if out is non-nil.
+func (c *githubClient) post(ctx context.Context, path string, body, out any) error {
+	return c.do(ctx, http.MethodPost, path, body, out)
+}
+
+func (c *githubClient) do(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+
+		payload = encoded
+	}
+
+	for attempt := 1; ; attempt++ {
+		data, wait, err := c.send(ctx, method, path, payload)
+		if err == nil {
+			if out == nil || len(data) == 0 {
+				return nil
+			}
+
+			if err := json.Unmarshal(data, out); err != nil {
+				return fmt.Errorf("failed to parse %s response: %w", path, err)
+			}
+
+			return nil
+		}
+
+		if wait < 0 || attempt >= maxRateLimitTries {
+			return err
+		}
+
+		if wait > maxRateLimitWait {
+			return fmt.Errorf("%w (resets in %s)", err, wait.Round(time.Second))
+		}
+
+		c.log.WithFields(logrus.Fields{
+			"path": path,
+			"wait": wait.Round(time.Second),
+		}).Warn("GitHub API rate limit reached, waiting for reset")
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// send performs one request. On a rate-limit rejection it returns how long
+// to wait before retrying; otherwise the wait is negative.
+func (c *githubClient) send(ctx context.Context, method, path string, payload []byte) ([]byte, time.Duration, error) {
+	url := c.baseURL + path
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", githubAPIVersion)
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	cached, hasCached := c.cached(method, url)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	c.log.WithFields(logrus.Fields{"method": method, "path": path}).Debug("GitHub API request")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, -1, fmt.Errorf("GitHub API %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	c.trackRateLimit(resp.Header)
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		return cached.body, -1, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, -1, fmt.Errorf("failed to read %s response: %w", path, err)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if etag := resp.Header.Get("ETag"); method == http.MethodGet && etag != "" {
+			c.mu.Lock()
+			c.cache[url] = cachedResponse{etag: etag, body: data}
+			c.mu.Unlock()
+		}
+
+		return data, -1, nil
+	}
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       path,
+		Message:    errorMessage(data),
+	}
+
+	return nil, rateLimitWait(resp), apiErr
+}
+
+func (c *githubClient) cached(method, url string) (cachedResponse, bool) {
+	if method != http.MethodGet {
+		return cachedResponse{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.cache[url]
+
+	return cached, ok
+}
+
+// trackRateLimit warns once when the remaining request budget runs low.
+func (c *githubClient) trackRateLimit(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if remaining < rateLimitLowWater && !c.warnedLow {
+		c.warnedLow = true
+
+		c.log.WithFields(logrus.Fields{
+			"remaining": remaining,
+			"reset":     rateLimitReset(header).Format(time.Kitchen),
+		}).Warn("GitHub API rate limit running low")
+	}
+}
+
+// rateLimitWait returns how long to wait before retrying a response that
+// was rejected by a primary or secondary rate limit, or -1 if the response
+// was not a rate-limit rejection.
+func rateLimitWait(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return -1
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset := rateLimitReset(resp.Header); !reset.IsZero() {
+			return max(time.Until(reset)+time.Second, 0)
+		}
+	}
+
+	// Secondary rate limits without a hint: GitHub asks for at least a minute.
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return time.Minute
+	}
+
+	return -1
+}
+
+func rateLimitReset(header http.Header) time.Time {
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.Unix(reset, 0)
+}
+
+// errorMessage extracts the message from a GitHub API error body.
+func errorMessage(data []byte) string {
+	var body struct {
+		Message string `json:"message"`
+	}
+
+	if json.Unmarshal(data, &body) == nil && body.Message != "" {
+		return body.Message
+	}
+
+	if len(data) > maxErrorBodyLength {
+		data = data[:maxErrorBodyLength]
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// ResolveGitHubToken returns a GitHub token from GITHUB_TOKEN, GH_TOKEN or
+// the github.com entry of the gh CLI's hosts.yml, in that order. Tokens gh
+// keeps in the system keyring are not read; ErrNoGitHubToken is returned
+// instead.
+func ResolveGitHubToken() (string, error) {
+	for _, env := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := strings.TrimSpace(os.Getenv(env)); token != "" {
+			return token, nil
+		}
+	}
+
+	dir := ghConfigDir()
+	if dir == "" {
+		return "", ErrNoGitHubToken
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "hosts.yml"))
+	if err != nil {
+		return "", ErrNoGitHubToken
+	}
+
+	var hosts map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	}
+
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", fmt.Errorf("failed to parse gh hosts.yml: %w", err)
+	}
+
+	if token := strings.TrimSpace(hosts[githubHost].OAuthToken); token != "" {
+		return token, nil
+	}
+
+	return "", ErrNoGitHubToken
+}
+
+// ghConfigDir returns the gh CLI's config directory.
+func ghConfigDir() string {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config", "gh")
+}
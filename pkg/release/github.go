@@ -16,6 +16,9 @@ import (
 // VersionNotAvailable is used when version info cannot be fetched.
 const VersionNotAvailable = "N/A"
 
+// noReleases is a semver project's current version before its first release.
+const noReleases = "no releases"
+
 // ghRelease represents the JSON response from gh release list.
 type ghRelease struct {
 	TagName      string `json:"tagName"`
@@ -64,13 +67,7 @@ func (s *service) GetProjectInfo(ctx context.Context) ([]ProjectInfo, error) {
 		if err != nil {
 			s.log.WithError(err).WithField("project", project).Warn("failed to get project info")
 			// Continue with partial info rather than failing completely
-			info = &ProjectInfo{
-				Name:           project,
-				Repo:           fmt.Sprintf("%s/%s", constants.GitHubOrg, project),
-				CurrentVersion: "unknown",
-				IsSemver:       slices.Contains(constants.SemverProjects, project),
-				Description:    "failed to fetch version",
-			}
+			info = unknownProjectInfo(project)
 		}
 
 		projects = append(projects, *info)
@@ -84,6 +81,7 @@ func (s *service) ReleaseSemver(
 	ctx context.Context,
 	project string,
 	bumpType BumpType,
+	notes string,
 ) (*ReleaseResult, error) {
 	repo := fmt.Sprintf("%s/%s", constants.GitHubOrg, project)
 
@@ -100,23 +98,13 @@ func (s *service) ReleaseSemver(
 		return result, result.Error
 	}
 
-	if info.CurrentVersion == "no releases" {
-		result.Error = fmt.Errorf("no existing releases found for %s; create first release manually", project)
-
-		return result, result.Error
-	}
-
-	// Parse and bump version
-	currentVersion, err := ParseVersion(info.CurrentVersion)
+	result.Version, err = nextVersion(project, info, bumpType)
 	if err != nil {
-		result.Error = fmt.Errorf("failed to parse current version %s: %w", info.CurrentVersion, err)
+		result.Error = err
 
 		return result, result.Error
 	}
 
-	newVersion := currentVersion.Bump(bumpType)
-	result.Version = newVersion.String()
-
 	s.log.WithFields(map[string]any{
 		"project":        project,
 		"currentVersion": info.CurrentVersion,
@@ -125,11 +113,14 @@ func (s *service) ReleaseSemver(
 	}).Info("creating release")
 
 	// Create the release using gh
-	_, err = s.runGH(ctx, "release", "create",
-		result.Version,
-		"--repo", repo,
-		"--generate-notes",
-		"--title", result.Version)
+	args := []string{"release", "create", result.Version, "--repo", repo, "--title", result.Version}
+	if notes != "" {
+		args = append(args, "--notes", notes)
+	} else {
+		args = append(args, "--generate-notes")
+	}
+
+	_, err = s.runGH(ctx, args...)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create release: %w", err)
 
@@ -163,13 +154,8 @@ func (s *service) ReleaseWorkflow(ctx context.Context, project string) (*Release
 		Repo:    repo,
 	}
 
-	// Determine workflow file
-	var workflowFile string
-
-	switch project {
-	case constants.ProjectXatuCBT:
-		workflowFile = constants.WorkflowXatuCBTDocker
-	default:
+	workflowFile, ok := DispatchWorkflow(project)
+	if !ok {
 		result.Error = fmt.Errorf("unknown workflow for project: %s", project)
 
 		return result, result.Error
@@ -208,6 +194,43 @@ func (s *service) ReleaseWorkflow(ctx context.Context, project string) (*Release
 	return result, nil
 }
 
+// DispatchWorkflow returns the workflow file a non-semver project's release
+// dispatches.
+func DispatchWorkflow(project string) (string, bool) {
+	switch project {
+	case constants.ProjectXatuCBT:
+		return constants.WorkflowXatuCBTDocker, true
+	default:
+		return "", false
+	}
+}
+
+// unknownProjectInfo is the partial info shown for a project whose version
+// could not be fetched.
+func unknownProjectInfo(project string) *ProjectInfo {
+	return &ProjectInfo{
+		Name:           project,
+		Repo:           fmt.Sprintf("%s/%s", constants.GitHubOrg, project),
+		CurrentVersion: "unknown",
+		IsSemver:       slices.Contains(constants.SemverProjects, project),
+		Description:    "failed to fetch version",
+	}
+}
+
+// nextVersion bumps a semver project's current release version.
+func nextVersion(project string, info *ProjectInfo, bumpType BumpType) (string, error) {
+	if info.CurrentVersion == noReleases {
+		return "", fmt.Errorf("no existing releases found for %s; create first release manually", project)
+	}
+
+	currentVersion, err := ParseVersion(info.CurrentVersion)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse current version %s: %w", info.CurrentVersion, err)
+	}
+
+	return currentVersion.Bump(bumpType).String(), nil
+}
+
 // getProjectInfo fetches info for a single project.
 func (s *service) getProjectInfo(ctx context.Context, project string) (*ProjectInfo, error) {
 	repo := fmt.Sprintf("%s/%s", constants.GitHubOrg, project)
@@ -235,7 +258,7 @@ func (s *service) getProjectInfo(ctx context.Context, project string) (*ProjectI
 		}
 
 		// Find first non-prerelease, non-draft release
-		info.CurrentVersion = "no releases"
+		info.CurrentVersion = noReleases
 
 		for _, rel := range releases {
 			if !rel.IsPrerelease && !rel.IsDraft {
@@ -0,0 +1,70 @@
+package release
+
+// Dependency phases a release runs in, see SplitByDependencyPhase.
+const (
+	PhaseDependencies = 1 // Released first; their builds are awaited
+	PhaseDependents   = 2 // Released once their dependencies' builds succeed
+	PhaseIndependent  = 3 // Released last, watched concurrently
+)
+
+// PlanStep is one project's release in a plan.
+type PlanStep struct {
+	Project        string
+	CurrentVersion string
+	NextVersion    string // Empty for workflow dispatch projects
+	BumpType       BumpType
+	Phase          int
+	DependsOn      []string
+	Workflow       string // Workflow dispatched; empty for tag-triggered releases
+	Changelog      *Changelog
+}
+
+// BuildPlan orders release configs by dependency phase, the order a release
+// executes them in.
+func BuildPlan(
+	configs []ProjectReleaseConfig,
+	ordered []string,
+	deps map[string]*DependencyInfo,
+) []PlanStep {
+	byProject := make(map[string]ProjectReleaseConfig, len(configs))
+	for _, cfg := range configs {
+		byProject[cfg.Project] = cfg
+	}
+
+	phase1, phase2, phase3 := SplitByDependencyPhase(ordered, deps)
+	steps := make([]PlanStep, 0, len(configs))
+
+	for phase, projects := range [][]string{phase1, phase2, phase3} {
+		for _, project := range projects {
+			cfg, ok := byProject[project]
+			if !ok {
+				continue
+			}
+
+			step := PlanStep{
+				Project:   project,
+				BumpType:  cfg.BumpType,
+				Phase:     phase + 1,
+				Changelog: cfg.Changelog,
+			}
+
+			if cfg.Info != nil {
+				step.CurrentVersion = cfg.Info.CurrentVersion
+
+				if cfg.Info.IsSemver {
+					step.NextVersion = cfg.NewVersion
+				} else {
+					step.Workflow, _ = DispatchWorkflow(project)
+				}
+			}
+
+			if info, ok := deps[project]; ok {
+				step.DependsOn = info.DependsOn
+			}
+
+			steps = append(steps, step)
+		}
+	}
+
+	return steps
+}
@@ -0,0 +1,50 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPlanOrdersByDependencyPhase(t *testing.T) {
+	selected := []string{constants.ProjectCBTAPI, constants.ProjectXatuCBT, constants.ProjectCBT}
+	ordered, deps, _ := AnalyzeDependencies(selected)
+
+	configs := []ProjectReleaseConfig{
+		{
+			Project:    constants.ProjectCBT,
+			BumpType:   BumpMinor,
+			NewVersion: "v0.6.0",
+			Info:       &ProjectInfo{Name: constants.ProjectCBT, CurrentVersion: "v0.5.2", IsSemver: true},
+		},
+		{
+			Project: constants.ProjectXatuCBT,
+			Info:    &ProjectInfo{Name: constants.ProjectXatuCBT, CurrentVersion: "0123456789ab"},
+		},
+		{
+			Project:    constants.ProjectCBTAPI,
+			BumpType:   BumpPatch,
+			NewVersion: "v1.0.1",
+			Info:       &ProjectInfo{Name: constants.ProjectCBTAPI, CurrentVersion: "v1.0.0", IsSemver: true},
+		},
+	}
+
+	steps := BuildPlan(configs, ordered, deps)
+	require.Len(t, steps, 3)
+
+	require.Equal(t, constants.ProjectCBT, steps[0].Project)
+	require.Equal(t, PhaseDependencies, steps[0].Phase)
+	require.Equal(t, "v0.6.0", steps[0].NextVersion)
+	require.Empty(t, steps[0].Workflow)
+
+	require.Equal(t, constants.ProjectCBTAPI, steps[1].Project)
+	require.Equal(t, PhaseDependents, steps[1].Phase)
+	require.Equal(t, []string{constants.ProjectCBT}, steps[1].DependsOn)
+	require.Equal(t, "v1.0.1", steps[1].NextVersion)
+
+	require.Equal(t, constants.ProjectXatuCBT, steps[2].Project)
+	require.Equal(t, PhaseDependents, steps[2].Phase)
+	require.Equal(t, []string{constants.ProjectCBT}, steps[2].DependsOn)
+	require.Equal(t, constants.WorkflowXatuCBTDocker, steps[2].Workflow)
+}
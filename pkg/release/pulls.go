@@ -0,0 +1,131 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PullRequest is a GitHub pull request.
+type PullRequest struct {
+	Number int    `json:"number"`
+	URL    string `json:"html_url"`
+	State  string `json:"state"` // "open" or "closed"
+	Merged bool   `json:"merged"`
+}
+
+// PullRequestService opens pull requests and waits for them to merge. Only
+// the GitHub API service implements it.
+type PullRequestService interface {
+	// DefaultBranch returns a repository's default branch
+	DefaultBranch(ctx context.Context, repo string) (string, error)
+
+	// OpenPullRequest opens a pull request from head into base, or returns
+	// the open pull request that already exists for head
+	OpenPullRequest(ctx context.Context, repo, head, base, title, body string) (*PullRequest, error)
+
+	// WaitForMerge polls a pull request until it is merged, closed or the
+	// timeout passes
+	WaitForMerge(ctx context.Context, repo string, number int, opts WatchOptions) (*PullRequest, error)
+}
+
+// DefaultBranch returns a repository's default branch.
+func (s *apiService) DefaultBranch(ctx context.Context, repo string) (string, error) {
+	var repoInfo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+
+	if err := s.client.get(ctx, "/repos/"+repo, &repoInfo); err != nil {
+		return "", fmt.Errorf("failed to get default branch: %w", err)
+	}
+
+	return repoInfo.DefaultBranch, nil
+}
+
+// OpenPullRequest opens a pull request from head into base. GitHub rejects a
+// second pull request for the same head, so the existing one is returned.
+func (s *apiService) OpenPullRequest(
+	ctx context.Context,
+	repo, head, base, title, body string,
+) (*PullRequest, error) {
+	var pr PullRequest
+
+	err := s.client.post(ctx, "/repos/"+repo+"/pulls", map[string]string{
+		"title": title,
+		"head":  head,
+		"base":  base,
+		"body":  body,
+	}, &pr)
+	if err == nil {
+		return &pr, nil
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		return nil, fmt.Errorf("failed to open pull request: %w", err)
+	}
+
+	owner, _, _ := strings.Cut(repo, "/")
+
+	var existing []PullRequest
+
+	path := fmt.Sprintf("/repos/%s/pulls?state=open&head=%s", repo, url.QueryEscape(owner+":"+head))
+	if listErr := s.client.get(ctx, path, &existing); listErr != nil || len(existing) == 0 {
+		return nil, fmt.Errorf("failed to open pull request: %w", err)
+	}
+
+	s.log.WithField("number", existing[0].Number).Debug("pull request already open")
+
+	return &existing[0], nil
+}
+
+// WaitForMerge polls a pull request until it is merged. Polls are
+// conditional requests, so waiting on review does not use up the rate limit.
+func (s *apiService) WaitForMerge(
+	ctx context.Context,
+	repo string,
+	number int,
+	opts WatchOptions,
+) (*PullRequest, error) {
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Minute
+	}
+
+	if opts.PollInterval == 0 {
+		opts.PollInterval = 30 * time.Second
+	}
+
+	deadline := time.Now().Add(opts.Timeout)
+	ticker := time.NewTicker(opts.PollInterval)
+
+	defer ticker.Stop()
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d", repo, number)
+
+	for {
+		var pr PullRequest
+		if err := s.client.get(ctx, path, &pr); err != nil {
+			s.log.WithError(err).Warn("failed to get pull request, will retry")
+		} else {
+			switch {
+			case pr.Merged:
+				return &pr, nil
+			case pr.State == "closed":
+				return &pr, fmt.Errorf("pull request #%d was closed without merging", number)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			if time.Now().After(deadline) {
+				return nil, fmt.Errorf("timed out after %v waiting for pull request #%d to merge", opts.Timeout, number)
+			}
+		}
+	}
+}
@@ -1,5 +1,6 @@
 // Package release provides GitHub release operations for lab stack components.
-// It uses the GitHub CLI (gh) for all GitHub interactions.
+// It talks to the GitHub REST API when a token is available and falls back to
+// the GitHub CLI (gh) otherwise.
 package release
 
 import (
@@ -77,14 +78,15 @@ type WatchOptions struct {
 
 // Service provides release operations.
 type Service interface {
-	// CheckPrerequisites verifies gh CLI is installed and authenticated
+	// CheckPrerequisites verifies GitHub access is configured and authenticated
 	CheckPrerequisites(ctx context.Context) error
 
 	// GetProjectInfo fetches current version info for all releasable projects
 	GetProjectInfo(ctx context.Context) ([]ProjectInfo, error)
 
-	// ReleaseSemver creates a new semver release by pushing a tag
-	ReleaseSemver(ctx context.Context, project string, bumpType BumpType) (*ReleaseResult, error)
+	// ReleaseSemver creates a new semver release by pushing a tag. A non-empty
+	// notes becomes the release body; otherwise GitHub generates one.
+	ReleaseSemver(ctx context.Context, project string, bumpType BumpType, notes string) (*ReleaseResult, error)
 
 	// ReleaseWorkflow triggers a workflow dispatch for non-semver projects
 	ReleaseWorkflow(ctx context.Context, project string) (*ReleaseResult, error)
@@ -104,6 +106,7 @@ type ProjectReleaseConfig struct {
 	BumpType   BumpType     // For semver projects (ignored for workflow dispatch)
 	NewVersion string       // Calculated new version (for display)
 	Info       *ProjectInfo // Project info (version, semver status)
+	Changelog  *Changelog   // Commits since the last tag (nil if unavailable)
 }
 
 // MultiReleaseResult aggregates results from releasing multiple projects.
@@ -117,8 +120,13 @@ type service struct {
 	log logrus.FieldLogger
 }
 
-// NewService creates a new release service.
+// NewService creates a new release service. It uses the GitHub REST API when
+// ResolveGitHubToken finds a token, and the gh CLI otherwise.
 func NewService(log logrus.FieldLogger) Service {
+	if token, err := ResolveGitHubToken(); err == nil {
+		return NewAPIService(log, APIOptions{Token: token})
+	}
+
 	return &service{
 		log: log.WithField("package", "release"),
 	}
@@ -24,6 +24,7 @@ type ReportEntry struct {
 	DependsOn []string      // Dependencies (for display)
 	Skipped   bool          // True if skipped due to dependency failure
 	HeadSha   string        // Git commit SHA (for workflow dispatch projects)
+	Changelog *Changelog    // Commits shipped by this release (nil if unavailable)
 }
 
 // NewReleaseReport creates a new release report.
@@ -111,6 +112,13 @@ func (r *ReleaseReport) UpdateFromMultiWatch(watchResult *MultiWatchResult) {
 	}
 }
 
+// SetChangelog attaches a project's changelog to its entry.
+func (r *ReleaseReport) SetChangelog(project string, changelog *Changelog) {
+	if entry := r.GetEntry(project); entry != nil {
+		entry.Changelog = changelog
+	}
+}
+
 // GetEntry returns the entry for a project.
 func (r *ReleaseReport) GetEntry(project string) *ReportEntry {
 	for i := range r.entries {
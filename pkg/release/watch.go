@@ -7,9 +7,16 @@ import (
 	"sync"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
+// runStatusFunc fetches the current status of a workflow run.
+type runStatusFunc func(ctx context.Context, repo, runID string) (*runStatus, error)
+
+// watchRunFunc watches a single workflow run until it completes.
+type watchRunFunc func(ctx context.Context, repo, runID string, opts WatchOptions) (*WatchResult, error)
+
 // runStatus represents the JSON response from gh run view.
 type runStatus struct {
 	Status     string    `json:"status"`     // "queued", "in_progress", "completed"
@@ -40,6 +47,28 @@ func (s *service) WatchRun(
 	repo string,
 	runID string,
 	opts WatchOptions,
+) (*WatchResult, error) {
+	return watchRun(ctx, s.log, s.getRunStatus, repo, runID, opts)
+}
+
+// WatchMultiple watches multiple workflow runs concurrently.
+func (s *service) WatchMultiple(
+	ctx context.Context,
+	items []WatchItem,
+	opts WatchOptions,
+	onUpdate func(project string, status string),
+) (*MultiWatchResult, error) {
+	return watchMultiple(ctx, s.log, s.WatchRun, items, opts, onUpdate)
+}
+
+// watchRun polls a workflow run through getStatus until completion or timeout.
+func watchRun(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	getStatus runStatusFunc,
+	repo string,
+	runID string,
+	opts WatchOptions,
 ) (*WatchResult, error) {
 	if opts.Timeout == 0 {
 		opts.Timeout = 30 * time.Minute
@@ -60,7 +89,7 @@ func (s *service) WatchRun(
 	defer ticker.Stop()
 
 	// Do an immediate check first
-	status, err := s.getRunStatus(ctx, repo, runID)
+	status, err := getStatus(ctx, repo, runID)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to get initial run status: %w", err)
 
@@ -71,7 +100,7 @@ func (s *service) WatchRun(
 
 	// Check if already completed
 	if status.Status == "completed" {
-		return s.finalizeWatchResult(ctx, result, status, repo, startTime)
+		return finalizeWatchResult(result, status, repo, startTime)
 	}
 
 	// Poll until completion or timeout
@@ -94,14 +123,14 @@ func (s *service) WatchRun(
 			}
 
 			// Get current status
-			status, err = s.getRunStatus(ctx, repo, runID)
+			status, err = getStatus(ctx, repo, runID)
 			if err != nil {
-				s.log.WithError(err).Warn("failed to get run status, will retry")
+				log.WithError(err).Warn("failed to get run status, will retry")
 
 				continue
 			}
 
-			s.log.WithFields(map[string]any{
+			log.WithFields(map[string]any{
 				"runID":    runID,
 				"status":   status.Status,
 				"elapsed":  time.Since(startTime).Round(time.Second),
@@ -109,17 +138,19 @@ func (s *service) WatchRun(
 			}).Debug("polling workflow run")
 
 			if status.Status == "completed" {
-				return s.finalizeWatchResult(ctx, result, status, repo, startTime)
+				return finalizeWatchResult(result, status, repo, startTime)
 			}
 		}
 	}
 }
 
-// WatchMultiple watches multiple workflow runs concurrently.
+// watchMultiple watches multiple workflow runs concurrently with watch.
 // Returns results as each completes, respects context cancellation.
 // Uses a single timeout for the entire operation.
-func (s *service) WatchMultiple(
+func watchMultiple(
 	ctx context.Context,
+	log logrus.FieldLogger,
+	watch watchRunFunc,
 	items []WatchItem,
 	opts WatchOptions,
 	onUpdate func(project string, status string),
@@ -153,7 +184,7 @@ func (s *service) WatchMultiple(
 
 	for _, item := range items {
 		g.Go(func() error {
-			watchResult, err := s.WatchRun(gctx, item.Repo, item.RunID, opts)
+			watchResult, err := watch(gctx, item.Repo, item.RunID, opts)
 
 			mu.Lock()
 			defer mu.Unlock()
@@ -181,7 +212,7 @@ func (s *service) WatchMultiple(
 	}
 
 	if err := g.Wait(); err != nil {
-		s.log.WithError(err).Warn("error waiting for watch group")
+		log.WithError(err).Warn("error waiting for watch group")
 	}
 
 	result.EndTime = time.Now()
@@ -215,8 +246,7 @@ func (m *MultiWatchResult) Get(project string) *WatchResult {
 }
 
 // finalizeWatchResult populates the final result after completion.
-func (s *service) finalizeWatchResult(
-	ctx context.Context,
+func finalizeWatchResult(
 	result *WatchResult,
 	status *runStatus,
 	repo string,
@@ -228,13 +258,7 @@ func (s *service) finalizeWatchResult(
 	result.WorkflowURL = status.HTMLURL
 	result.HeadSha = status.HeadSha
 
-	// Get artifacts info
-	artifacts, err := s.getArtifacts(ctx, repo, result.RunID)
-	if err != nil {
-		s.log.WithError(err).Debug("failed to get artifacts")
-	} else {
-		result.Artifacts = artifacts
-	}
+	result.Artifacts = runArtifacts(repo, result.RunID)
 
 	if result.Conclusion != StatusSuccess {
 		result.Error = fmt.Errorf("workflow completed with conclusion: %s", result.Conclusion)
@@ -261,13 +285,11 @@ func (s *service) getRunStatus(ctx context.Context, repo, runID string) (*runSta
 	return &status, nil
 }
 
-// getArtifacts returns human-readable artifact descriptions for a completed run.
-func (s *service) getArtifacts(ctx context.Context, repo, runID string) ([]string, error) {
+// runArtifacts returns human-readable artifact descriptions for a completed run.
+func runArtifacts(repo, runID string) []string {
 	// For now, just return a generic message about where to find artifacts
 	// A more sophisticated implementation could parse the workflow summary
-	artifacts := []string{
+	return []string{
 		fmt.Sprintf("View artifacts: https://github.com/%s/actions/runs/%s", repo, runID),
 	}
-
-	return artifacts, nil
 }
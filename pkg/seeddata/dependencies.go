@@ -109,21 +109,27 @@ type sqlFrontmatter struct {
 // If the model's frontmatter specifies a database, it uses "database.table".
 // Otherwise it falls back to "default.modelName" for backward compatibility.
 func ResolveExternalTableRef(model, xatuCBTPath string) string {
+	return resolveExternalTableRef(model, xatuCBTPath, "default")
+}
+
+// resolveExternalTableRef is ResolveExternalTableRef with the database used
+// for models whose frontmatter does not name one.
+func resolveExternalTableRef(model, xatuCBTPath, database string) string {
 	modelPath := findModelFile(xatuCBTPath, "external", model)
 	if modelPath == "" {
-		return "default." + model
+		return database + "." + model
 	}
 
 	fm, err := parseFrontmatter(modelPath)
 	if err != nil {
-		return "default." + model
+		return database + "." + model
 	}
 
 	if fm.Database != "" && fm.Table != "" {
 		return fm.Database + "." + fm.Table
 	}
 
-	return "default." + model
+	return database + "." + model
 }
 
 // ParseDependencies parses the dependencies from a SQL file's YAML frontmatter.
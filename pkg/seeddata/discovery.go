@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -76,6 +77,7 @@ type DiscoveryResult struct {
 	OverallConfidence  float64              `yaml:"overallConfidence"`
 	Summary            string               `yaml:"summary"`
 	Warnings           []string             `yaml:"warnings,omitempty"`
+	Reasoning          []string             `yaml:"reasoning,omitempty"` // Step-by-step explanation (heuristic engine)
 }
 
 // TableSchemaInfo contains schema information for a table.
@@ -193,7 +195,7 @@ func (c *ClaudeDiscoveryClient) GatherSchemaInfo(
 		}
 
 		// Get column schema from ClickHouse
-		columns, err := c.gen.DescribeTable(ctx, model)
+		columns, err := c.gen.DescribeTable(ctx, model, network)
 		if err != nil {
 			return nil, fmt.Errorf("failed to describe table %s: %w", model, err)
 		}
@@ -302,6 +304,16 @@ func (g *Generator) ValidateStrategyHasData(
 	ctx context.Context,
 	result *DiscoveryResult,
 	network string,
+) (*ValidationResult, error) {
+	return validateStrategyHasData(ctx, g, result, network)
+}
+
+// validateStrategyHasData runs the row count validation against any rangeQuerier.
+func validateStrategyHasData(
+	ctx context.Context,
+	querier rangeQuerier,
+	result *DiscoveryResult,
+	network string,
 ) (*ValidationResult, error) {
 	counts := make([]ModelDataCount, 0, len(result.Strategies))
 	emptyModels := make([]string, 0)
@@ -313,7 +325,7 @@ func (g *Generator) ValidateStrategyHasData(
 	minRowModel := ""
 
 	for _, strategy := range result.Strategies {
-		count, err := g.QueryRowCount(ctx, strategy.Model, network, strategy.RangeColumn, strategy.FromValue, strategy.ToValue, strategy.FilterSQL, strategy.CorrelationFilter)
+		count, err := querier.QueryRowCount(ctx, strategy.Model, network, strategy.RangeColumn, strategy.FromValue, strategy.ToValue, strategy.FilterSQL, strategy.CorrelationFilter)
 
 		modelCount := ModelDataCount{
 			Model:    strategy.Model,
@@ -375,7 +387,7 @@ func (g *Generator) QueryRowCount(
 		filterClause += fmt.Sprintf("\n			  AND %s", correlationFilter)
 	}
 
-	tableRef := g.resolveTableRef(model)
+	tableRef := g.resolveTableRef(model, network)
 
 	var query string
 
@@ -495,7 +507,7 @@ func (g *Generator) QueryTableSample(
 	network string,
 	limit int,
 ) ([]map[string]any, error) {
-	tableRef := g.resolveTableRef(model)
+	tableRef := g.resolveTableRef(model, network)
 	query := fmt.Sprintf(`
 		SELECT *
 		FROM %s
@@ -600,326 +612,65 @@ func ReadTransformationSQL(model, xatuCBTPath string) (string, error) {
 	return string(content), nil
 }
 
-// FallbackRangeDiscovery provides heuristic-based range discovery when Claude is unavailable.
-//
-//nolint:funlen,gocognit,cyclop,gocyclo // Complex heuristic logic requires length
-func FallbackRangeDiscovery(
-	ctx context.Context,
-	gen *Generator,
-	models []string,
-	network string,
-	rangeInfos map[string]*RangeColumnInfo,
-	duration string,
-	xatuCBTPath string,
-) (*DiscoveryResult, error) {
-	// Get interval types from model frontmatter for accurate categorization
-	intervalTypes, err := GetExternalModelIntervalTypes(models, xatuCBTPath)
-	if err != nil {
-		// Log warning but continue with column-based detection as fallback
-		gen.log.WithError(err).Warn("failed to get interval types from frontmatter, using column-based detection")
-
-		intervalTypes = nil
-	}
-
-	// Group models by interval type
-	_, blockModels, entityModels, unknownModels := categorizeModelsByType(models, intervalTypes, rangeInfos)
-
-	// Track time ranges and block ranges separately
-	var latestTimeMin, earliestTimeMax time.Time
-
-	var latestBlockMin, earliestBlockMax int64
-
-	hasTimeRanges := false
-	hasBlockRanges := false
-
-	// Store per-model range info for later assignment
-	type modelRangeInfo struct {
-		rangeCol   string
-		colType    RangeColumnType
-		reasoning  string
-		confidence float64
+// SuggestExpandedStrategy creates a new strategy with an expanded time window.
+// This is used when the original strategy has models with no data.
+// Each range is widened backwards from its upper bound so the window grows to
+// multiplier times its original size while keeping the most recent data.
+func SuggestExpandedStrategy(original *DiscoveryResult, multiplier int) *DiscoveryResult {
+	expanded := &DiscoveryResult{
+		PrimaryRangeType:   original.PrimaryRangeType,
+		PrimaryRangeColumn: original.PrimaryRangeColumn,
+		FromValue:          expandRangeFrom(original.FromValue, original.ToValue, multiplier),
+		ToValue:            original.ToValue,
+		OverallConfidence:  original.OverallConfidence * 0.9, // Reduce confidence slightly
+		Summary:            fmt.Sprintf("%s (window expanded %dx)", original.Summary, multiplier),
+		Warnings:           append([]string{}, original.Warnings...),
+		Strategies:         make([]TableRangeStrategy, len(original.Strategies)),
 	}
 
-	modelRanges := make(map[string]*modelRangeInfo, len(models))
-	strategies := make([]TableRangeStrategy, 0, len(models))
-
-	for _, model := range models {
-		info := rangeInfos[model]
-
-		// Check model category
-		isEntity := contains(entityModels, model)
-		isBlock := contains(blockModels, model)
-		isUnknown := contains(unknownModels, model)
-
-		var rangeCol string
-
-		var colType RangeColumnType
-
-		// For entity models, they typically don't have time-based ranges
-		// Mark them as "none" type - they'll get all data or use correlation
-		if isEntity {
-			gen.log.WithField(fieldKeyModel, model).Info("entity/dimension table - will query without range filter")
-
-			strategies = append(strategies, TableRangeStrategy{
-				Model:      model,
-				ColumnType: RangeColumnTypeNone,
-				Confidence: 0.7,
-				Reasoning:  "Entity/dimension table - no range filtering (all data)",
-			})
-
-			continue
-		} else if isBlock {
-			// Block-based models use block_number
-			rangeCol = BlockNumberColumn
-			if info != nil && info.RangeColumn != "" {
-				rangeCol = info.RangeColumn
-			}
-
-			colType = RangeColumnTypeBlock
-		} else if isUnknown {
-			// Unknown models - try default range column
-			rangeCol = DefaultRangeColumn
-			colType = RangeColumnTypeTime
-		} else {
-			// Time models - use detected range column
-			rangeCol = DefaultRangeColumn
-			if info != nil {
-				rangeCol = info.RangeColumn
-			}
-
-			colType = ClassifyRangeColumn(rangeCol, nil)
-		}
-
-		modelRange, queryErr := gen.QueryModelRange(ctx, model, network, rangeCol)
-		if queryErr != nil {
-			gen.log.WithError(queryErr).WithField(fieldKeyModel, model).Warn("range query failed")
-
-			strategies = append(strategies, TableRangeStrategy{
-				Model:       model,
-				RangeColumn: rangeCol,
-				ColumnType:  colType,
-				Confidence:  0.3,
-				Reasoning:   fmt.Sprintf("Range query failed: %v", queryErr),
-			})
+	copy(expanded.Strategies, original.Strategies)
 
+	for i := range expanded.Strategies {
+		strategy := &expanded.Strategies[i]
+		if strategy.RangeColumn == "" || strategy.ColumnType == RangeColumnTypeNone {
 			continue
 		}
 
-		// Track ranges based on column type
-		if colType == RangeColumnTypeBlock {
-			// Parse raw values as block numbers
-			var minBlock, maxBlock int64
-
-			if _, scanErr := fmt.Sscanf(modelRange.MinRaw, "%d", &minBlock); scanErr != nil {
-				gen.log.WithError(scanErr).WithField(fieldKeyModel, model).Warn("failed to parse min block number")
-
-				continue
-			}
-
-			if _, scanErr := fmt.Sscanf(modelRange.MaxRaw, "%d", &maxBlock); scanErr != nil {
-				gen.log.WithError(scanErr).WithField(fieldKeyModel, model).Warn("failed to parse max block number")
-
-				continue
-			}
-
-			if !hasBlockRanges || minBlock > latestBlockMin {
-				latestBlockMin = minBlock
-			}
-
-			if !hasBlockRanges || maxBlock < earliestBlockMax {
-				earliestBlockMax = maxBlock
-			}
-
-			hasBlockRanges = true
-
-			modelRanges[model] = &modelRangeInfo{
-				rangeCol:   rangeCol,
-				colType:    colType,
-				reasoning:  "Block-based model",
-				confidence: 0.7,
-			}
-		} else {
-			// Time-based range
-			if latestTimeMin.IsZero() || modelRange.Min.After(latestTimeMin) {
-				latestTimeMin = modelRange.Min
-			}
-
-			if earliestTimeMax.IsZero() || modelRange.Max.Before(earliestTimeMax) {
-				earliestTimeMax = modelRange.Max
-			}
-
-			hasTimeRanges = true
-
-			modelRanges[model] = &modelRangeInfo{
-				rangeCol:   rangeCol,
-				colType:    colType,
-				reasoning:  "Time-based model",
-				confidence: 0.7,
-			}
-		}
-	}
-
-	// Determine primary type and compute range values
-	var fromValue, toValue string
-
-	var primaryType RangeColumnType
-
-	var primaryColumn string
-
-	// Calculate time-based range values if we have time models
-	var timeFromValue, timeToValue string
-
-	if hasTimeRanges {
-		if latestTimeMin.After(earliestTimeMax) {
-			gen.log.Warn("no intersecting time range found, using latest available data")
-
-			earliestTimeMax = latestTimeMin.Add(5 * time.Minute)
-		}
-
-		rangeDuration, parseErr := time.ParseDuration(duration)
-		if parseErr != nil {
-			rangeDuration = 5 * time.Minute
-		}
-
-		effectiveMax := earliestTimeMax.Add(-1 * time.Minute)
-		effectiveMin := effectiveMax.Add(-rangeDuration)
-
-		if effectiveMin.Before(latestTimeMin) {
-			effectiveMin = latestTimeMin
-		}
-
-		timeFromValue = effectiveMin.Format("2006-01-02 15:04:05")
-		timeToValue = effectiveMax.Format("2006-01-02 15:04:05")
+		strategy.FromValue = expandRangeFrom(strategy.FromValue, strategy.ToValue, multiplier)
 	}
 
-	// Calculate block-based range values if we have block models
-	var blockFromValue, blockToValue string
-
-	if hasBlockRanges {
-		if latestBlockMin > earliestBlockMax {
-			gen.log.Warn("no intersecting block range found, using latest available data")
-
-			earliestBlockMax = latestBlockMin + 1000
-		}
-
-		// For blocks, use a reasonable range (e.g., last 1000 blocks or based on duration)
-		// Approximate: 1 block every 12 seconds, so 5 minutes = ~25 blocks
-		rangeDuration, parseErr := time.ParseDuration(duration)
-		if parseErr != nil {
-			rangeDuration = 5 * time.Minute
-		}
-
-		blocksPerDuration := max(
-			// ~12 second block time
-			int64(rangeDuration.Seconds()/12),
-			// Minimum 100 blocks
-			100)
-
-		effectiveMax := earliestBlockMax - 10 // Account for reorgs
-		effectiveMin := max(effectiveMax-blocksPerDuration, latestBlockMin)
+	expanded.Warnings = append(expanded.Warnings,
+		fmt.Sprintf("Window expanded %dx to find data - verify data quality", multiplier))
 
-		blockFromValue = fmt.Sprintf("%d", effectiveMin)
-		blockToValue = fmt.Sprintf("%d", effectiveMax)
-	}
+	return expanded
+}
 
-	// Set primary type based on what we have
-	if hasTimeRanges && !hasBlockRanges {
-		primaryType = RangeColumnTypeTime
-		primaryColumn = DefaultRangeColumn
-		fromValue = timeFromValue
-		toValue = timeToValue
-	} else if hasBlockRanges && !hasTimeRanges {
-		primaryType = RangeColumnTypeBlock
-		primaryColumn = BlockNumberColumn
-		fromValue = blockFromValue
-		toValue = blockToValue
-	} else if hasTimeRanges && hasBlockRanges {
-		// Mixed - prefer time as primary
-		primaryType = RangeColumnTypeTime
-		primaryColumn = DefaultRangeColumn
-		fromValue = timeFromValue
-		toValue = timeToValue
-	} else {
-		// No valid ranges found - check if we have entity-only models
-		if len(strategies) > 0 {
-			// All models are entity tables - proceed without primary range
-			primaryType = RangeColumnTypeNone
-			primaryColumn = ""
-			fromValue = ""
-			toValue = ""
-		} else {
-			return nil, fmt.Errorf("no valid range columns found for any model")
-		}
+// expandRangeFrom returns a new lower bound so that [from, to] grows to multiplier times its size.
+// Supports datetime and integer bounds; other values are returned unchanged.
+func expandRangeFrom(from, to string, multiplier int) string {
+	if multiplier <= 1 || from == "" || to == "" {
+		return from
 	}
 
-	// Create strategies for models with ranges
-	for model, rangeInfo := range modelRanges {
-		strategy := TableRangeStrategy{
-			Model:       model,
-			RangeColumn: rangeInfo.rangeCol,
-			ColumnType:  rangeInfo.colType,
-			Confidence:  rangeInfo.confidence,
-			Reasoning:   rangeInfo.reasoning,
+	if fromTime, err := time.Parse(heuristicTimeLayout, from); err == nil {
+		toTime, toErr := time.Parse(heuristicTimeLayout, to)
+		if toErr != nil || !toTime.After(fromTime) {
+			return from
 		}
 
-		// Assign appropriate range values based on column type
-		if rangeInfo.colType == RangeColumnTypeBlock {
-			strategy.FromValue = blockFromValue
-			strategy.ToValue = blockToValue
-		} else {
-			strategy.FromValue = timeFromValue
-			strategy.ToValue = timeToValue
-		}
+		width := toTime.Sub(fromTime)
 
-		strategies = append(strategies, strategy)
+		return toTime.Add(-width * time.Duration(multiplier)).Format(heuristicTimeLayout)
 	}
 
-	warnings := make([]string, 0)
-
-	if hasBlockRanges && hasTimeRanges {
-		warnings = append(warnings,
-			"Mixed range column types detected (time and block). "+
-				"Each table type will use its appropriate range values.")
-	}
-
-	if len(entityModels) > 0 {
-		warnings = append(warnings,
-			fmt.Sprintf("Entity/dimension tables detected (%v). These will query all data without range filtering.",
-				entityModels))
-	}
-
-	return &DiscoveryResult{
-		PrimaryRangeType:   primaryType,
-		PrimaryRangeColumn: primaryColumn,
-		FromValue:          fromValue,
-		ToValue:            toValue,
-		Strategies:         strategies,
-		OverallConfidence:  0.6, // Lower confidence for heuristic
-		Summary:            "Heuristic-based range detection (Claude unavailable)",
-		Warnings:           warnings,
-	}, nil
-}
+	fromNum, fromErr := strconv.ParseInt(from, 10, 64)
+	toNum, toErr := strconv.ParseInt(to, 10, 64)
 
-// SuggestExpandedStrategy creates a new strategy with an expanded time window.
-// This is used when the original strategy has models with no data.
-func SuggestExpandedStrategy(original *DiscoveryResult, multiplier int) *DiscoveryResult {
-	expanded := &DiscoveryResult{
-		PrimaryRangeType:   original.PrimaryRangeType,
-		PrimaryRangeColumn: original.PrimaryRangeColumn,
-		OverallConfidence:  original.OverallConfidence * 0.9, // Reduce confidence slightly
-		Summary:            fmt.Sprintf("%s (window expanded %dx)", original.Summary, multiplier),
-		Warnings:           append([]string{}, original.Warnings...),
-		Strategies:         make([]TableRangeStrategy, len(original.Strategies)),
+	if fromErr != nil || toErr != nil || toNum <= fromNum {
+		return from
 	}
 
-	// For time-based ranges, we can try to expand by parsing and adjusting
-	// For now, just copy and add a warning - the actual expansion would need
-	// to be done with knowledge of the original window size
-	copy(expanded.Strategies, original.Strategies)
-	expanded.Warnings = append(expanded.Warnings,
-		fmt.Sprintf("Window expanded %dx to find data - verify data quality", multiplier))
-
-	return expanded
+	return strconv.FormatInt(max(toNum-(toNum-fromNum)*int64(multiplier), 0), 10)
 }
 
 // buildDiscoveryPrompt constructs the prompt for Claude.
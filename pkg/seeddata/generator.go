@@ -101,7 +101,7 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (*Genera
 	var sanitizedColumns []string
 
 	if opts.SanitizeIPs && opts.Salt != "" {
-		result, err := g.BuildSanitizedColumnList(ctx, opts.Model, opts.Salt)
+		result, err := g.BuildSanitizedColumnList(ctx, opts.Model, opts.Network, opts.Salt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build sanitized column list: %w", err)
 		}
@@ -188,7 +188,7 @@ func (g *Generator) ValidateModel(model string) error {
 func (g *Generator) buildQuery(opts GenerateOptions) string {
 	var sb strings.Builder
 
-	tableRef := g.resolveTableRef(opts.Model)
+	tableRef := g.resolveTableRef(opts.Model, opts.Network)
 
 	// Use sanitized column list if available, otherwise SELECT *
 	if opts.sanitizedColumns != "" {
@@ -268,7 +268,7 @@ func (g *Generator) buildQuery(opts GenerateOptions) string {
 func (g *Generator) queryGeneratedRowCount(ctx context.Context, opts GenerateOptions) (int64, error) {
 	var sb strings.Builder
 
-	tableRef := g.resolveTableRef(opts.Model)
+	tableRef := g.resolveTableRef(opts.Model, opts.Network)
 
 	// Build a SELECT 1 query with the same filters, then wrap with COUNT.
 	// Using SELECT 1 (not SELECT COUNT(*)) so we can wrap with LIMIT in a subquery.
@@ -526,9 +526,9 @@ func (g *Generator) buildClickHouseHTTPURL() (string, error) {
 
 // resolveTableRef returns the fully qualified "database.table" reference for an external model.
 // If the model's frontmatter specifies a database and table, it uses those.
-// Otherwise it falls back to "default.modelName" for backward compatibility.
-func (g *Generator) resolveTableRef(model string) string {
-	return ResolveExternalTableRef(model, g.cfg.Repos.XatuCBT)
+// Otherwise it falls back to the network's Xatu database, as configgen does.
+func (g *Generator) resolveTableRef(model, network string) string {
+	return resolveExternalTableRef(model, g.cfg.Repos.XatuCBT, g.cfg.XatuDatabase(network))
 }
 
 // formatSQLValue formats a value for use in SQL.
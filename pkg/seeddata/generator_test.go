@@ -0,0 +1,30 @@
+package seeddata
+
+import (
+	"testing"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTableRefUsesNetworkDatabase(t *testing.T) {
+	cfg := config.DefaultLab()
+	cfg.Repos.XatuCBT = t.TempDir()
+	cfg.Networks = append(cfg.Networks, config.NetworkConfig{
+		Name:             "fusaka_devnet_3",
+		GenesisTimestamp: 1753300800,
+		Database:         "devnet_xatu",
+	})
+
+	gen := NewGenerator(logrus.New(), cfg)
+
+	require.Equal(t, "devnet_xatu.beacon_api_eth_v1_events_block",
+		gen.resolveTableRef("beacon_api_eth_v1_events_block", "fusaka_devnet_3"))
+	require.Equal(t, "default.beacon_api_eth_v1_events_block",
+		gen.resolveTableRef("beacon_api_eth_v1_events_block", "mainnet"))
+
+	cfg.Infrastructure.ClickHouse.Xatu.ExternalDatabase = "xatu"
+	require.Equal(t, "xatu.beacon_api_eth_v1_events_block",
+		gen.resolveTableRef("beacon_api_eth_v1_events_block", "mainnet"))
+}
@@ -0,0 +1,487 @@
+package seeddata
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultHeuristicSamples is the number of candidate windows sampled per range type.
+	DefaultHeuristicSamples = 6
+
+	// DefaultHeuristicMaxExpansions is the maximum number of times the chosen window
+	// is widened (by ExpandWindowMultiplier) while validation still finds empty models.
+	DefaultHeuristicMaxExpansions = 4
+
+	// heuristicTimeLayout is the datetime layout used for time-based range values.
+	heuristicTimeLayout = "2006-01-02 15:04:05"
+
+	// heuristicSafetyMargin keeps candidate windows away from the head of the data,
+	// where the most recent slot may still be partially ingested.
+	heuristicSafetyMargin = time.Minute
+
+	// heuristicReorgMargin keeps block windows away from the chain head.
+	heuristicReorgMargin = 10
+
+	// heuristicSecondsPerBlock approximates execution block time for sizing block windows.
+	heuristicSecondsPerBlock = 12
+
+	// heuristicMinBlocks is the minimum block window size.
+	heuristicMinBlocks = 100
+)
+
+// rangeQuerier is the subset of Generator used by the heuristic engine.
+// It exists so window selection can be exercised without a ClickHouse server.
+type rangeQuerier interface {
+	QueryModelRange(ctx context.Context, model, network, rangeColumn string) (*ModelRange, error)
+	QueryModelRangeRaw(ctx context.Context, model, network, rangeColumn string) (*ModelRangeRaw, error)
+	QueryRowCount(
+		ctx context.Context,
+		model, network, rangeColumn, fromValue, toValue, filterSQL, correlationFilter string,
+	) (int64, error)
+}
+
+// HeuristicDiscovery is an offline range strategy engine that does not need an AI engine.
+// It samples row density across the common window of all external dependencies, picks
+// the densest window and widens it until every dependency has data.
+type HeuristicDiscovery struct {
+	log           logrus.FieldLogger
+	querier       rangeQuerier
+	samples       int
+	maxExpansions int
+}
+
+// NewHeuristicDiscovery creates a heuristic discovery engine backed by a Generator.
+func NewHeuristicDiscovery(log logrus.FieldLogger, gen *Generator) *HeuristicDiscovery {
+	return newHeuristicDiscovery(log, gen)
+}
+
+// newHeuristicDiscovery creates a heuristic discovery engine backed by any rangeQuerier.
+func newHeuristicDiscovery(log logrus.FieldLogger, querier rangeQuerier) *HeuristicDiscovery {
+	return &HeuristicDiscovery{
+		log:           log.WithField("component", "heuristic-discovery"),
+		querier:       querier,
+		samples:       DefaultHeuristicSamples,
+		maxExpansions: DefaultHeuristicMaxExpansions,
+	}
+}
+
+// SetSamples overrides the number of candidate windows sampled per range type.
+func (h *HeuristicDiscovery) SetSamples(samples int) {
+	if samples > 0 {
+		h.samples = samples
+	}
+}
+
+// SetMaxExpansions overrides how many times the window may be widened.
+func (h *HeuristicDiscovery) SetMaxExpansions(maxExpansions int) {
+	if maxExpansions >= 0 {
+		h.maxExpansions = maxExpansions
+	}
+}
+
+// heuristicModel holds the per-model state gathered during discovery.
+type heuristicModel struct {
+	model    string
+	rangeCol string
+	colType  RangeColumnType
+}
+
+// heuristicWindow is a candidate [from, to] window and its sampled row counts.
+type heuristicWindow struct {
+	from    string
+	to      string
+	counts  map[string]int64
+	minRows int64
+	total   int64
+}
+
+// Discover builds a range strategy for the given external models.
+//
+//nolint:funlen,gocognit,cyclop // Sequential discovery phases read best in one place
+func (h *HeuristicDiscovery) Discover(
+	ctx context.Context,
+	models []string,
+	network string,
+	rangeInfos map[string]*RangeColumnInfo,
+	duration string,
+	xatuCBTPath string,
+) (*DiscoveryResult, error) {
+	windowDuration, err := time.ParseDuration(duration)
+	if err != nil || windowDuration <= 0 {
+		windowDuration = 5 * time.Minute
+	}
+
+	intervalTypes, err := GetExternalModelIntervalTypes(models, xatuCBTPath)
+	if err != nil {
+		h.log.WithError(err).Warn("failed to get interval types from frontmatter, using column-based detection")
+
+		intervalTypes = nil
+	}
+
+	_, blockModels, entityModels, unknownModels := categorizeModelsByType(models, intervalTypes, rangeInfos)
+
+	var (
+		reasoning  = make([]string, 0, 8)
+		warnings   = make([]string, 0, 4)
+		strategies = make([]TableRangeStrategy, 0, len(models))
+		timeGroup  = make([]heuristicModel, 0, len(models))
+		blockGroup = make([]heuristicModel, 0, len(models))
+	)
+
+	for _, model := range models {
+		info := rangeInfos[model]
+
+		switch {
+		case contains(entityModels, model):
+			strategies = append(strategies, TableRangeStrategy{
+				Model:      model,
+				ColumnType: RangeColumnTypeNone,
+				Confidence: 0.7,
+				Reasoning:  "Entity interval type - dimension table, extracted without range filter",
+			})
+		case contains(blockModels, model):
+			rangeCol := BlockNumberColumn
+			if info != nil && info.RangeColumn != "" {
+				rangeCol = info.RangeColumn
+			}
+
+			blockGroup = append(blockGroup, heuristicModel{model: model, rangeCol: rangeCol, colType: RangeColumnTypeBlock})
+		case contains(unknownModels, model):
+			timeGroup = append(timeGroup, heuristicModel{model: model, rangeCol: DefaultRangeColumn, colType: RangeColumnTypeTime})
+		default:
+			rangeCol := DefaultRangeColumn
+			if info != nil && info.RangeColumn != "" {
+				rangeCol = info.RangeColumn
+			}
+
+			timeGroup = append(timeGroup, heuristicModel{model: model, rangeCol: rangeCol, colType: RangeColumnTypeTime})
+		}
+	}
+
+	if len(entityModels) > 0 {
+		reasoning = append(reasoning,
+			fmt.Sprintf("%d entity model(s) extracted in full: %s", len(entityModels), strings.Join(entityModels, ", ")))
+	}
+
+	var timeWindow, blockWindow *heuristicWindow
+
+	if len(timeGroup) > 0 {
+		timeWindow, err = h.selectTimeWindow(ctx, timeGroup, network, windowDuration, &reasoning, &warnings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(blockGroup) > 0 {
+		blockWindow, err = h.selectBlockWindow(ctx, blockGroup, network, windowDuration, &reasoning, &warnings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, group := range []struct {
+		members []heuristicModel
+		window  *heuristicWindow
+	}{
+		{timeGroup, timeWindow},
+		{blockGroup, blockWindow},
+	} {
+		for _, m := range group.members {
+			strategy := TableRangeStrategy{
+				Model:       m.model,
+				RangeColumn: m.rangeCol,
+				ColumnType:  m.colType,
+				Confidence:  0.5,
+				Reasoning:   "No common window found",
+			}
+
+			if group.window != nil {
+				strategy.FromValue = group.window.from
+				strategy.ToValue = group.window.to
+				strategy.Confidence = 0.75
+				strategy.Reasoning = fmt.Sprintf("%d rows in densest common window", group.window.counts[m.model])
+			}
+
+			strategies = append(strategies, strategy)
+		}
+	}
+
+	result := &DiscoveryResult{
+		Strategies:        strategies,
+		OverallConfidence: 0.7,
+		Warnings:          warnings,
+	}
+
+	switch {
+	case timeWindow != nil:
+		result.PrimaryRangeType = RangeColumnTypeTime
+		result.PrimaryRangeColumn = DefaultRangeColumn
+		result.FromValue = timeWindow.from
+		result.ToValue = timeWindow.to
+	case blockWindow != nil:
+		result.PrimaryRangeType = RangeColumnTypeBlock
+		result.PrimaryRangeColumn = BlockNumberColumn
+		result.FromValue = blockWindow.from
+		result.ToValue = blockWindow.to
+	case len(entityModels) > 0 && len(timeGroup) == 0 && len(blockGroup) == 0:
+		result.PrimaryRangeType = RangeColumnTypeNone
+	default:
+		return nil, fmt.Errorf("no valid range columns found for any model")
+	}
+
+	if timeWindow != nil && blockWindow != nil {
+		result.Warnings = append(result.Warnings,
+			"Mixed range column types detected (time and block). "+
+				"Each table type will use its own densest window.")
+	}
+
+	result.Summary = fmt.Sprintf("Heuristic density-based range selection (%d sampled window(s) per range type)", h.samples)
+	result.Reasoning = reasoning
+
+	return h.expandUntilValid(ctx, result, network)
+}
+
+// expandUntilValid widens the strategy until every model has data or the expansion budget is spent.
+func (h *HeuristicDiscovery) expandUntilValid(
+	ctx context.Context,
+	base *DiscoveryResult,
+	network string,
+) (*DiscoveryResult, error) {
+	result := base
+	factor := 1
+
+	for attempt := 0; ; attempt++ {
+		validation, err := validateStrategyHasData(ctx, h.querier, result, network)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate strategy: %w", err)
+		}
+
+		if validation.AllHaveData {
+			if attempt > 0 {
+				result.Reasoning = append(result.Reasoning,
+					fmt.Sprintf("Window widened %dx until all models had data (fewest rows: %s with %d)",
+						factor, validation.MinRowModel, validation.MinRowCount))
+			}
+
+			return result, nil
+		}
+
+		if attempt >= h.maxExpansions || base.PrimaryRangeType == RangeColumnTypeNone {
+			missing := append(append([]string{}, validation.EmptyModels...), validation.ErroredModels...)
+
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("No data found for %s after widening the window %dx", strings.Join(missing, ", "), factor))
+			result.OverallConfidence *= 0.5
+
+			return result, nil
+		}
+
+		factor *= ExpandWindowMultiplier
+
+		h.log.WithFields(logrus.Fields{
+			"empty":   validation.EmptyModels,
+			"errored": validation.ErroredModels,
+			"factor":  factor,
+		}).Debug("widening heuristic window")
+
+		expanded := SuggestExpandedStrategy(base, factor)
+		expanded.Reasoning = append([]string{}, base.Reasoning...)
+		result = expanded
+	}
+}
+
+// selectTimeWindow finds the common time range of all models and samples it for density.
+func (h *HeuristicDiscovery) selectTimeWindow(
+	ctx context.Context,
+	members []heuristicModel,
+	network string,
+	windowDuration time.Duration,
+	reasoning, warnings *[]string,
+) (*heuristicWindow, error) {
+	var latestMin, earliestMax time.Time
+
+	for _, m := range members {
+		modelRange, err := h.querier.QueryModelRange(ctx, m.model, network, m.rangeCol)
+		if err != nil {
+			h.log.WithError(err).WithField(fieldKeyModel, m.model).Warn("range query failed")
+			*warnings = append(*warnings, fmt.Sprintf("Range query failed for %s: %v", m.model, err))
+
+			continue
+		}
+
+		if latestMin.IsZero() || modelRange.Min.After(latestMin) {
+			latestMin = modelRange.Min
+		}
+
+		if earliestMax.IsZero() || modelRange.Max.Before(earliestMax) {
+			earliestMax = modelRange.Max
+		}
+	}
+
+	if latestMin.IsZero() || earliestMax.IsZero() {
+		return nil, nil //nolint:nilnil // No time ranges could be queried; caller reports it
+	}
+
+	if latestMin.After(earliestMax) {
+		*warnings = append(*warnings, "Time ranges of the dependencies do not overlap, using latest available data")
+		earliestMax = latestMin.Add(windowDuration)
+	}
+
+	*reasoning = append(*reasoning, fmt.Sprintf("Common time range across %d model(s): %s → %s",
+		len(members), latestMin.Format(heuristicTimeLayout), earliestMax.Format(heuristicTimeLayout)))
+
+	end := earliestMax.Add(-heuristicSafetyMargin)
+	candidates := make([][2]string, 0, h.samples)
+
+	for i := 0; i < h.samples; i++ {
+		to := end.Add(-time.Duration(i) * windowDuration)
+		from := to.Add(-windowDuration)
+
+		if from.Before(latestMin) {
+			if i == 0 {
+				candidates = append(candidates, [2]string{
+					latestMin.Format(heuristicTimeLayout), to.Format(heuristicTimeLayout),
+				})
+			}
+
+			break
+		}
+
+		candidates = append(candidates, [2]string{from.Format(heuristicTimeLayout), to.Format(heuristicTimeLayout)})
+	}
+
+	return h.pickDensest(ctx, members, network, candidates, windowDuration.String(), reasoning), nil
+}
+
+// selectBlockWindow finds the common block range of all models and samples it for density.
+func (h *HeuristicDiscovery) selectBlockWindow(
+	ctx context.Context,
+	members []heuristicModel,
+	network string,
+	windowDuration time.Duration,
+	reasoning, warnings *[]string,
+) (*heuristicWindow, error) {
+	var latestMin, earliestMax int64
+
+	found := false
+
+	for _, m := range members {
+		raw, err := h.querier.QueryModelRangeRaw(ctx, m.model, network, m.rangeCol)
+		if err != nil {
+			h.log.WithError(err).WithField(fieldKeyModel, m.model).Warn("range query failed")
+			*warnings = append(*warnings, fmt.Sprintf("Range query failed for %s: %v", m.model, err))
+
+			continue
+		}
+
+		minBlock, minErr := strconv.ParseInt(strings.TrimSpace(raw.MinRaw), 10, 64)
+		maxBlock, maxErr := strconv.ParseInt(strings.TrimSpace(raw.MaxRaw), 10, 64)
+
+		if minErr != nil || maxErr != nil {
+			*warnings = append(*warnings, fmt.Sprintf("Could not parse block range for %s", m.model))
+
+			continue
+		}
+
+		if !found || minBlock > latestMin {
+			latestMin = minBlock
+		}
+
+		if !found || maxBlock < earliestMax {
+			earliestMax = maxBlock
+		}
+
+		found = true
+	}
+
+	if !found {
+		return nil, nil //nolint:nilnil // No block ranges could be queried; caller reports it
+	}
+
+	blocksPerWindow := max(int64(windowDuration.Seconds()/heuristicSecondsPerBlock), heuristicMinBlocks)
+
+	if latestMin > earliestMax {
+		*warnings = append(*warnings, "Block ranges of the dependencies do not overlap, using latest available data")
+		earliestMax = latestMin + blocksPerWindow
+	}
+
+	*reasoning = append(*reasoning, fmt.Sprintf("Common block range across %d model(s): %d → %d",
+		len(members), latestMin, earliestMax))
+
+	end := earliestMax - heuristicReorgMargin
+	candidates := make([][2]string, 0, h.samples)
+
+	for i := 0; i < h.samples; i++ {
+		to := end - int64(i)*blocksPerWindow
+		from := to - blocksPerWindow
+
+		if from < latestMin {
+			if i == 0 {
+				candidates = append(candidates, [2]string{strconv.FormatInt(latestMin, 10), strconv.FormatInt(to, 10)})
+			}
+
+			break
+		}
+
+		candidates = append(candidates, [2]string{strconv.FormatInt(from, 10), strconv.FormatInt(to, 10)})
+	}
+
+	return h.pickDensest(ctx, members, network, candidates, fmt.Sprintf("%d blocks", blocksPerWindow), reasoning), nil
+}
+
+// pickDensest counts rows per model for each candidate window and returns the one whose
+// sparsest model has the most rows, breaking ties by total rows and then recency.
+func (h *HeuristicDiscovery) pickDensest(
+	ctx context.Context,
+	members []heuristicModel,
+	network string,
+	candidates [][2]string,
+	windowLabel string,
+	reasoning *[]string,
+) *heuristicWindow {
+	var best *heuristicWindow
+
+	for _, candidate := range candidates {
+		window := &heuristicWindow{
+			from:    candidate[0],
+			to:      candidate[1],
+			counts:  make(map[string]int64, len(members)),
+			minRows: -1,
+		}
+
+		for _, m := range members {
+			count, err := h.querier.QueryRowCount(ctx, m.model, network, m.rangeCol, window.from, window.to, "", "")
+			if err != nil {
+				h.log.WithError(err).WithField(fieldKeyModel, m.model).Debug("density sample failed")
+
+				count = 0
+			}
+
+			window.counts[m.model] = count
+			window.total += count
+
+			if window.minRows < 0 || count < window.minRows {
+				window.minRows = count
+			}
+		}
+
+		if best == nil || window.minRows > best.minRows ||
+			(window.minRows == best.minRows && window.total > best.total) {
+			best = window
+		}
+	}
+
+	if best != nil {
+		*reasoning = append(*reasoning, fmt.Sprintf(
+			"Sampled %d window(s) of %s; chose %s → %s (sparsest model %d rows, %d rows total)",
+			len(candidates), windowLabel, best.from, best.to, best.minRows, best.total))
+	}
+
+	return best
+}
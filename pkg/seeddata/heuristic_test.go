@@ -0,0 +1,172 @@
+package seeddata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRangeQuerier serves model ranges and per-minute row densities from memory.
+type fakeRangeQuerier struct {
+	ranges  map[string][2]time.Time
+	density map[string]func(minute time.Time) int64
+}
+
+func (f *fakeRangeQuerier) QueryModelRange(_ context.Context, model, network, rangeColumn string) (*ModelRange, error) {
+	r := f.ranges[model]
+
+	return &ModelRange{Model: model, Network: network, RangeColumn: rangeColumn, Min: r[0], Max: r[1]}, nil
+}
+
+func (f *fakeRangeQuerier) QueryModelRangeRaw(_ context.Context, model, network, rangeColumn string) (*ModelRangeRaw, error) {
+	return &ModelRangeRaw{Model: model, Network: network, RangeColumn: rangeColumn}, nil
+}
+
+func (f *fakeRangeQuerier) QueryRowCount(
+	_ context.Context,
+	model, _, _, fromValue, toValue, _, _ string,
+) (int64, error) {
+	from, err := time.Parse(heuristicTimeLayout, fromValue)
+	if err != nil {
+		return 0, err
+	}
+
+	to, err := time.Parse(heuristicTimeLayout, toValue)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+
+	for minute := from; minute.Before(to); minute = minute.Add(time.Minute) {
+		total += f.density[model](minute)
+	}
+
+	return total, nil
+}
+
+func TestHeuristicDiscoveryPicksDensestCommonWindow(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dense := base.Add(40 * time.Minute)
+
+	querier := &fakeRangeQuerier{
+		ranges: map[string][2]time.Time{
+			"a": {base, base.Add(2 * time.Hour)},
+			"b": {base.Add(10 * time.Minute), base.Add(61 * time.Minute)},
+		},
+		density: map[string]func(time.Time) int64{
+			"a": func(time.Time) int64 { return 10 },
+			"b": func(minute time.Time) int64 {
+				if !minute.Before(dense) && minute.Before(dense.Add(10*time.Minute)) {
+					return 5
+				}
+
+				return 0
+			},
+		},
+	}
+
+	rangeInfos := map[string]*RangeColumnInfo{
+		"a": {Model: "a", RangeColumn: DefaultRangeColumn},
+		"b": {Model: "b", RangeColumn: DefaultRangeColumn},
+	}
+
+	engine := newHeuristicDiscovery(logrus.New(), querier)
+
+	result, err := engine.Discover(context.Background(), []string{"a", "b"}, "mainnet", rangeInfos, "10m", t.TempDir())
+	require.NoError(t, err)
+
+	require.Equal(t, RangeColumnTypeTime, result.PrimaryRangeType)
+	require.Equal(t, "2025-01-01 00:40:00", result.FromValue)
+	require.Equal(t, "2025-01-01 00:50:00", result.ToValue)
+	require.Len(t, result.Strategies, 2)
+	require.NotEmpty(t, result.Reasoning)
+
+	for _, strategy := range result.Strategies {
+		require.Equal(t, result.FromValue, strategy.FromValue)
+		require.Equal(t, result.ToValue, strategy.ToValue)
+	}
+}
+
+func TestHeuristicDiscoveryWidensUntilAllModelsHaveData(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sparse := base.Add(5 * time.Minute)
+
+	querier := &fakeRangeQuerier{
+		ranges: map[string][2]time.Time{
+			"a": {base, base.Add(31 * time.Minute)},
+			"b": {base, base.Add(31 * time.Minute)},
+		},
+		density: map[string]func(time.Time) int64{
+			"a": func(time.Time) int64 { return 10 },
+			"b": func(minute time.Time) int64 {
+				if minute.Equal(sparse) {
+					return 1
+				}
+
+				return 0
+			},
+		},
+	}
+
+	rangeInfos := map[string]*RangeColumnInfo{
+		"a": {Model: "a", RangeColumn: DefaultRangeColumn},
+		"b": {Model: "b", RangeColumn: DefaultRangeColumn},
+	}
+
+	engine := newHeuristicDiscovery(logrus.New(), querier)
+	engine.SetSamples(1)
+
+	result, err := engine.Discover(context.Background(), []string{"a", "b"}, "mainnet", rangeInfos, "5m", t.TempDir())
+	require.NoError(t, err)
+
+	validation, err := validateStrategyHasData(context.Background(), querier, result, "mainnet")
+	require.NoError(t, err)
+	require.True(t, validation.AllHaveData)
+	require.Equal(t, "2025-01-01 00:30:00", result.ToValue)
+	require.Contains(t, result.Summary, "expanded")
+}
+
+func TestSuggestExpandedStrategyWidensWindows(t *testing.T) {
+	original := &DiscoveryResult{
+		PrimaryRangeType:   RangeColumnTypeTime,
+		PrimaryRangeColumn: DefaultRangeColumn,
+		FromValue:          "2025-01-01 00:50:00",
+		ToValue:            "2025-01-01 01:00:00",
+		Strategies: []TableRangeStrategy{
+			{
+				Model:       "time_model",
+				RangeColumn: DefaultRangeColumn,
+				ColumnType:  RangeColumnTypeTime,
+				FromValue:   "2025-01-01 00:50:00",
+				ToValue:     "2025-01-01 01:00:00",
+			},
+			{
+				Model:       "block_model",
+				RangeColumn: BlockNumberColumn,
+				ColumnType:  RangeColumnTypeBlock,
+				FromValue:   "900",
+				ToValue:     "1000",
+			},
+			{
+				Model:      "entity_model",
+				ColumnType: RangeColumnTypeNone,
+			},
+		},
+	}
+
+	expanded := SuggestExpandedStrategy(original, 4)
+
+	require.Equal(t, "2025-01-01 00:20:00", expanded.FromValue)
+	require.Equal(t, "2025-01-01 01:00:00", expanded.ToValue)
+	require.Equal(t, "2025-01-01 00:20:00", expanded.Strategies[0].FromValue)
+	require.Equal(t, "600", expanded.Strategies[1].FromValue)
+	require.Equal(t, "1000", expanded.Strategies[1].ToValue)
+	require.Empty(t, expanded.Strategies[2].FromValue)
+
+	// The original strategy must not be mutated.
+	require.Equal(t, "900", original.Strategies[1].FromValue)
+}
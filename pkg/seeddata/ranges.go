@@ -35,7 +35,7 @@ type clickHouseJSONResponse struct {
 // QueryModelRange queries external ClickHouse for a model's available data range.
 // Uses ORDER BY ... LIMIT 1 instead of MIN/MAX for better performance on large tables.
 func (g *Generator) QueryModelRange(ctx context.Context, model, network, rangeColumn string) (*ModelRange, error) {
-	tableRef := g.resolveTableRef(model)
+	tableRef := g.resolveTableRef(model, network)
 
 	// Query for minimum value (oldest data)
 	minQuery := fmt.Sprintf(`
@@ -97,7 +97,7 @@ type ModelRangeRaw struct {
 // returning raw string values without attempting to parse as time.
 // This handles block numbers, slots, epochs, and any other numeric column types.
 func (g *Generator) QueryModelRangeRaw(ctx context.Context, model, network, rangeColumn string) (*ModelRangeRaw, error) {
-	tableRef := g.resolveTableRef(model)
+	tableRef := g.resolveTableRef(model, network)
 
 	// Query for minimum value (oldest data)
 	minQuery := fmt.Sprintf(`
@@ -53,9 +53,10 @@ func GenerateSalt() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// DescribeTable queries ClickHouse to get the schema for a table.
-func (g *Generator) DescribeTable(ctx context.Context, model string) ([]ColumnInfo, error) {
-	tableRef := g.resolveTableRef(model)
+// DescribeTable queries ClickHouse to get the schema for a table in a
+// network's database.
+func (g *Generator) DescribeTable(ctx context.Context, model, network string) ([]ColumnInfo, error) {
+	tableRef := g.resolveTableRef(model, network)
 	query := fmt.Sprintf("DESCRIBE TABLE %s FORMAT JSON", tableRef)
 
 	chURL, err := g.buildClickHouseHTTPURL()
@@ -107,8 +108,11 @@ func (g *Generator) DescribeTable(ctx context.Context, model string) ([]ColumnIn
 
 // BuildSanitizedColumnList builds a complete SELECT column list with IP sanitization.
 // Returns the column expressions and a list of which columns were sanitized.
-func (g *Generator) BuildSanitizedColumnList(ctx context.Context, model, salt string) (*SanitizedColumnResult, error) {
-	columns, err := g.DescribeTable(ctx, model)
+func (g *Generator) BuildSanitizedColumnList(
+	ctx context.Context,
+	model, network, salt string,
+) (*SanitizedColumnResult, error) {
+	columns, err := g.DescribeTable(ctx, model, network)
 	if err != nil {
 		return nil, fmt.Errorf("failed to describe table %s: %w", model, err)
 	}
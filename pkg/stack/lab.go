@@ -16,9 +16,11 @@ import (
 	"github.com/ethpandaops/xcli/pkg/constants"
 	"github.com/ethpandaops/xcli/pkg/diagnostic"
 	"github.com/ethpandaops/xcli/pkg/discovery"
+	"github.com/ethpandaops/xcli/pkg/infrastructure"
 	"github.com/ethpandaops/xcli/pkg/instance"
 	"github.com/ethpandaops/xcli/pkg/orchestrator"
 	"github.com/ethpandaops/xcli/pkg/prerequisites"
+	"github.com/ethpandaops/xcli/pkg/toolchain"
 	"github.com/ethpandaops/xcli/pkg/ui"
 	"github.com/ethpandaops/xcli/pkg/version"
 	"github.com/ethpandaops/xcli/pkg/workspace"
@@ -39,7 +41,10 @@ type labStack struct {
 	// Flag values bound during ConfigureCommand.
 	upMode         string
 	upVerbose      bool
+	upNetworks     []string
+	upLocked       bool
 	rebuildVerbose bool
+	withDependents bool
 	statusAll      bool
 }
 
@@ -54,6 +59,40 @@ func (s *labStack) loadLabConfig(checkCWDOverrides bool) (*config.LabConfig, *wo
 	return workspace.LoadLabConfig(s.configPath, checkCWDOverrides)
 }
 
+// loadInstanceLabConfig loads the lab config with the profile the selected
+// instance was last started with, unless --profile chooses one explicitly.
+func (s *labStack) loadInstanceLabConfig(checkCWDOverrides bool) (*config.LabConfig, *workspace.Workspace, error) {
+	labCfg, ws, err := s.loadLabConfig(checkCWDOverrides)
+	if err != nil || config.ActiveProfile() != "" {
+		return labCfg, ws, err
+	}
+
+	instanceID, err := instance.ResolveID(ws, labCfg, s.instanceOverrideValue())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	registry, err := instance.DefaultRegistry()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, statErr := os.Stat(registry.ManifestPath(instanceID)); statErr != nil {
+		return labCfg, ws, nil
+	}
+
+	manifest, err := registry.Load(instanceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load instance %q manifest: %w", instanceID, err)
+	}
+
+	if manifest.Profile == "" {
+		return labCfg, ws, nil
+	}
+
+	return workspace.LoadLabConfigWithProfile(s.configPath, checkCWDOverrides, manifest.Profile)
+}
+
 func printRuntimeSelection(runtime *instance.Runtime) {
 	if runtime == nil || runtime.Manifest == nil {
 		return
@@ -61,6 +100,11 @@ func printRuntimeSelection(runtime *instance.Runtime) {
 
 	ui.Info(fmt.Sprintf("Instance: %s", runtime.InstanceID))
 	ui.Info(fmt.Sprintf("Config: %s", runtime.Manifest.ConfigPath))
+
+	if runtime.LabConfig != nil && runtime.LabConfig.Profile != "" {
+		ui.Info(fmt.Sprintf("Profile: %s", runtime.LabConfig.Profile))
+	}
+
 	ui.Info(fmt.Sprintf("Overrides: %s", runtime.Manifest.OverridesPath))
 	ui.Info(fmt.Sprintf("State dir: %s", runtime.Manifest.StateDir))
 }
@@ -78,7 +122,7 @@ func (s *labStack) loadLifecycleRuntime() (*instance.Runtime, error) {
 		return s.loadLifecycleRuntimeByID(override)
 	}
 
-	labCfg, ws, err := s.loadLabConfig(true)
+	labCfg, ws, err := s.loadInstanceLabConfig(true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
@@ -193,6 +237,7 @@ Verifies:
   - Prerequisites are met (node_modules, .env files, etc.)
   - Ports are available (not blocked by other processes)
   - Disk space is sufficient
+  - In xatu-local mode, the xatu stack's ClickHouse is running
 
 This is useful for:
   - Troubleshooting environment issues before 'xcli lab up'
@@ -209,26 +254,37 @@ Example:
 
 	case cmdUp:
 		cmd.Flags().StringVarP(&s.upMode, "mode", "m", "",
-			"Override mode (local or hybrid)")
+			"Override mode (local, hybrid or xatu-local)")
 		cmd.Flags().BoolVarP(&s.upVerbose, "verbose", "v", false,
 			"Show all build/setup command output (default: errors only)")
+		cmd.Flags().StringSliceVar(&s.upNetworks, "network", nil,
+			"Only start these networks' CBT services (repeatable; default: all enabled)")
+		cmd.Flags().BoolVar(&s.upLocked, "locked", false,
+			"Check out the commits in xcli.lock and fail if the workspace diverges from it")
 		cmd.Long = `Start the complete xcli lab stack including infrastructure and services.
 
 Prerequisites must be satisfied before running this command. If you haven't
 already, run 'xcli lab init' first to ensure all prerequisites are met.
 
-The stack starts in the configured mode (local or hybrid). Use 'xcli lab mode'
-to switch between modes.
+The stack starts in the configured mode (local, hybrid or xatu-local). Use
+'xcli lab mode' to switch between modes.
 
 This command always rebuilds all projects to ensure everything is up to date.
 
 Flags:
   --verbose   Enable verbose output for all operations
-  --mode      Override mode for this run (local or hybrid)
+  --mode      Override mode for this run (local, hybrid or xatu-local)
+  --network   Only start CBT and cbt-api for these networks; the others stay
+              stopped until 'xcli lab network start <name>'
+  --locked    Check out the repo commits recorded by 'xcli lab lock' before
+              building, and fail with a diff if the workspace still differs
+              from xcli.lock
 
 Examples:
-  xcli lab up              # Start all services (always rebuilds)
-  xcli lab up --verbose    # Startup with detailed output`
+  xcli lab up                      # Start all services (always rebuilds)
+  xcli lab up --verbose            # Startup with detailed output
+  xcli lab up --network sepolia    # Start only sepolia's CBT services
+  xcli lab up --locked             # Reproduce the stack pinned in xcli.lock`
 
 	case cmdDown:
 		cmd.Long = `Stop all running services and infrastructure in the xcli lab stack.
@@ -382,7 +438,18 @@ Example:
   xcli lab stop cbt-mainnet`
 
 	case cmdRestart:
-		cmd.Long = `Restart a specific lab service.`
+		cmd.Flags().BoolVar(&s.withDependents, "with-dependents", false,
+			"Also restart every service that depends on this one")
+		cmd.Long = `Restart a specific lab service.
+
+Services depend on each other: each cbt-api needs its network's CBT engine,
+lab-backend needs every cbt-api and Redis, and lab-frontend needs lab-backend.
+With --with-dependents the services that depend on the given one are stopped
+first and started again, in dependency order, once it is healthy.
+
+Examples:
+  xcli lab restart lab-backend
+  xcli lab restart cbt-mainnet --with-dependents`
 	}
 }
 
@@ -758,6 +825,25 @@ func (s *labStack) Check(ctx context.Context) error {
 		spinner.Success("Docker compose available")
 	}
 
+	spinner = renderer.Task("Checking toolchain")
+
+	if !checkToolchain(ctx, spinner, labCfg) {
+		allPassed = false
+	}
+
+	if labCfg != nil && labCfg.Mode == constants.ModeXatuLocal {
+		spinner = renderer.Task("Checking xatu stack")
+
+		clickhouse, err := infrastructure.FindXatuStackClickHouse(ctx, s.log, labCfg.XatuStack)
+		if err != nil {
+			spinner.Fail(fmt.Sprintf("Xatu stack not ready: %v", err))
+
+			allPassed = false
+		} else {
+			spinner.Success(fmt.Sprintf("Xatu stack ClickHouse %s on port %d", clickhouse.Service, clickhouse.Port))
+		}
+	}
+
 	renderer.Blank()
 
 	if allPassed {
@@ -772,9 +858,52 @@ func (s *labStack) Check(ctx context.Context) error {
 	return fmt.Errorf("environment checks failed")
 }
 
+// checkToolchain compares the installed tools with the versions the lab
+// repositories declare, failing the task with a remediation per tool.
+func checkToolchain(ctx context.Context, task ui.Task, labCfg *config.LabConfig) bool {
+	reqs := toolchain.Baseline()
+
+	var readErr error
+
+	if labCfg != nil {
+		repoReqs, err := toolchain.ForRepos(labCfg.Repos.Map())
+		reqs = append(reqs, repoReqs...)
+		readErr = err
+	}
+
+	var (
+		installed []string
+		failMsg   strings.Builder
+	)
+
+	for _, result := range toolchain.Check(ctx, reqs) {
+		if result.OK() {
+			installed = append(installed, fmt.Sprintf("%s %s", result.Tool, result.Installed))
+
+			continue
+		}
+
+		fmt.Fprintf(&failMsg, "\n    %s", result.Remediation)
+	}
+
+	if readErr != nil {
+		fmt.Fprintf(&failMsg, "\n    Could not read toolchain requirements: %v", readErr)
+	}
+
+	if failMsg.Len() > 0 {
+		task.Fail("Toolchain does not match the repositories' requirements" + failMsg.String())
+
+		return false
+	}
+
+	task.Success(fmt.Sprintf("Toolchain ready (%s)", strings.Join(installed, ", ")))
+
+	return true
+}
+
 // Up starts the lab stack.
 func (s *labStack) Up(ctx context.Context) error {
-	labCfg, ws, err := s.loadLabConfig(true)
+	labCfg, ws, err := s.loadInstanceLabConfig(true)
 	if err != nil {
 		return err
 	}
@@ -787,6 +916,12 @@ func (s *labStack) Up(ctx context.Context) error {
 		return fmt.Errorf("invalid lab configuration: %w", validationErr)
 	}
 
+	if s.upLocked {
+		if lockErr := checkLock(ctx, ws, labCfg, true); lockErr != nil {
+			return lockErr
+		}
+	}
+
 	runtime, err := instance.NewRuntimeFromWorkspace(ctx, ws, labCfg, s.instanceOverrideValue(), instance.RuntimeOptions{
 		ClaimPorts: true,
 		ProbePorts: true,
@@ -804,6 +939,10 @@ func (s *labStack) Up(ctx context.Context) error {
 
 	orch.SetVerbose(s.upVerbose)
 
+	if err := orch.SetUpNetworks(s.upNetworks); err != nil {
+		return err
+	}
+
 	// Derive a cancelable context so the live renderer can translate ctrl+c
 	// (which raw-mode swallows from the process signal handler) into a graceful
 	// shutdown. Verbose mode streams logs to stdout, which would corrupt a live
@@ -1144,7 +1283,12 @@ func (s *labStack) printAllInstanceStatus(ctx context.Context) error {
 		return err
 	}
 
-	result, err := instance.NewReconciler(registry).ReconcileAll(ctx)
+	labCfg, _, err := s.loadLabConfig(false)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	result, err := instance.NewReconciler(registry, labCfg.Infrastructure.Docker).ReconcileAll(ctx)
 	if err != nil {
 		return err
 	}
@@ -1269,6 +1413,44 @@ func (s *labStack) Stop(ctx context.Context, service string) error {
 	})
 }
 
+// StartNetwork starts one network's CBT services on the running stack.
+func (s *labStack) StartNetwork(ctx context.Context, network string) error {
+	return s.withRuntimeOrchestrator(ctx, lifecycleRuntimeSelected, func(runtime *instance.Runtime, orch *orchestrator.Orchestrator) error {
+		printRuntimeSelection(runtime)
+
+		if err := orch.StartNetwork(ctx, network); err != nil {
+			return err
+		}
+
+		ui.Success(fmt.Sprintf("Network %s started", network))
+
+		return nil
+	})
+}
+
+// StopNetwork stops one network's CBT services on the running stack.
+func (s *labStack) StopNetwork(ctx context.Context, network string, resetBounds bool) error {
+	return s.withRuntimeOrchestrator(ctx, lifecycleRuntimeSelected, func(runtime *instance.Runtime, orch *orchestrator.Orchestrator) error {
+		printRuntimeSelection(runtime)
+
+		if err := orch.StopNetwork(ctx, network, resetBounds); err != nil {
+			return err
+		}
+
+		ui.Success(fmt.Sprintf("Network %s stopped", network))
+
+		return nil
+	})
+}
+
+// ResetNetworkRedis flushes one network's Redis database on the selected
+// instance.
+func (s *labStack) ResetNetworkRedis(ctx context.Context, network string) error {
+	return s.withRuntimeOrchestrator(ctx, lifecycleRuntimeSelected, func(_ *instance.Runtime, orch *orchestrator.Orchestrator) error {
+		return orch.ResetNetworkRedis(ctx, network)
+	})
+}
+
 // Restart restarts a specific lab service.
 func (s *labStack) Restart(ctx context.Context, service string) error {
 	return s.withRuntimeOrchestrator(ctx, lifecycleRuntimeSelected, func(runtime *instance.Runtime, orch *orchestrator.Orchestrator) error {
@@ -1285,6 +1467,23 @@ func (s *labStack) Restart(ctx context.Context, service string) error {
 
 		spinner := ui.NewSpinner(fmt.Sprintf("Restarting %s", service))
 
+		if s.withDependents {
+			dependents, err := orch.RestartWithDependents(ctx, service)
+			if err != nil {
+				spinner.Fail(fmt.Sprintf("Failed to restart %s", service))
+
+				return fmt.Errorf("failed to restart service: %w", err)
+			}
+
+			if len(dependents) == 0 {
+				spinner.Success(fmt.Sprintf("%s restarted successfully (no dependents)", service))
+			} else {
+				spinner.Success(fmt.Sprintf("%s restarted with %s", service, strings.Join(dependents, ", ")))
+			}
+
+			return nil
+		}
+
 		if err := orch.Restart(ctx, service); err != nil {
 			spinner.Fail(fmt.Sprintf("Failed to restart %s", service))
 
@@ -1304,7 +1503,7 @@ func (s *labStack) runFullRebuild(
 	stateDir string,
 ) error {
 	report := diagnostic.NewRebuildReport()
-	store := diagnostic.NewStore(s.log, filepath.Join(stateDir, "errors"))
+	store := diagnostic.NewStore(s.log, filepath.Join(stateDir, constants.DirErrors))
 
 	// Verbose mode streams build output to stdout, which would corrupt a live
 	// frame, so it uses the plain renderer.
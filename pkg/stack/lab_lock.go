@@ -0,0 +1,197 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/ethpandaops/xcli/pkg/config"
+	xcligit "github.com/ethpandaops/xcli/pkg/git"
+	"github.com/ethpandaops/xcli/pkg/instance"
+	"github.com/ethpandaops/xcli/pkg/ui"
+	"github.com/ethpandaops/xcli/pkg/workspace"
+)
+
+// WriteLock writes xcli.lock for the workspace. With fromInstance the repo
+// commits and mode are those the selected instance was last started with,
+// instead of the current checkouts.
+func (s *labStack) WriteLock(ctx context.Context, fromInstance bool) error {
+	labCfg, ws, err := s.loadInstanceLabConfig(false)
+	if err != nil {
+		return err
+	}
+
+	var repos map[string]xcligit.RepoVersion
+
+	if fromInstance {
+		manifest, loadErr := s.loadManifest(ws, labCfg)
+		if loadErr != nil {
+			return loadErr
+		}
+
+		repos = manifest.Repos
+		labCfg.Mode = manifest.Mode
+	} else {
+		repos, err = instance.SnapshotLabRepos(ctx, labCfg)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot lab repos: %w", err)
+		}
+	}
+
+	lock, err := instance.NewLock(ctx, ws, labCfg, repos)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(ws.RootDir, instance.LockFileName)
+	if err := lock.Write(path); err != nil {
+		return err
+	}
+
+	var (
+		rows  = make([][]string, 0, len(lock.Repos)+1)
+		dirty []string
+	)
+
+	for _, repo := range labCfg.Repos.Ordered() {
+		locked := lock.Repos[repo.Name]
+		rows = append(rows, lockedRepoRow(repo.Name, locked))
+
+		if locked.Dirty {
+			dirty = append(dirty, repo.Name)
+		}
+	}
+
+	if lock.Xatu != nil {
+		rows = append(rows, lockedRepoRow(instance.LockRepoXatu, *lock.Xatu))
+
+		if lock.Xatu.Dirty {
+			dirty = append(dirty, instance.LockRepoXatu)
+		}
+	}
+
+	ui.Table([]string{"Repository", "Branch", "Commit"}, rows)
+
+	for _, name := range dirty {
+		ui.Warning(fmt.Sprintf("%s has uncommitted changes that the lock cannot reproduce", name))
+	}
+
+	ui.Success(fmt.Sprintf("Wrote %s (mode %s)", path, lock.Mode))
+
+	return nil
+}
+
+// CheckLock compares the workspace with xcli.lock and fails with a diff
+// when they diverge. With restore, clean repositories are first checked out
+// at their locked commits.
+func (s *labStack) CheckLock(ctx context.Context, restore bool) error {
+	labCfg, ws, err := s.loadInstanceLabConfig(false)
+	if err != nil {
+		return err
+	}
+
+	return checkLock(ctx, ws, labCfg, restore)
+}
+
+func checkLock(ctx context.Context, ws *workspace.Workspace, labCfg *config.LabConfig, restore bool) error {
+	path := filepath.Join(ws.RootDir, instance.LockFileName)
+
+	lock, err := instance.ReadLock(path)
+	if err != nil {
+		return err
+	}
+
+	if restore {
+		restoreLockedRepos(ctx, labCfg, lock)
+	}
+
+	repos, err := instance.SnapshotLabRepos(ctx, labCfg)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot lab repos: %w", err)
+	}
+
+	current, err := instance.NewLock(ctx, ws, labCfg, repos)
+	if err != nil {
+		return err
+	}
+
+	if lock.XcliVersion != current.XcliVersion {
+		ui.Warning(fmt.Sprintf("%s was written by xcli %s, this is %s", instance.LockFileName,
+			lock.XcliVersion, current.XcliVersion))
+	}
+
+	diffs := lock.Diff(current)
+	if len(diffs) == 0 {
+		ui.Success(fmt.Sprintf("Workspace matches %s", instance.LockFileName))
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(diffs))
+	for _, diff := range diffs {
+		rows = append(rows, []string{diff.Setting, diff.Locked, diff.Workspace})
+	}
+
+	ui.Table([]string{"Setting", instance.LockFileName, "Workspace"}, rows)
+
+	return fmt.Errorf("workspace diverges from %s (%d differences)", path, len(diffs))
+}
+
+// restoreLockedRepos checks out every locked commit it can. Repositories it
+// cannot restore are left for the diff to report.
+func restoreLockedRepos(ctx context.Context, labCfg *config.LabConfig, lock *instance.Lock) {
+	paths := labCfg.Repos.Map()
+	targets := make(map[string]instance.LockedRepo, len(lock.Repos)+1)
+
+	for name, repo := range lock.Repos {
+		targets[name] = repo
+	}
+
+	if lock.Xatu != nil && labCfg.XatuStack != nil {
+		paths[instance.LockRepoXatu] = labCfg.XatuStack.Repos.Xatu
+		targets[instance.LockRepoXatu] = *lock.Xatu
+	}
+
+	for name, repo := range targets {
+		path := paths[name]
+		if path == "" {
+			continue
+		}
+
+		changed, err := xcligit.Restore(ctx, path, repo.RepoVersion(path))
+
+		switch {
+		case errors.Is(err, xcligit.ErrDirty):
+			ui.Warning(fmt.Sprintf("%s has uncommitted changes, not checking out %s", name, xcligit.ShortCommit(repo.Commit)))
+		case err != nil:
+			ui.Warning(fmt.Sprintf("Failed to check out %s at %s: %v", name, xcligit.ShortCommit(repo.Commit), err))
+		case changed:
+			ui.Info(fmt.Sprintf("Checked out %s at %s", name, xcligit.ShortCommit(repo.Commit)))
+		}
+	}
+}
+
+// loadManifest loads the manifest of the selected instance.
+func (s *labStack) loadManifest(ws *workspace.Workspace, labCfg *config.LabConfig) (*instance.Manifest, error) {
+	instanceID, err := instance.ResolveID(ws, labCfg, s.instanceOverrideValue())
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := instance.DefaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := registry.Load(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instance %q manifest - start it with 'xcli lab up' first: %w", instanceID, err)
+	}
+
+	return manifest, nil
+}
+
+func lockedRepoRow(name string, repo instance.LockedRepo) []string {
+	return []string{name, repo.Branch, xcligit.ShortCommit(repo.Commit)}
+}
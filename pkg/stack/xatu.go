@@ -7,7 +7,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ethpandaops/xcli/pkg/compose"
 	"github.com/ethpandaops/xcli/pkg/config"
@@ -24,10 +26,18 @@ type xatuStack struct {
 	configPath string
 
 	// Flag values bound during ConfigureCommand.
-	upBuild     bool
-	downVolumes bool
+	upBuild       bool
+	upWait        bool
+	upWaitTimeout time.Duration
+	downVolumes   bool
 }
 
+// Defaults for 'xatu up --wait'.
+const (
+	xatuWaitTimeout  = 5 * time.Minute
+	xatuWaitLogLines = 20
+)
+
 // NewXatuStack creates a new xatu stack instance.
 func NewXatuStack(log logrus.FieldLogger, configPath string) Stack {
 	return &xatuStack{log: log, configPath: configPath}
@@ -70,17 +80,29 @@ Example:
 	case "up":
 		cmd.Flags().BoolVar(&s.upBuild, "build", false,
 			"Build images before starting containers")
+		cmd.Flags().BoolVar(&s.upWait, "wait", false,
+			"Wait until all services are healthy")
+		cmd.Flags().DurationVar(&s.upWaitTimeout, "wait-timeout", xatuWaitTimeout,
+			"How long --wait waits for services to become healthy")
 		cmd.Long = `Start the xatu docker-compose stack.
 
 This runs 'docker compose up -d' in the xatu repository directory,
 using any configured profiles and environment overrides.
 
+With --wait, xcli then polls the containers until every service is running
+and passes its healthcheck (one-shot services must exit 0). If a service
+exits with an error or the timeout passes, the last log lines of each
+service that is not ready are printed and the command fails.
+
 Flags:
-  --build   Build images before starting containers
+  --build          Build images before starting containers
+  --wait           Wait until all services are healthy
+  --wait-timeout   Timeout for --wait (default 5m)
 
 Examples:
   xcli xatu up              # Start all services
-  xcli xatu up --build      # Build images and start`
+  xcli xatu up --build      # Build images and start
+  xcli xatu up --wait       # Start and wait for healthchecks`
 
 	case "down":
 		// No shorthand -v: conflicts with root persistent --verbose flag.
@@ -147,9 +169,10 @@ Examples:
 		cmd.Long = `Display status of all xatu services.
 
 Shows:
-  - Running services and their states
-  - Port bindings
-  - Container health
+  - All service containers and their states (exit codes for stopped ones)
+  - Healthcheck status
+  - How often docker restarted each container
+  - Published endpoints (host:port)
 
 Example:
   xcli xatu status`
@@ -495,9 +518,72 @@ func (s *xatuStack) Up(ctx context.Context) error {
 	ui.Success("Xatu stack started")
 	ui.Blank()
 
+	if s.upWait {
+		if err := waitForXatu(ctx, runner, s.upWaitTimeout); err != nil {
+			return err
+		}
+
+		ui.Blank()
+	}
+
 	return printXatuStatus(ctx, runner)
 }
 
+// waitForXatu waits until every xatu service is healthy, printing the log
+// tail of each service that is not when it gives up.
+func waitForXatu(ctx context.Context, runner *compose.Runner, timeout time.Duration) error {
+	spinner := ui.NewSpinner("Waiting for services to become healthy")
+
+	statuses, err := runner.WaitHealthy(ctx, timeout, func(statuses []compose.ServiceStatus) {
+		ready := 0
+
+		for _, st := range statuses {
+			if st.Ready() {
+				ready++
+			}
+		}
+
+		spinner.UpdateText(fmt.Sprintf("Waiting for services to become healthy (%d/%d)", ready, len(statuses)))
+	})
+	if err == nil {
+		spinner.Success(fmt.Sprintf("All %d services healthy", len(statuses)))
+
+		return nil
+	}
+
+	spinner.Fail("Services did not become healthy")
+
+	for _, st := range statuses {
+		if st.Ready() {
+			continue
+		}
+
+		ui.Blank()
+		ui.Warning(fmt.Sprintf("%s: %s", st.Service, describeXatuState(st)))
+
+		logs, logErr := runner.LogTail(ctx, st.Service, xatuWaitLogLines)
+		if logErr != nil || logs == "" {
+			continue
+		}
+
+		fmt.Println(logs)
+	}
+
+	return fmt.Errorf("xatu stack is not healthy: %w", err)
+}
+
+// describeXatuState renders a container's state with its health or exit code.
+func describeXatuState(st compose.ServiceStatus) string {
+	switch {
+	case st.State == compose.StateExited:
+		return fmt.Sprintf("exited (%d)", st.ExitCode)
+	case st.Health != "":
+		return fmt.Sprintf("%s (%s)", st.State, st.Health)
+	default:
+		return st.State
+	}
+}
+
 // Down stops the xatu docker-compose stack.
 func (s *xatuStack) Down(ctx context.Context) error {
 	xatuCfg, _, err := config.LoadXatuConfig(s.configPath)
@@ -769,23 +855,54 @@ func printXatuStatus(ctx context.Context, runner *compose.Runner) error {
 		return nil
 	}
 
-	headers := []string{"Service", "State", "Status", "Ports"}
+	containers := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		containers = append(containers, st.Name)
+	}
+
+	restarts, err := runner.RestartCounts(ctx, containers)
+	if err != nil {
+		restarts = map[string]int{}
+	}
+
+	headers := []string{"Service", "State", "Health", "Restarts", "Endpoints"}
 	rows := make([][]string, 0, len(statuses))
 
 	for _, st := range statuses {
 		state := st.State
-		if state == "running" {
+		if st.State == compose.StateExited {
+			state = fmt.Sprintf("exited (%d)", st.ExitCode)
+		}
+
+		if st.Ready() {
 			state = pterm.Green(state)
 		} else {
 			state = pterm.Red(state)
 		}
 
+		health := st.Health
+		switch health {
+		case "":
+			health = "-"
+		case compose.HealthHealthy:
+			health = pterm.Green(health)
+		case compose.HealthUnhealthy:
+			health = pterm.Red(health)
+		default:
+			health = pterm.Yellow(health)
+		}
+
 		name := st.Service
 		if name == "" {
 			name = st.Name
 		}
 
-		rows = append(rows, []string{name, state, st.Status, st.Ports})
+		endpoints := strings.Join(st.Endpoints(), ", ")
+		if endpoints == "" {
+			endpoints = "-"
+		}
+
+		rows = append(rows, []string{name, state, health, strconv.Itoa(restarts[st.Name]), endpoints})
 	}
 
 	ui.Table(headers, rows)
@@ -0,0 +1,306 @@
+package toolchain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// bufConfigV2MinVersion is the first buf release that reads v2 configs.
+const bufConfigV2MinVersion = "1.32.0"
+
+// goToolchainSwitchVersion is the first Go release that downloads the
+// toolchain a go.mod asks for.
+const goToolchainSwitchVersion = "1.21"
+
+// versionNumberRe finds a version number such as "20.11.0", "9" or the "20"
+// of "20.x".
+var versionNumberRe = regexp.MustCompile(`\d+(?:\.\d+){0,2}`)
+
+// bufConfigFiles are the files that mark a repository as generating with buf.
+var bufConfigFiles = []string{"buf.yaml", "buf.gen.yaml", "buf.work.yaml"}
+
+// makefileTools match Makefile recipes that call a protobuf tool.
+var makefileTools = []struct {
+	tool    string
+	pattern *regexp.Regexp
+}{
+	{ToolProtoc, regexp.MustCompile(`(?m)(^|[\s/(])protoc\s`)},
+	{ToolBuf, regexp.MustCompile(`(?m)(^|[\s/(])buf\s`)},
+}
+
+// packageJSON holds the package.json fields that declare toolchain versions.
+type packageJSON struct {
+	PackageManager string            `json:"packageManager"`
+	Engines        map[string]string `json:"engines"`
+}
+
+// ForRepos reads the requirements of every repository, keyed by name. Missing
+// repositories are skipped; read errors are joined and returned alongside
+// the requirements that could be read.
+func ForRepos(repos map[string]string) ([]Requirement, error) {
+	names := make([]string, 0, len(repos))
+	for name := range repos {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	var (
+		reqs []Requirement
+		errs []error
+	)
+
+	for _, name := range names {
+		if info, err := os.Stat(repos[name]); err != nil || !info.IsDir() {
+			continue
+		}
+
+		repoReqs, err := ReadRequirements(name, repos[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+
+		reqs = append(reqs, repoReqs...)
+	}
+
+	return reqs, errors.Join(errs...)
+}
+
+// ReadRequirements reads the toolchain requirements a repository declares:
+//   - go.mod's go and toolchain directives when GOTOOLCHAIN stops Go from
+//     downloading them, and Go 1.21 (which downloads them) otherwise
+//   - .nvmrc, .node-version and package.json engines for Node
+//   - package.json packageManager and engines, or pnpm-lock.yaml, for pnpm
+//   - buf config files, and Makefile targets that call buf or protoc
+func ReadRequirements(repo, path string) ([]Requirement, error) {
+	var reqs []Requirement
+
+	goReqs, err := readGoMod(repo, path)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs = append(reqs, goReqs...)
+
+	nodeReqs, err := readNode(repo, path)
+	if err != nil {
+		return reqs, err
+	}
+
+	reqs = append(reqs, nodeReqs...)
+
+	protoReqs, err := readProto(repo, path)
+	if err != nil {
+		return reqs, err
+	}
+
+	return append(reqs, protoReqs...), nil
+}
+
+func readGoMod(repo, path string) ([]Requirement, error) {
+	data, err := readOptional(filepath.Join(path, "go.mod"))
+	if data == nil || err != nil {
+		return nil, err
+	}
+
+	var goVersion, toolchainVersion string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			goVersion = fields[1]
+		case "toolchain":
+			toolchainVersion = strings.TrimPrefix(fields[1], "go")
+		}
+	}
+
+	req := Requirement{Tool: ToolGo, Version: goVersion, Source: repo + " go.mod"}
+
+	// Go 1.21+ downloads the go and toolchain directives' versions by itself,
+	// so only Go 1.21 is needed unless GOTOOLCHAIN forbids downloads.
+	if !goToolchainDownloads() {
+		if toolchainVersion != "" && CompareVersions(toolchainVersion, goVersion) > 0 {
+			req.Version = toolchainVersion
+			req.Source = repo + " go.mod toolchain"
+		}
+	} else if goVersion != "" && CompareVersions(goVersion, goToolchainSwitchVersion) > 0 {
+		req.Version = goToolchainSwitchVersion
+	}
+
+	return []Requirement{req}, nil
+}
+
+// goToolchainDownloads reports whether GOTOOLCHAIN lets the go command
+// download a newer toolchain: "local", "path" and "<name>+path" do not.
+func goToolchainDownloads() bool {
+	value := os.Getenv("GOTOOLCHAIN")
+
+	return value != "local" && value != "path" && !strings.HasSuffix(value, "+path")
+}
+
+func readNode(repo, path string) ([]Requirement, error) {
+	var reqs []Requirement
+
+	for _, file := range []string{".nvmrc", ".node-version"} {
+		data, err := readOptional(filepath.Join(path, file))
+		if err != nil {
+			return nil, err
+		}
+
+		// Aliases such as lts/iron name no version to compare with.
+		if version := minimumVersion(string(data)); version != "" {
+			reqs = append(reqs, Requirement{Tool: ToolNode, Version: version, Source: repo + " " + file})
+		}
+	}
+
+	data, err := readOptional(filepath.Join(path, "package.json"))
+	if data == nil || err != nil {
+		return reqs, err
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return reqs, fmt.Errorf("invalid package.json: %w", err)
+	}
+
+	reqs = append(reqs, Requirement{
+		Tool:    ToolNode,
+		Version: minimumVersion(pkg.Engines["node"]),
+		Source:  repo + " package.json",
+	})
+
+	if name, version, ok := strings.Cut(pkg.PackageManager, "@"); ok && name == ToolPnpm {
+		reqs = append(reqs, Requirement{
+			Tool:    ToolPnpm,
+			Version: minimumVersion(version),
+			Source:  repo + " packageManager",
+		})
+	}
+
+	if constraint, ok := pkg.Engines[ToolPnpm]; ok {
+		reqs = append(reqs, Requirement{
+			Tool:    ToolPnpm,
+			Version: minimumVersion(constraint),
+			Source:  repo + " package.json",
+		})
+	} else if _, statErr := os.Stat(filepath.Join(path, "pnpm-lock.yaml")); statErr == nil {
+		reqs = append(reqs, Requirement{Tool: ToolPnpm, Source: repo + " pnpm-lock.yaml"})
+	}
+
+	return reqs, nil
+}
+
+func readProto(repo, path string) ([]Requirement, error) {
+	var reqs []Requirement
+
+	for _, file := range bufConfigFiles {
+		data, err := readOptional(filepath.Join(path, file))
+		if err != nil {
+			return nil, err
+		}
+
+		if data == nil {
+			continue
+		}
+
+		req := Requirement{Tool: ToolBuf, Source: repo + " " + file}
+		if bytes.Contains(data, []byte("version: v2")) {
+			req.Version = bufConfigV2MinVersion
+		}
+
+		reqs = append(reqs, req)
+	}
+
+	makefile, err := readOptional(filepath.Join(path, "Makefile"))
+	if makefile == nil || err != nil {
+		return reqs, err
+	}
+
+	for _, mt := range makefileTools {
+		if mt.pattern.Match(makefile) {
+			reqs = append(reqs, Requirement{Tool: mt.tool, Source: repo + " Makefile"})
+		}
+	}
+
+	return reqs, nil
+}
+
+// minimumVersion returns the lowest version a semver range such as
+// ">=20.11.0", "^9", "20.x", ">=18 <21" or "18 || 20" accepts, or "" when it
+// has no lower bound. Upper bounds ("<21", "<=20") are ignored, since only
+// minimum versions are checked.
+func minimumVersion(constraint string) string {
+	var lowest string
+
+	for alternative := range strings.SplitSeq(constraint, "||") {
+		var (
+			bound string
+			upper bool
+		)
+
+		for comparator := range strings.FieldsSeq(alternative) {
+			// "18 - 20": everything after the hyphen is the upper bound.
+			if comparator == "-" {
+				break
+			}
+
+			// Skip "<21" and "<= 20", whose version follows the operator.
+			if strings.HasPrefix(comparator, "<") {
+				upper = versionNumberRe.FindString(comparator) == ""
+
+				continue
+			}
+
+			if upper {
+				upper = false
+
+				continue
+			}
+
+			version := versionNumberRe.FindString(comparator)
+			if version != "" && (bound == "" || CompareVersions(version, bound) > 0) {
+				bound = version
+			}
+		}
+
+		// An alternative without a lower bound accepts any version.
+		if bound == "" {
+			return ""
+		}
+
+		if lowest == "" || CompareVersions(bound, lowest) < 0 {
+			lowest = bound
+		}
+	}
+
+	return lowest
+}
+
+// readOptional reads a file, returning nil without an error when it does not
+// exist.
+func readOptional(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,100 @@
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRequirements(t *testing.T) {
+	t.Setenv("GOTOOLCHAIN", "")
+
+	repo := t.TempDir()
+	writeFile(t, repo, "go.mod", "module example.com/api\n\ngo 1.24.0\n\ntoolchain go1.24.4\n")
+	writeFile(t, repo, ".nvmrc", "v22.11.0\n")
+	writeFile(t, repo, "package.json", `{"packageManager": "pnpm@9.15.4+sha512.abc", "engines": {"node": ">=20"}}`)
+	writeFile(t, repo, "buf.gen.yaml", "version: v2\n")
+	writeFile(t, repo, "Makefile", "proto:\n\tprotoc --go_out=. api.proto\n")
+
+	reqs, err := ReadRequirements("api", repo)
+	require.NoError(t, err)
+	require.Equal(t, []Requirement{
+		{Tool: ToolGo, Version: goToolchainSwitchVersion, Source: "api go.mod"},
+		{Tool: ToolNode, Version: "22.11.0", Source: "api .nvmrc"},
+		{Tool: ToolNode, Version: "20", Source: "api package.json"},
+		{Tool: ToolPnpm, Version: "9.15.4", Source: "api packageManager"},
+		{Tool: ToolBuf, Version: bufConfigV2MinVersion, Source: "api buf.gen.yaml"},
+		{Tool: ToolProtoc, Source: "api Makefile"},
+	}, reqs)
+}
+
+func TestReadRequirementsLocalToolchain(t *testing.T) {
+	t.Setenv("GOTOOLCHAIN", "local")
+
+	repo := t.TempDir()
+	writeFile(t, repo, "go.mod", "module example.com/api\n\ngo 1.24.0\n\ntoolchain go1.24.4\n")
+
+	reqs, err := ReadRequirements("api", repo)
+	require.NoError(t, err)
+	require.Equal(t, []Requirement{{Tool: ToolGo, Version: "1.24.4", Source: "api go.mod toolchain"}}, reqs)
+}
+
+func TestReadRequirementsOldGoDirective(t *testing.T) {
+	t.Setenv("GOTOOLCHAIN", "")
+
+	repo := t.TempDir()
+	writeFile(t, repo, "go.mod", "module example.com/api\n\ngo 1.19\n")
+
+	reqs, err := ReadRequirements("api", repo)
+	require.NoError(t, err)
+	require.Equal(t, []Requirement{{Tool: ToolGo, Version: "1.19", Source: "api go.mod"}}, reqs)
+}
+
+func TestMinimumVersion(t *testing.T) {
+	for constraint, want := range map[string]string{
+		"":              "",
+		"lts/iron":      "",
+		"v22.11.0\n":    "22.11.0",
+		">=20.11.0":     "20.11.0",
+		"^9":            "9",
+		"~9.1":          "9.1",
+		"20.x":          "20",
+		"<21":           "",
+		"<= 20":         "",
+		">=18 <21":      "18",
+		">= 18 < 21":    "18",
+		"18 - 20":       "18",
+		"20 || 18":      "18",
+		">=18 || <16":   "",
+		"9.15.4+sha512": "9.15.4",
+	} {
+		require.Equal(t, want, minimumVersion(constraint), constraint)
+	}
+}
+
+func TestForRepos(t *testing.T) {
+	t.Setenv("GOTOOLCHAIN", "")
+
+	frontend := t.TempDir()
+	writeFile(t, frontend, "package.json", `{`)
+	writeFile(t, frontend, "pnpm-lock.yaml", "")
+
+	backend := t.TempDir()
+	writeFile(t, backend, "go.mod", "module example.com/backend\n\ngo 1.23\n")
+
+	reqs, err := ForRepos(map[string]string{
+		"backend":  backend,
+		"frontend": frontend,
+		"missing":  filepath.Join(t.TempDir(), "missing"),
+	})
+	require.ErrorContains(t, err, "frontend: invalid package.json")
+	require.Equal(t, []Requirement{{Tool: ToolGo, Version: goToolchainSwitchVersion, Source: "backend go.mod"}}, reqs)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
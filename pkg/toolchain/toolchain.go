@@ -0,0 +1,302 @@
+// Package toolchain detects the developer tools the lab repositories build
+// with and checks their installed versions against what is required.
+package toolchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/xcli/pkg/constants"
+)
+
+// detectTimeout bounds each tool's version command.
+const detectTimeout = 10 * time.Second
+
+// Tool names.
+const (
+	ToolGo     = "go"
+	ToolNode   = "node"
+	ToolPnpm   = "pnpm"
+	ToolDocker = "docker"
+	ToolProtoc = "protoc"
+	ToolBuf    = "buf"
+)
+
+// Status is the outcome of checking a tool.
+type Status string
+
+const (
+	// StatusOK means the tool is installed and satisfies the requirement.
+	StatusOK Status = "ok"
+	// StatusMissing means the tool is not installed or not in PATH.
+	StatusMissing Status = "missing"
+	// StatusOutdated means the installed version is older than required.
+	StatusOutdated Status = "outdated"
+	// StatusUnknown means the installed version could not be read.
+	StatusUnknown Status = "unknown"
+)
+
+// Tool describes how to find a tool's installed version and install it.
+type Tool struct {
+	Name    string
+	Command string
+	Args    []string
+	// Pattern captures the version from the command's output.
+	Pattern *regexp.Regexp
+	// Install explains how to install or upgrade the tool.
+	Install string
+}
+
+// tools lists the known tools in display order.
+var tools = []Tool{
+	{
+		Name:    ToolGo,
+		Command: "go",
+		Args:    []string{"version"},
+		Pattern: regexp.MustCompile(`go(\d+\.\d+(?:\.\d+)?)`),
+		Install: "https://go.dev/dl/ (or: brew install go)",
+	},
+	{
+		Name:    ToolNode,
+		Command: "node",
+		Args:    []string{"--version"},
+		Pattern: regexp.MustCompile(`v?(\d+\.\d+\.\d+)`),
+		Install: "nvm install (or: brew install node)",
+	},
+	{
+		Name:    ToolPnpm,
+		Command: "pnpm",
+		Args:    []string{"--version"},
+		Pattern: regexp.MustCompile(`(\d+\.\d+\.\d+)`),
+		Install: "corepack enable pnpm (or: npm install -g pnpm)",
+	},
+	{
+		Name:    ToolDocker,
+		Command: "docker",
+		Args:    []string{"--version"}, // Works without a running daemon
+		Pattern: regexp.MustCompile(`(\d+\.\d+\.\d+)`),
+		Install: "https://docs.docker.com/get-docker/",
+	},
+	{
+		Name:    ToolProtoc,
+		Command: "protoc",
+		Args:    []string{"--version"},
+		Pattern: regexp.MustCompile(`(\d+\.\d+(?:\.\d+)?)`),
+		Install: "brew install protobuf (Linux: apt install protobuf-compiler)",
+	},
+	{
+		Name:    ToolBuf,
+		Command: "buf",
+		Args:    []string{"--version"},
+		Pattern: regexp.MustCompile(`(\d+\.\d+\.\d+)`),
+		Install: "brew install bufbuild/buf/buf (or: go install github.com/bufbuild/buf/cmd/buf@latest)",
+	},
+}
+
+// Tools returns the known tools in display order.
+func Tools() []Tool {
+	return append([]Tool(nil), tools...)
+}
+
+// LookupTool returns the known tool with the given name.
+func LookupTool(name string) (Tool, bool) {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return tool, true
+		}
+	}
+
+	return Tool{}, false
+}
+
+// Requirement is a tool a repository needs, optionally at a minimum version.
+type Requirement struct {
+	Tool    string
+	Version string // Minimum version; empty when any version works
+	Source  string // Where the requirement comes from, e.g. "lab"
+}
+
+// Result is the outcome of checking one tool against its requirements.
+type Result struct {
+	Tool      string `json:"tool"`
+	Installed string `json:"installed,omitempty"`
+	Required  string `json:"required,omitempty"`
+	// Sources lists everything that requires the tool.
+	Sources []string `json:"sources"`
+	// RequiredBy lists the sources that require the Required version.
+	RequiredBy  []string `json:"requiredBy,omitempty"`
+	Status      Status   `json:"status"`
+	Remediation string   `json:"remediation,omitempty"`
+}
+
+// OK reports whether the tool is usable.
+func (r Result) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Baseline returns the tools the lab stack needs whatever its repositories
+// declare: Go for the backend repos, Node and pnpm for the frontend, and
+// Docker for the stack. Protobuf tools are only required by the repositories
+// that use them (see ReadRequirements).
+func Baseline() []Requirement {
+	return []Requirement{
+		{Tool: ToolGo, Source: "lab"},
+		{Tool: ToolNode, Source: constants.RepoLab},
+		{Tool: ToolPnpm, Source: constants.RepoLab},
+		{Tool: ToolDocker, Source: "lab"},
+	}
+}
+
+// Check detects every required tool and compares it with the highest
+// version required of it. Results are in display order.
+func Check(ctx context.Context, reqs []Requirement) []Result {
+	results := make([]Result, 0, len(tools))
+
+	for _, tool := range tools {
+		result := Result{Tool: tool.Name, Status: StatusOK}
+
+		for _, req := range reqs {
+			if req.Tool != tool.Name {
+				continue
+			}
+
+			if !slices.Contains(result.Sources, req.Source) {
+				result.Sources = append(result.Sources, req.Source)
+			}
+
+			if req.Version != "" && (result.Required == "" || CompareVersions(req.Version, result.Required) > 0) {
+				result.Required = req.Version
+			}
+		}
+
+		if result.Sources == nil {
+			continue
+		}
+
+		for _, req := range reqs {
+			if req.Tool == tool.Name && result.Required != "" && CompareVersions(req.Version, result.Required) == 0 &&
+				!slices.Contains(result.RequiredBy, req.Source) {
+				result.RequiredBy = append(result.RequiredBy, req.Source)
+			}
+		}
+
+		installed, err := Detect(ctx, tool)
+		results = append(results, evaluate(tool, installed, err, result))
+	}
+
+	return results
+}
+
+// Detect returns the installed version of a tool. It returns exec.ErrNotFound
+// when the tool is not in PATH, and an empty version when its output has no
+// recognisable version.
+func Detect(ctx context.Context, tool Tool) (string, error) {
+	if _, err := exec.LookPath(tool.Command); err != nil {
+		return "", exec.ErrNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, detectTimeout)
+	defer cancel()
+
+	//nolint:gosec // Commands come from the hardcoded tool list
+	output, err := exec.CommandContext(ctx, tool.Command, tool.Args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %w", tool.Command, strings.Join(tool.Args, " "), err)
+	}
+
+	match := tool.Pattern.FindStringSubmatch(string(output))
+	if match == nil {
+		return "", nil
+	}
+
+	return match[1], nil
+}
+
+// evaluate sets the status and remediation of a result from the detected
+// version.
+func evaluate(tool Tool, installed string, detectErr error, result Result) Result {
+	result.Installed = installed
+
+	switch {
+	case errors.Is(detectErr, exec.ErrNotFound):
+		result.Status = StatusMissing
+		result.Remediation = fmt.Sprintf("Install %s (required by %s): %s",
+			tool.Name, strings.Join(result.Sources, ", "), tool.Install)
+	case detectErr != nil || installed == "":
+		result.Status = StatusUnknown
+		result.Remediation = fmt.Sprintf("Could not read the %s version; check '%s %s' works",
+			tool.Name, tool.Command, strings.Join(tool.Args, " "))
+	case result.Required != "" && CompareVersions(installed, result.Required) < 0:
+		result.Status = StatusOutdated
+		result.Remediation = fmt.Sprintf("Upgrade %s from %s to %s or newer (required by %s): %s",
+			tool.Name, installed, result.Required, strings.Join(result.RequiredBy, ", "), tool.Install)
+	}
+
+	return result
+}
+
+// CompareVersions compares dotted numeric versions, ignoring a leading "v"
+// and any pre-release or build suffix ("1.25rc1" compares as "1.25").
+// Missing components count as zero, so "1.24" equals "1.24.0".
+func CompareVersions(a, b string) int {
+	left, right := versionParts(a), versionParts(b)
+
+	for i := range max(len(left), len(right)) {
+		var l, r int
+
+		if i < len(left) {
+			l = left[i]
+		}
+
+		if i < len(right) {
+			r = right[i]
+		}
+
+		if l != r {
+			if l < r {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	if idx := strings.IndexAny(version, "-+ "); idx >= 0 {
+		version = version[:idx]
+	}
+
+	var parts []int
+
+	for part := range strings.SplitSeq(version, ".") {
+		digits := part
+		if idx := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); idx >= 0 {
+			digits = part[:idx]
+		}
+
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			break
+		}
+
+		parts = append(parts, n)
+
+		if digits != part {
+			break
+		}
+	}
+
+	return parts
+}
@@ -0,0 +1,57 @@
+package toolchain
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	require.Equal(t, 0, CompareVersions("1.24", "1.24.0"))
+	require.Equal(t, 0, CompareVersions("v20.11.1", "20.11.1"))
+	require.Equal(t, -1, CompareVersions("1.23.9", "1.24"))
+	require.Equal(t, 1, CompareVersions("10.0.0", "9.15.4"))
+	require.Equal(t, 1, CompareVersions("1.25rc1", "1.24"))
+	require.Equal(t, 0, CompareVersions("9.15.4-beta.1", "9.15.4"))
+}
+
+func TestCheck(t *testing.T) {
+	binDir := t.TempDir()
+	writeTool(t, binDir, "go", "go version go1.23.4 linux/amd64")
+	writeTool(t, binDir, "pnpm", "9.15.4")
+	t.Setenv("PATH", binDir)
+
+	results := Check(context.Background(), []Requirement{
+		{Tool: ToolGo, Version: "1.22", Source: "cbt"},
+		{Tool: ToolGo, Version: "1.24.1", Source: "cbt-api"},
+		{Tool: ToolPnpm, Source: "lab"},
+		{Tool: ToolProtoc, Source: "cbt-api"},
+	})
+
+	require.Len(t, results, 3)
+
+	require.Equal(t, ToolGo, results[0].Tool)
+	require.Equal(t, "1.23.4", results[0].Installed)
+	require.Equal(t, "1.24.1", results[0].Required)
+	require.Equal(t, []string{"cbt", "cbt-api"}, results[0].Sources)
+	require.Equal(t, []string{"cbt-api"}, results[0].RequiredBy)
+	require.Equal(t, StatusOutdated, results[0].Status)
+	require.Contains(t, results[0].Remediation, "Upgrade go from 1.23.4 to 1.24.1")
+
+	require.Equal(t, ToolPnpm, results[1].Tool)
+	require.True(t, results[1].OK())
+
+	require.Equal(t, ToolProtoc, results[2].Tool)
+	require.Equal(t, StatusMissing, results[2].Status)
+	require.Contains(t, results[2].Remediation, "Install protoc")
+}
+
+func writeTool(t *testing.T, binDir, name, output string) {
+	t.Helper()
+
+	script := "#!/bin/sh\necho '" + output + "'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755))
+}
@@ -27,6 +27,7 @@ type ServiceInfo struct {
 	Ports   []int
 	Health  string // "healthy", "unhealthy", "unknown"
 	LogFile string
+	Usage   *ResourceUsage // nil when not sampled
 }
 
 // InfraInfo contains infrastructure status.
@@ -34,6 +35,20 @@ type InfraInfo struct {
 	Name   string
 	Status string // "running", "stopped"
 	Type   string // "clickhouse", "redis"
+	Usage  *ResourceUsage
+}
+
+// ResourceUsage is a resource usage sample of a service or container.
+type ResourceUsage struct {
+	CPUPercent  float64
+	MemoryBytes uint64
+	// MemoryLimitBytes is the enforced memory cap, or 0 when none is known.
+	MemoryLimitBytes uint64
+	// OpenFiles is the number of open file descriptors (processes only).
+	OpenFiles int
+	// LimitMode tells how a process's limits are enforced ("cgroup",
+	// "nice"), or is empty.
+	LimitMode string
 }
 
 // NewOrchestratorWrapper creates a wrapper.
@@ -64,6 +79,9 @@ func (w *OrchestratorWrapper) GetServices() []ServiceInfo {
 		obsStatus = nil
 	}
 
+	serviceUsage := w.orch.ServiceUsage()
+	containerUsage := w.orch.InfrastructureManager().CachedContainerUsage()
+
 	services := make([]ServiceInfo, 0, len(validServices))
 
 	for _, name := range validServices {
@@ -84,6 +102,12 @@ func (w *OrchestratorWrapper) GetServices() []ServiceInfo {
 				}
 			}
 
+			if container, ok := w.orch.InfrastructureManager().DockerContainerName(name); ok {
+				if usage, ok := containerUsage[container]; ok && info.Status == statusRunning {
+					info.Usage = containerResourceUsage(usage)
+				}
+			}
+
 			services = append(services, info)
 
 			continue
@@ -105,6 +129,16 @@ func (w *OrchestratorWrapper) GetServices() []ServiceInfo {
 				info.Uptime = time.Since(proc.Started)
 				info.LogFile = proc.LogFile
 
+				if usage, ok := serviceUsage[name]; ok {
+					info.Usage = &ResourceUsage{
+						CPUPercent:       usage.CPUPercent,
+						MemoryBytes:      usage.RSSBytes,
+						MemoryLimitBytes: orchestrator.MemoryLimit(proc),
+						OpenFiles:        usage.OpenFiles,
+						LimitMode:        proc.LimitMode,
+					}
+				}
+
 				break
 			}
 		}
@@ -125,6 +159,7 @@ func (w *OrchestratorWrapper) GetServices() []ServiceInfo {
 func (w *OrchestratorWrapper) GetInfrastructure() []InfraInfo {
 	infraMgr := w.orch.InfrastructureManager()
 	statuses := infraMgr.Status(context.Background())
+	componentUsage := infraMgr.ComponentUsage(infraMgr.CachedContainerUsage())
 
 	// Sort by name for stable display order.
 	names := make([]string, 0, len(statuses))
@@ -151,11 +186,17 @@ func (w *OrchestratorWrapper) GetInfrastructure() []InfraInfo {
 			infraType = "redis"
 		}
 
-		infra = append(infra, InfraInfo{
+		info := InfraInfo{
 			Name:   name,
 			Status: status,
 			Type:   infraType,
-		})
+		}
+
+		if usage, ok := componentUsage[name]; ok && running {
+			info.Usage = containerResourceUsage(usage)
+		}
+
+		infra = append(infra, info)
 	}
 
 	return infra
@@ -277,6 +318,15 @@ func (w *OrchestratorWrapper) RebuildAll(ctx context.Context) error {
 	return nil
 }
 
+// containerResourceUsage converts a docker stats sample. Docker reports the
+// host's memory as the limit of an unlimited container, so it is not shown.
+func containerResourceUsage(usage infrastructure.ContainerUsage) *ResourceUsage {
+	return &ResourceUsage{
+		CPUPercent:  usage.CPUPercent,
+		MemoryBytes: usage.MemoryBytes,
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && s[:len(substr)] == substr
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/acarl005/stripansi"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ethpandaops/xcli/pkg/ui"
 )
 
 // View renders the TUI.
@@ -76,8 +77,9 @@ func (m Model) View() string {
 
 func (m Model) renderServicesPanel() string {
 	rows := make([]string, 0, len(m.services)+2)
-	rows = append(rows, "SERVICE                STATUS      UPTIME       HEALTH")
-	rows = append(rows, strings.Repeat("─", 60))
+	rows = append(rows, fmt.Sprintf("%-22s %-11s %-8s %8s %10s     %s",
+		"SERVICE", "STATUS", "UPTIME", "CPU", "MEM", "HEALTH"))
+	rows = append(rows, strings.Repeat("─", 78))
 
 	for i, svc := range m.services {
 		status := svc.Status
@@ -99,6 +101,13 @@ func (m Model) renderServicesPanel() string {
 		// Uptime column - right-aligned for better readability
 		uptimeStr := fmt.Sprintf("%8s", uptime)
 
+		// Resource columns - blank until a sample is available
+		cpuStr, memStr := fmt.Sprintf("%8s", "-"), fmt.Sprintf("%10s", "-")
+		if svc.Usage != nil {
+			cpuStr = fmt.Sprintf("%7.1f%%", svc.Usage.CPUPercent)
+			memStr = fmt.Sprintf("%10s", ui.FormatBytes(svc.Usage.MemoryBytes))
+		}
+
 		// Health column
 		healthStr := health
 		switch health {
@@ -108,8 +117,8 @@ func (m Model) renderServicesPanel() string {
 			healthStr = StyleError.Render("✗ " + health)
 		}
 
-		row := fmt.Sprintf("%s %s %s     %s",
-			serviceName, statusStr, uptimeStr, healthStr)
+		row := fmt.Sprintf("%s %s %s %s %s     %s",
+			serviceName, statusStr, uptimeStr, cpuStr, memStr, healthStr)
 
 		// Highlight selected
 		if i == m.selectedIndex && m.activePanel == panelServices {
@@ -82,6 +82,8 @@ func statusRow(s Service, nameWidth, urlWidth int) string {
 
 	if !running {
 		row += "  " + styleTreeDim.Render("("+s.Status+")")
+	} else if s.Usage != "" {
+		row += "   " + styleTreeDim.Render(s.Usage)
 	}
 
 	return row
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -368,6 +369,20 @@ func DisplayPatternDiagnosis(result *diagnostic.BuildResult, diag *diagnostic.Di
 
 		Blank()
 	}
+
+	if len(diag.FixCommands) > 0 {
+		fmt.Printf("%s\n", pterm.Bold.Sprint("Fix Commands:"))
+
+		for _, cmd := range diag.FixCommands {
+			fmt.Printf("  %s %s\n", pterm.Yellow("$"), cmd)
+		}
+
+		Blank()
+	}
+
+	if diag.Source != "" {
+		fmt.Printf("%s %s\n", pterm.Gray("Pattern source:"), diag.Source)
+	}
 }
 
 // DisplayRawError shows the raw error when no pattern matches.
@@ -439,6 +454,81 @@ func DisplayRawError(result *diagnostic.BuildResult) {
 	Blank()
 }
 
+// DisplayIncident shows a recorded runtime incident: what happened, the port
+// and infrastructure state at the time, the log tail and the pattern match.
+// Format:
+// ✗ [service] [summary] at [time]
+//
+// Ports: [port] (bound by [process] [pid]), ...
+// Infrastructure down: [name], ...
+//
+// Last log lines:
+// [last 30 log lines].
+func DisplayIncident(incident *diagnostic.Incident) {
+	if incident == nil {
+		return
+	}
+
+	Blank()
+	fmt.Printf("%s %s %s %s\n",
+		pterm.Red(symbolFailure),
+		pterm.Bold.Sprint(incident.Service),
+		pterm.Red(incident.Summary()),
+		pterm.Gray("at "+incident.Time.Format(time.DateTime)))
+
+	fmt.Printf("%s %s\n", pterm.Gray("Incident ID:"), incident.ID)
+
+	if len(incident.Ports) > 0 {
+		ports := make([]string, 0, len(incident.Ports))
+
+		for _, port := range incident.Ports {
+			switch {
+			case port.Bound && port.Process != "":
+				ports = append(ports, fmt.Sprintf("%d (bound by %s %d)", port.Port, port.Process, port.PID))
+			case port.Bound:
+				ports = append(ports, fmt.Sprintf("%d (bound)", port.Port))
+			default:
+				ports = append(ports, fmt.Sprintf("%d (free)", port.Port))
+			}
+		}
+
+		fmt.Printf("%s %s\n", pterm.Gray("Ports:"), strings.Join(ports, ", "))
+	}
+
+	down := make([]string, 0, len(incident.Infra))
+
+	for name, running := range incident.Infra {
+		if !running {
+			down = append(down, name)
+		}
+	}
+
+	if len(down) > 0 {
+		sort.Strings(down)
+		fmt.Printf("%s %s\n", pterm.Yellow("Infrastructure down:"), strings.Join(down, ", "))
+	}
+
+	if len(incident.LogLines) > 0 {
+		lines := incident.LogLines
+		if len(lines) > maxRawErrorLines {
+			lines = lines[len(lines)-maxRawErrorLines:]
+		}
+
+		Blank()
+		fmt.Printf("%s\n", pterm.Bold.Sprint("Last log lines:"))
+
+		for _, line := range lines {
+			fmt.Printf("%s\n", pterm.Gray(line))
+		}
+	}
+
+	if incident.Diagnosis != nil {
+		DisplayPatternDiagnosis(incident.BuildResult(), incident.Diagnosis)
+	}
+
+	Blank()
+}
+
 // DisplayReportSummary shows a single report in list view format.
 // Format:
 // [2024-01-15 10:30:45] ID: abc123 | 2/7 failed | Duration: 5.2s
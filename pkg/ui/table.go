@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/pterm/pterm"
 )
 
@@ -10,6 +13,8 @@ type Service struct {
 	Name   string
 	URL    string
 	Status string
+	// Usage is an optional resource usage summary shown after the URL.
+	Usage string
 }
 
 // GitStatus represents a git repository status for display in a GitStatusTable.
@@ -36,6 +41,11 @@ func ServiceTable(services []Service) {
 	headers := []string{"Service", "URL", "Status"}
 	rows := make([][]string, 0, len(services))
 
+	withUsage := slices.ContainsFunc(services, func(svc Service) bool { return svc.Usage != "" })
+	if withUsage {
+		headers = append(headers, "Usage")
+	}
+
 	for _, svc := range services {
 		var status string
 		if svc.Status == statusRunning {
@@ -44,7 +54,12 @@ func ServiceTable(services []Service) {
 			status = pterm.Red(svc.Status)
 		}
 
-		rows = append(rows, []string{svc.Name, svc.URL, status})
+		row := []string{svc.Name, svc.URL, status}
+		if withUsage {
+			row = append(row, svc.Usage)
+		}
+
+		rows = append(rows, row)
 	}
 
 	Table(headers, rows)
@@ -62,3 +77,20 @@ func GitStatusTable(statuses []GitStatus) {
 
 	Table(headers, rows)
 }
+
+// FormatBytes renders a byte count with a binary unit, e.g. 512.0 MiB.
+func FormatBytes(bytes uint64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTP"[exp])
+}
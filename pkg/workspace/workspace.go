@@ -79,12 +79,22 @@ func Resolve(configPath string, requireConfig bool, checkCWDOverrides bool) (*Wo
 // LoadConfig resolves and loads the root config from the authoritative
 // workspace.
 func LoadConfig(configPath string, requireConfig bool, checkCWDOverrides bool) (*config.Config, *Workspace, error) {
+	return LoadConfigWithProfile(configPath, requireConfig, checkCWDOverrides, config.ActiveProfile())
+}
+
+// LoadConfigWithProfile is LoadConfig with an explicit lab profile.
+func LoadConfigWithProfile(
+	configPath string,
+	requireConfig bool,
+	checkCWDOverrides bool,
+	profile string,
+) (*config.Config, *Workspace, error) {
 	ws, err := Resolve(configPath, requireConfig, checkCWDOverrides)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	result, err := config.Load(ws.ConfigPath)
+	result, err := config.LoadWithProfile(ws.ConfigPath, profile)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load config: %w", err)
 	}
@@ -95,7 +105,16 @@ func LoadConfig(configPath string, requireConfig bool, checkCWDOverrides bool) (
 // LoadLabConfig resolves the workspace, loads the lab config, and converts
 // repository paths to absolute paths relative to the config file directory.
 func LoadLabConfig(configPath string, checkCWDOverrides bool) (*config.LabConfig, *Workspace, error) {
-	rootCfg, ws, err := LoadConfig(configPath, true, checkCWDOverrides)
+	return LoadLabConfigWithProfile(configPath, checkCWDOverrides, config.ActiveProfile())
+}
+
+// LoadLabConfigWithProfile is LoadLabConfig with an explicit lab profile.
+func LoadLabConfigWithProfile(
+	configPath string,
+	checkCWDOverrides bool,
+	profile string,
+) (*config.LabConfig, *Workspace, error) {
+	rootCfg, ws, err := LoadConfigWithProfile(configPath, true, checkCWDOverrides, profile)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -106,6 +125,12 @@ func LoadLabConfig(configPath string, checkCWDOverrides bool) (*config.LabConfig
 
 	ResolveLabRepoPaths(rootCfg.Lab, ws.RootDir)
 
+	if rootCfg.Xatu != nil {
+		xatu := *rootCfg.Xatu
+		xatu.Repos.Xatu = resolvePath(ws.RootDir, xatu.Repos.Xatu)
+		rootCfg.Lab.XatuStack = &xatu
+	}
+
 	return rootCfg.Lab, ws, nil
 }
 